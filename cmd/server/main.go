@@ -3,12 +3,40 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
 
 	"github.com/AutOpsProject/AutOps-API/internal/api"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
 )
 
 func main() {
-	router := api.SetupRouter()
+	baseURL := os.Getenv("AUTOPS_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	var masterKey *secret.MasterKey
+	if encoded := os.Getenv("AUTOPS_MASTER_KEY"); encoded != "" {
+		key, err := secret.NewMasterKey(encoded)
+		if err != nil {
+			log.Fatalf("AUTOPS_MASTER_KEY: %v", err)
+		}
+		masterKey = key
+	}
+	var fetcher *gitsource.Fetcher
+	if cacheDir := os.Getenv("AUTOPS_SOURCE_CACHE"); cacheDir != "" {
+		gitFetcher, err := gitsource.NewFetcher(cacheDir)
+		if err != nil {
+			log.Fatalf("AUTOPS_SOURCE_CACHE: %v", err)
+		}
+		fetcher = gitFetcher
+	}
+	router := api.SetupRouter(api.Dependencies{
+		BaseURL:   baseURL,
+		MasterKey: masterKey,
+		Sources:   versioning.NewSourceLoader(fetcher, os.Getenv("AUTOPS_SOURCE_ROOT")),
+	})
 	log.Println("Server running on :8080")
 	http.ListenAndServe(":8080", router)
 }
@@ -0,0 +1,5 @@
+package accessreport
+
+import "errors"
+
+var ErrResourceNotFound = errors.New("the project has no resource with the provided id")
@@ -0,0 +1,126 @@
+// Package accessreport builds the effective permissions reports of access reviews: the actions a user can perform on
+// the resources of a project, and the principals that can perform each action on a resource.
+//
+// Reports are built by evaluating the policies of every principal, as requests are authorized, at the date of the report.
+// Runs against environments are reported when the policies of the principal allow workflow:Run on the environment,
+// regardless of the policies attached to the environment itself, which restrict runs per workflow.
+package accessreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// CSV_HEADER lists the columns of the CSV reports, one row per grant.
+// Policies allowing the same grant are separated by semicolons.
+var CSV_HEADER = []string{"principal_type", "principal_id", "principal_name", "resource_type", "resource_id", "resource_name", "action", "policy_ids", "policy_names"}
+
+// Grant is an action a principal is allowed to perform on a resource, along with the policies allowing it.
+type Grant struct {
+	principal     *common.Identifier
+	principalName string
+	principalTags []*common.Tag
+	resource      *common.Identifier
+	resourceName  string
+	action        policy.PolicyAction
+	policies      []*policy.Policy
+}
+
+// GetPrincipal returns the identifier of the user, group or role allowed to perform the action.
+func (g *Grant) GetPrincipal() *common.Identifier {
+	return g.principal
+}
+
+// GetPrincipalName returns the username of the user, or the name of the group or role.
+func (g *Grant) GetPrincipalName() string {
+	return g.principalName
+}
+
+// ListPrincipalTags returns the tags of the user, group or role.
+func (g *Grant) ListPrincipalTags() []*common.Tag {
+	return g.principalTags
+}
+
+// GetResource returns the identifier of the resource.
+func (g *Grant) GetResource() *common.Identifier {
+	return g.resource
+}
+
+// GetResourceName returns the name of the resource.
+func (g *Grant) GetResourceName() string {
+	return g.resourceName
+}
+
+// GetAction returns the allowed action.
+func (g *Grant) GetAction() policy.PolicyAction {
+	return g.action
+}
+
+// ListPolicies returns the policies with a statement allowing the action on the resource.
+func (g *Grant) ListPolicies() []*policy.Policy {
+	return g.policies
+}
+
+// Report lists the grants of an access review, evaluated at the date the report was generated.
+type Report struct {
+	generatedAt string
+	grants      []*Grant
+}
+
+// GetGeneratedAt returns the date the policies were evaluated at.
+func (r *Report) GetGeneratedAt() string {
+	return r.generatedAt
+}
+
+// ListGrants returns the grants of the report.
+func (r *Report) ListGrants() []*Grant {
+	return r.grants
+}
+
+// Filter returns a report, generated at the same date, with only the grants kept by the function.
+func (r *Report) Filter(keep func(grant *Grant) bool) *Report {
+	filtered := &Report{generatedAt: r.generatedAt, grants: []*Grant{}}
+	for _, grant := range r.grants {
+		if keep(grant) {
+			filtered.grants = append(filtered.grants, grant)
+		}
+	}
+	return filtered
+}
+
+// MarshalCSV writes the grants of the report as a CSV document, with the CSV_HEADER columns.
+func (r *Report) MarshalCSV() ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(CSV_HEADER); err != nil {
+		return nil, err
+	}
+	for _, grant := range r.grants {
+		principalType, _ := grant.principal.GetType().ToString()
+		resourceType, _ := grant.resource.GetType().ToString()
+		action, _ := policy.FormatPolicyAction(grant.action)
+		policyIds := make([]string, 0, len(grant.policies))
+		policyNames := make([]string, 0, len(grant.policies))
+		for _, p := range grant.policies {
+			policyIds = append(policyIds, p.GetIdentifier().ToString())
+			policyNames = append(policyNames, p.GetName())
+		}
+		record := []string{
+			principalType, grant.principal.ToString(), grant.principalName,
+			resourceType, grant.resource.ToString(), grant.resourceName,
+			action, strings.Join(policyIds, ";"), strings.Join(policyNames, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
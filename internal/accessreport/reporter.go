@@ -0,0 +1,215 @@
+package accessreport
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+)
+
+// pageSize is the number of principals loaded at once when listing every principal.
+const pageSize = 100
+
+// Reporter builds the effective permissions reports of the resources of projects.
+type Reporter struct {
+	users  identity.UserRepository
+	groups identity.GroupRepository
+	roles  identity.RoleRepository
+}
+
+// NewReporter creates a Reporter listing the principals of the given repositories. Groups and roles are not reported if nil.
+// The user repository is expected to load the groups of the users, see identity.WithGroupMemberships,
+// so that the policies of their groups are evaluated.
+func NewReporter(users identity.UserRepository, groups identity.GroupRepository, roles identity.RoleRepository) *Reporter {
+	return &Reporter{
+		users:  users,
+		groups: groups,
+		roles:  roles,
+	}
+}
+
+// explainer is implemented by the principals whose policies can be evaluated: users, groups and roles.
+type explainer interface {
+	ExplainPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) *policy.Decision
+}
+
+// principal is a user, group or role whose permissions are reported, with the tags the statement conditions are evaluated against.
+type principal struct {
+	identifier *common.Identifier
+	name       string
+	tags       []*common.Tag
+	explainer  explainer
+}
+
+// resource is a resource of a project, with the tags the statement conditions are evaluated against.
+type resource struct {
+	identifier *common.Identifier
+	name       string
+	tags       []*common.Tag
+}
+
+// listResources returns the project itself, followed by its templates, workflows, policies and environments.
+func listResources(p *project.Project) []resource {
+	resources := []resource{{p.GetIdentifier(), p.GetName(), p.ListTags()}}
+	for _, t := range p.ListTemplates() {
+		resources = append(resources, resource{t.GetIdentifier(), t.GetName(), t.ListTags()})
+	}
+	for _, wf := range p.ListWorkflows() {
+		resources = append(resources, resource{wf.GetIdentifier(), wf.GetName(), wf.ListTags()})
+	}
+	for _, pol := range p.ListPolicies() {
+		resources = append(resources, resource{pol.GetIdentifier(), pol.GetName(), pol.ListTags()})
+	}
+	for _, environment := range p.ListEnvironments() {
+		resources = append(resources, resource{environment.GetIdentifier(), environment.GetName(), nil})
+	}
+	return resources
+}
+
+// listActions returns the actions that can be requested on resources of the given type.
+// Besides workflows, workflow runs are authorized on the environments they target.
+func listActions(resourceType common.ResourceType) []policy.PolicyAction {
+	if resourceType == common.ENVIRONMENT {
+		return []policy.PolicyAction{policy.RUN_WORKFLOW}
+	}
+	return policy.ListPolicyActions(resourceType)
+}
+
+// evaluate returns the grant of the action on the resource to the principal, or nil if its policies do not allow it.
+func evaluate(granted principal, target resource, action policy.PolicyAction, at time.Time) *Grant {
+	context := policy.NewRequestContext(granted.identifier, at)
+	context.SetPrincipalTags(granted.tags)
+	context.SetResourceTags(target.tags)
+	decision := granted.explainer.ExplainPermission(target.identifier, action, context)
+	if !decision.IsAllowed() {
+		return nil
+	}
+	policies := common.NewList(policy.PolicyComparator{}, []*policy.Policy{})
+	for _, match := range decision.ListMatches() {
+		if match.GetEffect() == policy.ALLOW && !policies.Contains(match.GetPolicy()) {
+			policies.Append(match.GetPolicy())
+		}
+	}
+	return &Grant{
+		principal:     granted.identifier,
+		principalName: granted.name,
+		principalTags: granted.tags,
+		resource:      target.identifier,
+		resourceName:  target.name,
+		action:        action,
+		policies:      policies.Items(),
+	}
+}
+
+// ForUser reports every action the user can perform on every resource of the project,
+// with its own policies, the policies of its groups and its permission boundary.
+func (r *Reporter) ForUser(user *identity.User, p *project.Project) *Report {
+	at := time.Now()
+	granted := principal{user.GetIdentifier(), user.GetUsername(), user.ListPrincipalTags(), user}
+	report := &Report{generatedAt: at.Format(time.RFC3339), grants: []*Grant{}}
+	for _, target := range listResources(p) {
+		for _, action := range listActions(target.identifier.GetType()) {
+			if grant := evaluate(granted, target, action, at); grant != nil {
+				report.grants = append(report.grants, grant)
+			}
+		}
+	}
+	return report
+}
+
+// ForResource reports, for each action on the resource of the project, every user, group and role that can perform it.
+// Users are listed first, then groups and roles, each sorted by name.
+//
+// Returns ErrResourceNotFound if the resource is neither the project nor one of its resources,
+// or an error if the principals cannot be listed.
+func (r *Reporter) ForResource(p *project.Project, resourceId *common.Identifier) (*Report, error) {
+	var target *resource
+	for _, candidate := range listResources(p) {
+		if candidate.identifier.ToString() == resourceId.ToString() {
+			target = &candidate
+			break
+		}
+	}
+	if target == nil {
+		return nil, ErrResourceNotFound
+	}
+	principals, err := r.listPrincipals()
+	if err != nil {
+		return nil, err
+	}
+	at := time.Now()
+	report := &Report{generatedAt: at.Format(time.RFC3339), grants: []*Grant{}}
+	for _, action := range listActions(target.identifier.GetType()) {
+		for _, granted := range principals {
+			if grant := evaluate(granted, *target, action, at); grant != nil {
+				report.grants = append(report.grants, grant)
+			}
+		}
+	}
+	return report, nil
+}
+
+// listPrincipals loads every user, group and role, each sorted by name.
+func (r *Reporter) listPrincipals() ([]principal, error) {
+	principals := []principal{}
+	users, err := listAll(r.users.FindAll)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		principals = append(principals, principal{user.GetIdentifier(), user.GetUsername(), user.ListPrincipalTags(), user})
+	}
+	sortByName(principals)
+	if r.groups != nil {
+		groups, err := listAll(r.groups.FindAll)
+		if err != nil {
+			return nil, err
+		}
+		found := []principal{}
+		for _, group := range groups {
+			found = append(found, principal{group.GetIdentifier(), group.GetName(), group.ListTags(), group})
+		}
+		principals = append(principals, sortByName(found)...)
+	}
+	if r.roles != nil {
+		roles, err := listAll(r.roles.FindAll)
+		if err != nil {
+			return nil, err
+		}
+		found := []principal{}
+		for _, role := range roles {
+			found = append(found, principal{role.GetIdentifier(), role.GetName(), role.ListTags(), role})
+		}
+		principals = append(principals, sortByName(found)...)
+	}
+	return principals, nil
+}
+
+// listAll loads every page of the given finder.
+func listAll[T any](findAll func(offset int, limit int) ([]T, error)) ([]T, error) {
+	items := []T{}
+	for offset := 0; ; offset += pageSize {
+		page, err := findAll(offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(page) < pageSize {
+			return items, nil
+		}
+	}
+}
+
+// sortByName sorts the principals by name, then by identifier, and returns them.
+func sortByName(principals []principal) []principal {
+	sort.SliceStable(principals, func(i, j int) bool {
+		if principals[i].name != principals[j].name {
+			return principals[i].name < principals[j].name
+		}
+		return principals[i].identifier.ToString() < principals[j].identifier.ToString()
+	})
+	return principals
+}
@@ -0,0 +1,207 @@
+package accessreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// fakeUserRepository is an in-memory identity.UserRepository listing a fixed set of users.
+type fakeUserRepository struct {
+	identity.UserRepository
+	users []*identity.User
+}
+
+func (f *fakeUserRepository) FindAll(offset int, limit int) ([]*identity.User, error) {
+	return f.users, nil
+}
+
+// fakeGroupRepository is an in-memory identity.GroupRepository listing a fixed set of groups.
+type fakeGroupRepository struct {
+	identity.GroupRepository
+	groups []*identity.Group
+}
+
+func (f *fakeGroupRepository) FindAll(offset int, limit int) ([]*identity.Group, error) {
+	return f.groups, nil
+}
+
+// fakeRoleRepository is an in-memory identity.RoleRepository listing a fixed set of roles.
+type fakeRoleRepository struct {
+	identity.RoleRepository
+	roles []*identity.Role
+}
+
+func (f *fakeRoleRepository) FindAll(offset int, limit int) ([]*identity.Role, error) {
+	return f.roles, nil
+}
+
+// describe formats each grant as "principal_name resource_name action policy_names".
+func describe(report *Report) []string {
+	described := []string{}
+	for _, grant := range report.ListGrants() {
+		action, _ := policy.FormatPolicyAction(grant.GetAction())
+		names := []string{}
+		for _, p := range grant.ListPolicies() {
+			names = append(names, p.GetName())
+		}
+		described = append(described, strings.Join([]string{grant.GetPrincipalName(), grant.GetResourceName(), action, strings.Join(names, ";")}, " "))
+	}
+	return described
+}
+
+func TestReporter(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	wf, _ := workflow.NewWorkflow(projectId, "deploy", "", "./deploy")
+	p.AddWorkflow(wf)
+	env, _ := project.NewEnvironment(projectId, "prod", "", true)
+	p.AddEnvironment(env)
+
+	newPolicy := func(name string, statements ...*policy.PolicyStatement) *policy.Policy {
+		created, err := policy.NewPolicy(projectId, name, "", statements)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return created
+	}
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	runWorkflow, _ := policy.ParsePolicyStatement(1, "Allow", []string{"workflow:Read", "workflow:Run"}, []string{wf.GetIdentifier().ToString(), env.GetIdentifier().ToString()})
+	operators := newPolicy("operators", readProject, runWorkflow)
+	auditors := newPolicy("auditors", readProject)
+
+	alice, _ := identity.NewUser("alice@example.com", "alice")
+	alice.AttachPolicy(operators)
+	group, _ := identity.NewGroup("auditors", "")
+	group.AttachPolicy(auditors)
+	bob, _ := identity.NewUser("bob@example.com", "bob")
+	bob.SetGroups([]*identity.Group{group})
+	carol, _ := identity.NewUser("carol@example.com", "carol")
+	carol.AttachPolicy(operators)
+	carol.SetPermissionBoundary(auditors)
+	role, _ := identity.NewRole("deployer", "", 0)
+	role.AttachPolicy(operators)
+
+	reporter := NewReporter(&fakeUserRepository{users: []*identity.User{carol, bob, alice}}, &fakeGroupRepository{groups: []*identity.Group{group}}, &fakeRoleRepository{roles: []*identity.Role{role}})
+
+	expected := []string{
+		"alice network project:Read operators",
+		"alice deploy workflow:Read operators",
+		"alice deploy workflow:Run operators",
+		"alice prod workflow:Run operators",
+	}
+	if found := describe(reporter.ForUser(alice, p)); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected user grants %v, got %v", expected, found)
+	}
+	expected = []string{"carol network project:Read operators"}
+	if found := describe(reporter.ForUser(carol, p)); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected grants limited by the permission boundary %v, got %v", expected, found)
+	}
+
+	report, err := reporter.ForResource(p, p.GetIdentifier())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{
+		"alice network project:Read operators",
+		"bob network project:Read auditors",
+		"carol network project:Read operators",
+		"auditors network project:Read auditors",
+		"deployer network project:Read operators",
+	}
+	if found := describe(report); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected resource grants %v, got %v", expected, found)
+	}
+	report, _ = reporter.ForResource(p, wf.GetIdentifier())
+	expected = []string{
+		"alice deploy workflow:Read operators",
+		"deployer deploy workflow:Read operators",
+		"alice deploy workflow:Run operators",
+		"deployer deploy workflow:Run operators",
+	}
+	if found := describe(report); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected resource grants %v, got %v", expected, found)
+	}
+
+	missing, _ := common.BuildTemplateIdentifier(projectId)
+	if _, err := reporter.ForResource(p, missing); err != ErrResourceNotFound {
+		t.Errorf("expected %v, got %v", ErrResourceNotFound, err)
+	}
+	if report, err := NewReporter(&fakeUserRepository{users: []*identity.User{bob}}, nil, nil).ForResource(p, p.GetIdentifier()); err != nil || len(report.ListGrants()) != 1 {
+		t.Errorf("expected only the users to be reported without groups and roles, got %v", err)
+	}
+}
+
+func TestReportMarshalCSV(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	readers, _ := policy.NewPolicy(projectId, "readers", "", []*policy.PolicyStatement{readProject})
+	admins, _ := policy.NewPolicy(projectId, "admins", "", []*policy.PolicyStatement{readProject})
+	alice, _ := identity.NewUser("alice@example.com", "alice")
+	alice.AttachPolicy(readers)
+	alice.AttachPolicy(admins)
+
+	document, err := NewReporter(nil, nil, nil).ForUser(alice, p).MarshalCSV()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(document)).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %v: %s", err, document)
+	}
+	if !reflect.DeepEqual(records[0], CSV_HEADER) {
+		t.Errorf("expected header %v, got %v", CSV_HEADER, records[0])
+	}
+	expected := []string{
+		"user", alice.GetIdentifier().ToString(), "alice", "project", projectId, "network", "project:Read",
+		readers.GetIdentifier().ToString() + ";" + admins.GetIdentifier().ToString(), "readers;admins",
+	}
+	if !reflect.DeepEqual(records[1], expected) {
+		t.Errorf("expected row %v, got %v", expected, records[1])
+	}
+}
+
+func TestReporterPrincipalTags(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	team, _ := policy.NewCondition(policy.STRING_EQUALS, policy.PrincipalTagKey("team"), []string{"platform"})
+	readProject.SetConditions([]*policy.Condition{team})
+	platform, _ := policy.NewPolicy(projectId, "platform", "", []*policy.PolicyStatement{readProject})
+
+	alice, _ := identity.NewUser("alice@example.com", "alice")
+	alice.AttachPolicy(platform)
+	alice.AddTag(common.NewTag("team", "platform"))
+	bob, _ := identity.NewUser("bob@example.com", "bob")
+	bob.AttachPolicy(platform)
+	group, _ := identity.NewGroup("platform", "")
+	group.AttachPolicy(platform)
+	group.AddTag(common.NewTag("team", "platform"))
+	role, _ := identity.NewRole("deployer", "", 0)
+	role.AttachPolicy(platform)
+
+	reporter := NewReporter(&fakeUserRepository{users: []*identity.User{alice, bob}}, &fakeGroupRepository{groups: []*identity.Group{group}}, &fakeRoleRepository{roles: []*identity.Role{role}})
+	report, err := reporter.ForResource(p, p.GetIdentifier())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"alice network project:Read platform",
+		"platform network project:Read platform",
+	}
+	if found := describe(report); !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected the grants of the principals tagged with the team %v, got %v", expected, found)
+	}
+	if found := describe(reporter.ForUser(bob, p)); len(found) != 0 {
+		t.Errorf("expected an untagged user to have no grant, got %v", found)
+	}
+}
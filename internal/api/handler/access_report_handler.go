@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/accessreport"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidReportFormat = errors.New("invalid report format : correct values are 'json' or 'csv'")
+
+// AccessReportHandler builds the effective permissions reports of access reviews, as JSON or CSV documents.
+// Reports require the authenticated user to be allowed to read the project.
+type AccessReportHandler struct {
+	projects project.ProjectRepository
+	users    identity.UserRepository
+	reporter *accessreport.Reporter
+}
+
+// NewAccessReportHandler creates an AccessReportHandler. Groups and roles are not reported if their repository is nil.
+// The user repository is expected to load the groups of the users, see identity.WithGroupMemberships.
+func NewAccessReportHandler(projects project.ProjectRepository, users identity.UserRepository, groups identity.GroupRepository, roles identity.RoleRepository) *AccessReportHandler {
+	return &AccessReportHandler{
+		projects: projects,
+		users:    users,
+		reporter: accessreport.NewReporter(users, groups, roles),
+	}
+}
+
+// findProject loads the project of the request path, writing the error response if it cannot be found.
+func (h *AccessReportHandler) findProject(w http.ResponseWriter, r *http.Request) *project.Project {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil {
+		return nil
+	}
+	found, err := h.projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrProjectNotFound)
+		return nil
+	}
+	return found
+}
+
+// parseReportFormat reads the format query parameter, "json" by default, writing the error response if it is invalid.
+func parseReportFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		return "json", true
+	case "json", "csv":
+		return format, true
+	default:
+		writeError(w, http.StatusBadRequest, ErrInvalidReportFormat)
+		return "", false
+	}
+}
+
+// writeReport writes the report in the given format.
+func writeReport(w http.ResponseWriter, format string, report *accessreport.Report) {
+	if format == "json" {
+		writeJSON(w, http.StatusOK, dto.NewAccessReportDTO(report))
+		return
+	}
+	document, err := report.MarshalCSV()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// GetUserReport handles GET /projects/{projectId}/users/{userId}/permissions, reporting every action the user can perform
+// on every resource of the project. It also requires the user:Read action on the user.
+// The format query parameter selects a "json" (default) or "csv" document.
+func (h *AccessReportHandler) GetUserReport(w http.ResponseWriter, r *http.Request) {
+	format, ok := parseReportFormat(w, r)
+	if !ok {
+		return
+	}
+	p := h.findProject(w, r)
+	if p == nil {
+		return
+	}
+	user := findUser(w, r, h.users)
+	if user == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}, permission{user.GetIdentifier(), policy.READ_USER, nil}) {
+		return
+	}
+	writeReport(w, format, h.reporter.ForUser(user, p))
+}
+
+// GetResourceReport handles GET /projects/{projectId}/resources/{resourceId}/principals, reporting for each action
+// on the project or one of its resources every user, group and role that can perform it.
+// Only the principals the authenticated user is allowed to read, with the user:Read, group:Read or role:Read action, are reported.
+// The format query parameter selects a "json" (default) or "csv" document.
+func (h *AccessReportHandler) GetResourceReport(w http.ResponseWriter, r *http.Request) {
+	format, ok := parseReportFormat(w, r)
+	if !ok {
+		return
+	}
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	resourceId, err := common.NewIdentifier(mux.Vars(r)["resourceId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, accessreport.ErrResourceNotFound)
+		return
+	}
+	report, err := h.reporter.ForResource(p, resourceId)
+	if err == accessreport.ErrResourceNotFound {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if h.users != nil {
+		user := currentUser(w, r, h.users)
+		if user == nil {
+			return
+		}
+		report = report.Filter(func(grant *accessreport.Grant) bool {
+			return canRead(user, grant)
+		})
+	}
+	writeReport(w, format, report)
+}
+
+// canRead returns true if the user is allowed to read the principal of the grant.
+func canRead(user *identity.User, grant *accessreport.Grant) bool {
+	var action policy.PolicyAction
+	switch grant.GetPrincipal().GetType() {
+	case common.USER:
+		action = policy.READ_USER
+	case common.GROUP:
+		action = policy.READ_GROUP
+	case common.ROLE:
+		action = policy.READ_ROLE
+	default:
+		return false
+	}
+	context := user.NewRequestContext(time.Now())
+	context.SetResourceTags(grant.ListPrincipalTags())
+	return user.IsAllowed(grant.GetPrincipal(), action, context)
+}
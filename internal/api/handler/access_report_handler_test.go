@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestAccessReportHandler(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	auditor, _ := identity.NewUser("auditor@example.com", "auditor")
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	readers, _ := policy.NewPolicy(projectId, "readers", "", []*policy.PolicyStatement{readProject})
+	p.AddPolicy(readers)
+	developer.AttachPolicy(readers)
+	group, _ := identity.NewGroup("readers", "")
+	group.AttachPolicy(readers)
+	readUsers, _ := policy.ParsePolicyStatement(1, "Allow", []string{"user:Read"}, []string{developer.GetIdentifier().ToString(), auditor.GetIdentifier().ToString()})
+	readGroups, _ := policy.ParsePolicyStatement(2, "Allow", []string{"group:Read"}, []string{group.GetIdentifier().ToString()})
+	auditors, _ := policy.NewPolicy(projectId, "auditors", "", []*policy.PolicyStatement{readProject, readUsers, readGroups})
+	auditor.AttachPolicy(auditors)
+
+	h := NewAccessReportHandler(newFakeProjectRepository(p), newFakeUserRepository(developer, auditor), newFakeGroupRepository(group), nil)
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/{projectId}/users/{userId}/permissions", h.GetUserReport).Methods("GET")
+	r.HandleFunc("/projects/{projectId}/resources/{resourceId}/principals", h.GetResourceReport).Methods("GET")
+	send := func(path string, user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	userReport := "/projects/" + projectId + "/users/" + developer.GetIdentifier().ToString() + "/permissions"
+
+	response := send(userReport, auditor)
+	var report dto.AccessReportDTO
+	json.Unmarshal(response.Body.Bytes(), &report)
+	if response.Code != http.StatusOK || len(report.Grants) != 1 || report.GeneratedAt == "" {
+		t.Fatalf("expected %d with 1 grant, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if grant := report.Grants[0]; grant.PrincipalType != "user" || grant.PrincipalName != "developer" || grant.ResourceId != projectId ||
+		grant.Action != "project:Read" || len(grant.Policies) != 1 || grant.Policies[0].PolicyName != "readers" {
+		t.Errorf("unexpected grant %+v", grant)
+	}
+	if response := send(userReport, developer); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the user:Read action, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := send(userReport+"?format=xml", auditor); response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d for an invalid format, got %d", http.StatusBadRequest, response.Code)
+	}
+
+	response = send("/projects/"+projectId+"/resources/"+projectId+"/principals?format=csv", auditor)
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("expected %d with a CSV document, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	records, err := csv.NewReader(response.Body).ReadAll()
+	// The header, then the auditor and the developer in the order of their names, then the group.
+	if err != nil || len(records) != 4 || records[1][2] != "auditor" || records[2][2] != "developer" || records[3][0] != "group" || records[3][8] != "readers" {
+		t.Errorf("unexpected CSV report %v: %v", records, err)
+	}
+	response = send("/projects/"+projectId+"/resources/"+projectId+"/principals?format=csv", developer)
+	if records, err := csv.NewReader(response.Body).ReadAll(); response.Code != http.StatusOK || err != nil || len(records) != 1 {
+		t.Errorf("expected only the header without the user:Read and group:Read actions, got %d: %v", response.Code, records)
+	}
+	if response := send("/projects/"+projectId+"/resources/"+auditors.GetIdentifier().ToString()+"/principals", developer); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d for a resource outside of the project, got %d", http.StatusNotFound, response.Code)
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+// ApprovalHandler exposes the approval requests of workflow runs waiting on approval gates.
+type ApprovalHandler struct {
+	workflows workflow.WorkflowRepository
+	users     identity.UserRepository
+}
+
+// NewApprovalHandler creates an ApprovalHandler.
+func NewApprovalHandler(workflows workflow.WorkflowRepository, users identity.UserRepository) *ApprovalHandler {
+	return &ApprovalHandler{
+		workflows: workflows,
+		users:     users,
+	}
+}
+
+// findRun loads the workflow and the run referenced by the request path, writing the error response if one cannot be found.
+func findRun(w http.ResponseWriter, r *http.Request, workflows workflow.WorkflowRepository) (*workflow.Workflow, *workflow.WorkflowRun) {
+	wf := findWorkflow(w, workflows, mux.Vars(r)["workflowId"])
+	if wf == nil {
+		return nil, nil
+	}
+	run := wf.GetRun(mux.Vars(r)["runId"])
+	if run == nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWorkflowRunNotFound)
+		return nil, nil
+	}
+	return wf, run
+}
+
+// ListApprovals handles GET /workflows/{workflowId}/runs/{runId}/approvals, which requires the workflow:Read action on the workflow.
+func (h *ApprovalHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.READ_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	requests := []dto.ApprovalRequestDTO{}
+	for _, request := range run.ListApprovalRequests() {
+		requests = append(requests, dto.NewApprovalRequestDTO(request))
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// SubmitApproval handles POST /workflows/{workflowId}/runs/{runId}/approvals.
+// The authenticated user approves or rejects the pending approval of the run, which is resumed or failed accordingly.
+// Users cannot approve the runs they started, and an approved run waits again if the next step is another approval gate.
+func (h *ApprovalHandler) SubmitApproval(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil {
+		return
+	}
+	var body dto.SubmitApprovalDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	err := wf.SubmitApproval(run.GetIdentifier().ToString(), user, body.Approved, body.Comment)
+	switch err {
+	case nil, workflow.ErrApprovalExpired:
+		if updateErr := h.workflows.Update(wf); updateErr != nil {
+			writeError(w, http.StatusInternalServerError, updateErr)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+	case workflow.ErrNotAnApprover, workflow.ErrSelfApproval:
+		writeError(w, http.StatusForbidden, err)
+		return
+	default:
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewWorkflowRunDTO(run))
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestApprovalHandler(t *testing.T) {
+	approver, _ := identity.NewUser("approver@example.com", "approver")
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	gate, _ := workflow.NewApprovalGate([]*common.Identifier{approver.GetIdentifier()}, nil, 1, time.Hour)
+	step, _ := workflow.NewApprovalStep(wf.GetIdentifier().ToString(), "approve", "", 1, gate)
+	wf.AddStep(step)
+	run, _ := workflow.NewWorkflowRun(wf.GetIdentifier().ToString(), "run", "")
+	wf.AddRun(run)
+	run.RequestApproval(step)
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read"}, []string{wf.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "readers", "", []*policy.PolicyStatement{statement})
+	approver.AttachPolicy(readers)
+
+	h := NewApprovalHandler(newFakeWorkflowRepository(wf), newFakeUserRepository(approver, stranger))
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/approvals", h.ListApprovals).Methods("GET")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/approvals", h.SubmitApproval).Methods("POST")
+	path := "/workflows/" + wf.GetIdentifier().ToString() + "/runs/" + run.GetIdentifier().ToString() + "/approvals"
+
+	submit := func(user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{"approved": true, "comment": "ship it"}`))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := submit(nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := submit(stranger); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, response.Code)
+	}
+	response := submit(approver)
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	var result dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&result)
+	if result.Status != "running" {
+		t.Errorf("expected run to be resumed, got %s", result.Status)
+	}
+	if response := submit(approver); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+
+	list := func(user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+	if response := list(stranger); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the workflow:Read action, got %d", http.StatusForbidden, response.Code)
+	}
+	response = list(approver)
+	var requests []dto.ApprovalRequestDTO
+	json.NewDecoder(response.Body).Decode(&requests)
+	if len(requests) != 1 || requests[0].Status != "success" || len(requests[0].Decisions) != 1 || requests[0].Decisions[0].Comment != "ship it" {
+		t.Errorf("unexpected approval requests %+v", requests)
+	}
+
+	own, _ := wf.StartRun("own", "", workflow.STANDARD, nil)
+	own.SetStartedBy(approver.GetIdentifier())
+	path = "/workflows/" + wf.GetIdentifier().ToString() + "/runs/" + own.GetIdentifier().ToString() + "/approvals"
+	if response := submit(approver); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d when approving its own run, got %d", http.StatusForbidden, response.Code)
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// USER_HEADER carries the identifier of the authenticated user.
+// It is expected to be set by the authenticating proxy in front of the API, which must strip it from client requests.
+const USER_HEADER = "X-AutOps-User"
+
+// SESSION_HEADER and SESSION_TOKEN_HEADER carry the identifier and the token of a role session, obtained by assuming a role.
+// When provided, the request is authenticated as the user who assumed the role, acting with the policies of the role.
+const (
+	SESSION_HEADER       = "X-AutOps-Session"
+	SESSION_TOKEN_HEADER = "X-AutOps-Session-Token"
+)
+
+var (
+	ErrUnauthenticated = errors.New("the request is not associated with an authenticated user")
+	ErrForbidden       = errors.New("the authenticated user is not allowed to perform this action")
+)
+
+// currentUser loads the authenticated user of the request, writing the error response if it cannot be determined.
+// Role session credentials are only accepted if the repository authenticates them, see identity.WithRoleSessions.
+func currentUser(w http.ResponseWriter, r *http.Request, users identity.UserRepository) *identity.User {
+	if r.Header.Get(SESSION_HEADER) != "" {
+		return sessionUser(w, r, users)
+	}
+	identifier, err := common.NewIdentifier(r.Header.Get(USER_HEADER))
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+		return nil
+	}
+	user, err := users.FindById(*identifier, 0, 1)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+		return nil
+	}
+	return user
+}
+
+// sessionUser loads the user acting with the role session of the request, writing the error response if it cannot be authenticated.
+func sessionUser(w http.ResponseWriter, r *http.Request, users identity.UserRepository) *identity.User {
+	sessions, ok := users.(identity.SessionUserRepository)
+	identifier, err := common.NewIdentifier(r.Header.Get(SESSION_HEADER))
+	if !ok || err != nil || identifier.GetType() != common.SESSION {
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+		return nil
+	}
+	user, err := sessions.FindBySession(*identifier, r.Header.Get(SESSION_TOKEN_HEADER))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, ErrUnauthenticated)
+		return nil
+	}
+	return user
+}
+
+// permission is an action required on a resource by a request, along with the tags of the resource for the policy conditions.
+type permission struct {
+	resource *common.Identifier
+	action   policy.PolicyAction
+	tags     []*common.Tag
+}
+
+// authorize checks that the policies of the authenticated user of the request allow every permission,
+// writing the error response otherwise. Requests are not authorized when users is nil.
+func authorize(w http.ResponseWriter, r *http.Request, users identity.UserRepository, permissions ...permission) bool {
+	if users == nil {
+		return true
+	}
+	user := currentUser(w, r, users)
+	return user != nil && allowed(w, user, permissions...)
+}
+
+// authorizeAttachment checks that the authenticated user of the request is allowed to attach the policy and every other permission,
+// and that the policy does not allow actions outside of the permission boundary of the user, writing the error response otherwise.
+// Requests are not authorized when users is nil.
+func authorizeAttachment(w http.ResponseWriter, r *http.Request, users identity.UserRepository, attached *policy.Policy, permissions ...permission) bool {
+	permissions = append([]permission{{attached.GetIdentifier(), policy.ATTACH_POLICY, attached.ListTags()}}, permissions...)
+	return authorizeGrant(w, r, users, []*policy.Policy{attached}, permissions...)
+}
+
+// authorizeGrant checks that the authenticated user of the request is allowed every permission, and that none of the granted policies
+// allows actions outside of the permission boundary of the user, writing the error response otherwise.
+// Requests are not authorized when users is nil.
+func authorizeGrant(w http.ResponseWriter, r *http.Request, users identity.UserRepository, granted []*policy.Policy, permissions ...permission) bool {
+	if users == nil {
+		return true
+	}
+	user := currentUser(w, r, users)
+	return user != nil && allowed(w, user, permissions...) && canGrant(w, user, granted...)
+}
+
+// canGrant checks that none of the policies allows actions outside of the permission boundary of the user, writing the error response otherwise.
+func canGrant(w http.ResponseWriter, user *identity.User, granted ...*policy.Policy) bool {
+	for _, p := range granted {
+		if !user.CanGrant(p) {
+			writeError(w, http.StatusForbidden, identity.ErrPolicyExceedsBoundary)
+			return false
+		}
+	}
+	return true
+}
+
+// allowed checks that the policies of the user allow every permission, writing the error response otherwise.
+func allowed(w http.ResponseWriter, user *identity.User, permissions ...permission) bool {
+	for _, required := range permissions {
+		context := user.NewRequestContext(time.Now())
+		context.SetResourceTags(required.tags)
+		if !user.IsAllowed(required.resource, required.action, context) {
+			writeError(w, http.StatusForbidden, ErrForbidden)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrProjectNotFound = errors.New("cannot find a project with the provided id")
+
+// EnvironmentHandler manages the environments of projects and the runs targeting them.
+// Reading environments requires the project:Read action on their project, and changing them the project:Update action.
+type EnvironmentHandler struct {
+	projects  project.ProjectRepository
+	workflows workflow.WorkflowRepository
+	users     identity.UserRepository
+}
+
+// NewEnvironmentHandler creates an EnvironmentHandler.
+func NewEnvironmentHandler(projects project.ProjectRepository, workflows workflow.WorkflowRepository, users identity.UserRepository) *EnvironmentHandler {
+	return &EnvironmentHandler{
+		projects:  projects,
+		workflows: workflows,
+		users:     users,
+	}
+}
+
+// findProject loads the project of the request path, writing the error response if it cannot be found.
+func (h *EnvironmentHandler) findProject(w http.ResponseWriter, r *http.Request) *project.Project {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil {
+		return nil
+	}
+	found, err := h.projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrProjectNotFound)
+		return nil
+	}
+	return found
+}
+
+// findEnvironment loads the project and the environment of the request path, writing the error response if they cannot be found.
+func (h *EnvironmentHandler) findEnvironment(w http.ResponseWriter, r *http.Request) (*project.Project, *project.Environment) {
+	p := h.findProject(w, r)
+	if p == nil {
+		return nil, nil
+	}
+	environmentId, err := common.NewIdentifier(mux.Vars(r)["environmentId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, project.ErrEnvironmentNotFound)
+		return nil, nil
+	}
+	environment := p.GetEnvironment(environmentId)
+	if environment == nil {
+		writeError(w, http.StatusNotFound, project.ErrEnvironmentNotFound)
+		return nil, nil
+	}
+	return p, environment
+}
+
+// newVariableSet builds the variable set of an environment from its DTOs.
+func newVariableSet(name string, variables []dto.VariableDTO) (*project.VariableSet, error) {
+	parsed := make([]*project.Variable, 0, len(variables))
+	for _, variable := range variables {
+		attributeType, err := common.ParseAttributeType(variable.Type)
+		if err != nil {
+			return nil, err
+		}
+		v, err := project.NewVariable(variable.Name, attributeType, variable.Value)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, v)
+	}
+	return project.NewVariableSet(name, parsed)
+}
+
+// CreateEnvironment handles POST /projects/{projectId}/environments.
+func (h *EnvironmentHandler) CreateEnvironment(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}) {
+		return
+	}
+	var body dto.CreateEnvironmentDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	environment, err := project.NewEnvironment(p.GetIdentifier().ToString(), body.Name, body.Description, body.Protected)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	variableSet, err := newVariableSet(environment.GetName(), body.Variables)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	environment.SetVariableSet(variableSet)
+	if err := p.AddEnvironment(environment); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewEnvironmentDTO(environment))
+}
+
+// ListEnvironments handles GET /projects/{projectId}/environments.
+func (h *EnvironmentHandler) ListEnvironments(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	environments := []dto.EnvironmentDTO{}
+	for _, environment := range p.ListEnvironments() {
+		environments = append(environments, dto.NewEnvironmentDTO(environment))
+	}
+	writeJSON(w, http.StatusOK, environments)
+}
+
+// GetEnvironment handles GET /projects/{projectId}/environments/{environmentId}.
+func (h *EnvironmentHandler) GetEnvironment(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewEnvironmentDTO(environment))
+}
+
+// DeleteEnvironment handles DELETE /projects/{projectId}/environments/{environmentId}.
+func (h *EnvironmentHandler) DeleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}) {
+		return
+	}
+	if err := p.RemoveEnvironment(environment.GetIdentifier()); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetVariables handles PUT /projects/{projectId}/environments/{environmentId}/variables, replacing the variable set of the environment.
+func (h *EnvironmentHandler) SetVariables(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}) {
+		return
+	}
+	var body []dto.VariableDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	variableSet, err := newVariableSet(environment.GetVariableSet().GetName(), body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	environment.SetVariableSet(variableSet)
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewEnvironmentDTO(environment))
+}
+
+// AttachPolicy handles PUT /projects/{projectId}/environments/{environmentId}/policies/{policyId}, attaching a policy of the project
+// to the environment, which can deny running workflows against it. It also requires the policy:Attach action on the policy.
+func (h *EnvironmentHandler) AttachPolicy(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if environment == nil {
+		return
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil || p.GetPolicy(policyId) == nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return
+	}
+	attached := p.GetPolicy(policyId)
+	if !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}, permission{attached.GetIdentifier(), policy.ATTACH_POLICY, attached.ListTags()}) {
+		return
+	}
+	if err := environment.AttachPolicy(attached); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewEnvironmentDTO(environment))
+}
+
+// DetachPolicy handles DELETE /projects/{projectId}/environments/{environmentId}/policies/{policyId}.
+// It also requires the policy:Detach action on the policy.
+func (h *EnvironmentHandler) DetachPolicy(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if environment == nil {
+		return
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return
+	}
+	var attached *policy.Policy
+	for _, candidate := range environment.ListPolicies() {
+		if candidate.GetIdentifier().ToString() == policyId.ToString() {
+			attached = candidate
+		}
+	}
+	if attached == nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}, permission{attached.GetIdentifier(), policy.DETACH_POLICY, attached.ListTags()}) {
+		return
+	}
+	environment.DetachPolicy(policyId)
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewEnvironmentDTO(environment))
+}
+
+// StartRun handles POST /projects/{projectId}/environments/{environmentId}/runs, starting a run of a workflow of the project
+// on behalf of the authenticated user, with the environment variables merged into its inputs.
+// The user must be allowed to read the workflows addressed by the output references of the inputs.
+func (h *EnvironmentHandler) StartRun(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	_, environment := h.findEnvironment(w, r)
+	if environment == nil {
+		return
+	}
+	var body dto.StartEnvironmentRunDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	wf := findWorkflow(w, h.workflows, body.WorkflowIdentifier)
+	if wf == nil {
+		return
+	}
+	mode, values, ok := parseRunRequest(w, body.StartWorkflowRunDTO, wf, h.workflows, user)
+	if !ok {
+		return
+	}
+	run, err := environment.StartRun(user, wf, body.Name, body.Description, mode, values)
+	switch err {
+	case nil:
+	case project.ErrWorkflowNotFound:
+		writeError(w, http.StatusNotFound, ErrWorkflowNotFound)
+		return
+	case project.ErrRunNotAllowed:
+		writeError(w, http.StatusForbidden, err)
+		return
+	default:
+		writeRunError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewWorkflowRunDTO(run))
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestEnvironmentHandler(t *testing.T) {
+	p, _ := project.NewProject("project", "")
+	wf, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "/path/to/file.yml")
+	region, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "region", "", workflow.STRING, "")
+	wf.AddInput(region)
+	freezeStatement, _ := policy.NewPolicyStatement(policy.DENY, []*common.Identifier{wf.GetIdentifier()}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	freeze, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "freeze", "", []*policy.PolicyStatement{freezeStatement})
+	p.AddPolicy(freeze)
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	statement, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{wf.GetIdentifier()}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	manage, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{p.GetIdentifier(), freeze.GetIdentifier()}, []policy.PolicyAction{policy.READ_PROJECT, policy.UPDATE_PROJECT, policy.ATTACH_POLICY, policy.DETACH_POLICY})
+	runner, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "runner", "", []*policy.PolicyStatement{statement, manage})
+	developer.AttachPolicy(runner)
+	viewer, _ := identity.NewUser("viewer@example.com", "viewer")
+	view, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{p.GetIdentifier()}, []policy.PolicyAction{policy.READ_PROJECT})
+	viewers, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "viewers", "", []*policy.PolicyStatement{view})
+	viewer.AttachPolicy(viewers)
+
+	projects := newFakeProjectRepository(p)
+	workflows := newFakeWorkflowRepository(wf)
+	h := NewEnvironmentHandler(projects, workflows, newFakeUserRepository(developer, viewer))
+	router := mux.NewRouter()
+	router.HandleFunc("/projects/{projectId}/environments", h.CreateEnvironment).Methods("POST")
+	router.HandleFunc("/projects/{projectId}/environments", h.ListEnvironments).Methods("GET")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/variables", h.SetVariables).Methods("PUT")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/policies/{policyId}", h.AttachPolicy).Methods("PUT")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/policies/{policyId}", h.DetachPolicy).Methods("DELETE")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/runs", h.StartRun).Methods("POST")
+	path := "/projects/" + p.GetIdentifier().ToString() + "/environments"
+
+	serveAs := func(user *identity.User, method string, target string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+	serve := func(method string, target string, body string) *httptest.ResponseRecorder {
+		return serveAs(developer, method, target, body)
+	}
+
+	if response := serveAs(nil, http.MethodGet, path, ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d for an anonymous request, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := serveAs(viewer, http.MethodPost, path, `{"name": "staging"}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the project:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+
+	response := serve(http.MethodPost, path, `{"name": "staging", "variables": [{"name": "region", "type": "string", "value": "eu-west-1"}]}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected create response %d: %s", response.Code, response.Body.String())
+	}
+	var staging dto.EnvironmentDTO
+	json.NewDecoder(response.Body).Decode(&staging)
+	if len(staging.Variables) != 1 || staging.Variables[0].Value != "eu-west-1" {
+		t.Errorf("unexpected environment %+v", staging)
+	}
+	if response := serve(http.MethodPost, path, `{"name": "staging"}`); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+	if response := serve(http.MethodPost, path, `{"name": "dev", "variables": [{"name": "replicas", "type": "number", "value": "three"}]}`); response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, response.Code)
+	}
+	response = serve(http.MethodPost, path, `{"name": "prod", "protected": true}`)
+	var prod dto.EnvironmentDTO
+	json.NewDecoder(response.Body).Decode(&prod)
+
+	response = serve(http.MethodPost, path+"/"+staging.Identifier+"/runs", `{"workflow_id": "`+wf.GetIdentifier().ToString()+`", "name": "deploy"}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected run response %d: %s", response.Code, response.Body.String())
+	}
+	var run dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&run)
+	if run.Environment == nil || *run.Environment != staging.Identifier || run.Inputs["region"] != "eu-west-1" {
+		t.Errorf("unexpected run %+v", run)
+	}
+
+	response = serve(http.MethodPost, path+"/"+prod.Identifier+"/runs", `{"workflow_id": "`+wf.GetIdentifier().ToString()+`", "name": "deploy", "inputs": {"region": "eu-west-1"}}`)
+	if response.Code != http.StatusForbidden {
+		t.Errorf("expected runs against a protected environment to be forbidden, got %d", response.Code)
+	}
+
+	response = serve(http.MethodPut, path+"/"+prod.Identifier+"/variables", `[{"name": "region", "type": "string", "value": "us-east-1"}]`)
+	if response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if variable := p.GetEnvironment(mustIdentifier(t, prod.Identifier)).GetVariableSet().GetVariable("region"); variable == nil || variable.GetValue() != "us-east-1" {
+		t.Error("expected the variable set to be replaced")
+	}
+	if response := serveAs(viewer, http.MethodGet, path, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d with the project:Read action, got %d", http.StatusOK, response.Code)
+	}
+
+	policyPath := path + "/" + staging.Identifier + "/policies/" + freeze.GetIdentifier().ToString()
+	if response := serveAs(viewer, http.MethodPut, policyPath, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the project:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+	response = serve(http.MethodPut, policyPath, "")
+	json.NewDecoder(response.Body).Decode(&staging)
+	if response.Code != http.StatusOK || len(staging.Policies) != 1 {
+		t.Fatalf("unexpected attach response %d: %+v", response.Code, staging)
+	}
+	if response := serve(http.MethodPut, policyPath, ""); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+	response = serve(http.MethodPost, path+"/"+staging.Identifier+"/runs", `{"workflow_id": "`+wf.GetIdentifier().ToString()+`", "name": "deploy"}`)
+	if response.Code != http.StatusForbidden {
+		t.Errorf("expected the environment policy to deny the run, got %d", response.Code)
+	}
+	if response := serve(http.MethodDelete, policyPath, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := serve(http.MethodDelete, policyPath, ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
+
+func mustIdentifier(t *testing.T, id string) *common.Identifier {
+	t.Helper()
+	identifier, err := common.NewIdentifier(id)
+	if err != nil {
+		t.Fatalf("invalid identifier %s: %v", id, err)
+	}
+	return identifier
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/gitops"
+)
+
+// GitOpsHandler exposes the syncs of the projects from the GitOps repository.
+// When the user repository is provided, each request requires the authenticated user to be allowed to manage GitOps.
+type GitOpsHandler struct {
+	reconciler *gitops.Reconciler
+	users      identity.UserRepository
+}
+
+// NewGitOpsHandler creates a GitOpsHandler. Requests are not authorized if users is nil.
+func NewGitOpsHandler(reconciler *gitops.Reconciler, users identity.UserRepository) *GitOpsHandler {
+	return &GitOpsHandler{
+		reconciler: reconciler,
+		users:      users,
+	}
+}
+
+// authorizeGitOps checks that the authenticated user is allowed to manage GitOps, writing the error response otherwise.
+// Requests are not authorized when the user repository is not provided.
+func (h *GitOpsHandler) authorizeGitOps(w http.ResponseWriter, r *http.Request) bool {
+	if h.users == nil {
+		return true
+	}
+	user := currentUser(w, r, h.users)
+	return user != nil && allowed(w, user, permission{user.GetIdentifier(), policy.MANAGE_GITOPS, nil})
+}
+
+// ListSyncs handles GET /gitops/syncs, listing the sync records from the most recent one.
+func (h *GitOpsHandler) ListSyncs(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeGitOps(w, r) {
+		return
+	}
+	offset, limit := parsePagination(r)
+	syncs := h.reconciler.ListSyncs()
+	result := []dto.SyncRecordDTO{}
+	for i := offset; i < len(syncs) && i < offset+limit; i++ {
+		result = append(result, dto.NewSyncRecordDTO(syncs[i]))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Sync handles POST /gitops/sync, syncing the projects from the repository without waiting for the next interval.
+// Responds with 502 if the repository cannot be fetched, and with 409 if a sync is already in progress.
+func (h *GitOpsHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeGitOps(w, r) {
+		return
+	}
+	record, err := h.reconciler.Sync(r.Context())
+	if errors.Is(err, gitops.ErrSyncInProgress) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, dto.NewSyncRecordDTO(record))
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewSyncRecordDTO(record))
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/gitops"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/gorilla/mux"
+)
+
+func TestGitOpsHandler(t *testing.T) {
+	fetcher, err := gitsource.NewFetcher(t.TempDir())
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+	source, _ := common.ParseGitSource("git::" + filepath.Join(t.TempDir(), "missing.git") + "?ref=main")
+	p, _ := project.NewProject("platform", "")
+	operator, _ := identity.NewUser("operator@example.com", "operator")
+	manage, _ := policy.ParsePolicyStatement(0, "Allow", []string{"user:ManageGitOps"}, []string{operator.GetIdentifier().ToString()})
+	operators, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "operators", "", []*policy.PolicyStatement{manage})
+	operator.AttachPolicy(operators)
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	h := NewGitOpsHandler(gitops.NewReconciler(fetcher, source, newFakeProjectRepository(), nil, nil), newFakeUserRepository(operator, stranger))
+	r := mux.NewRouter()
+	r.HandleFunc("/gitops/syncs", h.ListSyncs).Methods("GET")
+	r.HandleFunc("/gitops/sync", h.Sync).Methods("POST")
+	send := func(method string, path string, user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, nil)
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(http.MethodPost, "/gitops/sync", nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPost, "/gitops/sync", stranger); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := send(http.MethodGet, "/gitops/syncs", stranger); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, response.Code)
+	}
+
+	response := send(http.MethodPost, "/gitops/sync", operator)
+	var record dto.SyncRecordDTO
+	json.Unmarshal(response.Body.Bytes(), &record)
+	if response.Code != http.StatusBadGateway || record.Status != "failure" || record.Error == nil || record.Commit != nil {
+		t.Fatalf("expected a failed sync, got %d: %s", response.Code, response.Body.String())
+	}
+
+	response = send(http.MethodGet, "/gitops/syncs", operator)
+	var syncs []dto.SyncRecordDTO
+	json.Unmarshal(response.Body.Bytes(), &syncs)
+	if response.Code != http.StatusOK || len(syncs) != 1 {
+		t.Errorf("expected the failed sync to be recorded, got %d: %s", response.Code, response.Body.String())
+	}
+}
@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrGroupNotFound = errors.New("cannot find a group with the provided id")
+
+// GroupHandler manages the groups of users, their members and the policies attached to them,
+// and lists the effective policies of users. Every change requires the authenticated user to be allowed to update the group.
+type GroupHandler struct {
+	groups   identity.GroupRepository
+	users    identity.UserRepository
+	projects project.ProjectRepository
+}
+
+// NewGroupHandler creates a GroupHandler. The policies of the projects can only be attached to groups if projects is provided.
+// The user repository is expected to load the groups of the users, see identity.WithGroupMemberships.
+func NewGroupHandler(groups identity.GroupRepository, users identity.UserRepository, projects project.ProjectRepository) *GroupHandler {
+	return &GroupHandler{
+		groups:   groups,
+		users:    users,
+		projects: projects,
+	}
+}
+
+// findGroup loads the group of the request path, writing the error response if it cannot be found.
+func (h *GroupHandler) findGroup(w http.ResponseWriter, r *http.Request) *identity.Group {
+	groupId, err := common.NewIdentifier(mux.Vars(r)["groupId"])
+	if err != nil || groupId.GetType() != common.GROUP {
+		writeError(w, http.StatusNotFound, ErrGroupNotFound)
+		return nil
+	}
+	found, err := h.groups.FindById(*groupId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrGroupNotFound)
+		return nil
+	}
+	return found
+}
+
+// findUser loads the user of the request path, writing the error response if it cannot be found.
+func findUser(w http.ResponseWriter, r *http.Request, users identity.UserRepository) *identity.User {
+	userId, err := common.NewIdentifier(mux.Vars(r)["userId"])
+	if err != nil || userId.GetType() != common.USER {
+		writeError(w, http.StatusNotFound, ErrUserNotFound)
+		return nil
+	}
+	found, err := users.FindById(*userId, 0, 1)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrUserNotFound)
+		return nil
+	}
+	return found
+}
+
+// findPolicy loads the policy of the request path from its project, writing the error response if it cannot be found.
+func findPolicy(w http.ResponseWriter, r *http.Request, projects project.ProjectRepository) *policy.Policy {
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil || policyId.GetType() != common.POLICY {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return nil
+	}
+	projectId, err := policyId.GetParent()
+	if err != nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return nil
+	}
+	p, err := projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if p == nil || p.GetPolicy(policyId) == nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return nil
+	}
+	return p.GetPolicy(policyId)
+}
+
+// updateGroup stores the group and writes it as the response.
+func (h *GroupHandler) updateGroup(w http.ResponseWriter, group *identity.Group) {
+	group.UpdateModificationDate()
+	if err := h.groups.Update(group); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewGroupDTO(group))
+}
+
+// CreateGroup handles POST /groups, which requires the user:CreateGroup action on the authenticated user.
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil || !allowed(w, user, permission{user.GetIdentifier(), policy.CREATE_GROUP, nil}) {
+		return
+	}
+	var body dto.CreateGroupDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	group, err := identity.NewGroup(body.Name, body.Description)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	dto.AddTags(&group.TaggedEntity, body.Tags)
+	if err := h.groups.Create(group); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewGroupDTO(group))
+}
+
+// ListGroups handles GET /groups.
+func (h *GroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	found, err := h.groups.FindAll(offset, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	groups := []dto.GroupDTO{}
+	for _, group := range found {
+		groups = append(groups, dto.NewGroupDTO(group))
+	}
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// GetGroup handles GET /groups/{groupId}.
+func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewGroupDTO(group))
+}
+
+// DeleteGroup handles DELETE /groups/{groupId}, which requires the group:Delete action on the group.
+// Its members lose the policies of the group.
+func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil || !authorize(w, r, h.users, permission{group.GetIdentifier(), policy.DELETE_GROUP, nil}) {
+		return
+	}
+	if err := h.groups.Delete(*group.GetIdentifier()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddMember handles PUT /groups/{groupId}/members/{userId}, which requires the group:Update action on the group
+// and the user:Update action on the user, who is granted the policies of the group.
+// The policies of the group must be within the permission boundary of the authenticated user.
+func (h *GroupHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil {
+		return
+	}
+	user := findUser(w, r, h.users)
+	if user == nil || !authorizeGrant(w, r, h.users, group.ListAttachedPolicies(), permission{group.GetIdentifier(), policy.UPDATE_GROUP, nil}, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}) {
+		return
+	}
+	if err := group.AddMember(user.GetIdentifier()); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	h.updateGroup(w, group)
+}
+
+// RemoveMember handles DELETE /groups/{groupId}/members/{userId}, which requires the group:Update action on the group
+// and the user:Update action on the user.
+func (h *GroupHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil {
+		return
+	}
+	userId, err := common.NewIdentifier(mux.Vars(r)["userId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, identity.ErrMemberNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{group.GetIdentifier(), policy.UPDATE_GROUP, nil}, permission{userId, policy.UPDATE_USER, nil}) {
+		return
+	}
+	if err := group.RemoveMember(userId); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	h.updateGroup(w, group)
+}
+
+// AttachPolicy handles PUT /groups/{groupId}/policies/{policyId}, which requires the group:Update action on the group,
+// the policy:Attach action on the policy and the policy to be within the permission boundary of the authenticated user.
+func (h *GroupHandler) AttachPolicy(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil {
+		return
+	}
+	attached := findPolicy(w, r, h.projects)
+	if attached == nil || !authorizeAttachment(w, r, h.users, attached, permission{group.GetIdentifier(), policy.UPDATE_GROUP, nil}) {
+		return
+	}
+	if group.GetAttachedPolicy(attached.GetIdentifier()) != nil {
+		writeError(w, http.StatusConflict, identity.ErrPolicyAlreadyAttached)
+		return
+	}
+	group.AttachPolicy(attached)
+	h.updateGroup(w, group)
+}
+
+// DetachPolicy handles DELETE /groups/{groupId}/policies/{policyId}, which requires the group:Update action on the group
+// and the policy:Detach action on the policy.
+func (h *GroupHandler) DetachPolicy(w http.ResponseWriter, r *http.Request) {
+	group := h.findGroup(w, r)
+	if group == nil {
+		return
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	attached := group.GetAttachedPolicy(policyId)
+	if attached == nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{group.GetIdentifier(), policy.UPDATE_GROUP, nil}, permission{attached.GetIdentifier(), policy.DETACH_POLICY, attached.ListTags()}) {
+		return
+	}
+	group.DetachPolicy(policyId)
+	h.updateGroup(w, group)
+}
+
+// ListEffectivePolicies handles GET /users/{userId}/policies, listing the policies attached to the user and to its groups.
+// It requires the user:Read action on the user.
+func (h *GroupHandler) ListEffectivePolicies(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil || !authorize(w, r, h.users, permission{user.GetIdentifier(), policy.READ_USER, nil}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewEffectivePolicyDTOs(user))
+}
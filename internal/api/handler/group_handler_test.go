@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestGroupHandler(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{p.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "readers", "", []*policy.PolicyStatement{readProject})
+	p.AddPolicy(readers)
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	attach, _ := policy.ParsePolicyStatement(0, "Allow", []string{"policy:Attach", "policy:Detach"}, []string{readers.GetIdentifier().ToString()})
+	createGroups, _ := policy.ParsePolicyStatement(1, "Allow", []string{"user:CreateGroup"}, []string{admin.GetIdentifier().ToString()})
+	admins, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "admins", "", []*policy.PolicyStatement{attach, createGroups})
+	admin.AttachPolicy(admins)
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	groups := newFakeGroupRepository()
+	users := identity.WithGroupMemberships(newFakeUserRepository(admin, developer), groups)
+	h := NewGroupHandler(groups, users, newFakeProjectRepository(p))
+	r := mux.NewRouter()
+	r.HandleFunc("/groups", h.CreateGroup).Methods("POST")
+	r.HandleFunc("/groups", h.ListGroups).Methods("GET")
+	r.HandleFunc("/groups/{groupId}", h.GetGroup).Methods("GET")
+	r.HandleFunc("/groups/{groupId}", h.DeleteGroup).Methods("DELETE")
+	r.HandleFunc("/groups/{groupId}/members/{userId}", h.AddMember).Methods("PUT")
+	r.HandleFunc("/groups/{groupId}/members/{userId}", h.RemoveMember).Methods("DELETE")
+	r.HandleFunc("/groups/{groupId}/policies/{policyId}", h.AttachPolicy).Methods("PUT")
+	r.HandleFunc("/groups/{groupId}/policies/{policyId}", h.DetachPolicy).Methods("DELETE")
+	r.HandleFunc("/users/{userId}/policies", h.ListEffectivePolicies).Methods("GET")
+	send := func(method string, path string, user *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	createGroup := `{"name": "developers", "description": "Platform developers"}`
+	if response := send(http.MethodPost, "/groups", nil, createGroup); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPost, "/groups", developer, createGroup); response.Code != http.StatusForbidden {
+		t.Errorf("expected creating a group to require user:CreateGroup, got %d", response.Code)
+	}
+	response := send(http.MethodPost, "/groups", admin, createGroup)
+	var created dto.GroupDTO
+	json.Unmarshal(response.Body.Bytes(), &created)
+	if response.Code != http.StatusCreated || created.Name != "developers" || len(groups.groups) != 1 {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	base := "/groups/" + created.Identifier
+	manageGroup, _ := policy.ParsePolicyStatement(1, "Allow", []string{"group:Update", "group:Delete"}, []string{created.Identifier})
+	manageUsers, _ := policy.ParsePolicyStatement(2, "Allow", []string{"user:Read", "user:Update"}, []string{developer.GetIdentifier().ToString()})
+	groupAdmins, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "group-admins", "", []*policy.PolicyStatement{manageGroup, manageUsers})
+	admin.AttachPolicy(groupAdmins)
+
+	members := base + "/members/" + developer.GetIdentifier().ToString()
+	if response := send(http.MethodPut, members, nil, ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPut, members, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected adding a member to require group:Update, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, members, admin, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if response := send(http.MethodPut, members, admin, ""); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+	if response := send(http.MethodPut, base+"/members/"+admin.GetIdentifier().ToString(), admin, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected adding a member to require user:Update on the member, got %d", response.Code)
+	}
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	if response := send(http.MethodPut, base+"/members/"+stranger.GetIdentifier().ToString(), admin, ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+
+	policies := base + "/policies/" + readers.GetIdentifier().ToString()
+	if response := send(http.MethodPut, policies, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected attaching a policy to require policy:Attach, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, policies, admin, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if response := send(http.MethodPut, policies, admin, ""); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+
+	found, _ := users.FindById(*developer.GetIdentifier(), 0, 1)
+	if !found.IsAllowed(p.GetIdentifier(), policy.READ_PROJECT, nil) {
+		t.Errorf("expected the developer to be allowed by the policy of its group")
+	}
+	if response := send(http.MethodGet, "/users/"+developer.GetIdentifier().ToString()+"/policies", developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected listing the effective policies to require user:Read, got %d", response.Code)
+	}
+	response = send(http.MethodGet, "/users/"+developer.GetIdentifier().ToString()+"/policies", admin, "")
+	var effective []dto.EffectivePolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &effective)
+	if response.Code != http.StatusOK || len(effective) != 1 || effective[0].Attached || len(effective[0].Groups) != 1 || effective[0].Groups[0] != created.Identifier {
+		t.Errorf("unexpected effective policies %d: %s", response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodDelete, policies, admin, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := send(http.MethodDelete, members, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected removing a member to require group:Update, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, members, admin, ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := send(http.MethodDelete, members, admin, ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+	response = send(http.MethodGet, "/groups", nil, "")
+	var listed []dto.GroupDTO
+	json.Unmarshal(response.Body.Bytes(), &listed)
+	if len(listed) != 1 || len(listed[0].Members) != 0 || len(listed[0].Policies) != 0 {
+		t.Errorf("unexpected groups %s", response.Body.String())
+	}
+	if response := send(http.MethodDelete, base, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected deleting a group to require group:Delete, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, base, admin, ""); response.Code != http.StatusNoContent || len(groups.groups) != 0 {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+	if response := send(http.MethodGet, base, nil, ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
+
+func TestGroupHandlerAddMemberBoundary(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	readers, _ := policy.NewPolicy(projectId, "readers", "", []*policy.PolicyStatement{readProject})
+	updateProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Update"}, []string{projectId})
+	writers, _ := policy.NewPolicy(projectId, "writers", "", []*policy.PolicyStatement{updateProject})
+	viewers, _ := identity.NewGroup("viewers", "")
+	viewers.AttachPolicy(readers)
+	maintainers, _ := identity.NewGroup("maintainers", "")
+	maintainers.AttachPolicy(writers)
+
+	// The delegate manages the groups, but its boundary only allows reading the project.
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	manage, _ := policy.ParsePolicyStatement(0, "Allow", []string{"group:Update", "user:Update"}, []string{viewers.GetIdentifier().ToString(), maintainers.GetIdentifier().ToString(), developer.GetIdentifier().ToString()})
+	delegation, _ := policy.NewPolicy(projectId, "delegation", "", []*policy.PolicyStatement{manage})
+	boundary, _ := policy.NewPolicy(projectId, "boundary", "", []*policy.PolicyStatement{manage, readProject})
+	delegate.AttachPolicy(delegation)
+	delegate.SetPermissionBoundary(boundary)
+
+	groups := newFakeGroupRepository(viewers, maintainers)
+	users := identity.WithGroupMemberships(newFakeUserRepository(developer, delegate), groups)
+	h := NewGroupHandler(groups, users, newFakeProjectRepository(p))
+	r := mux.NewRouter()
+	r.HandleFunc("/groups/{groupId}/members/{userId}", h.AddMember).Methods("PUT")
+	send := func(group *identity.Group) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPut, "/groups/"+group.GetIdentifier().ToString()+"/members/"+developer.GetIdentifier().ToString(), nil)
+		request.Header.Set(USER_HEADER, delegate.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(maintainers); response.Code != http.StatusForbidden || maintainers.HasMember(developer.GetIdentifier()) {
+		t.Errorf("expected adding a member to a group with policies outside of the boundary to be forbidden, got %d", response.Code)
+	}
+	if response := send(viewers); response.Code != http.StatusOK || !viewers.HasMember(developer.GetIdentifier()) {
+		t.Errorf("expected adding a member to a group with policies within the boundary to be allowed, got %d: %s", response.Code, response.Body.String())
+	}
+}
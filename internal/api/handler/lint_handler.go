@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/policylint"
+)
+
+// LintHandler statically analyzes policy documents, without storing them.
+// When the user repository is provided, linting requires an authenticated user, who is only shown the resources it can read.
+type LintHandler struct {
+	linter *policylint.Linter
+	users  identity.UserRepository
+}
+
+// NewLintHandler creates a LintHandler. The existence of the resources stored by the repositories that are nil is not checked,
+// and requests are not authenticated if users is nil.
+func NewLintHandler(projects project.ProjectRepository, users identity.UserRepository, groups identity.GroupRepository) *LintHandler {
+	return &LintHandler{
+		linter: policylint.NewLinter(projects, users, groups),
+		users:  users,
+	}
+}
+
+// Lint handles POST /policies/lint, reporting the statements of the policy document of the body that are invalid,
+// never apply or grant too much. Documents that cannot be parsed are rejected like on creation.
+// The resources the authenticated user is not allowed to read are reported as unknown, like the ones that do not exist.
+func (h *LintHandler) Lint(w http.ResponseWriter, r *http.Request) {
+	var reader *identity.User
+	if h.users != nil {
+		if reader = currentUser(w, r, h.users); reader == nil {
+			return
+		}
+	}
+	projectId, err := common.BuildProjectIdentifier()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	identifier, err := common.BuildPolicyIdentifier(projectId.ToString())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	parsed := readPolicy(w, r, identifier.ToString(), common.CurrentTimestamp())
+	if parsed == nil {
+		return
+	}
+	findings, err := h.linter.LintAs(parsed, reader)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewLintReportDTO(findings))
+}
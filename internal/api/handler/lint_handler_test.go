@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+)
+
+func TestLintHandler(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	missingId, _ := common.BuildProjectIdentifier()
+	h := NewLintHandler(newFakeProjectRepository(p), nil, nil)
+	lint := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/policies/lint", bytes.NewBufferString(body))
+		response := httptest.NewRecorder()
+		h.Lint(response, request)
+		return response
+	}
+	existing, missing := p.GetIdentifier().ToString(), missingId.ToString()
+
+	response := lint(`{"name": "readers", "statements": [{"effect": "Allow", "actions": ["project:Read"], "resources": ["` + existing + `"]}]}`)
+	var report dto.LintReportDTO
+	json.Unmarshal(response.Body.Bytes(), &report)
+	if response.Code != http.StatusOK || !report.Valid || len(report.Findings) != 0 {
+		t.Fatalf("expected %d with a clean report, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+
+	response = lint(`{"name": "mistakes", "statements": [
+		{"effect": "Allow", "actions": ["workflow:Read"], "resources": ["` + existing + `"]},
+		{"effect": "Allow", "actions": ["project:Read"], "resources": ["` + missing + `"]},
+		{"effect": "Deny", "actions": ["project:Read"], "resources": ["` + missing + `"]}
+	]}`)
+	report = dto.LintReportDTO{}
+	json.Unmarshal(response.Body.Bytes(), &report)
+	if response.Code != http.StatusOK || report.Valid {
+		t.Fatalf("expected %d with an invalid report, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	rules := map[string]bool{}
+	for _, finding := range report.Findings {
+		rules[finding.Rule] = true
+		if finding.Rule == "action-type-mismatch" && (finding.Severity != "error" || finding.Statement != 0) {
+			t.Errorf("unexpected finding %+v", finding)
+		}
+		if finding.Rule == "neutralized-allow" && (finding.Severity != "warning" || finding.Statement != 1 || finding.Field != "") {
+			t.Errorf("unexpected finding %+v", finding)
+		}
+	}
+	for _, rule := range []string{"action-type-mismatch", "unknown-resource", "neutralized-allow"} {
+		if !rules[rule] {
+			t.Errorf("expected a %s finding, got %+v", rule, report.Findings)
+		}
+	}
+
+	response = lint(`{"name": "invalid", "statements": [{"effect": "Allow", "actions": ["project:Fly"], "resources": ["` + existing + `"]}]}`)
+	if response.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected %d for an unparsable action, got %d", http.StatusUnprocessableEntity, response.Code)
+	}
+}
+
+func TestLintHandlerAuthentication(t *testing.T) {
+	visible, _ := project.NewProject("network", "")
+	hidden, _ := project.NewProject("billing", "")
+	missing, _ := common.BuildProjectIdentifier()
+	read, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{visible.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy(visible.GetIdentifier().ToString(), "readers", "", []*policy.PolicyStatement{read})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(readers)
+	h := NewLintHandler(newFakeProjectRepository(visible, hidden), newFakeUserRepository(reader), nil)
+	body := `{"name": "readers", "statements": [{"effect": "Allow", "actions": ["project:Read"], "resources": ["` +
+		visible.GetIdentifier().ToString() + `", "` + hidden.GetIdentifier().ToString() + `", "` + missing.ToString() + `"]}]}`
+	lint := func(user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/policies/lint", bytes.NewBufferString(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		h.Lint(response, request)
+		return response
+	}
+
+	if response := lint(nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	response := lint(reader)
+	var report dto.LintReportDTO
+	json.Unmarshal(response.Body.Bytes(), &report)
+	if response.Code != http.StatusOK || len(report.Findings) != 2 {
+		t.Fatalf("expected %d with 2 findings, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	hiddenFinding, missingFinding := report.Findings[0], report.Findings[1]
+	if hiddenFinding.Rule != "unknown-resource" || hiddenFinding.Field != "resources[1]" || missingFinding.Field != "resources[2]" ||
+		strings.Replace(hiddenFinding.Message, hidden.GetIdentifier().ToString(), missing.ToString(), 1) != missingFinding.Message {
+		t.Errorf("expected the unreadable project to be reported like a missing one, got %+v", report.Findings)
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/AutOpsProject/AutOps-API/internal/manifest"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+)
+
+// ManifestHandler imports and exports projects as YAML manifests.
+// Templates and workflows are only persisted in their own repositories when they are provided.
+// When the source loader is provided, the content of the imported template and workflow versions is recorded.
+// When the user repository is provided, the policies of the authenticated user must allow each request.
+type ManifestHandler struct {
+	projects  project.ProjectRepository
+	templates template.TemplateRepository
+	workflows workflow.WorkflowRepository
+	sources   *versioning.SourceLoader
+	secrets   secret.SecretRepository
+	key       *secret.MasterKey
+	users     identity.UserRepository
+}
+
+// NewManifestHandler creates a ManifestHandler. The content of imported versions is not recorded if sources is nil,
+// and the content of private template sources is not recorded if secrets or key is nil. Requests are not authorized if users is nil.
+func NewManifestHandler(projects project.ProjectRepository, templates template.TemplateRepository, workflows workflow.WorkflowRepository, sources *versioning.SourceLoader, secrets secret.SecretRepository, key *secret.MasterKey, users identity.UserRepository) *ManifestHandler {
+	return &ManifestHandler{
+		projects:  projects,
+		templates: templates,
+		workflows: workflows,
+		sources:   sources,
+		secrets:   secrets,
+		key:       key,
+		users:     users,
+	}
+}
+
+// ExportProject handles GET /projects/{projectId}/export, returning the manifest of the project as a YAML document.
+func (h *ManifestHandler) ExportProject(w http.ResponseWriter, r *http.Request) {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil {
+		return
+	}
+	found, err := h.projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrProjectNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.READ_PROJECT, found.ListTags()}) {
+		return
+	}
+	exported, err := manifest.Export(found)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	document, err := exported.Marshal()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
+
+// ImportProject handles POST /projects/import, creating or updating the project declared by the YAML manifest of the body.
+// With the dry_run=true query parameter, the changes are only reported. Otherwise they are applied, and the response status
+// is 201 if a new project was created. Both require the permissions listed by the import plan,
+// and the updated policies to be within the permission boundary of the authenticated user.
+// The template and workflow versions created by the import are attributed to the authenticated user,
+// and described by the optional changelog query parameter.
+func (h *ManifestHandler) ImportProject(w http.ResponseWriter, r *http.Request) {
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	parsed, err := manifest.Parse(document)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var current *project.Project
+	if parsed.Metadata.ID != "" {
+		projectId, err := common.NewIdentifier(parsed.Metadata.ID)
+		if err != nil || projectId.GetType() != common.PROJECT || len(projectId.Segments()) != 4 {
+			writeError(w, http.StatusBadRequest, ErrInvalidProjectIdentifier)
+			return
+		}
+		current, err = h.projects.FindById(*projectId)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if current == nil {
+			writeError(w, http.StatusNotFound, ErrProjectNotFound)
+			return
+		}
+	}
+	plan, err := manifest.NewImportPlan(current, parsed)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	permissions := []permission{}
+	for _, required := range plan.ListRequiredPermissions() {
+		permissions = append(permissions, permission{required.GetResource(), required.GetAction(), resourceTags(current, required.GetResource())})
+	}
+	var user *identity.User
+	if h.users != nil {
+		if user = currentUser(w, r, h.users); user == nil || !allowed(w, user, permissions...) || !canGrant(w, user, plan.ListUpdatedPolicies()...) {
+			return
+		}
+	}
+	projectId := plan.GetProjectIdentifier().ToString()
+	if r.URL.Query().Get("dry_run") == "true" {
+		writeJSON(w, http.StatusOK, dto.NewImportPlanDTO(projectId, plan, false))
+		return
+	}
+	if !h.recordVersions(w, r, plan, user) {
+		return
+	}
+
+	if _, err := plan.Save(h.projects, h.templates, h.workflows); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	status := http.StatusOK
+	if plan.IsNewProject() {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, dto.NewImportPlanDTO(projectId, plan, true))
+}
+
+// recordVersions records the author, the changelog and the source content of the versions created by the plan,
+// writing the error response if they cannot be recorded.
+func (h *ManifestHandler) recordVersions(w http.ResponseWriter, r *http.Request, plan *manifest.ImportPlan, user *identity.User) bool {
+	if user != nil {
+		if err := plan.SetAuthor(user.GetIdentifier()); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return false
+		}
+	}
+	if err := plan.SetChangelog(r.URL.Query().Get("changelog")); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	if h.sources == nil {
+		return true
+	}
+	err := plan.RecordContent(r.Context(), h.sources, h.secrets, h.key)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, gitsource.ErrGitCommandFailed), errors.Is(err, gitsource.ErrRefNotFound), errors.Is(err, gitsource.ErrSubdirectoryNotFound):
+		writeError(w, http.StatusBadGateway, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+	return false
+}
+
+// resourceTags returns the tags of the project or of one of its resources, or nil if the project does not have the resource.
+func resourceTags(p *project.Project, identifier *common.Identifier) []*common.Tag {
+	if p == nil {
+		return nil
+	}
+	switch identifier.GetType() {
+	case common.PROJECT:
+		return p.ListTags()
+	case common.TEMPLATE:
+		if t := p.GetTemplate(identifier); t != nil {
+			return t.ListTags()
+		}
+	case common.WORKFLOW:
+		if wf := p.GetWorkflow(identifier); wf != nil {
+			return wf.ListTags()
+		}
+	case common.POLICY:
+		if pol := p.GetPolicy(identifier); pol != nil {
+			return pol.ListTags()
+		}
+	}
+	return nil
+}
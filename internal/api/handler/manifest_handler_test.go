@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/manifest"
+	"github.com/gorilla/mux"
+)
+
+func TestManifestHandler(t *testing.T) {
+	projects := newFakeProjectRepository()
+	templates := newFakeTemplateRepository()
+	h := NewManifestHandler(projects, templates, newFakeWorkflowRepository(), nil, nil, nil, nil)
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/import", h.ImportProject).Methods("POST")
+	r.HandleFunc("/projects/{projectId}/export", h.ExportProject).Methods("GET")
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	document := `apiVersion: autops/v1
+kind: Project
+metadata:
+  name: network
+templates:
+  - name: vpc
+    type: terraform
+    source: path/to/vpc.zip
+`
+
+	response := send(http.MethodPost, "/projects/import?dry_run=true", document)
+	var plan dto.ImportPlanDTO
+	json.Unmarshal(response.Body.Bytes(), &plan)
+	if response.Code != http.StatusOK || plan.Applied || len(plan.Changes) != 2 {
+		t.Fatalf("expected a dry run reporting 2 changes, got %d: %s", response.Code, response.Body.String())
+	}
+	if len(projects.projects) != 0 || len(templates.versions) != 0 {
+		t.Error("expected a dry run to persist nothing")
+	}
+
+	response = send(http.MethodPost, "/projects/import", document)
+	json.Unmarshal(response.Body.Bytes(), &plan)
+	if response.Code != http.StatusCreated || !plan.Applied {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	if projects.projects[plan.ProjectId] == nil || len(templates.versions) != 1 {
+		t.Fatal("expected the project and its template to be persisted")
+	}
+
+	response = send(http.MethodGet, "/projects/"+plan.ProjectId+"/export", "")
+	if response.Code != http.StatusOK || response.Header().Get("Content-Type") != "application/yaml" {
+		t.Fatalf("expected a YAML export, got %d: %s", response.Code, response.Body.String())
+	}
+	exported := response.Body.String()
+	if !strings.Contains(exported, "id: "+plan.ProjectId) {
+		t.Errorf("expected the export to declare the project identifier, got %s", exported)
+	}
+	response = send(http.MethodPost, "/projects/import", exported)
+	json.Unmarshal(response.Body.Bytes(), &plan)
+	if response.Code != http.StatusOK || len(plan.Changes) != 0 || projects.updates != 0 {
+		t.Errorf("expected importing the export to change nothing, got %d: %s", response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodPost, "/projects/import", "kind: Project"); response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, response.Code)
+	}
+	if response := send(http.MethodPost, "/projects/import", strings.Replace(exported, plan.ProjectId, "autops::project:ZYXWVUTSRQ", 1)); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+	if response := send(http.MethodGet, "/projects/autops::project:ZYXWVUTSRQ/export", ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
+
+func TestManifestHandlerVersionAuthor(t *testing.T) {
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	templates := newFakeTemplateRepository()
+	h := NewManifestHandler(newFakeProjectRepository(), templates, newFakeWorkflowRepository(), nil, nil, nil, newFakeUserRepository(developer))
+	document := `apiVersion: autops/v1
+kind: Project
+metadata:
+  name: network
+templates:
+  - name: vpc
+    type: terraform
+    source: path/to/vpc.zip
+`
+	send := func(path string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(document))
+		request.Header.Set(USER_HEADER, developer.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		h.ImportProject(response, request)
+		return response
+	}
+
+	if response := send("/projects/import?changelog=" + strings.Repeat("a", common.MAX_CHANGELOG_LENGTH+1)); response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, response.Code)
+	}
+	if response := send("/projects/import?changelog=Initial+import"); response.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	if len(templates.versions) != 1 {
+		t.Fatalf("expected the template to be persisted")
+	}
+	vpc := templates.versions[0]
+	if vpc.GetAuthor() == nil || vpc.GetAuthor().ToString() != developer.GetIdentifier().ToString() || vpc.GetChangelog() != "Initial import" {
+		t.Errorf("expected the template version to be attributed to the caller, with the changelog")
+	}
+}
+
+func TestManifestHandlerPolicyBoundary(t *testing.T) {
+	document := `apiVersion: autops/v1
+kind: Project
+metadata:
+  name: network
+policies:
+  - name: readers
+    statements:
+      - effect: Allow
+        actions:
+          - project:Read
+        resources:
+          - project
+`
+	parsed, _ := manifest.Parse([]byte(document))
+	plan, _ := manifest.NewImportPlan(nil, parsed)
+	p := plan.Apply()
+	projectId := p.GetIdentifier().ToString()
+	readers := p.ListPolicies()[0]
+	// The delegate can update the policy, but its boundary only allows reading the project.
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	update, _ := policy.ParsePolicyStatement(0, "Allow", []string{"policy:Update"}, []string{readers.GetIdentifier().ToString()})
+	readProject, _ := policy.ParsePolicyStatement(1, "Allow", []string{"project:Read"}, []string{projectId})
+	delegation, _ := policy.NewPolicy(projectId, "delegation", "", []*policy.PolicyStatement{update})
+	boundary, _ := policy.NewPolicy(projectId, "boundary", "", []*policy.PolicyStatement{update, readProject})
+	delegate.AttachPolicy(delegation)
+	delegate.SetPermissionBoundary(boundary)
+	projects := newFakeProjectRepository(p)
+	h := NewManifestHandler(projects, nil, nil, nil, nil, nil, newFakeUserRepository(delegate))
+	send := func(document string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/projects/import", bytes.NewBufferString(document))
+		request.Header.Set(USER_HEADER, delegate.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		h.ImportProject(response, request)
+		return response
+	}
+	document = strings.Replace(document, "  name: network\n", "  id: "+projectId+"\n  name: network\n", 1)
+
+	if response := send(strings.Replace(document, "          - project:Read\n", "          - project:Read\n          - project:Delete\n", 1)); response.Code != http.StatusForbidden || projects.updates != 0 {
+		t.Errorf("expected a policy update outside of the boundary to be forbidden, got %d: %s", response.Code, response.Body.String())
+	}
+	if response := send(strings.Replace(document, "name: readers", "name: readers\n    description: Reads the project", 1)); response.Code != http.StatusOK || projects.updates != 1 {
+		t.Errorf("expected a policy update within the boundary to be allowed, got %d: %s", response.Code, response.Body.String())
+	}
+}
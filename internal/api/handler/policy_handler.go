@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+// PolicyHandler manages the policies of projects as JSON policy documents.
+// When the user repository is provided, the policies of the authenticated user must allow each request.
+type PolicyHandler struct {
+	projects project.ProjectRepository
+	users    identity.UserRepository
+}
+
+// NewPolicyHandler creates a PolicyHandler. Requests are not authorized if users is nil.
+func NewPolicyHandler(projects project.ProjectRepository, users identity.UserRepository) *PolicyHandler {
+	return &PolicyHandler{
+		projects: projects,
+		users:    users,
+	}
+}
+
+// findProject loads the project of the request path, writing the error response if it cannot be found.
+func (h *PolicyHandler) findProject(w http.ResponseWriter, r *http.Request) *project.Project {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil {
+		return nil
+	}
+	found, err := h.projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrProjectNotFound)
+		return nil
+	}
+	return found
+}
+
+// findPolicy loads the project and the policy of the request path, writing the error response if they cannot be found.
+func (h *PolicyHandler) findPolicy(w http.ResponseWriter, r *http.Request) (*project.Project, *policy.Policy) {
+	p := h.findProject(w, r)
+	if p == nil {
+		return nil, nil
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return nil, nil
+	}
+	found := p.GetPolicy(policyId)
+	if found == nil {
+		writeError(w, http.StatusNotFound, project.ErrPolicyNotFound)
+		return nil, nil
+	}
+	return p, found
+}
+
+// readPolicy parses the policy document of the request body, writing the error response if it is invalid.
+// Invalid statements are reported with the 422 status code, pointing at the index of the statement and the invalid field.
+func readPolicy(w http.ResponseWriter, r *http.Request, identifier string, createdAt string) *policy.Policy {
+	var body dto.PolicyDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	parsed, err := dto.ParsePolicyDTO(identifier, createdAt, body)
+	if statementErr, ok := err.(*policy.StatementError); ok {
+		writeJSON(w, http.StatusUnprocessableEntity, dto.NewPolicyStatementErrorDTO(statementErr))
+		return nil
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	return parsed
+}
+
+// grantedPermissions returns the permissions the policy grants outside of its project, which the authenticated user must hold
+// to create or update it, so that project policies cannot grant access to other projects, users, groups or roles.
+func grantedPermissions(pol *policy.Policy) []permission {
+	permissions := []permission{}
+	for _, grant := range pol.ListGrantsOutsideProject() {
+		permissions = append(permissions, permission{grant.GetResource(), grant.GetAction(), nil})
+	}
+	return permissions
+}
+
+// isPolicyNameUsed returns true if another policy of the project has the given name.
+func isPolicyNameUsed(p *project.Project, name string, except *common.Identifier) bool {
+	for _, existing := range p.ListPolicies() {
+		if existing.GetName() == name && (except == nil || existing.GetIdentifier().ToString() != except.ToString()) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatePolicy handles POST /projects/{projectId}/policies.
+// Actions allowed on resources outside of the project must also be allowed to the authenticated user.
+func (h *PolicyHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.CREATE_POLICY, p.ListTags()}) {
+		return
+	}
+	identifier, err := common.BuildPolicyIdentifier(p.GetIdentifier().ToString())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	created := readPolicy(w, r, identifier.ToString(), common.CurrentTimestamp())
+	if created == nil || !authorize(w, r, h.users, grantedPermissions(created)...) {
+		return
+	}
+	if isPolicyNameUsed(p, created.GetName(), nil) {
+		writeError(w, http.StatusConflict, project.ErrPolicyAlreadyPresent)
+		return
+	}
+	p.AddPolicy(created)
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewPolicyDTO(created))
+}
+
+// ListPolicies handles GET /projects/{projectId}/policies.
+func (h *PolicyHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	offset, limit := parsePagination(r)
+	found := p.ListPolicies()
+	policies := []dto.PolicyDTO{}
+	for i := offset; i < len(found) && i < offset+limit; i++ {
+		policies = append(policies, dto.NewPolicyDTO(found[i]))
+	}
+	writeJSON(w, http.StatusOK, policies)
+}
+
+// GetPolicy handles GET /projects/{projectId}/policies/{policyId}.
+func (h *PolicyHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	_, found := h.findPolicy(w, r)
+	if found == nil || !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.READ_POLICY, found.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewPolicyDTO(found))
+}
+
+// UpdatePolicy handles PUT /projects/{projectId}/policies/{policyId}, replacing the policy by the policy document of the body.
+// Actions allowed on resources outside of the project must also be allowed to the authenticated user,
+// and the updated policy must be within the permission boundary of the authenticated user, as it applies to its principals.
+func (h *PolicyHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	p, found := h.findPolicy(w, r)
+	if found == nil || !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.UPDATE_POLICY, found.ListTags()}) {
+		return
+	}
+	updated := readPolicy(w, r, found.GetIdentifier().ToString(), found.GetCreatedAt())
+	if updated == nil || !authorizeGrant(w, r, h.users, []*policy.Policy{updated}, grantedPermissions(updated)...) {
+		return
+	}
+	if isPolicyNameUsed(p, updated.GetName(), found.GetIdentifier()) {
+		writeError(w, http.StatusConflict, project.ErrPolicyAlreadyPresent)
+		return
+	}
+	p.RemovePolicy(found.GetIdentifier())
+	p.AddPolicy(updated)
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewPolicyDTO(updated))
+}
+
+// DeletePolicy handles DELETE /projects/{projectId}/policies/{policyId}.
+func (h *PolicyHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	p, found := h.findPolicy(w, r)
+	if found == nil || !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.DELETE_POLICY, found.ListTags()}) {
+		return
+	}
+	p.RemovePolicy(found.GetIdentifier())
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
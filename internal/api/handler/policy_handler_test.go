@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestPolicyHandler(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projects := newFakeProjectRepository(p)
+	h := NewPolicyHandler(projects, nil)
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/{projectId}/policies", h.CreatePolicy).Methods("POST")
+	r.HandleFunc("/projects/{projectId}/policies", h.ListPolicies).Methods("GET")
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.GetPolicy).Methods("GET")
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.UpdatePolicy).Methods("PUT")
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.DeletePolicy).Methods("DELETE")
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	base := "/projects/" + p.GetIdentifier().ToString() + "/policies"
+	document := `{"name": "deployers", "tags": [{"key": "team", "value": "platform"}], "statements": [
+		{"effect": "Allow", "actions": ["workflow:Run", "project:Read"], "resources": ["` + p.GetIdentifier().ToString() + `"]}
+	]}`
+
+	response := send(http.MethodPost, base, document)
+	var created dto.PolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &created)
+	if response.Code != http.StatusCreated || len(p.ListPolicies()) != 1 {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	if len(created.Statements) != 1 || created.Statements[0].Effect != "Allow" || len(created.Statements[0].Actions) != 2 || len(created.Tags) != 1 {
+		t.Errorf("unexpected created policy %s", response.Body.String())
+	}
+	if response := send(http.MethodPost, base, document); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+
+	response = send(http.MethodGet, base+"/"+created.Identifier, "")
+	var found dto.PolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &found)
+	if response.Code != http.StatusOK || found.Name != "deployers" || found.Statements[0].Resources[0] != p.GetIdentifier().ToString() {
+		t.Errorf("unexpected policy %d: %s", response.Code, response.Body.String())
+	}
+
+	invalid := `{"name": "deployers", "statements": [{"effect": "Allow", "actions": ["workflow:Run"], "resources": []}, {"effect": "Deny", "actions": ["workflow:Run", "workflow:Fly"], "resources": []}]}`
+	response = send(http.MethodPut, base+"/"+created.Identifier, invalid)
+	var statementErr dto.PolicyStatementErrorDTO
+	json.Unmarshal(response.Body.Bytes(), &statementErr)
+	if response.Code != http.StatusUnprocessableEntity || statementErr.Statement != 1 || statementErr.Field != "actions[1]" {
+		t.Errorf("expected the error to point at statement 1, actions[1], got %d: %s", response.Code, response.Body.String())
+	}
+
+	response = send(http.MethodPut, base+"/"+created.Identifier, `{"name": "readers", "statements": [{"effect": "Deny", "actions": ["workflow:Delete"], "resources": []}]}`)
+	if response.Code != http.StatusOK || p.GetPolicy(p.ListPolicies()[0].GetIdentifier()).GetName() != "readers" || p.ListPolicies()[0].GetIdentifier().ToString() != created.Identifier {
+		t.Errorf("expected the policy to be replaced, got %d: %s", response.Code, response.Body.String())
+	}
+	response = send(http.MethodGet, base, "")
+	var policies []dto.PolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &policies)
+	if response.Code != http.StatusOK || len(policies) != 1 || policies[0].Statements[0].Effect != "Deny" {
+		t.Errorf("unexpected policies %s", response.Body.String())
+	}
+
+	if response := send(http.MethodDelete, base+"/"+created.Identifier, ""); response.Code != http.StatusNoContent || len(p.ListPolicies()) != 0 {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+	if response := send(http.MethodGet, base+"/"+created.Identifier, ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
+
+func TestPolicyHandlerAuthorization(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:CreatePolicy"}, []string{p.GetIdentifier().ToString()})
+	creators, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "creators", "", []*policy.PolicyStatement{statement})
+	admin.AttachPolicy(creators)
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	h := NewPolicyHandler(newFakeProjectRepository(p), newFakeUserRepository(admin, stranger))
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/{projectId}/policies", h.CreatePolicy).Methods("POST")
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.GetPolicy).Methods("GET")
+	send := func(method string, path string, user string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		request.Header.Set(USER_HEADER, user)
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	base := "/projects/" + p.GetIdentifier().ToString() + "/policies"
+	document := `{"name": "readers", "statements": [{"effect": "Allow", "actions": ["policy:Read"], "resources": []}]}`
+
+	if response := send(http.MethodPost, base, "", document); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPost, base, stranger.GetIdentifier().ToString(), document); response.Code != http.StatusForbidden || len(p.ListPolicies()) != 0 {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, response.Code)
+	}
+	response := send(http.MethodPost, base, admin.GetIdentifier().ToString(), document)
+	var created dto.PolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &created)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	if response := send(http.MethodGet, base+"/"+created.Identifier, admin.GetIdentifier().ToString(), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected reading the policy to require policy:Read, got %d", response.Code)
+	}
+}
+
+func TestPolicyHandlerOutsideProject(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	other, _ := project.NewProject("billing", "")
+	existing, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "existing", "", []*policy.PolicyStatement{})
+	p.AddPolicy(existing)
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	create, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:CreatePolicy"}, []string{p.GetIdentifier().ToString()})
+	update, _ := policy.ParsePolicyStatement(1, "Allow", []string{"policy:Update"}, []string{existing.GetIdentifier().ToString()})
+	readOther, _ := policy.ParsePolicyStatement(2, "Allow", []string{"project:Read"}, []string{other.GetIdentifier().ToString()})
+	admins, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "admins", "", []*policy.PolicyStatement{create, update, readOther})
+	admin.AttachPolicy(admins)
+	h := NewPolicyHandler(newFakeProjectRepository(p, other), newFakeUserRepository(admin, stranger))
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/{projectId}/policies", h.CreatePolicy).Methods("POST")
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.UpdatePolicy).Methods("PUT")
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		request.Header.Set(USER_HEADER, admin.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	base := "/projects/" + p.GetIdentifier().ToString() + "/policies"
+	document := func(name string, action string, resource string) string {
+		return `{"name": "` + name + `", "statements": [{"effect": "Allow", "actions": ["` + action + `"], "resources": ["` + resource + `"]}]}`
+	}
+
+	if response := send(http.MethodPost, base, document("billing", "project:Update", other.GetIdentifier().ToString())); response.Code != http.StatusForbidden {
+		t.Errorf("expected granting an action on another project the caller does not hold to be forbidden, got %d", response.Code)
+	}
+	if response := send(http.MethodPost, base, document("groups", "user:CreateGroup", stranger.GetIdentifier().ToString())); response.Code != http.StatusForbidden {
+		t.Errorf("expected granting an action on a user the caller does not hold to be forbidden, got %d", response.Code)
+	}
+	if len(p.ListPolicies()) != 1 {
+		t.Fatal("expected the forbidden policies not to be created")
+	}
+	if response := send(http.MethodPost, base, document("billing", "project:Read", other.GetIdentifier().ToString())); response.Code != http.StatusCreated {
+		t.Errorf("expected granting an action the caller holds to be allowed, got %d: %s", response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodPut, base+"/"+existing.GetIdentifier().ToString(), document("existing", "project:Update", other.GetIdentifier().ToString())); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d, got %d", http.StatusForbidden, response.Code)
+	}
+	if len(p.GetPolicy(existing.GetIdentifier()).ListStatements()) != 0 {
+		t.Error("expected the policy to be left unchanged")
+	}
+	if response := send(http.MethodPut, base+"/"+existing.GetIdentifier().ToString(), document("existing", "workflow:Run", p.GetIdentifier().ToString())); response.Code != http.StatusOK {
+		t.Errorf("expected actions on the project to only require updating the policy, got %d: %s", response.Code, response.Body.String())
+	}
+}
+
+func TestPolicyHandlerUpdateBoundary(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	readers, _ := policy.NewPolicy(projectId, "readers", "", []*policy.PolicyStatement{readProject})
+	p.AddPolicy(readers)
+	// The delegate can update the policy, but its boundary only allows reading the project.
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	update, _ := policy.ParsePolicyStatement(0, "Allow", []string{"policy:Update"}, []string{readers.GetIdentifier().ToString()})
+	delegation, _ := policy.NewPolicy(projectId, "delegation", "", []*policy.PolicyStatement{update})
+	boundary, _ := policy.NewPolicy(projectId, "boundary", "", []*policy.PolicyStatement{update, readProject})
+	delegate.AttachPolicy(delegation)
+	delegate.SetPermissionBoundary(boundary)
+	h := NewPolicyHandler(newFakeProjectRepository(p), newFakeUserRepository(delegate))
+	r := mux.NewRouter()
+	r.HandleFunc("/projects/{projectId}/policies/{policyId}", h.UpdatePolicy).Methods("PUT")
+	send := func(actions string) *httptest.ResponseRecorder {
+		body := `{"name": "readers", "statements": [{"effect": "Allow", "actions": [` + actions + `], "resources": ["` + projectId + `"]}]}`
+		request := httptest.NewRequest(http.MethodPut, "/projects/"+projectId+"/policies/"+readers.GetIdentifier().ToString(), bytes.NewBufferString(body))
+		request.Header.Set(USER_HEADER, delegate.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(`"project:Read", "project:Delete"`); response.Code != http.StatusForbidden || len(p.GetPolicy(readers.GetIdentifier()).ListStatements()[0].ListActions()) != 1 {
+		t.Errorf("expected an update allowing actions outside of the boundary to be forbidden, got %d", response.Code)
+	}
+	if response := send(`"project:Read"`); response.Code != http.StatusOK {
+		t.Errorf("expected an update within the boundary to be allowed, got %d: %s", response.Code, response.Body.String())
+	}
+}
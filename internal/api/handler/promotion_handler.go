@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+)
+
+// GetPipeline handles GET /projects/{projectId}/pipeline.
+func (h *EnvironmentHandler) GetPipeline(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewPipelineDTO(p))
+}
+
+// SetPipeline handles PUT /projects/{projectId}/pipeline, replacing the promotion pipeline of the project.
+func (h *EnvironmentHandler) SetPipeline(w http.ResponseWriter, r *http.Request) {
+	p := h.findProject(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.UPDATE_PROJECT, p.ListTags()}) {
+		return
+	}
+	var body dto.PipelineDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	stages := make([]*common.Identifier, 0, len(body.Environments))
+	for _, id := range body.Environments {
+		identifier, err := common.NewIdentifier(id)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, project.ErrEnvironmentNotFound)
+			return
+		}
+		stages = append(stages, identifier)
+	}
+	if err := p.SetPipeline(stages); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.projects.Update(p); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewPipelineDTO(p))
+}
+
+// ListDeployments handles GET /projects/{projectId}/environments/{environmentId}/deployments,
+// returning the workflow versions deployed in the environment.
+func (h *EnvironmentHandler) ListDeployments(w http.ResponseWriter, r *http.Request) {
+	p, environment := h.findEnvironment(w, r)
+	if p == nil || !authorize(w, r, h.users, permission{p.GetIdentifier(), policy.READ_PROJECT, p.ListTags()}) {
+		return
+	}
+	deployments := []dto.DeploymentDTO{}
+	for _, deployment := range environment.ListDeployments() {
+		deployments = append(deployments, dto.NewDeploymentDTO(deployment))
+	}
+	writeJSON(w, http.StatusOK, deployments)
+}
+
+// Promote handles POST /projects/{projectId}/environments/{environmentId}/promote, running the workflow version deployed
+// in the environment against the next environment of the pipeline, on behalf of the authenticated user.
+// Runs always execute the current version of the workflow, so the promotion conflicts if the workflow or its templates
+// changed since the deployment.
+func (h *EnvironmentHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	p, environment := h.findEnvironment(w, r)
+	if environment == nil {
+		return
+	}
+	var body dto.PromoteWorkflowDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	wf := findWorkflow(w, h.workflows, body.WorkflowIdentifier)
+	if wf == nil {
+		return
+	}
+	run, err := p.Promote(user, wf, environment.GetIdentifier(), body.Name, body.Description)
+	switch err {
+	case nil:
+	case project.ErrWorkflowNotFound:
+		writeError(w, http.StatusNotFound, ErrWorkflowNotFound)
+		return
+	case project.ErrRunNotAllowed:
+		writeError(w, http.StatusForbidden, err)
+		return
+	case project.ErrNoNextEnvironment, project.ErrNothingToPromote, project.ErrPromotionBlocked, project.ErrPromotedVersionMismatch:
+		writeError(w, http.StatusConflict, err)
+		return
+	default:
+		writeRunError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewWorkflowRunDTO(run))
+}
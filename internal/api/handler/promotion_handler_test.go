@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestEnvironmentHandlerPromote(t *testing.T) {
+	p, _ := project.NewProject("project", "")
+	dev, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "dev", "", false)
+	staging, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "staging", "", false)
+	p.AddEnvironment(dev)
+	p.AddEnvironment(staging)
+	wf, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "/path/to/file.yml")
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	statement, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{wf.GetIdentifier()}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	manage, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{p.GetIdentifier()}, []policy.PolicyAction{policy.READ_PROJECT, policy.UPDATE_PROJECT})
+	runner, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "runner", "", []*policy.PolicyStatement{statement, manage})
+	developer.AttachPolicy(runner)
+
+	projects := newFakeProjectRepository(p)
+	workflows := newFakeWorkflowRepository(wf)
+	users := newFakeUserRepository(developer)
+	h := NewEnvironmentHandler(projects, workflows, users)
+	runs := NewWorkflowRunHandler(workflows, projects, nil, nil, users)
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/complete", runs.CompleteRun).Methods("POST")
+	router.HandleFunc("/projects/{projectId}/pipeline", h.SetPipeline).Methods("PUT")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/deployments", h.ListDeployments).Methods("GET")
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/promote", h.Promote).Methods("POST")
+	path := "/projects/" + p.GetIdentifier().ToString()
+	promotion := `{"workflow_id": "` + wf.GetIdentifier().ToString() + `", "name": "promote"}`
+
+	serve := func(method string, target string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set(USER_HEADER, developer.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	response := serve(http.MethodPut, path+"/pipeline", `{"environments": ["`+dev.GetIdentifier().ToString()+`", "`+staging.GetIdentifier().ToString()+`"]}`)
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected pipeline response %d: %s", response.Code, response.Body.String())
+	}
+	if response := serve(http.MethodPost, path+"/environments/"+dev.GetIdentifier().ToString()+"/promote", promotion); response.Code != http.StatusConflict {
+		t.Errorf("expected promotions without deployment to conflict, got %d", response.Code)
+	}
+
+	planning, _ := dev.StartRun(developer, wf, "plan", "", workflow.PLAN_ONLY, nil)
+	serve(http.MethodPost, "/workflows/"+wf.GetIdentifier().ToString()+"/runs/"+planning.GetIdentifier().ToString()+"/complete", "")
+	if len(dev.ListDeployments()) != 0 {
+		t.Error("expected plan-only runs not to be recorded as deployments")
+	}
+	deploying, _ := dev.StartRun(developer, wf, "deploy", "", workflow.STANDARD, nil)
+	response = serve(http.MethodPost, "/workflows/"+wf.GetIdentifier().ToString()+"/runs/"+deploying.GetIdentifier().ToString()+"/complete", "")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected complete response %d: %s", response.Code, response.Body.String())
+	}
+
+	response = serve(http.MethodGet, path+"/environments/"+dev.GetIdentifier().ToString()+"/deployments", "")
+	var deployments []dto.DeploymentDTO
+	json.NewDecoder(response.Body).Decode(&deployments)
+	if len(deployments) != 1 || deployments[0].RunIdentifier != deploying.GetIdentifier().ToString() {
+		t.Errorf("unexpected deployments %+v", deployments)
+	}
+
+	response = serve(http.MethodPost, path+"/environments/"+dev.GetIdentifier().ToString()+"/promote", promotion)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("unexpected promote response %d: %s", response.Code, response.Body.String())
+	}
+	var run dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&run)
+	if run.Environment == nil || *run.Environment != staging.GetIdentifier().ToString() || run.PromotedFrom == nil {
+		t.Errorf("unexpected run %+v", run)
+	}
+
+	failed, _ := dev.StartRun(developer, wf, "deploy", "", workflow.STANDARD, nil)
+	failed.SetStatus(common.FAILURE)
+	if response := serve(http.MethodPost, path+"/environments/"+dev.GetIdentifier().ToString()+"/promote", promotion); response.Code != http.StatusConflict {
+		t.Errorf("expected promotions after a failed run to conflict, got %d", response.Code)
+	}
+}
+
+func TestEnvironmentHandlerPromoteUpdatedWorkflow(t *testing.T) {
+	p, _ := project.NewProject("project", "")
+	dev, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "dev", "", false)
+	staging, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "staging", "", false)
+	p.AddEnvironment(dev)
+	p.AddEnvironment(staging)
+	p.SetPipeline([]*common.Identifier{dev.GetIdentifier(), staging.GetIdentifier()})
+	deployed, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "/path/to/file.yml")
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	statement, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{deployed.GetIdentifier()}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	runner, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "runner", "", []*policy.PolicyStatement{statement})
+	developer.AttachPolicy(runner)
+	deploying, _ := dev.StartRun(developer, deployed, "deploy", "", workflow.STANDARD, nil)
+	deployed.CompleteRun(deploying.GetIdentifier().ToString())
+	if _, err := p.RecordDeployment(deployed, deploying); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	current, _ := workflow.ExistingWorkflow(deployed.GetIdentifier().ToString(), "deploy", "", common.SUCCESS, "/path/to/file-v2.yml", 2, nil, nil, nil, deployed.ListRuns(), nil)
+	later, _ := current.StartRun("later", "", workflow.STANDARD, nil)
+	workflows := newFakeWorkflowRepository(current)
+	workflows.addVersion(deployed)
+	workflows.addVersion(current)
+	h := NewEnvironmentHandler(newFakeProjectRepository(p), workflows, newFakeUserRepository(developer))
+	router := mux.NewRouter()
+	router.HandleFunc("/projects/{projectId}/environments/{environmentId}/promote", h.Promote).Methods("POST")
+
+	request := httptest.NewRequest(http.MethodPost, "/projects/"+p.GetIdentifier().ToString()+"/environments/"+dev.GetIdentifier().ToString()+"/promote", strings.NewReader(`{"workflow_id": "`+deployed.GetIdentifier().ToString()+`", "name": "promote"}`))
+	request.Header.Set(USER_HEADER, developer.GetIdentifier().ToString())
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	if response.Code != http.StatusConflict {
+		t.Errorf("expected %d when the workflow changed since the deployment, got %d", http.StatusConflict, response.Code)
+	}
+	stored, _ := workflows.FindById(*deployed.GetIdentifier())
+	if workflows.updates != 0 || stored.GetVersion() != 2 || stored.GetRun(later.GetIdentifier().ToString()) == nil || len(stored.ListRuns()) != 2 {
+		t.Errorf("expected the current version of the workflow and its runs to be kept, got version %d with %d runs", stored.GetVersion(), len(stored.ListRuns()))
+	}
+}
@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// fakeWorkflowRepository is an in-memory workflow.WorkflowRepository used by the handler tests.
+// Previous versions of the workflows are only returned by FindAllVersions once added with addVersion.
+type fakeWorkflowRepository struct {
+	workflows map[string]*workflow.Workflow
+	versions  []*workflow.Workflow
+	updates   int
+}
+
+func newFakeWorkflowRepository(workflows ...*workflow.Workflow) *fakeWorkflowRepository {
+	repository := &fakeWorkflowRepository{workflows: map[string]*workflow.Workflow{}}
+	for _, wf := range workflows {
+		repository.workflows[wf.GetIdentifier().ToString()] = wf
+	}
+	return repository
+}
+
+func (f *fakeWorkflowRepository) Create(wf *workflow.Workflow) error {
+	f.workflows[wf.GetIdentifier().ToString()] = wf
+	return nil
+}
+
+func (f *fakeWorkflowRepository) Update(wf *workflow.Workflow) error {
+	f.updates++
+	f.workflows[wf.GetIdentifier().ToString()] = wf
+	return nil
+}
+
+func (f *fakeWorkflowRepository) Delete(workflowId common.Identifier) error {
+	delete(f.workflows, workflowId.ToString())
+	return nil
+}
+
+func (f *fakeWorkflowRepository) FindByProject(projectId common.Identifier, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+// addVersion stores a version of a workflow, in the order versions are added.
+func (f *fakeWorkflowRepository) addVersion(wf *workflow.Workflow) {
+	f.versions = append(f.versions, wf)
+}
+
+func (f *fakeWorkflowRepository) FindAllVersions(workflowId common.Identifier, offset int, limit int) ([]*workflow.Workflow, error) {
+	versions := []*workflow.Workflow{}
+	for _, wf := range f.versions {
+		if wf.GetIdentifier().ToString() == workflowId.ToString() {
+			versions = append(versions, wf)
+		}
+	}
+	if offset >= len(versions) {
+		return []*workflow.Workflow{}, nil
+	}
+	return versions[offset:min(offset+limit, len(versions))], nil
+}
+
+func (f *fakeWorkflowRepository) FindById(workflowId common.Identifier) (*workflow.Workflow, error) {
+	return f.workflows[workflowId.ToString()], nil
+}
+
+func (f *fakeWorkflowRepository) FindAll(offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindWithAllTags(tags []*common.Tag, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindWithAnyTags(tags []*common.Tag, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+// fakeUserRepository is an in-memory identity.UserRepository used by the handler tests.
+type fakeUserRepository struct {
+	users map[string]*identity.User
+}
+
+func newFakeUserRepository(users ...*identity.User) *fakeUserRepository {
+	repository := &fakeUserRepository{users: map[string]*identity.User{}}
+	for _, user := range users {
+		repository.users[user.GetIdentifier().ToString()] = user
+	}
+	return repository
+}
+
+func (f *fakeUserRepository) Create(user *identity.User) error {
+	f.users[user.GetIdentifier().ToString()] = user
+	return nil
+}
+
+func (f *fakeUserRepository) Update(user *identity.User) error {
+	f.users[user.GetIdentifier().ToString()] = user
+	return nil
+}
+
+func (f *fakeUserRepository) Delete(userId common.Identifier) error {
+	delete(f.users, userId.ToString())
+	return nil
+}
+
+func (f *fakeUserRepository) FindById(id common.Identifier, offset int, limit int) (*identity.User, error) {
+	return f.users[id.ToString()], nil
+}
+
+func (f *fakeUserRepository) FindAll(offset int, limit int) ([]*identity.User, error) {
+	users := []*identity.User{}
+	for _, user := range f.users {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (f *fakeUserRepository) FindByUsername(username string, offset int, limit int) (*identity.User, error) {
+	return nil, nil
+}
+
+func (f *fakeUserRepository) FindByEmail(email string, offset int, limit int) (*identity.User, error) {
+	return nil, nil
+}
+
+// fakeGroupRepository is an in-memory identity.GroupRepository used by the handler tests.
+type fakeGroupRepository struct {
+	groups map[string]*identity.Group
+}
+
+func newFakeGroupRepository(groups ...*identity.Group) *fakeGroupRepository {
+	repository := &fakeGroupRepository{groups: map[string]*identity.Group{}}
+	for _, group := range groups {
+		repository.groups[group.GetIdentifier().ToString()] = group
+	}
+	return repository
+}
+
+func (f *fakeGroupRepository) Create(group *identity.Group) error {
+	f.groups[group.GetIdentifier().ToString()] = group
+	return nil
+}
+
+func (f *fakeGroupRepository) Update(group *identity.Group) error {
+	f.groups[group.GetIdentifier().ToString()] = group
+	return nil
+}
+
+func (f *fakeGroupRepository) Delete(groupId common.Identifier) error {
+	delete(f.groups, groupId.ToString())
+	return nil
+}
+
+func (f *fakeGroupRepository) FindById(id common.Identifier) (*identity.Group, error) {
+	return f.groups[id.ToString()], nil
+}
+
+func (f *fakeGroupRepository) FindAll(offset int, limit int) ([]*identity.Group, error) {
+	groups := []*identity.Group{}
+	for _, group := range f.groups {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (f *fakeGroupRepository) FindByMember(userId common.Identifier, offset int, limit int) ([]*identity.Group, error) {
+	groups := []*identity.Group{}
+	for _, group := range f.groups {
+		if group.HasMember(&userId) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// fakeRoleRepository is an in-memory identity.RoleRepository used by the handler tests.
+type fakeRoleRepository struct {
+	roles map[string]*identity.Role
+}
+
+func newFakeRoleRepository(roles ...*identity.Role) *fakeRoleRepository {
+	repository := &fakeRoleRepository{roles: map[string]*identity.Role{}}
+	for _, role := range roles {
+		repository.roles[role.GetIdentifier().ToString()] = role
+	}
+	return repository
+}
+
+func (f *fakeRoleRepository) Create(role *identity.Role) error {
+	f.roles[role.GetIdentifier().ToString()] = role
+	return nil
+}
+
+func (f *fakeRoleRepository) Update(role *identity.Role) error {
+	f.roles[role.GetIdentifier().ToString()] = role
+	return nil
+}
+
+func (f *fakeRoleRepository) Delete(roleId common.Identifier) error {
+	delete(f.roles, roleId.ToString())
+	return nil
+}
+
+func (f *fakeRoleRepository) FindById(id common.Identifier) (*identity.Role, error) {
+	return f.roles[id.ToString()], nil
+}
+
+func (f *fakeRoleRepository) FindAll(offset int, limit int) ([]*identity.Role, error) {
+	roles := []*identity.Role{}
+	for _, role := range f.roles {
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// fakeRoleSessionRepository is an in-memory identity.RoleSessionRepository used by the handler tests.
+type fakeRoleSessionRepository struct {
+	sessions []*identity.RoleSession
+}
+
+func (f *fakeRoleSessionRepository) Create(session *identity.RoleSession) error {
+	f.sessions = append([]*identity.RoleSession{session}, f.sessions...)
+	return nil
+}
+
+func (f *fakeRoleSessionRepository) FindById(id common.Identifier) (*identity.RoleSession, error) {
+	for _, session := range f.sessions {
+		if session.GetIdentifier().ToString() == id.ToString() {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeRoleSessionRepository) FindByRole(roleId common.Identifier, offset int, limit int) ([]*identity.RoleSession, error) {
+	sessions := []*identity.RoleSession{}
+	for _, session := range f.sessions {
+		if session.GetRole().ToString() == roleId.ToString() {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// fakeSecretRepository is an in-memory secret.SecretRepository used by the handler tests.
+type fakeSecretRepository struct {
+	secrets map[string]*secret.Secret
+}
+
+func newFakeSecretRepository() *fakeSecretRepository {
+	return &fakeSecretRepository{secrets: map[string]*secret.Secret{}}
+}
+
+func (f *fakeSecretRepository) Create(s *secret.Secret) error {
+	f.secrets[s.GetIdentifier().ToString()] = s
+	return nil
+}
+
+func (f *fakeSecretRepository) Update(s *secret.Secret) error {
+	f.secrets[s.GetIdentifier().ToString()] = s
+	return nil
+}
+
+func (f *fakeSecretRepository) Delete(secretId common.Identifier) error {
+	delete(f.secrets, secretId.ToString())
+	return nil
+}
+
+func (f *fakeSecretRepository) FindById(secretId common.Identifier) (*secret.Secret, error) {
+	return f.secrets[secretId.ToString()], nil
+}
+
+func (f *fakeSecretRepository) FindByProject(projectId common.Identifier, offset int, limit int) ([]*secret.Secret, error) {
+	secrets := []*secret.Secret{}
+	for _, s := range f.secrets {
+		if parent, _ := s.GetIdentifier().GetParent(); parent.ToString() == projectId.ToString() {
+			secrets = append(secrets, s)
+		}
+	}
+	return secrets, nil
+}
+
+// fakeProjectRepository is an in-memory project.ProjectRepository used by the handler tests.
+type fakeProjectRepository struct {
+	projects map[string]*project.Project
+	updates  int
+}
+
+func newFakeProjectRepository(projects ...*project.Project) *fakeProjectRepository {
+	repository := &fakeProjectRepository{projects: map[string]*project.Project{}}
+	for _, p := range projects {
+		repository.projects[p.GetIdentifier().ToString()] = p
+	}
+	return repository
+}
+
+func (f *fakeProjectRepository) Create(p *project.Project) error {
+	f.projects[p.GetIdentifier().ToString()] = p
+	return nil
+}
+
+func (f *fakeProjectRepository) Update(p *project.Project) error {
+	f.updates++
+	f.projects[p.GetIdentifier().ToString()] = p
+	return nil
+}
+
+func (f *fakeProjectRepository) Delete(projectId common.Identifier) error {
+	delete(f.projects, projectId.ToString())
+	return nil
+}
+
+func (f *fakeProjectRepository) FindById(id common.Identifier) (*project.Project, error) {
+	return f.projects[id.ToString()], nil
+}
+
+func (f *fakeProjectRepository) FindAll(offset int, limit int) ([]*project.Project, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectRepository) FindWithAllTags(tags []*common.Tag, offset int, limit int) ([]*project.Project, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectRepository) FindWithAnyTags(tags []*common.Tag, offset int, limit int) ([]*project.Project, error) {
+	return nil, nil
+}
+
+// fakeTemplateRepository is an in-memory template.TemplateRepository used by the handler tests.
+// It stores every version of the templates, in the order they were created.
+type fakeTemplateRepository struct {
+	versions []*template.Template
+}
+
+func newFakeTemplateRepository(versions ...*template.Template) *fakeTemplateRepository {
+	return &fakeTemplateRepository{versions: append([]*template.Template(nil), versions...)}
+}
+
+func (f *fakeTemplateRepository) Create(t *template.Template) error {
+	f.versions = append(f.versions, t)
+	return nil
+}
+
+func (f *fakeTemplateRepository) Update(t *template.Template) error {
+	return nil
+}
+
+func (f *fakeTemplateRepository) Delete(templateId common.Identifier) error {
+	return nil
+}
+
+func (f *fakeTemplateRepository) FindByProject(projectId common.Identifier, offset int, limit int) ([]*template.Template, error) {
+	return nil, nil
+}
+
+func (f *fakeTemplateRepository) FindAllVersions(templateId common.Identifier, offset int, limit int) ([]*template.Template, error) {
+	versions := []*template.Template{}
+	for _, t := range f.versions {
+		if t.GetIdentifier().ToString() == templateId.ToString() {
+			versions = append(versions, t)
+		}
+	}
+	if offset >= len(versions) {
+		return []*template.Template{}, nil
+	}
+	return versions[offset:min(offset+limit, len(versions))], nil
+}
+
+func (f *fakeTemplateRepository) FindById(templateId common.Identifier) (*template.Template, error) {
+	var latest *template.Template
+	for _, t := range f.versions {
+		if t.GetIdentifier().ToString() == templateId.ToString() {
+			latest = t
+		}
+	}
+	return latest, nil
+}
+
+func (f *fakeTemplateRepository) FindAll(offset int, limit int) ([]*template.Template, error) {
+	return nil, nil
+}
+
+func (f *fakeTemplateRepository) FindWithAllTags(tags []*common.Tag, offset int, limit int) ([]*template.Template, error) {
+	return nil, nil
+}
+
+func (f *fakeTemplateRepository) FindWithAnyTags(tags []*common.Tag, offset int, limit int) ([]*template.Template, error) {
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+// Package handler implements the HTTP handlers of the AutOps API.
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+)
+
+// DEFAULT_PAGE_SIZE and MAX_PAGE_SIZE bound the number of items returned by list endpoints.
+const (
+	DEFAULT_PAGE_SIZE = 50
+	MAX_PAGE_SIZE     = 200
+)
+
+// parsePagination reads the offset and limit query parameters, falling back to the first page of DEFAULT_PAGE_SIZE items.
+func parsePagination(r *http.Request) (int, int) {
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = DEFAULT_PAGE_SIZE
+	}
+	if limit > MAX_PAGE_SIZE {
+		limit = MAX_PAGE_SIZE
+	}
+	return offset, limit
+}
+
+// writeJSON serializes the body as JSON and writes it with the provided status code.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes the error message as a JSON body with the provided status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, dto.ErrorDTO{Error: err.Error()})
+}
+
+// writeRunError writes an error returned when starting a workflow run.
+// Invalid inputs are reported field by field with the 422 status code, other errors use the provided status code.
+func writeRunError(w http.ResponseWriter, status int, err error) {
+	if validationErr, ok := err.(*workflow.AttributeValidationError); ok {
+		writeJSON(w, http.StatusUnprocessableEntity, dto.NewValidationErrorDTO(validationErr))
+		return
+	}
+	writeError(w, status, err)
+}
+
+// decodeJSON deserializes the JSON body of the request into the provided value, rejecting unknown fields.
+func decodeJSON(r *http.Request, value interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(value)
+}
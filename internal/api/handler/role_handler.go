@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrRoleNotFound = errors.New("cannot find a role with the provided id")
+
+// RoleHandler manages the roles, their trust policy and the policies attached to them,
+// and creates the sessions of the users assuming them. Every change requires the authenticated user to be allowed to update the role.
+type RoleHandler struct {
+	roles    identity.RoleRepository
+	sessions identity.RoleSessionRepository
+	users    identity.UserRepository
+	projects project.ProjectRepository
+}
+
+// NewRoleHandler creates a RoleHandler. The policies of the projects can only be attached to roles if projects is provided.
+// The user repository is expected to load the groups of the users, see identity.WithGroupMemberships,
+// so that the members of the trusted groups can assume the roles.
+func NewRoleHandler(roles identity.RoleRepository, sessions identity.RoleSessionRepository, users identity.UserRepository, projects project.ProjectRepository) *RoleHandler {
+	return &RoleHandler{
+		roles:    roles,
+		sessions: sessions,
+		users:    users,
+		projects: projects,
+	}
+}
+
+// findRole loads the role of the request path, writing the error response if it cannot be found.
+func (h *RoleHandler) findRole(w http.ResponseWriter, r *http.Request) *identity.Role {
+	roleId, err := common.NewIdentifier(mux.Vars(r)["roleId"])
+	if err != nil || roleId.GetType() != common.ROLE {
+		writeError(w, http.StatusNotFound, ErrRoleNotFound)
+		return nil
+	}
+	found, err := h.roles.FindById(*roleId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrRoleNotFound)
+		return nil
+	}
+	return found
+}
+
+// updateRole stores the role and writes it as the response.
+func (h *RoleHandler) updateRole(w http.ResponseWriter, role *identity.Role) {
+	role.UpdateModificationDate()
+	if err := h.roles.Update(role); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewRoleDTO(role))
+}
+
+// principalPermission returns the permission required to grant or revoke the trust of a role to a principal:
+// the user:Update action on a user, or the group:Update action on a group.
+// The principal is not authorized if it is of another type, which cannot be trusted.
+func principalPermission(principalId *common.Identifier) []permission {
+	switch principalId.GetType() {
+	case common.USER:
+		return []permission{{principalId, policy.UPDATE_USER, nil}}
+	case common.GROUP:
+		return []permission{{principalId, policy.UPDATE_GROUP, nil}}
+	default:
+		return []permission{}
+	}
+}
+
+// parseSessionDuration parses a duration like "1h", an empty value being parsed as zero.
+func parseSessionDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, identity.ErrInvalidSessionDuration
+	}
+	return duration, nil
+}
+
+// CreateRole handles POST /roles, which requires the user:CreateRole action on the authenticated user.
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil || !allowed(w, user, permission{user.GetIdentifier(), policy.CREATE_ROLE, nil}) {
+		return
+	}
+	var body dto.CreateRoleDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	duration, err := parseSessionDuration(body.MaxSessionDuration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	role, err := identity.NewRole(body.Name, body.Description, duration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	dto.AddTags(&role.TaggedEntity, body.Tags)
+	if err := h.roles.Create(role); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewRoleDTO(role))
+}
+
+// ListRoles handles GET /roles.
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	offset, limit := parsePagination(r)
+	found, err := h.roles.FindAll(offset, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	roles := []dto.RoleDTO{}
+	for _, role := range found {
+		roles = append(roles, dto.NewRoleDTO(role))
+	}
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// GetRole handles GET /roles/{roleId}.
+func (h *RoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewRoleDTO(role))
+}
+
+// DeleteRole handles DELETE /roles/{roleId}, which requires the role:Delete action on the role.
+// The credentials of its sessions are rejected once it is deleted.
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil || !authorize(w, r, h.users, permission{role.GetIdentifier(), policy.DELETE_ROLE, nil}) {
+		return
+	}
+	if err := h.roles.Delete(*role.GetIdentifier()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TrustPrincipal handles PUT /roles/{roleId}/trust/{principalId}, allowing a user or the members of a group to assume the role.
+// It requires the role:Update action on the role, and the user:Update or group:Update action on the principal.
+// The policies of the role must be within the permission boundary of the authenticated user.
+func (h *RoleHandler) TrustPrincipal(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	principalId, err := common.NewIdentifier(mux.Vars(r)["principalId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, identity.ErrInvalidTrustedPrincipal)
+		return
+	}
+	permissions := append([]permission{{role.GetIdentifier(), policy.UPDATE_ROLE, nil}}, principalPermission(principalId)...)
+	if !authorizeGrant(w, r, h.users, role.ListAttachedPolicies(), permissions...) {
+		return
+	}
+	switch err := role.Trust(principalId); err {
+	case nil:
+	case identity.ErrPrincipalAlreadyTrusted:
+		writeError(w, http.StatusConflict, err)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.updateRole(w, role)
+}
+
+// UntrustPrincipal handles DELETE /roles/{roleId}/trust/{principalId}, which requires the role:Update action on the role,
+// and the user:Update or group:Update action on the principal.
+func (h *RoleHandler) UntrustPrincipal(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	principalId, err := common.NewIdentifier(mux.Vars(r)["principalId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, identity.ErrTrustedPrincipalNotFound)
+		return
+	}
+	permissions := append([]permission{{role.GetIdentifier(), policy.UPDATE_ROLE, nil}}, principalPermission(principalId)...)
+	if !authorize(w, r, h.users, permissions...) {
+		return
+	}
+	if err := role.Untrust(principalId); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	h.updateRole(w, role)
+}
+
+// AttachPolicy handles PUT /roles/{roleId}/policies/{policyId}, which requires the role:Update action on the role,
+// the policy:Attach action on the policy and the policy to be within the permission boundary of the authenticated user.
+func (h *RoleHandler) AttachPolicy(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	attached := findPolicy(w, r, h.projects)
+	if attached == nil || !authorizeAttachment(w, r, h.users, attached, permission{role.GetIdentifier(), policy.UPDATE_ROLE, nil}) {
+		return
+	}
+	if role.GetAttachedPolicy(attached.GetIdentifier()) != nil {
+		writeError(w, http.StatusConflict, identity.ErrPolicyAlreadyAttached)
+		return
+	}
+	role.AttachPolicy(attached)
+	h.updateRole(w, role)
+}
+
+// DetachPolicy handles DELETE /roles/{roleId}/policies/{policyId}, which requires the role:Update action on the role
+// and the policy:Detach action on the policy.
+func (h *RoleHandler) DetachPolicy(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	attached := role.GetAttachedPolicy(policyId)
+	if attached == nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{role.GetIdentifier(), policy.UPDATE_ROLE, nil}, permission{attached.GetIdentifier(), policy.DETACH_POLICY, attached.ListTags()}) {
+		return
+	}
+	role.DetachPolicy(policyId)
+	h.updateRole(w, role)
+}
+
+// AssumeRole handles POST /roles/{roleId}/assume. The authenticated user must be trusted by the role,
+// and obtains temporary credentials acting with the policies of the role. The session is recorded for auditing.
+func (h *RoleHandler) AssumeRole(w http.ResponseWriter, r *http.Request) {
+	user := currentUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	role := h.findRole(w, r)
+	if role == nil {
+		return
+	}
+	var body dto.AssumeRoleDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	duration, err := parseSessionDuration(body.Duration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	session, token, err := role.Assume(user, duration, body.Reason)
+	switch err {
+	case nil:
+	case identity.ErrRoleNotTrusted, identity.ErrRoleChaining:
+		writeError(w, http.StatusForbidden, err)
+		return
+	case identity.ErrInvalidSessionDuration:
+		writeError(w, http.StatusBadRequest, err)
+		return
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := h.sessions.Create(session); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewRoleCredentialsDTO(session, token))
+}
+
+// ListSessions handles GET /roles/{roleId}/sessions, listing who assumed the role, when and why.
+// It requires the role:Read action on the role.
+func (h *RoleHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	role := h.findRole(w, r)
+	if role == nil || !authorize(w, r, h.users, permission{role.GetIdentifier(), policy.READ_ROLE, nil}) {
+		return
+	}
+	offset, limit := parsePagination(r)
+	found, err := h.sessions.FindByRole(*role.GetIdentifier(), offset, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sessions := []dto.RoleSessionDTO{}
+	for _, session := range found {
+		sessions = append(sessions, dto.NewRoleSessionDTO(session))
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
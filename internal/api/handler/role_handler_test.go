@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestRoleHandler(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{p.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "readers", "", []*policy.PolicyStatement{readProject})
+	p.AddPolicy(readers)
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	createRoles, _ := policy.ParsePolicyStatement(0, "Allow", []string{"user:CreateRole"}, []string{admin.GetIdentifier().ToString()})
+	creators, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "creators", "", []*policy.PolicyStatement{createRoles})
+	admin.AttachPolicy(creators)
+	runner, _ := identity.NewUser("ci@example.com", "ci_runner")
+	ci, _ := identity.NewGroup("ci", "")
+	ci.AddMember(runner.GetIdentifier())
+
+	roles := newFakeRoleRepository()
+	sessions := &fakeRoleSessionRepository{}
+	users := identity.WithGroupMemberships(newFakeUserRepository(admin, runner), newFakeGroupRepository(ci))
+	users = identity.WithRoleSessions(users, roles, sessions)
+	h := NewRoleHandler(roles, sessions, users, newFakeProjectRepository(p))
+	r := mux.NewRouter()
+	r.HandleFunc("/roles", h.CreateRole).Methods("POST")
+	r.HandleFunc("/roles", h.ListRoles).Methods("GET")
+	r.HandleFunc("/roles/{roleId}", h.GetRole).Methods("GET")
+	r.HandleFunc("/roles/{roleId}", h.DeleteRole).Methods("DELETE")
+	r.HandleFunc("/roles/{roleId}/trust/{principalId}", h.TrustPrincipal).Methods("PUT")
+	r.HandleFunc("/roles/{roleId}/trust/{principalId}", h.UntrustPrincipal).Methods("DELETE")
+	r.HandleFunc("/roles/{roleId}/policies/{policyId}", h.AttachPolicy).Methods("PUT")
+	r.HandleFunc("/roles/{roleId}/policies/{policyId}", h.DetachPolicy).Methods("DELETE")
+	r.HandleFunc("/roles/{roleId}/assume", h.AssumeRole).Methods("POST")
+	r.HandleFunc("/roles/{roleId}/sessions", h.ListSessions).Methods("GET")
+	send := func(method string, path string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	as := func(user *identity.User) map[string]string {
+		return map[string]string{USER_HEADER: user.GetIdentifier().ToString()}
+	}
+
+	createRole := `{"name": "deployer", "description": "Deploys to production", "max_session_duration": "2h"}`
+	if response := send(http.MethodPost, "/roles", nil, createRole); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPost, "/roles", as(runner), createRole); response.Code != http.StatusForbidden {
+		t.Errorf("expected creating a role to require user:CreateRole, got %d", response.Code)
+	}
+	if response := send(http.MethodPost, "/roles", as(admin), `{"name": "deployer", "max_session_duration": "1d"}`); response.Code != http.StatusBadRequest {
+		t.Errorf("expected an invalid duration to be rejected, got %d", response.Code)
+	}
+	response := send(http.MethodPost, "/roles", as(admin), createRole)
+	var created dto.RoleDTO
+	json.Unmarshal(response.Body.Bytes(), &created)
+	if response.Code != http.StatusCreated || created.MaxSessionDuration != "2h0m0s" {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	base := "/roles/" + created.Identifier
+	attach, _ := policy.ParsePolicyStatement(0, "Allow", []string{"policy:Attach"}, []string{readers.GetIdentifier().ToString()})
+	updateRole, _ := policy.ParsePolicyStatement(1, "Allow", []string{"role:Update"}, []string{created.Identifier})
+	attachers, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "attachers", "", []*policy.PolicyStatement{attach, updateRole})
+	p.AddPolicy(attachers)
+	manage, _ := policy.ParsePolicyStatement(0, "Allow", []string{"policy:Attach"}, []string{attachers.GetIdentifier().ToString()})
+	manageRole, _ := policy.ParsePolicyStatement(1, "Allow", []string{"role:Read", "role:Update", "role:Delete"}, []string{created.Identifier})
+	manageGroup, _ := policy.ParsePolicyStatement(2, "Allow", []string{"group:Update"}, []string{ci.GetIdentifier().ToString()})
+	admins, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "admins", "", []*policy.PolicyStatement{manage, manageRole, manageGroup})
+	admin.AttachPolicy(admins)
+
+	if response := send(http.MethodPut, base+"/trust/"+p.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusBadRequest {
+		t.Errorf("expected only users and groups to be trusted, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, base+"/trust/"+ci.GetIdentifier().ToString(), as(runner), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected trusting a principal to require role:Update, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, base+"/trust/"+runner.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected trusting a user to require user:Update on the user, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, base+"/trust/"+ci.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if response := send(http.MethodPut, base+"/trust/"+ci.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+	if response := send(http.MethodPut, base+"/policies/"+attachers.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodPost, base+"/assume", as(admin), `{}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected an untrusted user to be forbidden, got %d", response.Code)
+	}
+	if response := send(http.MethodPost, base+"/assume", as(runner), `{"duration": "3h"}`); response.Code != http.StatusBadRequest {
+		t.Errorf("expected a duration above the maximum to be rejected, got %d", response.Code)
+	}
+	response = send(http.MethodPost, base+"/assume", as(runner), `{"duration": "30m", "reason": "release 1.2"}`)
+	var credentials dto.RoleCredentialsDTO
+	json.Unmarshal(response.Body.Bytes(), &credentials)
+	if response.Code != http.StatusCreated || credentials.Token == "" || credentials.Role != created.Identifier {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	session := map[string]string{SESSION_HEADER: credentials.Session, SESSION_TOKEN_HEADER: credentials.Token}
+
+	policies := base + "/policies/" + readers.GetIdentifier().ToString()
+	if response := send(http.MethodPut, policies, as(runner), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected the user to be forbidden with its own policies, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, policies, session, ""); response.Code != http.StatusOK {
+		t.Errorf("expected the user to be allowed with the policies of the role, got %d: %s", response.Code, response.Body.String())
+	}
+	if response := send(http.MethodPost, base+"/assume", session, `{}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected role chaining to be forbidden, got %d", response.Code)
+	}
+	invalid := map[string]string{SESSION_HEADER: credentials.Session, SESSION_TOKEN_HEADER: "invalid"}
+	if response := send(http.MethodPut, policies, invalid, ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected an invalid token to be rejected, got %d", response.Code)
+	}
+
+	if response := send(http.MethodGet, base+"/sessions", as(runner), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected the sessions to require role:Read, got %d", response.Code)
+	}
+	response = send(http.MethodGet, base+"/sessions", as(admin), "")
+	var audited []dto.RoleSessionDTO
+	json.Unmarshal(response.Body.Bytes(), &audited)
+	if len(audited) != 1 || audited[0].Principal != runner.GetIdentifier().ToString() || audited[0].Reason != "release 1.2" || audited[0].Expired {
+		t.Errorf("unexpected sessions %s", response.Body.String())
+	}
+
+	if response := send(http.MethodDelete, base+"/trust/"+ci.GetIdentifier().ToString(), as(runner), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected untrusting a principal to require role:Update, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, base+"/trust/"+ci.GetIdentifier().ToString(), as(admin), ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := send(http.MethodPost, base+"/assume", as(runner), `{}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected an untrusted group member to be forbidden, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, base, as(runner), ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected deleting a role to require role:Delete, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, base, as(admin), ""); response.Code != http.StatusNoContent {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+}
+
+func TestRoleHandlerTrustPrincipalBoundary(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	projectId := p.GetIdentifier().ToString()
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId})
+	readers, _ := policy.NewPolicy(projectId, "readers", "", []*policy.PolicyStatement{readProject})
+	updateProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Update"}, []string{projectId})
+	writers, _ := policy.NewPolicy(projectId, "writers", "", []*policy.PolicyStatement{updateProject})
+	viewer, _ := identity.NewRole("viewer", "", 0)
+	viewer.AttachPolicy(readers)
+	maintainer, _ := identity.NewRole("maintainer", "", 0)
+	maintainer.AttachPolicy(writers)
+
+	// The delegate manages the roles, but its boundary only allows reading the project.
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	manage, _ := policy.ParsePolicyStatement(0, "Allow", []string{"role:Update", "user:Update"}, []string{viewer.GetIdentifier().ToString(), maintainer.GetIdentifier().ToString(), developer.GetIdentifier().ToString()})
+	delegation, _ := policy.NewPolicy(projectId, "delegation", "", []*policy.PolicyStatement{manage})
+	boundary, _ := policy.NewPolicy(projectId, "boundary", "", []*policy.PolicyStatement{manage, readProject})
+	delegate.AttachPolicy(delegation)
+	delegate.SetPermissionBoundary(boundary)
+
+	roles := newFakeRoleRepository(viewer, maintainer)
+	h := NewRoleHandler(roles, &fakeRoleSessionRepository{}, newFakeUserRepository(developer, delegate), newFakeProjectRepository(p))
+	r := mux.NewRouter()
+	r.HandleFunc("/roles/{roleId}/trust/{principalId}", h.TrustPrincipal).Methods("PUT")
+	send := func(role *identity.Role) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPut, "/roles/"+role.GetIdentifier().ToString()+"/trust/"+developer.GetIdentifier().ToString(), nil)
+		request.Header.Set(USER_HEADER, delegate.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(maintainer); response.Code != http.StatusForbidden || maintainer.IsTrusted(developer) {
+		t.Errorf("expected trusting a principal with a role whose policies exceed the boundary to be forbidden, got %d", response.Code)
+	}
+	if response := send(viewer); response.Code != http.StatusOK || !viewer.IsTrusted(developer) {
+		t.Errorf("expected trusting a principal with a role whose policies are within the boundary to be allowed, got %d: %s", response.Code, response.Body.String())
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/gorilla/mux"
+)
+
+// SchemaHandler exposes the inputs of templates and workflows as JSON Schema documents, used by clients to render their forms,
+// and creates inputs in bulk from such documents.
+// When the user repository is provided, the policies of the authenticated user must allow each request.
+type SchemaHandler struct {
+	templates template.TemplateRepository
+	workflows workflow.WorkflowRepository
+	users     identity.UserRepository
+}
+
+// NewSchemaHandler creates a SchemaHandler. Requests are not authorized if users is nil.
+func NewSchemaHandler(templates template.TemplateRepository, workflows workflow.WorkflowRepository, users identity.UserRepository) *SchemaHandler {
+	return &SchemaHandler{
+		templates: templates,
+		workflows: workflows,
+		users:     users,
+	}
+}
+
+// findTemplate loads the template with the given identifier, writing the error response if it cannot be found.
+func findTemplate(w http.ResponseWriter, templates template.TemplateRepository, id string) *template.Template {
+	identifier, err := common.NewIdentifier(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	found, err := templates.FindById(*identifier)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrTemplateNotFound)
+		return nil
+	}
+	return found
+}
+
+// readInputSchema parses the JSON Schema document of the request body, writing the error response if it is invalid.
+func readInputSchema(w http.ResponseWriter, r *http.Request) *common.InputSchema {
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	schema, err := common.ParseInputSchema(document)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	return schema
+}
+
+// GetTemplateSchema handles GET /templates/{templateId}/schema.
+func (h *SchemaHandler) GetTemplateSchema(w http.ResponseWriter, r *http.Request) {
+	found := findTemplate(w, h.templates, mux.Vars(r)["templateId"])
+	if found == nil || !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.READ_TEMPLATE, found.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, found.GetInputSchema().ToDocument())
+}
+
+// CreateTemplateInputs handles POST /templates/{templateId}/schema, creating an input for each property of the JSON Schema of the body.
+// The response is the schema of every input of the template.
+func (h *SchemaHandler) CreateTemplateInputs(w http.ResponseWriter, r *http.Request) {
+	found := findTemplate(w, h.templates, mux.Vars(r)["templateId"])
+	if found == nil || !authorize(w, r, h.users, permission{found.GetIdentifier(), policy.UPDATE_TEMPLATE, found.ListTags()}) {
+		return
+	}
+	schema := readInputSchema(w, r)
+	if schema == nil {
+		return
+	}
+	if _, err := found.AddInputsFromSchema(schema); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, template.ErrTemplateInputNameAlreadyUsed) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+	if err := h.templates.Update(found); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, found.GetInputSchema().ToDocument())
+}
+
+// GetWorkflowSchema handles GET /workflows/{workflowId}/schema.
+func (h *SchemaHandler) GetWorkflowSchema(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.READ_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, wf.GetInputSchema().ToDocument())
+}
+
+// CreateWorkflowInputs handles POST /workflows/{workflowId}/schema, creating an input for each property of the JSON Schema of the body.
+// The response is the schema of every input of the workflow.
+func (h *SchemaHandler) CreateWorkflowInputs(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.UPDATE_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	schema := readInputSchema(w, r)
+	if schema == nil {
+		return
+	}
+	if _, err := wf.AddInputsFromSchema(schema); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, workflow.ErrWorkflowInputNameAlreadyUsed) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, wf.GetInputSchema().ToDocument())
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/gorilla/mux"
+)
+
+func TestSchemaHandler(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	commit, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "commit", "Commit to deploy", workflow.STRING, "")
+	wf.AddInput(commit)
+	vpc, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 1)
+	h := NewSchemaHandler(newFakeTemplateRepository(vpc), newFakeWorkflowRepository(wf), nil)
+	r := mux.NewRouter()
+	r.HandleFunc("/templates/{templateId}/schema", h.GetTemplateSchema).Methods("GET")
+	r.HandleFunc("/templates/{templateId}/schema", h.CreateTemplateInputs).Methods("POST")
+	r.HandleFunc("/workflows/{workflowId}/schema", h.GetWorkflowSchema).Methods("GET")
+	r.HandleFunc("/workflows/{workflowId}/schema", h.CreateWorkflowInputs).Methods("POST")
+	send := func(method string, path string, body string) (*httptest.ResponseRecorder, map[string]any) {
+		request := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		var document map[string]any
+		json.Unmarshal(response.Body.Bytes(), &document)
+		return response, document
+	}
+
+	response, document := send(http.MethodGet, "/workflows/"+wf.GetIdentifier().ToString()+"/schema", "")
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	property := document["properties"].(map[string]any)["commit"].(map[string]any)
+	if document["$schema"] != common.JSON_SCHEMA_DIALECT || property["type"] != "string" || property["description"] != "Commit to deploy" {
+		t.Errorf("unexpected schema %v", document)
+	}
+
+	body := `{"properties": {"cidr": {"type": "string", "pattern": "^10\\.", "default": "10.0.0.0/16"}, "az_count": {"type": "number", "enum": [2, 3]}}}`
+	response, document = send(http.MethodPost, "/templates/"+vpc.GetIdentifier().ToString()+"/schema", body)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	if len(vpc.ListInputs()) != 2 || len(document["properties"].(map[string]any)) != 2 {
+		t.Errorf("expected 2 template inputs, got %d", len(vpc.ListInputs()))
+	}
+	if response, _ := send(http.MethodGet, "/templates/"+vpc.GetIdentifier().ToString()+"/schema", ""); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+
+	if response, _ := send(http.MethodPost, "/workflows/"+wf.GetIdentifier().ToString()+"/schema", `{"properties": {"commit": {"type": "string"}}}`); response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+	if response, _ := send(http.MethodPost, "/workflows/"+wf.GetIdentifier().ToString()+"/schema", `{"properties": {"size": {"type": "null"}}}`); response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, response.Code)
+	}
+	if response, _ := send(http.MethodGet, "/templates/autops::project:ABCDEFGHIJ:template:unknown123/schema", ""); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrInvalidProjectIdentifier = errors.New("the provided id is not a project identifier")
+
+// SecretHandler manages the secrets of projects. Secret values are write-only: they are never returned.
+// When the user repository is provided, reading secrets requires the project:Read action on their project,
+// and changing them the project:Update action.
+type SecretHandler struct {
+	secrets secret.SecretRepository
+	key     *secret.MasterKey
+	users   identity.UserRepository
+}
+
+// NewSecretHandler creates a SecretHandler encrypting secret values with the provided master key.
+// Requests are not authorized if users is nil.
+func NewSecretHandler(secrets secret.SecretRepository, key *secret.MasterKey, users identity.UserRepository) *SecretHandler {
+	return &SecretHandler{
+		secrets: secrets,
+		key:     key,
+		users:   users,
+	}
+}
+
+// parseProjectIdentifier parses the project identifier of the request path, writing the error response if it is invalid.
+func parseProjectIdentifier(w http.ResponseWriter, r *http.Request) *common.Identifier {
+	projectId, err := common.NewIdentifier(mux.Vars(r)["projectId"])
+	if err != nil || projectId.GetType() != common.PROJECT || len(projectId.Segments()) != 4 {
+		writeError(w, http.StatusBadRequest, ErrInvalidProjectIdentifier)
+		return nil
+	}
+	return projectId
+}
+
+// findSecret loads the secret of the request path once the user is allowed the action on its project,
+// writing the error response if it does not belong to the project.
+func (h *SecretHandler) findSecret(w http.ResponseWriter, r *http.Request, action policy.PolicyAction) *secret.Secret {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil || !authorize(w, r, h.users, permission{projectId, action, nil}) {
+		return nil
+	}
+	secretId, err := common.NewIdentifier(mux.Vars(r)["secretId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, secret.ErrSecretNotFound)
+		return nil
+	}
+	parent, err := secretId.GetParent()
+	if err != nil || secretId.GetType() != common.SECRET || parent.ToString() != projectId.ToString() {
+		writeError(w, http.StatusNotFound, secret.ErrSecretNotFound)
+		return nil
+	}
+	found, err := h.secrets.FindById(*secretId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, secret.ErrSecretNotFound)
+		return nil
+	}
+	return found
+}
+
+// CreateSecret handles POST /projects/{projectId}/secrets.
+func (h *SecretHandler) CreateSecret(w http.ResponseWriter, r *http.Request) {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil || !authorize(w, r, h.users, permission{projectId, policy.UPDATE_PROJECT, nil}) {
+		return
+	}
+	var body dto.CreateSecretDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	injectionType, err := secret.ParseInjectionType(body.InjectionType)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	created, err := secret.NewSecret(projectId.ToString(), body.Name, body.Description, injectionType, body.InjectionTarget, []byte(body.Value), h.key)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.secrets.Create(created); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewSecretDTO(created))
+}
+
+// ListSecrets handles GET /projects/{projectId}/secrets.
+func (h *SecretHandler) ListSecrets(w http.ResponseWriter, r *http.Request) {
+	projectId := parseProjectIdentifier(w, r)
+	if projectId == nil || !authorize(w, r, h.users, permission{projectId, policy.READ_PROJECT, nil}) {
+		return
+	}
+	offset, limit := parsePagination(r)
+	found, err := h.secrets.FindByProject(*projectId, offset, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	secrets := []dto.SecretDTO{}
+	for _, s := range found {
+		secrets = append(secrets, dto.NewSecretDTO(s))
+	}
+	writeJSON(w, http.StatusOK, secrets)
+}
+
+// GetSecret handles GET /projects/{projectId}/secrets/{secretId}.
+func (h *SecretHandler) GetSecret(w http.ResponseWriter, r *http.Request) {
+	found := h.findSecret(w, r, policy.READ_PROJECT)
+	if found == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewSecretDTO(found))
+}
+
+// RotateSecret handles PUT /projects/{projectId}/secrets/{secretId}/value, replacing the value of the secret.
+func (h *SecretHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	found := h.findSecret(w, r, policy.UPDATE_PROJECT)
+	if found == nil {
+		return
+	}
+	var body dto.SecretValueDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := found.SetValue([]byte(body.Value), h.key); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.secrets.Update(found); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteSecret handles DELETE /projects/{projectId}/secrets/{secretId}.
+func (h *SecretHandler) DeleteSecret(w http.ResponseWriter, r *http.Request) {
+	found := h.findSecret(w, r, policy.UPDATE_PROJECT)
+	if found == nil {
+		return
+	}
+	if err := h.secrets.Delete(*found.GetIdentifier()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestSecretHandler(t *testing.T) {
+	key, _ := secret.NewMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.MASTER_KEY_LENGTH)))
+	repository := newFakeSecretRepository()
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read", "project:Update"}, []string{"autops::project:ABCDEFGHIJ", "autops::project:KLMNOPQRST"})
+	admins, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "admins", "", []*policy.PolicyStatement{statement})
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	admin.AttachPolicy(admins)
+	statement, _ = policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{"autops::project:ABCDEFGHIJ"})
+	readers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "readers", "", []*policy.PolicyStatement{statement})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(readers)
+	h := NewSecretHandler(repository, key, newFakeUserRepository(admin, reader))
+	router := mux.NewRouter()
+	router.HandleFunc("/projects/{projectId}/secrets", h.CreateSecret).Methods("POST")
+	router.HandleFunc("/projects/{projectId}/secrets", h.ListSecrets).Methods("GET")
+	router.HandleFunc("/projects/{projectId}/secrets/{secretId}", h.DeleteSecret).Methods("DELETE")
+	router.HandleFunc("/projects/{projectId}/secrets/{secretId}/value", h.RotateSecret).Methods("PUT")
+	path := "/projects/autops::project:ABCDEFGHIJ/secrets"
+	serve := func(method string, target string, user *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	body := `{"name": "db", "injection_type": "env", "injection_target": "DB_PASSWORD", "value": "hunter2"}`
+	if response := serve(http.MethodPost, path, nil, body); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d for an anonymous request, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := serve(http.MethodPost, path, reader, body); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the project:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+	response := serve(http.MethodPost, path, admin, body)
+	if response.Code != http.StatusCreated || strings.Contains(response.Body.String(), "hunter2") {
+		t.Fatalf("unexpected create response %d: %s", response.Code, response.Body.String())
+	}
+	var created dto.SecretDTO
+	json.NewDecoder(response.Body).Decode(&created)
+	if created.Reference != "${"+created.Identifier+"}" || created.InjectionTarget != "DB_PASSWORD" {
+		t.Errorf("unexpected secret %+v", created)
+	}
+
+	response = serve(http.MethodPost, path, admin, `{"name": "db", "injection_type": "env", "injection_target": "DB-PASSWORD", "value": "hunter2"}`)
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d, got %d", http.StatusBadRequest, response.Code)
+	}
+
+	if response := serve(http.MethodGet, path, nil, ""); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d for an anonymous request, got %d", http.StatusUnauthorized, response.Code)
+	}
+	response = serve(http.MethodGet, path, reader, "")
+	var secrets []dto.SecretDTO
+	json.NewDecoder(response.Body).Decode(&secrets)
+	if response.Code != http.StatusOK || len(secrets) != 1 {
+		t.Errorf("unexpected list response %d: %+v", response.Code, secrets)
+	}
+
+	if response := serve(http.MethodPut, path+"/"+created.Identifier+"/value", reader, `{"value": "rotated"}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the project:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+	response = serve(http.MethodPut, path+"/"+created.Identifier+"/value", admin, `{"value": "rotated"}`)
+	if response.Code != http.StatusNoContent {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+	stored := repository.secrets[created.Identifier]
+	if revealed, _ := stored.Reveal(key); string(revealed) != "rotated" {
+		t.Errorf("expected rotated, got %s", revealed)
+	}
+
+	response = serve(http.MethodDelete, "/projects/autops::project:KLMNOPQRST/secrets/"+created.Identifier, admin, "")
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected secrets of other projects to be hidden, got %d", response.Code)
+	}
+	if response := serve(http.MethodDelete, path+"/"+created.Identifier, reader, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the project:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+	response = serve(http.MethodDelete, path+"/"+created.Identifier, admin, "")
+	if response.Code != http.StatusNoContent || len(repository.secrets) != 0 {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+}
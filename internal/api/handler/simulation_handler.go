@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+)
+
+var (
+	ErrInvalidPrincipal = errors.New("the principal must be either a user or a list of policy documents")
+	ErrUserNotFound     = errors.New("cannot find a user with the provided id")
+)
+
+// SimulationHandler explains the access decisions of users and policy documents, without performing any action.
+// Simulations require an authenticated user, who must be allowed to read the simulated user.
+type SimulationHandler struct {
+	users identity.UserRepository
+}
+
+// NewSimulationHandler creates a SimulationHandler. Only policy documents can be simulated, without authorization, if users is nil.
+func NewSimulationHandler(users identity.UserRepository) *SimulationHandler {
+	return &SimulationHandler{
+		users: users,
+	}
+}
+
+// Simulate handles POST /policies/simulate, returning the decision for each pair of action and resource of the body,
+// with every statement that applies and whether the result comes from an explicit deny, an allow, the default deny
+// or the permission boundary of the user.
+// Statement conditions are evaluated against the principal, the principal tags of a simulated user, the current date and the context keys of the body.
+// Policy documents without identifier are given a temporary one, reported in the matching statements.
+// Simulating a user requires the user:Read action on the user.
+func (h *SimulationHandler) Simulate(w http.ResponseWriter, r *http.Request) {
+	var caller *identity.User
+	if h.users != nil {
+		if caller = currentUser(w, r, h.users); caller == nil {
+			return
+		}
+	}
+	var body dto.SimulatePolicyDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if caller != nil && body.Principal.User != nil {
+		userId, err := common.NewIdentifier(*body.Principal.User)
+		if err == nil && !allowed(w, caller, permission{userId, policy.READ_USER, nil}) {
+			return
+		}
+	}
+	policies, boundary, tags, status, err := h.principalPolicies(body.Principal)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	actions := make([]policy.PolicyAction, 0, len(body.Actions))
+	for i, str := range body.Actions {
+		action, err := policy.ParsePolicyAction(str)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("actions[%d]: %w", i, err))
+			return
+		}
+		actions = append(actions, action)
+	}
+	resources := make([]*common.Identifier, 0, len(body.Resources))
+	for i, str := range body.Resources {
+		resource, err := common.NewIdentifier(str)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("resources[%d]: %w", i, err))
+			return
+		}
+		resources = append(resources, resource)
+	}
+
+	var principalId *common.Identifier
+	if body.Principal.User != nil {
+		principalId, _ = common.NewIdentifier(*body.Principal.User)
+	}
+	context := policy.NewRequestContext(principalId, time.Now())
+	context.SetPrincipalTags(tags)
+	for key, value := range body.Context {
+		context.Set(key, value)
+	}
+
+	result := dto.SimulationDTO{Results: []dto.DecisionDTO{}}
+	for _, action := range actions {
+		for _, resource := range resources {
+			result.Results = append(result.Results, dto.NewDecisionDTO(policy.ExplainWithinBoundary(policies, boundary, resource, action, context)))
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// principalPolicies returns the policies of the principal, and the permission boundary and the principal tags of the user, if any,
+// or the status code and error to respond with.
+func (h *SimulationHandler) principalPolicies(principal dto.PrincipalDTO) ([]*policy.Policy, *policy.Policy, []*common.Tag, int, error) {
+	if (principal.User == nil) == (principal.Policies == nil) {
+		return nil, nil, nil, http.StatusBadRequest, ErrInvalidPrincipal
+	}
+	if principal.User != nil {
+		userId, err := common.NewIdentifier(*principal.User)
+		if err != nil || userId.GetType() != common.USER {
+			return nil, nil, nil, http.StatusBadRequest, ErrInvalidPrincipal
+		}
+		if h.users == nil {
+			return nil, nil, nil, http.StatusNotFound, ErrUserNotFound
+		}
+		user, err := h.users.FindById(*userId, 0, 1)
+		if err != nil {
+			return nil, nil, nil, http.StatusInternalServerError, err
+		}
+		if user == nil {
+			return nil, nil, nil, http.StatusNotFound, ErrUserNotFound
+		}
+		return user.ListEffectivePolicies(), user.GetPermissionBoundary(), user.ListPrincipalTags(), http.StatusOK, nil
+	}
+
+	policies := make([]*policy.Policy, 0, len(principal.Policies))
+	for i, document := range principal.Policies {
+		identifier := document.Identifier
+		if identifier == "" {
+			projectId, err := common.BuildProjectIdentifier()
+			if err != nil {
+				return nil, nil, nil, http.StatusInternalServerError, err
+			}
+			generated, err := common.BuildPolicyIdentifier(projectId.ToString())
+			if err != nil {
+				return nil, nil, nil, http.StatusInternalServerError, err
+			}
+			identifier = generated.ToString()
+		}
+		parsed, err := dto.ParsePolicyDTO(identifier, common.CurrentTimestamp(), document)
+		if err != nil {
+			return nil, nil, nil, http.StatusBadRequest, fmt.Errorf("principal.policies[%d]: %w", i, err)
+		}
+		policies = append(policies, parsed)
+	}
+	return policies, nil, nil, http.StatusOK, nil
+}
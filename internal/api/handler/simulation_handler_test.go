@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+)
+
+func TestSimulationHandler(t *testing.T) {
+	projectId, _ := common.BuildProjectIdentifier()
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId.ToString()})
+	readers, _ := policy.NewPolicy(projectId.ToString(), "readers", "", []*policy.PolicyStatement{statement})
+	developer.AttachPolicy(readers)
+	auditor, _ := identity.NewUser("auditor@example.com", "auditor")
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+	readUsers, _ := policy.ParsePolicyStatement(0, "Allow", []string{"user:Read"}, []string{developer.GetIdentifier().ToString(), stranger.GetIdentifier().ToString()})
+	auditors, _ := policy.NewPolicy(projectId.ToString(), "auditors", "", []*policy.PolicyStatement{readUsers})
+	auditor.AttachPolicy(auditors)
+	h := NewSimulationHandler(newFakeUserRepository(developer, auditor))
+	simulateAs := func(caller *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/policies/simulate", bytes.NewBufferString(body))
+		if caller != nil {
+			request.Header.Set(USER_HEADER, caller.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		h.Simulate(response, request)
+		return response
+	}
+	simulate := func(body string) *httptest.ResponseRecorder {
+		return simulateAs(auditor, body)
+	}
+	project := projectId.ToString()
+
+	response := simulate(`{"principal": {"user": "` + developer.GetIdentifier().ToString() + `"}, "actions": ["project:Read", "project:Update"], "resources": ["` + project + `"]}`)
+	var result dto.SimulationDTO
+	json.Unmarshal(response.Body.Bytes(), &result)
+	if response.Code != http.StatusOK || len(result.Results) != 2 {
+		t.Fatalf("expected %d with 2 results, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	read, update := result.Results[0], result.Results[1]
+	if !read.Allowed || read.Effect != "Allow" || read.Reason != "allow" || len(read.Matches) != 1 || read.Matches[0].PolicyId != readers.GetIdentifier().ToString() {
+		t.Errorf("unexpected decision %+v", read)
+	}
+	if update.Allowed || update.Effect != "Unspecified" || update.Reason != "default_deny" || len(update.Matches) != 0 {
+		t.Errorf("unexpected decision %+v", update)
+	}
+
+	response = simulate(`{"principal": {"policies": [
+		{"name": "readers", "statements": [{"effect": "Allow", "actions": ["project:Read"], "resources": ["` + project + `"]}]},
+		{"name": "lockdown", "statements": [{"effect": "Deny", "actions": ["project:Read"], "resources": ["` + project + `"]}]}
+	]}, "actions": ["project:Read"], "resources": ["` + project + `"]}`)
+	result = dto.SimulationDTO{}
+	json.Unmarshal(response.Body.Bytes(), &result)
+	if response.Code != http.StatusOK || len(result.Results) != 1 {
+		t.Fatalf("expected %d with 1 result, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if denied := result.Results[0]; denied.Allowed || denied.Effect != "Deny" || denied.Reason != "explicit_deny" || len(denied.Matches) != 2 || denied.Matches[1].PolicyName != "lockdown" {
+		t.Errorf("unexpected decision %+v", denied)
+	}
+
+	conditional := `{"principal": {"policies": [{"name": "dev", "statements": [{"effect": "Allow", "actions": ["project:Read"], "resources": ["` + project + `"],
+		"conditions": [{"operator": "StringEquals", "key": "resource:tag/env", "values": ["dev"]}]}]}]}, "actions": ["project:Read"], "resources": ["` + project + `"]`
+	for env, allowed := range map[string]bool{"dev": true, "prod": false} {
+		response = simulate(conditional + `, "context": {"resource:tag/env": "` + env + `"}}`)
+		result = dto.SimulationDTO{}
+		json.Unmarshal(response.Body.Bytes(), &result)
+		if response.Code != http.StatusOK || len(result.Results) != 1 || result.Results[0].Allowed != allowed {
+			t.Errorf("expected the %s resource to be allowed: %t, got %d: %s", env, allowed, response.Code, response.Body.String())
+		}
+	}
+
+	developerPrincipal := `{"principal": {"user": "` + developer.GetIdentifier().ToString() + `"}, "actions": ["project:Read"], "resources": ["` + project + `"]}`
+	if response := simulateAs(nil, developerPrincipal); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d without an authenticated user, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := simulateAs(developer, developerPrincipal); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the user:Read action, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := simulateAs(developer, `{"principal": {"policies": []}, "actions": [], "resources": []}`); response.Code != http.StatusOK {
+		t.Errorf("expected %d for policy documents, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+
+	tests := map[string]struct {
+		body   string
+		status int
+	}{
+		"no principal":      {`{"principal": {}, "actions": [], "resources": []}`, http.StatusBadRequest},
+		"both principals":   {`{"principal": {"user": "` + developer.GetIdentifier().ToString() + `", "policies": []}, "actions": [], "resources": []}`, http.StatusBadRequest},
+		"unknown user":      {`{"principal": {"user": "` + stranger.GetIdentifier().ToString() + `"}, "actions": [], "resources": []}`, http.StatusNotFound},
+		"invalid action":    {`{"principal": {"policies": []}, "actions": ["project:Fly"], "resources": []}`, http.StatusBadRequest},
+		"invalid resource":  {`{"principal": {"policies": []}, "actions": [], "resources": ["nowhere"]}`, http.StatusBadRequest},
+		"invalid statement": {`{"principal": {"policies": [{"name": "p", "statements": [{"effect": "Maybe", "actions": [], "resources": []}]}]}, "actions": [], "resources": []}`, http.StatusBadRequest},
+	}
+	for name, test := range tests {
+		if response := simulate(test.body); response.Code != test.status {
+			t.Errorf("%s: expected %d, got %d: %s", name, test.status, response.Code, response.Body.String())
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+// UserHandler manages the policies attached to users, their permission boundaries and their tags.
+// Attaching a policy, either directly or as a boundary, requires it to be within the permission boundary of the authenticated user,
+// so that the management of users can be delegated without escalation of privileges.
+type UserHandler struct {
+	users    identity.UserRepository
+	projects project.ProjectRepository
+}
+
+// NewUserHandler creates a UserHandler.
+func NewUserHandler(users identity.UserRepository, projects project.ProjectRepository) *UserHandler {
+	return &UserHandler{
+		users:    users,
+		projects: projects,
+	}
+}
+
+// updateUser stores the user and writes the response with the given status and body.
+func (h *UserHandler) updateUser(w http.ResponseWriter, user *identity.User, status int, body any) {
+	user.UpdateModificationDate()
+	if err := h.users.Update(user); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, body)
+}
+
+// AttachPolicy handles PUT /users/{userId}/policies/{policyId}, which requires the user:Update action on the user,
+// the policy:Attach action on the policy and the policy to be within the permission boundary of the authenticated user.
+func (h *UserHandler) AttachPolicy(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	attached := findPolicy(w, r, h.projects)
+	if attached == nil || !authorizeAttachment(w, r, h.users, attached, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}) {
+		return
+	}
+	if user.GetAttachedPolicy(attached.GetIdentifier()) != nil {
+		writeError(w, http.StatusConflict, identity.ErrPolicyAlreadyAttached)
+		return
+	}
+	user.AttachPolicy(attached)
+	h.updateUser(w, user, http.StatusOK, dto.NewEffectivePolicyDTOs(user))
+}
+
+// DetachPolicy handles DELETE /users/{userId}/policies/{policyId},
+// which requires the user:Update action on the user and the policy:Detach action on the policy.
+func (h *UserHandler) DetachPolicy(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	policyId, err := common.NewIdentifier(mux.Vars(r)["policyId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	attached := user.GetAttachedPolicy(policyId)
+	if attached == nil {
+		writeError(w, http.StatusNotFound, identity.ErrAttachedPolicyNotFound)
+		return
+	}
+	if !authorize(w, r, h.users, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}, permission{attached.GetIdentifier(), policy.DETACH_POLICY, attached.ListTags()}) {
+		return
+	}
+	user.DetachPolicy(policyId)
+	h.updateUser(w, user, http.StatusOK, dto.NewEffectivePolicyDTOs(user))
+}
+
+// GetBoundary handles GET /users/{userId}/boundary.
+func (h *UserHandler) GetBoundary(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	if user.GetPermissionBoundary() == nil {
+		writeError(w, http.StatusNotFound, identity.ErrBoundaryNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewPolicyDTO(user.GetPermissionBoundary()))
+}
+
+// SetBoundary handles PUT /users/{userId}/boundary/{policyId}, replacing the permission boundary of the user.
+// It requires the user:Update action on the user, the policy:Attach action on the policy
+// and the policy to be within the permission boundary of the authenticated user.
+func (h *UserHandler) SetBoundary(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	boundary := findPolicy(w, r, h.projects)
+	if boundary == nil || !authorizeAttachment(w, r, h.users, boundary, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}) {
+		return
+	}
+	user.SetPermissionBoundary(boundary)
+	h.updateUser(w, user, http.StatusOK, dto.NewPolicyDTO(boundary))
+}
+
+// RemoveBoundary handles DELETE /users/{userId}/boundary, which requires the user:Update action on the user.
+// Users limited by a permission boundary cannot remove permission boundaries.
+func (h *UserHandler) RemoveBoundary(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	if user.GetPermissionBoundary() == nil {
+		writeError(w, http.StatusNotFound, identity.ErrBoundaryNotFound)
+		return
+	}
+	authenticated := currentUser(w, r, h.users)
+	if authenticated == nil || !allowed(w, authenticated, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}) {
+		return
+	}
+	if authenticated.GetPermissionBoundary() != nil {
+		writeError(w, http.StatusForbidden, identity.ErrBoundaryRequired)
+		return
+	}
+	user.SetPermissionBoundary(nil)
+	h.updateUser(w, user, http.StatusNoContent, nil)
+}
+
+// SetTags handles PUT /users/{userId}/tags, replacing the tags of the user by the tags of the body.
+// It requires the user:Update action on the user. As the tags of users are evaluated by the statement conditions on principal tags,
+// users limited by a permission boundary cannot change them.
+func (h *UserHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	user := findUser(w, r, h.users)
+	if user == nil {
+		return
+	}
+	authenticated := currentUser(w, r, h.users)
+	if authenticated == nil || !allowed(w, authenticated, permission{user.GetIdentifier(), policy.UPDATE_USER, nil}) {
+		return
+	}
+	if authenticated.GetPermissionBoundary() != nil {
+		writeError(w, http.StatusForbidden, identity.ErrTagsRequireNoBoundary)
+		return
+	}
+	var body []dto.TagDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, tag := range user.ListTags() {
+		user.RemoveTag(tag.GetKey())
+	}
+	dto.AddTags(&user.TaggedEntity, body)
+	h.updateUser(w, user, http.StatusOK, dto.NewTagDTOs(user.ListTags()))
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestUserHandlerDelegation(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	workflowId, _ := common.BuildWorkflowIdentifier(p.GetIdentifier().ToString())
+	newPolicy := func(name string, actions []string, resources ...string) *policy.Policy {
+		statement, _ := policy.ParsePolicyStatement(0, "Allow", actions, resources)
+		created, _ := policy.NewPolicy(p.GetIdentifier().ToString(), name, "", []*policy.PolicyStatement{statement})
+		p.AddPolicy(created)
+		return created
+	}
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	readers := newPolicy("readers", []string{"workflow:Read"}, workflowId.ToString())
+	runners := newPolicy("runners", []string{"workflow:Read", "workflow:Run"}, workflowId.ToString())
+	managed := []string{developer.GetIdentifier().ToString(), readers.GetIdentifier().ToString(), runners.GetIdentifier().ToString()}
+	delegation := newPolicy("delegation", []string{"user:Update", "policy:Attach", "policy:Detach"}, managed...)
+	// The boundary of the delegated administrator allows it to manage the developer, and to read the workflow at most.
+	boundary := newPolicy("boundary", []string{"user:Update", "policy:Attach", "policy:Detach", "workflow:Read"}, append(managed, workflowId.ToString())...)
+
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	delegate.AttachPolicy(delegation)
+	delegate.SetPermissionBoundary(boundary)
+	root, _ := identity.NewUser("root@example.com", "root")
+	root.AttachPolicy(delegation)
+
+	users := newFakeUserRepository(developer, delegate, root)
+	h := NewUserHandler(users, newFakeProjectRepository(p))
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{userId}/policies/{policyId}", h.AttachPolicy).Methods("PUT")
+	r.HandleFunc("/users/{userId}/policies/{policyId}", h.DetachPolicy).Methods("DELETE")
+	r.HandleFunc("/users/{userId}/boundary", h.GetBoundary).Methods("GET")
+	r.HandleFunc("/users/{userId}/boundary/{policyId}", h.SetBoundary).Methods("PUT")
+	r.HandleFunc("/users/{userId}/boundary", h.RemoveBoundary).Methods("DELETE")
+	send := func(method string, path string, user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, nil)
+		request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	base := "/users/" + developer.GetIdentifier().ToString()
+
+	if response := send(http.MethodPut, base+"/policies/"+readers.GetIdentifier().ToString(), delegate); response.Code != http.StatusOK {
+		t.Errorf("expected a policy within the boundary to be attached, got %d: %s", response.Code, response.Body.String())
+	}
+	response := send(http.MethodPut, base+"/policies/"+runners.GetIdentifier().ToString(), delegate)
+	if response.Code != http.StatusForbidden || !strings.Contains(response.Body.String(), identity.ErrPolicyExceedsBoundary.Error()) {
+		t.Errorf("expected a policy exceeding the boundary to be rejected, got %d: %s", response.Code, response.Body.String())
+	}
+	response = send(http.MethodPut, base+"/policies/"+runners.GetIdentifier().ToString(), root)
+	var effective []dto.EffectivePolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &effective)
+	if response.Code != http.StatusOK || len(effective) != 2 {
+		t.Errorf("expected a user without boundary to attach the policy, got %d: %s", response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodGet, base+"/boundary", root); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+	if response := send(http.MethodPut, base+"/boundary/"+readers.GetIdentifier().ToString(), delegate); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+	if !developer.IsAllowed(workflowId, policy.READ_WORKFLOW, nil) || developer.IsAllowed(workflowId, policy.RUN_WORKFLOW, nil) {
+		t.Errorf("expected the boundary to limit the permissions of the developer")
+	}
+	response = send(http.MethodGet, base+"/boundary", root)
+	var found dto.PolicyDTO
+	json.Unmarshal(response.Body.Bytes(), &found)
+	if response.Code != http.StatusOK || found.Identifier != readers.GetIdentifier().ToString() {
+		t.Errorf("unexpected boundary %d: %s", response.Code, response.Body.String())
+	}
+	if response := send(http.MethodDelete, base+"/boundary", delegate); response.Code != http.StatusForbidden {
+		t.Errorf("expected a bounded user not to remove boundaries, got %d", response.Code)
+	}
+	if response := send(http.MethodDelete, base+"/boundary", root); response.Code != http.StatusNoContent || developer.GetPermissionBoundary() != nil {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+
+	if response := send(http.MethodDelete, base+"/policies/"+readers.GetIdentifier().ToString(), delegate); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := send(http.MethodDelete, base+"/policies/"+readers.GetIdentifier().ToString(), delegate); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
+
+func TestUserHandlerPrincipalTags(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	developer, _ := identity.NewUser("developer@example.com", "developer")
+	delegate, _ := identity.NewUser("delegate@example.com", "delegate")
+	admin, _ := identity.NewUser("admin@example.com", "admin")
+	tagUsers, _ := policy.ParsePolicyStatement(0, "Allow", []string{"user:Update"}, []string{developer.GetIdentifier().ToString()})
+	admins, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "admins", "", []*policy.PolicyStatement{tagUsers})
+	admin.AttachPolicy(admins)
+	delegate.AttachPolicy(admins)
+	delegate.SetPermissionBoundary(admins)
+	// The platform team can read the project, whatever the user.
+	readProject, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{p.GetIdentifier().ToString()})
+	team, _ := policy.NewCondition(policy.STRING_EQUALS, policy.PrincipalTagKey("team"), []string{"platform"})
+	readProject.SetConditions([]*policy.Condition{team})
+	platform, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "platform", "", []*policy.PolicyStatement{readProject})
+	developer.AttachPolicy(platform)
+
+	users := newFakeUserRepository(developer, delegate, admin)
+	h := NewUserHandler(users, newFakeProjectRepository(p))
+	policies := NewPolicyHandler(newFakeProjectRepository(p), users)
+	r := mux.NewRouter()
+	r.HandleFunc("/users/{userId}/tags", h.SetTags).Methods("PUT")
+	r.HandleFunc("/projects/{projectId}/policies", policies.ListPolicies).Methods("GET")
+	send := func(method string, path string, user *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+	tags := "/users/" + developer.GetIdentifier().ToString() + "/tags"
+	list := "/projects/" + p.GetIdentifier().ToString() + "/policies"
+
+	if response := send(http.MethodGet, list, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected an untagged user not to match the condition, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, tags, developer, `[{"key": "team", "value": "platform"}]`); response.Code != http.StatusForbidden {
+		t.Errorf("expected tagging a user to require user:Update, got %d", response.Code)
+	}
+	if response := send(http.MethodPut, tags, delegate, `[{"key": "team", "value": "platform"}]`); response.Code != http.StatusForbidden || len(developer.ListTags()) != 0 {
+		t.Errorf("expected a user limited by a permission boundary not to tag users, got %d", response.Code)
+	}
+	response := send(http.MethodPut, tags, admin, `[{"key": "team", "value": "platform"}]`)
+	var set []dto.TagDTO
+	json.Unmarshal(response.Body.Bytes(), &set)
+	if response.Code != http.StatusOK || len(set) != 1 || set[0].Value != "platform" {
+		t.Fatalf("expected the tags to be set, got %d: %s", response.Code, response.Body.String())
+	}
+	if response := send(http.MethodGet, list, developer, ""); response.Code != http.StatusOK {
+		t.Errorf("expected the principal tags of the user to match the condition, got %d: %s", response.Code, response.Body.String())
+	}
+
+	if response := send(http.MethodPut, tags, admin, `[{"key": "team", "value": "billing"}]`); response.Code != http.StatusOK || len(developer.ListTags()) != 1 {
+		t.Fatalf("expected the tags to be replaced, got %d", response.Code)
+	}
+	if response := send(http.MethodGet, list, developer, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected the replaced tag not to match the condition anymore, got %d", response.Code)
+	}
+}
@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+	"github.com/gorilla/mux"
+)
+
+var (
+	ErrTemplateNotFound    = errors.New("cannot find a template with the provided id")
+	ErrVersionNotFound     = errors.New("cannot find the requested version")
+	ErrInvalidVersionRange = errors.New("the from and to query parameters must be version numbers")
+)
+
+// VersionHandler exposes the version history of templates and workflows, and the changes between two versions.
+// When the user repository is provided, the authenticated user must be allowed to read the template or the workflow.
+type VersionHandler struct {
+	templates template.TemplateRepository
+	workflows workflow.WorkflowRepository
+	sources   *versioning.SourceLoader
+	secrets   secret.SecretRepository
+	key       *secret.MasterKey
+	users     identity.UserRepository
+}
+
+// NewVersionHandler creates a VersionHandler.
+// The source content of the versions is only compared when a source loader is provided,
+// and the credentials of private git sources are only available when the secret repository and master key are provided.
+// Requests are not authorized if users is nil.
+func NewVersionHandler(templates template.TemplateRepository, workflows workflow.WorkflowRepository, sources *versioning.SourceLoader, secrets secret.SecretRepository, key *secret.MasterKey, users identity.UserRepository) *VersionHandler {
+	return &VersionHandler{
+		templates: templates,
+		workflows: workflows,
+		sources:   sources,
+		secrets:   secrets,
+		key:       key,
+		users:     users,
+	}
+}
+
+// findAllVersions loads every version of a resource, page by page.
+func findAllVersions[T any](find func(offset int, limit int) ([]T, error)) ([]T, error) {
+	versions := []T{}
+	for offset := 0; ; offset += MAX_PAGE_SIZE {
+		page, err := find(offset, MAX_PAGE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, page...)
+		if len(page) < MAX_PAGE_SIZE {
+			return versions, nil
+		}
+	}
+}
+
+// parseVersionRange parses the from and to query parameters of a diff request.
+func parseVersionRange(r *http.Request) (int, int, error) {
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || from < 1 {
+		return 0, 0, ErrInvalidVersionRange
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil || to < 1 {
+		return 0, 0, ErrInvalidVersionRange
+	}
+	return from, to, nil
+}
+
+// findTemplateVersions loads every version of the template of the request path, writing the error response if it cannot be found
+// or if the authenticated user is not allowed to read the latest version.
+func (h *VersionHandler) findTemplateVersions(w http.ResponseWriter, r *http.Request) []*template.Template {
+	identifier, err := common.NewIdentifier(mux.Vars(r)["templateId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	versions, err := findAllVersions(func(offset int, limit int) ([]*template.Template, error) {
+		return h.templates.FindAllVersions(*identifier, offset, limit)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if len(versions) == 0 {
+		writeError(w, http.StatusNotFound, ErrTemplateNotFound)
+		return nil
+	}
+	latest := versions[len(versions)-1]
+	if !authorize(w, r, h.users, permission{latest.GetIdentifier(), policy.READ_TEMPLATE, latest.ListTags()}) {
+		return nil
+	}
+	return versions
+}
+
+// findWorkflowVersions loads every version of the workflow of the request path, writing the error response if it cannot be found
+// or if the authenticated user is not allowed to read the latest version.
+func (h *VersionHandler) findWorkflowVersions(w http.ResponseWriter, r *http.Request) []*workflow.Workflow {
+	identifier, err := common.NewIdentifier(mux.Vars(r)["workflowId"])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	versions, err := findAllVersions(func(offset int, limit int) ([]*workflow.Workflow, error) {
+		return h.workflows.FindAllVersions(*identifier, offset, limit)
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if len(versions) == 0 {
+		writeError(w, http.StatusNotFound, ErrWorkflowNotFound)
+		return nil
+	}
+	latest := versions[len(versions)-1]
+	if !authorize(w, r, h.users, permission{latest.GetIdentifier(), policy.READ_WORKFLOW, latest.ListTags()}) {
+		return nil
+	}
+	return versions
+}
+
+// selectVersions returns the two versions of a diff request, writing the error response if they cannot be found.
+func selectVersions[T interface{ GetVersion() int }](w http.ResponseWriter, r *http.Request, versions []T) (T, T, bool) {
+	var from, to T
+	fromVersion, toVersion, err := parseVersionRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return from, to, false
+	}
+	foundFrom, foundTo := false, false
+	for _, version := range versions {
+		if version.GetVersion() == fromVersion {
+			from, foundFrom = version, true
+		}
+		if version.GetVersion() == toVersion {
+			to, foundTo = version, true
+		}
+	}
+	if !foundFrom || !foundTo {
+		writeError(w, http.StatusNotFound, ErrVersionNotFound)
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// diffSources compares the source content of two versions, writing the error response if it fails.
+// The returned files are nil, without error, if the source content cannot be loaded.
+func (h *VersionHandler) diffSources(w http.ResponseWriter, r *http.Request, from *common.VersionedSource, fromCredentials *gitsource.Credentials, to *common.VersionedSource, toCredentials *gitsource.Credentials) ([]*versioning.FileDiff, bool) {
+	if h.sources == nil {
+		return nil, true
+	}
+	files, err := h.sources.DiffSources(r.Context(), from, fromCredentials, to, toCredentials)
+	switch {
+	case err == nil:
+		return files, true
+	case errors.Is(err, versioning.ErrSourceNotAvailable):
+		return nil, true
+	case errors.Is(err, gitsource.ErrGitCommandFailed), errors.Is(err, gitsource.ErrRefNotFound), errors.Is(err, gitsource.ErrSubdirectoryNotFound):
+		writeError(w, http.StatusBadGateway, err)
+		return nil, false
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+}
+
+// templateCredentials returns the credentials of the git source of the template, or nil if it has none or they are unavailable.
+func (h *VersionHandler) templateCredentials(t *template.Template) (*gitsource.Credentials, error) {
+	if h.secrets == nil || h.key == nil || t.GetGitSource() == nil {
+		return nil, nil
+	}
+	return gitsource.TemplateCredentials(t, h.secrets, h.key)
+}
+
+// ListTemplateVersions handles GET /templates/{templateId}/versions.
+func (h *VersionHandler) ListTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	versions := h.findTemplateVersions(w, r)
+	if versions == nil {
+		return
+	}
+	result := []dto.VersionDTO{}
+	for _, version := range versions {
+		result = append(result, dto.NewVersionDTO(&version.VersionedSource, version.GetCreatedAt()))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// DiffTemplateVersions handles GET /templates/{templateId}/diff?from=<version>&to=<version>.
+func (h *VersionHandler) DiffTemplateVersions(w http.ResponseWriter, r *http.Request) {
+	versions := h.findTemplateVersions(w, r)
+	if versions == nil {
+		return
+	}
+	from, to, ok := selectVersions(w, r, versions)
+	if !ok {
+		return
+	}
+	fromCredentials, err := h.templateCredentials(from)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	toCredentials, err := h.templateCredentials(to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	files, ok := h.diffSources(w, r, &from.VersionedSource, fromCredentials, &to.VersionedSource, toCredentials)
+	if !ok {
+		return
+	}
+	result := dto.NewVersionDiffDTO(template.Diff(from, to), files)
+	result.Classification = dto.NewVersionChangeDTO(template.ClassifyChange(from, to))
+	writeJSON(w, http.StatusOK, result)
+}
+
+// ListWorkflowVersions handles GET /workflows/{workflowId}/versions.
+func (h *VersionHandler) ListWorkflowVersions(w http.ResponseWriter, r *http.Request) {
+	versions := h.findWorkflowVersions(w, r)
+	if versions == nil {
+		return
+	}
+	result := []dto.VersionDTO{}
+	for _, version := range versions {
+		result = append(result, dto.NewVersionDTO(&version.VersionedSource, version.GetCreatedAt()))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// DiffWorkflowVersions handles GET /workflows/{workflowId}/diff?from=<version>&to=<version>.
+func (h *VersionHandler) DiffWorkflowVersions(w http.ResponseWriter, r *http.Request) {
+	versions := h.findWorkflowVersions(w, r)
+	if versions == nil {
+		return
+	}
+	from, to, ok := selectVersions(w, r, versions)
+	if !ok {
+		return
+	}
+	files, ok := h.diffSources(w, r, &from.VersionedSource, nil, &to.VersionedSource, nil)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewVersionDiffDTO(workflow.Diff(from, to), files))
+}
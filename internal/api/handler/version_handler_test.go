@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+	"github.com/gorilla/mux"
+)
+
+func TestVersionHandlerTemplates(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(first, "main.tf"), []byte("resource \"a\" {}\n"), 0o644)
+	os.WriteFile(filepath.Join(second, "main.tf"), []byte("resource \"b\" {}\n"), 0o644)
+
+	v1, _ := template.NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, template.TERRAFORM, first)
+	v2, _ := template.ExistingTemplate(v1.GetIdentifier().ToString(), "vpc", "", common.PENDING, template.TERRAFORM, second, 2)
+	v2.SetChangelog("Rename the resource")
+	cidr, _ := template.NewTemplateAttribute(v1.GetIdentifier().ToString(), "cidr", "", template.STRING, "10.0.0.0/16")
+	v2.AddInput(cidr)
+
+	h := NewVersionHandler(newFakeTemplateRepository(v1, v2), nil, versioning.NewSourceLoader(nil, filepath.Dir(first)), nil, nil, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/templates/{templateId}/versions", h.ListTemplateVersions).Methods("GET")
+	router.HandleFunc("/templates/{templateId}/diff", h.DiffTemplateVersions).Methods("GET")
+	path := "/templates/" + v1.GetIdentifier().ToString()
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, target, nil))
+		return response
+	}
+
+	response := serve(path + "/versions")
+	var versions []dto.VersionDTO
+	json.NewDecoder(response.Body).Decode(&versions)
+	if response.Code != http.StatusOK || len(versions) != 2 || versions[1].Version != 2 || versions[1].Changelog != "Rename the resource" {
+		t.Errorf("unexpected versions %d %+v", response.Code, versions)
+	}
+
+	response = serve(path + "/diff?from=1&to=2")
+	if response.Code != http.StatusOK {
+		t.Fatalf("unexpected diff response %d: %s", response.Code, response.Body.String())
+	}
+	var diff dto.VersionDiffDTO
+	json.NewDecoder(response.Body).Decode(&diff)
+	if !diff.ContentChanged || len(diff.Files) != 1 || diff.Files[0].Path != "main.tf" || diff.Files[0].Change != "modified" {
+		t.Errorf("unexpected file changes %+v", diff.Files)
+	}
+	if len(diff.Inputs) != 1 || diff.Inputs[0].Name != "cidr" || diff.Inputs[0].Change != "added" {
+		t.Errorf("unexpected input changes %+v", diff.Inputs)
+	}
+	if diff.Classification == nil || diff.Classification.Level != "minor" || diff.Classification.Breaking {
+		t.Errorf("unexpected classification %+v", diff.Classification)
+	}
+
+	if response := serve(path + "/diff?from=1&to=3"); response.Code != http.StatusNotFound {
+		t.Errorf("expected unknown versions to be not found, got %d", response.Code)
+	}
+	if response := serve(path + "/diff?from=one&to=2"); response.Code != http.StatusBadRequest {
+		t.Errorf("expected invalid versions to be rejected, got %d", response.Code)
+	}
+	if response := serve("/templates/autops::project:ABCDEFGHIJ:template:0000000000/versions"); response.Code != http.StatusNotFound {
+		t.Errorf("expected unknown templates to be not found, got %d", response.Code)
+	}
+}
+
+func TestVersionHandlerAuthorization(t *testing.T) {
+	v1, _ := template.NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, template.TERRAFORM, t.TempDir())
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"template:Read"}, []string{v1.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "readers", "", []*policy.PolicyStatement{statement})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(readers)
+	stranger, _ := identity.NewUser("stranger@example.com", "stranger")
+
+	h := NewVersionHandler(newFakeTemplateRepository(v1), nil, nil, nil, nil, newFakeUserRepository(reader, stranger))
+	router := mux.NewRouter()
+	router.HandleFunc("/templates/{templateId}/versions", h.ListTemplateVersions).Methods("GET")
+	list := func(user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/templates/"+v1.GetIdentifier().ToString()+"/versions", nil)
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := list(nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := list(stranger); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the template:Read action, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := list(reader); response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d: %s", http.StatusOK, response.Code, response.Body.String())
+	}
+}
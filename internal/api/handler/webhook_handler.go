@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+// MAX_WEBHOOK_PAYLOAD_SIZE limits the size of the payloads accepted by webhook triggers, larger payloads being rejected.
+const MAX_WEBHOOK_PAYLOAD_SIZE = 5 << 20
+
+// WEBHOOK_SIGNATURE_HEADERS lists the headers carrying the payload signature, by order of preference.
+// The GitHub header is supported so that repositories can target AutOps directly.
+var WEBHOOK_SIGNATURE_HEADERS = []string{"X-AutOps-Signature-256", "X-Hub-Signature-256"}
+
+var (
+	ErrWorkflowNotFound       = errors.New("cannot find a workflow with the provided id")
+	ErrWebhookPayloadTooLarge = errors.New("the webhook payload exceeds the maximum size")
+)
+
+// WebhookHandler manages webhook triggers of workflows and receives their payloads.
+// When the user repository is provided, managing the triggers of a workflow requires the workflow:Update action on it,
+// while payloads are authenticated by their signature.
+type WebhookHandler struct {
+	workflows workflow.WorkflowRepository
+	users     identity.UserRepository
+	baseURL   string
+}
+
+// NewWebhookHandler creates a WebhookHandler, generating trigger URLs relative to the provided API base URL.
+// Requests are not authorized if users is nil.
+func NewWebhookHandler(workflows workflow.WorkflowRepository, users identity.UserRepository, baseURL string) *WebhookHandler {
+	return &WebhookHandler{
+		workflows: workflows,
+		users:     users,
+		baseURL:   baseURL,
+	}
+}
+
+// findWorkflow loads the workflow with the given identifier, writing the error response if it cannot be found.
+func findWorkflow(w http.ResponseWriter, workflows workflow.WorkflowRepository, id string) *workflow.Workflow {
+	identifier, err := common.NewIdentifier(id)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil
+	}
+	found, err := workflows.FindById(*identifier)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if found == nil {
+		writeError(w, http.StatusNotFound, ErrWorkflowNotFound)
+		return nil
+	}
+	return found
+}
+
+// CreateTrigger handles POST /workflows/{workflowId}/triggers, which requires the workflow:Update and workflow:Run actions on the workflow,
+// since the trigger starts runs on behalf of its creator. The response is the only one including the trigger secret.
+func (h *WebhookHandler) CreateTrigger(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.UPDATE_WORKFLOW, wf.ListTags()}, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	var body dto.WebhookTriggerDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	trigger, err := workflow.NewWebhookTrigger(wf.GetIdentifier().ToString(), body.Name, body.Description, body.Filter, body.InputMappings)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := wf.AddTrigger(trigger); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewWebhookTriggerDTO(trigger, h.baseURL, true))
+}
+
+// ListTriggers handles GET /workflows/{workflowId}/triggers, which requires the workflow:Update action on the workflow.
+func (h *WebhookHandler) ListTriggers(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.UPDATE_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	triggers := []dto.WebhookTriggerDTO{}
+	for _, trigger := range wf.ListTriggers() {
+		triggers = append(triggers, dto.NewWebhookTriggerDTO(trigger, h.baseURL, false))
+	}
+	writeJSON(w, http.StatusOK, triggers)
+}
+
+// DeleteTrigger handles DELETE /workflows/{workflowId}/triggers/{triggerId}, which requires the workflow:Update action on the workflow.
+func (h *WebhookHandler) DeleteTrigger(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.UPDATE_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	if err := wf.RemoveTrigger(mux.Vars(r)["triggerId"]); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Receive handles POST /webhooks/{triggerId}. Payloads larger than MAX_WEBHOOK_PAYLOAD_SIZE are rejected with the 413 status code.
+// A valid payload starts a new run of the workflow owning the trigger, unless it is rejected by the trigger filter.
+func (h *WebhookHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	triggerId, err := common.NewIdentifier(mux.Vars(r)["triggerId"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWebhookTriggerNotFound)
+		return
+	}
+	workflowId, err := triggerId.GetParent()
+	if err != nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWebhookTriggerNotFound)
+		return
+	}
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MAX_WEBHOOK_PAYLOAD_SIZE))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, ErrWebhookPayloadTooLarge)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	signature := ""
+	for _, header := range WEBHOOK_SIGNATURE_HEADERS {
+		if signature = r.Header.Get(header); signature != "" {
+			break
+		}
+	}
+
+	wf, err := h.workflows.FindById(*workflowId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if wf == nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWebhookTriggerNotFound)
+		return
+	}
+	run, err := wf.HandleWebhook(triggerId.ToString(), payload, signature)
+	switch err {
+	case nil:
+	case workflow.ErrWebhookPayloadFiltered:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case workflow.ErrWebhookTriggerNotFound:
+		writeError(w, http.StatusNotFound, err)
+		return
+	case workflow.ErrInvalidWebhookSignature:
+		writeError(w, http.StatusUnauthorized, err)
+		return
+	default:
+		writeRunError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, dto.NewWorkflowRunDTO(run))
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func newWebhookRouter(h *WebhookHandler) *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/workflows/{workflowId}/triggers", h.CreateTrigger).Methods("POST")
+	r.HandleFunc("/workflows/{workflowId}/triggers", h.ListTriggers).Methods("GET")
+	r.HandleFunc("/webhooks/{triggerId}", h.Receive).Methods("POST")
+	return r
+}
+
+func TestWebhookHandler(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	commit, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "commit", "", workflow.STRING, "")
+	wf.AddInput(commit)
+	repository := newFakeWorkflowRepository(wf)
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read", "workflow:Update"}, []string{wf.GetIdentifier().ToString()})
+	editors, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "editors", "", []*policy.PolicyStatement{statement})
+	editor, _ := identity.NewUser("editor@example.com", "editor")
+	editor.AttachPolicy(editors)
+	runStatement, _ := policy.ParsePolicyStatement(1, "Allow", []string{"workflow:Run"}, []string{wf.GetIdentifier().ToString()})
+	maintainers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "maintainers", "", []*policy.PolicyStatement{statement, runStatement})
+	maintainer, _ := identity.NewUser("maintainer@example.com", "maintainer")
+	maintainer.AttachPolicy(maintainers)
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	router := newWebhookRouter(NewWebhookHandler(repository, newFakeUserRepository(maintainer, editor, reader), "https://autops.example.com"))
+	manage := func(method string, user *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/workflows/"+wf.GetIdentifier().ToString()+"/triggers", bytes.NewBufferString(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	body := `{"name": "on-push", "filter": "$.ref == \"refs/heads/main\"", "input_mappings": {"commit": "$.after"}}`
+	if response := manage(http.MethodPost, nil, body); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d for an anonymous request, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := manage(http.MethodGet, reader, ""); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the workflow:Update action, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := manage(http.MethodPost, editor, body); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the workflow:Run action, got %d", http.StatusForbidden, response.Code)
+	}
+	response := manage(http.MethodPost, maintainer, body)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, response.Code, response.Body.String())
+	}
+	var created dto.WebhookTriggerDTO
+	json.NewDecoder(response.Body).Decode(&created)
+	if created.Secret == "" || created.URL != "https://autops.example.com/webhooks/"+created.Identifier {
+		t.Errorf("unexpected trigger %+v", created)
+	}
+
+	send := func(payload string, secret string) *httptest.ResponseRecorder {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(payload))
+		request := httptest.NewRequest(http.MethodPost, "/webhooks/"+created.Identifier, bytes.NewBufferString(payload))
+		request.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(`{"ref": "refs/heads/main"}`, "wrong"); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(`{"ref": "refs/heads/dev"}`, created.Secret); response.Code != http.StatusNoContent {
+		t.Errorf("expected %d, got %d", http.StatusNoContent, response.Code)
+	}
+	response = send(`{"ref": "refs/heads/main", "after": "0123abcd"}`, created.Secret)
+	if response.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d: %s", http.StatusAccepted, response.Code, response.Body.String())
+	}
+	var run dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&run)
+	if run.TriggeredBy == nil || *run.TriggeredBy != created.Identifier || run.Inputs["commit"] != "0123abcd" {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if len(wf.ListRuns()) != 1 {
+		t.Errorf("expected 1 run, got %d", len(wf.ListRuns()))
+	}
+
+	if response := send(`{"ref": "refs/heads/main", "padding": "`+strings.Repeat("x", MAX_WEBHOOK_PAYLOAD_SIZE)+`"}`, created.Secret); response.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected %d for an oversized payload, got %d", http.StatusRequestEntityTooLarge, response.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/webhooks/autops::project:ABCDEFGHIJ:workflow:unknown123:trigger:unknown123", bytes.NewBufferString("{}"))
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+}
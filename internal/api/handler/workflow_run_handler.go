@@ -0,0 +1,388 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+var ErrSecretsUnavailable = errors.New("the secrets referenced by the run inputs cannot be resolved")
+
+// WorkflowRunHandler manages the runs of workflows, and receives the results of their steps from the runner executing them.
+// When the user repository is provided, the policies of the authenticated user must allow each request.
+type WorkflowRunHandler struct {
+	workflows workflow.WorkflowRepository
+	projects  project.ProjectRepository
+	secrets   secret.SecretRepository
+	key       *secret.MasterKey
+	users     identity.UserRepository
+}
+
+// NewWorkflowRunHandler creates a WorkflowRunHandler. Requests are not authorized if users is nil.
+// The projects are needed to authorize the runs targeting an environment, which are refused if projects is nil.
+// The secrets and the master key are needed to mask the secret inputs of a run in what its steps report,
+// and the runs with secret inputs cannot be reported if they are nil.
+func NewWorkflowRunHandler(workflows workflow.WorkflowRepository, projects project.ProjectRepository, secrets secret.SecretRepository, key *secret.MasterKey, users identity.UserRepository) *WorkflowRunHandler {
+	return &WorkflowRunHandler{
+		workflows: workflows,
+		projects:  projects,
+		secrets:   secrets,
+		key:       key,
+		users:     users,
+	}
+}
+
+// runMasker builds the Masker hiding the values of the secrets referenced by the inputs of a run,
+// writing the error response if they cannot be resolved.
+func (h *WorkflowRunHandler) runMasker(w http.ResponseWriter, wf *workflow.Workflow, run *workflow.WorkflowRun) *secret.Masker {
+	if h.secrets == nil || h.key == nil {
+		for _, input := range wf.ListInputs() {
+			if _, found := run.GetInputs()[input.GetName()]; found && input.IsSensitive() {
+				writeError(w, http.StatusInternalServerError, ErrSecretsUnavailable)
+				return nil
+			}
+		}
+		return secret.NewMasker()
+	}
+	injection, err := wf.ResolveSecrets(run.GetIdentifier().ToString(), h.secrets, h.key)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	return injection.GetMasker()
+}
+
+// findEnvironment loads the environment targeted by a run, writing the error response if it cannot be found.
+func (h *WorkflowRunHandler) findEnvironment(w http.ResponseWriter, environmentId *common.Identifier) *project.Environment {
+	if h.projects == nil {
+		writeError(w, http.StatusForbidden, project.ErrRunNotAllowed)
+		return nil
+	}
+	projectId, err := environmentId.GetParent()
+	if err != nil {
+		writeError(w, http.StatusNotFound, project.ErrEnvironmentNotFound)
+		return nil
+	}
+	found, err := h.projects.FindById(*projectId)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	var environment *project.Environment
+	if found != nil {
+		environment = found.GetEnvironment(environmentId)
+	}
+	if environment == nil {
+		writeError(w, http.StatusNotFound, project.ErrEnvironmentNotFound)
+		return nil
+	}
+	return environment
+}
+
+// StartRun handles POST /workflows/{workflowId}/runs, starting a run after validating its inputs against the workflow inputs.
+// Input values may be given as JSON strings or as any other JSON value, which is then used in its JSON representation.
+// String values like ${<workflow id>.outputs.<name>} are replaced by the output of the latest successful run of that workflow,
+// which must belong to the same project. It requires the workflow:Run action on the workflow and the workflow:Read action
+// on each referenced workflow, and the run is attributed to the authenticated user.
+func (h *WorkflowRunHandler) StartRun(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil {
+		return
+	}
+	var user *identity.User
+	if h.users != nil {
+		if user = currentUser(w, r, h.users); user == nil || !allowed(w, user, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+			return
+		}
+	}
+	var body dto.StartWorkflowRunDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	mode, values, ok := parseRunRequest(w, body, wf, h.workflows, user)
+	if !ok {
+		return
+	}
+	run, err := wf.StartRun(body.Name, body.Description, mode, values)
+	if err != nil {
+		writeRunError(w, http.StatusBadRequest, err)
+		return
+	}
+	if user != nil {
+		run.SetStartedBy(user.GetIdentifier())
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewWorkflowRunDTO(run))
+}
+
+// parseRunRequest parses the mode and input values of a run request of the workflow, writing the error response if they are invalid.
+// Output references in the input values are replaced by the outputs they address, which the user must be allowed to read
+// unless user is nil.
+func parseRunRequest(w http.ResponseWriter, body dto.StartWorkflowRunDTO, wf *workflow.Workflow, workflows workflow.WorkflowRepository, user *identity.User) (workflow.RunMode, map[string]string, bool) {
+	mode := workflow.STANDARD
+	if body.Mode != "" {
+		parsed, err := workflow.ParseRunMode(body.Mode)
+		if err != nil || parsed == workflow.APPLY_PLAN {
+			writeError(w, http.StatusBadRequest, workflow.ErrInvalidRunMode)
+			return mode, nil, false
+		}
+		mode = parsed
+	}
+	values := make(map[string]string, len(body.Inputs))
+	for name, raw := range body.Inputs {
+		var str string
+		if err := json.Unmarshal(raw, &str); err == nil {
+			values[name] = str
+		} else {
+			values[name] = string(raw)
+		}
+	}
+	values, err := wf.ResolveOutputReferences(values, workflows, user)
+	if err != nil {
+		writeRunError(w, http.StatusInternalServerError, err)
+		return mode, nil, false
+	}
+	return mode, values, true
+}
+
+// GetOutputs handles GET /workflows/{workflowId}/runs/{runId}/outputs, returning the resolved outputs of a run.
+// It requires the workflow:Read action on the workflow.
+func (h *WorkflowRunHandler) GetOutputs(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.READ_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewWorkflowRunOutputsDTO(run))
+}
+
+// GetLatestOutputs handles GET /workflows/{workflowId}/outputs, returning the outputs of the latest successful run.
+// These are the values addressed by output references from other workflows. It requires the workflow:Read action on the workflow.
+func (h *WorkflowRunHandler) GetLatestOutputs(w http.ResponseWriter, r *http.Request) {
+	wf := findWorkflow(w, h.workflows, mux.Vars(r)["workflowId"])
+	if wf == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.READ_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	run := wf.GetLatestSuccessfulRun()
+	if run == nil {
+		writeError(w, http.StatusNotFound, workflow.ErrNoSuccessfulRun)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewWorkflowRunOutputsDTO(run))
+}
+
+// GetPlan handles GET /workflows/{workflowId}/runs/{runId}/plan, returning the plans saved by a plan-only run.
+// It requires the workflow:Read action on the workflow.
+func (h *WorkflowRunHandler) GetPlan(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.READ_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	if run.GetMode() != workflow.PLAN_ONLY {
+		writeError(w, http.StatusConflict, workflow.ErrRunIsNotAPlan)
+		return
+	}
+	plans := []dto.StepPlanDTO{}
+	for _, plan := range run.ListPlans() {
+		plans = append(plans, dto.NewStepPlanDTO(plan))
+	}
+	writeJSON(w, http.StatusOK, plans)
+}
+
+// ApplyPlan handles POST /workflows/{workflowId}/runs/{runId}/apply, confirming a plan-only run.
+// It creates a new run applying exactly the saved plans, with the environment of the plan run and its inputs resolved again,
+// which must still be the inputs the plans were computed with.
+// It requires the workflow:Run action on the workflow and, if the plan run targets an environment, to be able to run the workflow against it.
+func (h *WorkflowRunHandler) ApplyPlan(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil {
+		return
+	}
+	var applyRun *workflow.WorkflowRun
+	var err error
+	if h.users == nil {
+		applyRun, err = wf.ApplyPlan(run.GetIdentifier().ToString())
+	} else {
+		user := currentUser(w, r, h.users)
+		if user == nil {
+			return
+		}
+		if run.GetEnvironment() == nil {
+			if !allowed(w, user, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+				return
+			}
+			applyRun, err = wf.ApplyPlan(run.GetIdentifier().ToString())
+			if err == nil {
+				applyRun.SetStartedBy(user.GetIdentifier())
+			}
+		} else {
+			environment := h.findEnvironment(w, run.GetEnvironment())
+			if environment == nil {
+				return
+			}
+			applyRun, err = environment.ApplyPlan(user, wf, run.GetIdentifier().ToString())
+		}
+	}
+	switch err {
+	case nil:
+	case project.ErrRunNotAllowed:
+		writeError(w, http.StatusForbidden, err)
+		return
+	default:
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewWorkflowRunDTO(applyRun))
+}
+
+// CompleteStep handles POST /workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/complete, through which the runner reports
+// the outputs of a step. The run then waits for an approval if the next step is an approval gate, and steps cannot be reported
+// past an approval gate until it is approved. The values of the secrets referenced by the run inputs are masked in the outputs.
+// It requires the workflow:Run action on the workflow.
+func (h *WorkflowRunHandler) CompleteStep(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	stepNumber, err := strconv.Atoi(mux.Vars(r)["stepNumber"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWorkflowStepNotFound)
+		return
+	}
+	var body dto.CompleteStepDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	masker := h.runMasker(w, wf, run)
+	if masker == nil {
+		return
+	}
+	switch err := wf.CompleteStep(run.GetIdentifier().ToString(), stepNumber, masker.MaskValues(body.Outputs)); err {
+	case nil:
+	case workflow.ErrWorkflowStepNotFound:
+		writeError(w, http.StatusNotFound, err)
+		return
+	default:
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewWorkflowRunDTO(run))
+}
+
+// RecordStepPlan handles POST /workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/plan, through which the runner saves
+// the plan computed for a Terraform or OpenTofu step of a plan-only run. The plan is bound to the inputs of the run, and can
+// only be applied as long as the workflow, the template of the step and these inputs do not change.
+// The values of the secrets referenced by the run inputs are masked in the summary. It requires the workflow:Run action on the workflow.
+func (h *WorkflowRunHandler) RecordStepPlan(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	stepNumber, err := strconv.Atoi(mux.Vars(r)["stepNumber"])
+	if err != nil {
+		writeError(w, http.StatusNotFound, workflow.ErrWorkflowStepNotFound)
+		return
+	}
+	var body dto.RecordStepPlanDTO
+	if err := decodeJSON(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	masker := h.runMasker(w, wf, run)
+	if masker == nil {
+		return
+	}
+	plan, err := wf.RecordStepPlan(run.GetIdentifier().ToString(), stepNumber, body.BinaryPlan, masker.Mask(body.Summary), body.Plan)
+	switch err {
+	case nil:
+	case workflow.ErrWorkflowStepNotFound:
+		writeError(w, http.StatusNotFound, err)
+		return
+	case workflow.ErrInvalidPlanFormat, workflow.ErrPlanUnsupportedTemplateType:
+		writeError(w, http.StatusBadRequest, err)
+		return
+	default:
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.workflows.Update(wf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, dto.NewStepPlanDTO(plan))
+}
+
+// CompleteRun handles POST /workflows/{workflowId}/runs/{runId}/complete, through which the runner reports that every step of
+// the run is done. The outputs of the run are resolved, and the run fails if they are invalid, which is reported with
+// the 422 status code. Successful runs targeting an environment are recorded as its deployment of the workflow.
+// It requires the workflow:Run action on the workflow.
+func (h *WorkflowRunHandler) CompleteRun(w http.ResponseWriter, r *http.Request) {
+	wf, run := findRun(w, r, h.workflows)
+	if run == nil || !authorize(w, r, h.users, permission{wf.GetIdentifier(), policy.RUN_WORKFLOW, wf.ListTags()}) {
+		return
+	}
+	err := wf.CompleteRun(run.GetIdentifier().ToString())
+	if _, invalid := err.(*workflow.AttributeValidationError); err != nil && !invalid {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if updateErr := h.workflows.Update(wf); updateErr != nil {
+		writeError(w, http.StatusInternalServerError, updateErr)
+		return
+	}
+	if err != nil {
+		writeRunError(w, http.StatusConflict, err)
+		return
+	}
+	if err := h.recordDeployment(wf, run); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dto.NewWorkflowRunDTO(run))
+}
+
+// recordDeployment records the workflow version deployed by a successful run in the environment it targets, if any.
+// Nothing is recorded for plan-only runs, or if the environment was deleted since the run started.
+func (h *WorkflowRunHandler) recordDeployment(wf *workflow.Workflow, run *workflow.WorkflowRun) error {
+	if h.projects == nil || run.GetEnvironment() == nil || run.GetMode() == workflow.PLAN_ONLY {
+		return nil
+	}
+	projectId, err := run.GetEnvironment().GetParent()
+	if err != nil {
+		return err
+	}
+	p, err := h.projects.FindById(*projectId)
+	if err != nil || p == nil {
+		return err
+	}
+	switch _, err := p.RecordDeployment(wf, run); err {
+	case nil:
+		return h.projects.Update(p)
+	case project.ErrEnvironmentNotFound:
+		return nil
+	default:
+		return err
+	}
+}
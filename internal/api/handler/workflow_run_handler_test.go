@@ -0,0 +1,432 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/dto"
+	"github.com/gorilla/mux"
+)
+
+func TestWorkflowRunHandlerPlanAndApply(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	terraform, _ := template.NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.SUCCESS, template.TERRAFORM, "/path/to/vpc.zip")
+	step, _ := workflow.NewWorkflowStep(wf.GetIdentifier().ToString(), "network", "", 1, terraform)
+	wf.AddStep(step)
+	planRun, _ := workflow.NewWorkflowRun(wf.GetIdentifier().ToString(), "plan", "")
+	planRun.SetMode(workflow.PLAN_ONLY)
+	wf.AddRun(planRun)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, nil, nil, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/plan", h.RecordStepPlan).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/plan", h.GetPlan).Methods("GET")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/apply", h.ApplyPlan).Methods("POST")
+	path := "/workflows/" + wf.GetIdentifier().ToString() + "/runs/" + planRun.GetIdentifier().ToString()
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path+"/steps/1/plan", strings.NewReader(`{"summary": "", "plan": {"resource_changes": [{"change": {"actions": ["explode"]}}]}}`)))
+	if response.Code != http.StatusBadRequest {
+		t.Errorf("expected %d for an invalid plan, got %d", http.StatusBadRequest, response.Code)
+	}
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path+"/steps/2/plan", strings.NewReader(`{"plan": {"resource_changes": []}}`)))
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected %d for an unknown step, got %d", http.StatusNotFound, response.Code)
+	}
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path+"/steps/1/plan", strings.NewReader(`{"binary_plan": "UEsDBA==", "summary": "Plan: 1 to add, 0 to change, 0 to destroy.", "plan": {"resource_changes": [{"address": "aws_vpc.main", "type": "aws_vpc", "change": {"actions": ["create"]}}]}}`)))
+	if response.Code != http.StatusCreated || len(planRun.GetPlan(1).GetBinaryPlan()) != 4 {
+		t.Fatalf("expected the plan to be recorded, got %d: %s", response.Code, response.Body.String())
+	}
+	planRun.SetStatus(common.SUCCESS)
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path+"/plan", nil))
+	var plans []dto.StepPlanDTO
+	json.NewDecoder(response.Body).Decode(&plans)
+	if response.Code != http.StatusOK || len(plans) != 1 || plans[0].ChangeCounts["create"] != 1 || plans[0].Changes[0].Address != "aws_vpc.main" {
+		t.Errorf("unexpected plan response %d: %+v", response.Code, plans)
+	}
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path+"/apply", nil))
+	var applyRun dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&applyRun)
+	if response.Code != http.StatusCreated || applyRun.Mode != "apply" || applyRun.AppliedPlan == nil || *applyRun.AppliedPlan != planRun.GetIdentifier().ToString() {
+		t.Errorf("unexpected apply response %d: %+v", response.Code, applyRun)
+	}
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path+"/apply", nil))
+	if response.Code != http.StatusConflict {
+		t.Errorf("expected %d, got %d", http.StatusConflict, response.Code)
+	}
+}
+
+func TestWorkflowRunHandlerStartRun(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	replicas, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "replicas", "", workflow.NUMBER, "")
+	region, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "region", "", workflow.STRING, "eu-west-1")
+	regions := common.NewAttributeConstraints()
+	regions.SetAllowedValues([]string{"eu-west-1", "us-east-1"})
+	region.SetConstraints(regions)
+	wf.AddInput(replicas)
+	wf.AddInput(region)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, nil, nil, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs", h.StartRun).Methods("POST")
+	path := "/workflows/" + wf.GetIdentifier().ToString() + "/runs"
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name": "run", "inputs": {"replicas": "many", "zone": "a", "region": "mars"}}`)))
+	var validation dto.ValidationErrorDTO
+	json.NewDecoder(response.Body).Decode(&validation)
+	if response.Code != http.StatusUnprocessableEntity || len(validation.Fields) != 3 || validation.Fields["zone"] == "" || validation.Fields["replicas"] == "" {
+		t.Errorf("unexpected validation response %d: %+v", response.Code, validation)
+	}
+	if len(validation.Violations) != 1 || len(validation.Violations["region"]) != 1 {
+		t.Errorf("expected the region violations to be listed, got %+v", validation.Violations)
+	}
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"name": "run", "mode": "plan", "inputs": {"replicas": 3}}`)))
+	var run dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&run)
+	if response.Code != http.StatusCreated || run.Mode != "plan" || run.Inputs["replicas"] != "3" || run.Inputs["region"] != "eu-west-1" {
+		t.Errorf("unexpected run response %d: %+v", response.Code, run)
+	}
+	if len(wf.ListRuns()) != 1 {
+		t.Errorf("expected 1 run, got %d", len(wf.ListRuns()))
+	}
+}
+
+func TestWorkflowRunHandlerOutputs(t *testing.T) {
+	network, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.yml")
+	vpcId, _ := workflow.NewWorkflowAttribute(network.GetIdentifier().ToString(), "vpc_id", "", workflow.STRING, "vpc-0123")
+	network.AddOutput(vpcId)
+	app, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "app", "", "/path/to/file.yml")
+	appVpc, _ := workflow.NewWorkflowAttribute(app.GetIdentifier().ToString(), "vpc", "", workflow.STRING, "")
+	app.AddInput(appVpc)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(network, app), nil, nil, nil, nil)
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs", h.StartRun).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/outputs", h.GetLatestOutputs).Methods("GET")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/outputs", h.GetOutputs).Methods("GET")
+	reference := `{"name": "run", "inputs": {"vpc": "${` + network.GetIdentifier().ToString() + `.outputs.vpc_id}"}}`
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/workflows/"+network.GetIdentifier().ToString()+"/outputs", nil))
+	if response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/workflows/"+app.GetIdentifier().ToString()+"/runs", strings.NewReader(reference)))
+	if response.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected %d, got %d", http.StatusUnprocessableEntity, response.Code)
+	}
+
+	run, _ := network.StartRun("run", "", workflow.STANDARD, nil)
+	network.CompleteRun(run.GetIdentifier().ToString())
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/workflows/"+network.GetIdentifier().ToString()+"/runs/"+run.GetIdentifier().ToString()+"/outputs", nil))
+	var outputs dto.WorkflowRunOutputsDTO
+	json.NewDecoder(response.Body).Decode(&outputs)
+	if response.Code != http.StatusOK || outputs.Status != "success" || outputs.Outputs["vpc_id"] != "vpc-0123" {
+		t.Errorf("unexpected outputs response %d: %+v", response.Code, outputs)
+	}
+
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/workflows/"+app.GetIdentifier().ToString()+"/runs", strings.NewReader(reference)))
+	var appRun dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&appRun)
+	if response.Code != http.StatusCreated || appRun.Inputs["vpc"] != "vpc-0123" {
+		t.Errorf("unexpected run response %d: %+v", response.Code, appRun)
+	}
+}
+
+func TestWorkflowRunHandlerComplete(t *testing.T) {
+	approver, _ := identity.NewUser("approver@example.com", "approver")
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	apply, _ := workflow.NewWorkflowStep(wf.GetIdentifier().ToString(), "apply", "", 1, nil)
+	gate, _ := workflow.NewApprovalGate([]*common.Identifier{approver.GetIdentifier()}, nil, 1, time.Hour)
+	approval, _ := workflow.NewApprovalStep(wf.GetIdentifier().ToString(), "approve", "", 2, gate)
+	wf.AddStep(apply)
+	wf.AddStep(approval)
+	run, _ := wf.StartRun("run", "", workflow.STANDARD, nil)
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{wf.GetIdentifier().ToString()})
+	runners, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "runners", "", []*policy.PolicyStatement{statement})
+	runner, _ := identity.NewUser("runner@example.com", "runner")
+	runner.AttachPolicy(runners)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, nil, nil, newFakeUserRepository(runner, approver))
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/complete", h.CompleteStep).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/complete", h.CompleteRun).Methods("POST")
+	path := "/workflows/" + wf.GetIdentifier().ToString() + "/runs/" + run.GetIdentifier().ToString()
+	post := func(path string, user *identity.User, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := post(path+"/steps/1/complete", nil, `{}`); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := post(path+"/steps/1/complete", approver, `{}`); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the workflow:Run action, got %d", http.StatusForbidden, response.Code)
+	}
+	if response := post(path+"/steps/9/complete", runner, `{}`); response.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, response.Code)
+	}
+	response := post(path+"/steps/1/complete", runner, `{"outputs": {"vpc_id": "vpc-0123"}}`)
+	var result dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&result)
+	if response.Code != http.StatusOK || result.Status != "waiting" {
+		t.Errorf("expected run to wait on the approval gate, got %d: %+v", response.Code, result)
+	}
+	if response := post(path+"/complete", runner, ""); response.Code != http.StatusConflict {
+		t.Errorf("expected %d for an unapproved run, got %d", http.StatusConflict, response.Code)
+	}
+	if err := wf.SubmitApproval(run.GetIdentifier().ToString(), approver, true, ""); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	response = post(path+"/complete", runner, "")
+	json.NewDecoder(response.Body).Decode(&result)
+	if response.Code != http.StatusOK || result.Status != "success" {
+		t.Errorf("expected run to succeed, got %d: %+v", response.Code, result)
+	}
+}
+
+func TestWorkflowRunHandlerApplyPlanAuthorization(t *testing.T) {
+	p, _ := project.NewProject("project", "")
+	prod, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "prod", "", true)
+	p.AddEnvironment(prod)
+	wf, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "/path/to/file.yml")
+	planRun, _ := wf.StartRun("plan", "", workflow.PLAN_ONLY, nil)
+	planRun.SetEnvironment(prod.GetIdentifier())
+	planRun.SetStatus(common.SUCCESS)
+	newRunner := func(name string, resources ...*common.Identifier) *identity.User {
+		user, _ := identity.NewUser(name+"@example.com", name)
+		statement, _ := policy.NewPolicyStatement(policy.ALLOW, resources, []policy.PolicyAction{policy.RUN_WORKFLOW})
+		runners, _ := policy.NewPolicy(p.GetIdentifier().ToString(), name, "", []*policy.PolicyStatement{statement})
+		user.AttachPolicy(runners)
+		return user
+	}
+	developer := newRunner("developer", wf.GetIdentifier())
+	operator := newRunner("operator", wf.GetIdentifier(), prod.GetIdentifier())
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), newFakeProjectRepository(p), nil, nil, newFakeUserRepository(developer, operator))
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/apply", h.ApplyPlan).Methods("POST")
+	apply := func(user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/workflows/"+wf.GetIdentifier().ToString()+"/runs/"+planRun.GetIdentifier().ToString()+"/apply", nil)
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := apply(nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := apply(developer); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without access to the protected environment, got %d", http.StatusForbidden, response.Code)
+	}
+	response := apply(operator)
+	var applyRun dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&applyRun)
+	if response.Code != http.StatusCreated || applyRun.Environment == nil || *applyRun.Environment != prod.GetIdentifier().ToString() {
+		t.Errorf("unexpected apply response %d: %+v", response.Code, applyRun)
+	}
+	if applyRun.StartedBy == nil || *applyRun.StartedBy != operator.GetIdentifier().ToString() {
+		t.Errorf("expected the apply run to be started by the operator, got %v", applyRun.StartedBy)
+	}
+}
+
+func TestWorkflowRunHandlerStartRunAuthorization(t *testing.T) {
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "deploy", "", "/path/to/file.yml")
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read"}, []string{wf.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "readers", "", []*policy.PolicyStatement{statement})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(readers)
+	statement, _ = policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read", "workflow:Run"}, []string{wf.GetIdentifier().ToString()})
+	runners, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "runners", "", []*policy.PolicyStatement{statement})
+	runner, _ := identity.NewUser("runner@example.com", "runner")
+	runner.AttachPolicy(runners)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, nil, nil, newFakeUserRepository(reader, runner))
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs", h.StartRun).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/outputs", h.GetOutputs).Methods("GET")
+	send := func(method string, path string, user *identity.User) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/workflows/"+wf.GetIdentifier().ToString()+path, strings.NewReader(`{"name": "run"}`))
+		if user != nil {
+			request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	if response := send(http.MethodPost, "/runs", nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodPost, "/runs", reader); response.Code != http.StatusForbidden {
+		t.Errorf("expected %d without the workflow:Run action, got %d", http.StatusForbidden, response.Code)
+	}
+	response := send(http.MethodPost, "/runs", runner)
+	var run dto.WorkflowRunDTO
+	json.NewDecoder(response.Body).Decode(&run)
+	if response.Code != http.StatusCreated || run.StartedBy == nil || *run.StartedBy != runner.GetIdentifier().ToString() {
+		t.Fatalf("expected the run to be started by the runner, got %d: %+v", response.Code, run)
+	}
+	if response := send(http.MethodGet, "/runs/"+run.Identifier+"/outputs", nil); response.Code != http.StatusUnauthorized {
+		t.Errorf("expected %d, got %d", http.StatusUnauthorized, response.Code)
+	}
+	if response := send(http.MethodGet, "/runs/"+run.Identifier+"/outputs", reader); response.Code != http.StatusOK {
+		t.Errorf("expected %d with the workflow:Read action, got %d", http.StatusOK, response.Code)
+	}
+}
+
+func TestWorkflowRunHandlerOutputReferenceAuthorization(t *testing.T) {
+	network, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.yml")
+	vpcId, _ := workflow.NewWorkflowAttribute(network.GetIdentifier().ToString(), "vpc_id", "", workflow.STRING, "vpc-0123")
+	network.AddOutput(vpcId)
+	networkRun, _ := network.StartRun("run", "", workflow.STANDARD, nil)
+	network.CompleteRun(networkRun.GetIdentifier().ToString())
+	other, _ := workflow.NewWorkflow("autops::project:KLMNOPQRST", "network", "", "/path/to/file.yml")
+	other.AddOutput(vpcId)
+	otherRun, _ := other.StartRun("run", "", workflow.STANDARD, nil)
+	other.CompleteRun(otherRun.GetIdentifier().ToString())
+	app, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "app", "", "/path/to/file.yml")
+	appVpc, _ := workflow.NewWorkflowAttribute(app.GetIdentifier().ToString(), "vpc", "", workflow.STRING, "")
+	app.AddInput(appVpc)
+
+	statement, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{app.GetIdentifier().ToString()})
+	runners, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "runners", "", []*policy.PolicyStatement{statement})
+	runner, _ := identity.NewUser("runner@example.com", "runner")
+	runner.AttachPolicy(runners)
+	statement, _ = policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read"}, []string{network.GetIdentifier().ToString(), other.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "readers", "", []*policy.PolicyStatement{statement})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(runners)
+	reader.AttachPolicy(readers)
+
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(network, other, app), nil, nil, nil, newFakeUserRepository(runner, reader))
+	router := mux.NewRouter()
+	router.HandleFunc("/workflows/{workflowId}/runs", h.StartRun).Methods("POST")
+	start := func(user *identity.User, referenced *workflow.Workflow) (*httptest.ResponseRecorder, dto.ValidationErrorDTO) {
+		body := `{"name": "run", "inputs": {"vpc": "${` + referenced.GetIdentifier().ToString() + `.outputs.vpc_id}"}}`
+		request := httptest.NewRequest(http.MethodPost, "/workflows/"+app.GetIdentifier().ToString()+"/runs", strings.NewReader(body))
+		request.Header.Set(USER_HEADER, user.GetIdentifier().ToString())
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		var validation dto.ValidationErrorDTO
+		if response.Code != http.StatusCreated {
+			json.NewDecoder(response.Body).Decode(&validation)
+		}
+		return response, validation
+	}
+
+	response, validation := start(runner, network)
+	if response.Code != http.StatusUnprocessableEntity || validation.Fields["vpc"] != workflow.ErrReferenceNotAllowed.Error() {
+		t.Errorf("expected the reference to be denied without the workflow:Read action, got %d: %+v", response.Code, validation)
+	}
+	response, validation = start(reader, other)
+	if response.Code != http.StatusUnprocessableEntity || validation.Fields["vpc"] != workflow.ErrReferenceOutsideProject.Error() {
+		t.Errorf("expected the reference to another project to be rejected, got %d: %+v", response.Code, validation)
+	}
+	if response, _ := start(reader, network); response.Code != http.StatusCreated {
+		t.Errorf("expected %d with the workflow:Read action, got %d", http.StatusCreated, response.Code)
+	}
+	if len(app.ListRuns()) != 1 || app.ListRuns()[0].GetInputs()["vpc"] != "vpc-0123" {
+		t.Error("expected a single run with the referenced output")
+	}
+}
+
+func TestWorkflowRunHandlerMasksSecrets(t *testing.T) {
+	key, _ := secret.NewMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.MASTER_KEY_LENGTH)))
+	password, _ := secret.NewSecret("autops::project:ABCDEFGHIJ", "db", "", secret.ENV_VAR, "DB_PASSWORD", []byte("hunter2"), key)
+	secrets := newFakeSecretRepository()
+	secrets.Create(password)
+
+	wf, _ := workflow.NewWorkflow("autops::project:ABCDEFGHIJ", "database", "", "/path/to/file.yml")
+	database, _ := template.NewTemplate("autops::project:ABCDEFGHIJ", "database", "", common.SUCCESS, template.TERRAFORM, "/path/to/database.zip")
+	connection, _ := template.NewTemplateAttribute(database.GetIdentifier().ToString(), "connection", "", template.STRING, "")
+	database.AddOutput(connection)
+	step, _ := workflow.NewWorkflowStep(wf.GetIdentifier().ToString(), "database", "", 1, database)
+	wf.AddStep(step)
+	input, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "password", "", workflow.STRING, "")
+	input.SetSensitive(true)
+	wf.AddInput(input)
+	output, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "connection", "", workflow.STRING, "")
+	wf.AddOutput(output)
+	wf.BindOutput("connection", 1, "connection")
+	inputs := map[string]string{"password": secret.FormatReference(password.GetIdentifier())}
+	run, _ := wf.StartRun("run", "", workflow.STANDARD, inputs)
+	planRun, _ := wf.StartRun("plan", "", workflow.PLAN_ONLY, inputs)
+
+	router := mux.NewRouter()
+	unmasked := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, nil, nil, nil)
+	router.HandleFunc("/unmasked/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/complete", unmasked.CompleteStep).Methods("POST")
+	h := NewWorkflowRunHandler(newFakeWorkflowRepository(wf), nil, secrets, key, nil)
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/complete", h.CompleteStep).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/plan", h.RecordStepPlan).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/complete", h.CompleteRun).Methods("POST")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/outputs", h.GetOutputs).Methods("GET")
+	router.HandleFunc("/workflows/{workflowId}/runs/{runId}/plan", h.GetPlan).Methods("GET")
+	path := "/workflows/" + wf.GetIdentifier().ToString() + "/runs/"
+	send := func(method string, path string, body string) *httptest.ResponseRecorder {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return response
+	}
+
+	if response := send(http.MethodPost, "/unmasked"+path+run.GetIdentifier().ToString()+"/steps/1/complete", `{"outputs": {"connection": "postgres://admin:hunter2@db"}}`); response.Code != http.StatusInternalServerError {
+		t.Errorf("expected %d when the secrets cannot be resolved, got %d", http.StatusInternalServerError, response.Code)
+	}
+	if response := send(http.MethodPost, path+run.GetIdentifier().ToString()+"/steps/1/complete", `{"outputs": {"connection": "postgres://admin:hunter2@db"}}`); response.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	if response := send(http.MethodPost, path+run.GetIdentifier().ToString()+"/complete", ``); response.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+	response := send(http.MethodGet, path+run.GetIdentifier().ToString()+"/outputs", ``)
+	var outputs dto.WorkflowRunOutputsDTO
+	json.NewDecoder(response.Body).Decode(&outputs)
+	if outputs.Outputs["connection"] != "postgres://admin:"+secret.MASKED_VALUE+"@db" || run.GetStepOutputs(1)["connection"] != outputs.Outputs["connection"] {
+		t.Errorf("expected the secret value to be masked, got %v", outputs.Outputs)
+	}
+
+	if response := send(http.MethodPost, path+planRun.GetIdentifier().ToString()+"/steps/1/plan", `{"summary": "password = hunter2", "plan": {"resource_changes": []}}`); response.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d", http.StatusCreated, response.Code)
+	}
+	response = send(http.MethodGet, path+planRun.GetIdentifier().ToString()+"/plan", ``)
+	var plans []dto.StepPlanDTO
+	json.NewDecoder(response.Body).Decode(&plans)
+	if len(plans) != 1 || plans[0].Summary != "password = "+secret.MASKED_VALUE {
+		t.Errorf("expected the secret value to be masked in the plan summary, got %+v", plans)
+	}
+}
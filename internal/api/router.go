@@ -3,11 +3,182 @@ package api
 import (
 	"net/http"
 
+	"github.com/AutOpsProject/AutOps-API/internal/api/handler"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/gitops"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
 	"github.com/gorilla/mux"
 )
 
-func SetupRouter() http.Handler {
+// Dependencies groups the configuration and repositories required by the HTTP handlers.
+// Routes whose repositories are not provided are not registered.
+// When Users is provided, the policy, schema, version, manifest, secret, environment, workflow run, approval, webhook trigger and GitOps routes,
+// the changes to groups and roles, and the sessions of roles require the authenticated user to be allowed each request.
+// Policy simulations and lints then also require an authenticated user.
+type Dependencies struct {
+	BaseURL   string
+	Projects  project.ProjectRepository
+	Templates template.TemplateRepository
+	Workflows workflow.WorkflowRepository
+	Users     identity.UserRepository
+	// Groups stores the groups of users. When provided, the policies of the groups of a user apply to its requests.
+	Groups identity.GroupRepository
+	// Roles and RoleSessions store the roles assumed by users and the audit records of their sessions.
+	// When both are provided, requests can be authenticated with the credentials of a role session.
+	Roles        identity.RoleRepository
+	RoleSessions identity.RoleSessionRepository
+	// Secrets and MasterKey store and decrypt the secrets of projects. They also mask the secret inputs of runs in what their steps report.
+	Secrets   secret.SecretRepository
+	MasterKey *secret.MasterKey
+	// Sources loads the source content of templates and workflows to compare their versions, and to record the content of the versions imported from manifests.
+	// Version diffs only compare attributes when it is not provided, or when it cannot load the sources of the versions.
+	Sources *versioning.SourceLoader
+	// Reconciler syncs projects from a git repository of manifests. The GitOps routes are only registered when it is provided.
+	Reconciler *gitops.Reconciler
+}
+
+func SetupRouter(deps Dependencies) http.Handler {
 	r := mux.NewRouter()
+	if deps.Users != nil && deps.Groups != nil {
+		deps.Users = identity.WithGroupMemberships(deps.Users, deps.Groups)
+	}
+	if deps.Users != nil && deps.Roles != nil && deps.RoleSessions != nil {
+		deps.Users = identity.WithRoleSessions(deps.Users, deps.Roles, deps.RoleSessions)
+	}
 	// r.HandleFunc("/project", handler.CreateProject).Methods("POST")
+	if deps.Workflows != nil {
+		webhooks := handler.NewWebhookHandler(deps.Workflows, deps.Users, deps.BaseURL)
+		r.HandleFunc("/workflows/{workflowId}/triggers", webhooks.CreateTrigger).Methods("POST")
+		r.HandleFunc("/workflows/{workflowId}/triggers", webhooks.ListTriggers).Methods("GET")
+		r.HandleFunc("/workflows/{workflowId}/triggers/{triggerId}", webhooks.DeleteTrigger).Methods("DELETE")
+		r.HandleFunc("/webhooks/{triggerId}", webhooks.Receive).Methods("POST")
+
+		runs := handler.NewWorkflowRunHandler(deps.Workflows, deps.Projects, deps.Secrets, deps.MasterKey, deps.Users)
+		r.HandleFunc("/workflows/{workflowId}/runs", runs.StartRun).Methods("POST")
+		r.HandleFunc("/workflows/{workflowId}/outputs", runs.GetLatestOutputs).Methods("GET")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/outputs", runs.GetOutputs).Methods("GET")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/plan", runs.GetPlan).Methods("GET")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/apply", runs.ApplyPlan).Methods("POST")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/complete", runs.CompleteStep).Methods("POST")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/steps/{stepNumber}/plan", runs.RecordStepPlan).Methods("POST")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/complete", runs.CompleteRun).Methods("POST")
+	}
+	if deps.Workflows != nil && deps.Users != nil {
+		approvals := handler.NewApprovalHandler(deps.Workflows, deps.Users)
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/approvals", approvals.ListApprovals).Methods("GET")
+		r.HandleFunc("/workflows/{workflowId}/runs/{runId}/approvals", approvals.SubmitApproval).Methods("POST")
+	}
+	if deps.Projects != nil && deps.Workflows != nil && deps.Users != nil {
+		environments := handler.NewEnvironmentHandler(deps.Projects, deps.Workflows, deps.Users)
+		r.HandleFunc("/projects/{projectId}/environments", environments.CreateEnvironment).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/environments", environments.ListEnvironments).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}", environments.GetEnvironment).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}", environments.DeleteEnvironment).Methods("DELETE")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/variables", environments.SetVariables).Methods("PUT")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/policies/{policyId}", environments.AttachPolicy).Methods("PUT")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/policies/{policyId}", environments.DetachPolicy).Methods("DELETE")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/runs", environments.StartRun).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/deployments", environments.ListDeployments).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/environments/{environmentId}/promote", environments.Promote).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/pipeline", environments.GetPipeline).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/pipeline", environments.SetPipeline).Methods("PUT")
+	}
+	if deps.Templates != nil || deps.Workflows != nil {
+		versions := handler.NewVersionHandler(deps.Templates, deps.Workflows, deps.Sources, deps.Secrets, deps.MasterKey, deps.Users)
+		if deps.Templates != nil {
+			r.HandleFunc("/templates/{templateId}/versions", versions.ListTemplateVersions).Methods("GET")
+			r.HandleFunc("/templates/{templateId}/diff", versions.DiffTemplateVersions).Methods("GET")
+		}
+		if deps.Workflows != nil {
+			r.HandleFunc("/workflows/{workflowId}/versions", versions.ListWorkflowVersions).Methods("GET")
+			r.HandleFunc("/workflows/{workflowId}/diff", versions.DiffWorkflowVersions).Methods("GET")
+		}
+	}
+	if deps.Templates != nil || deps.Workflows != nil {
+		schemas := handler.NewSchemaHandler(deps.Templates, deps.Workflows, deps.Users)
+		if deps.Templates != nil {
+			r.HandleFunc("/templates/{templateId}/schema", schemas.GetTemplateSchema).Methods("GET")
+			r.HandleFunc("/templates/{templateId}/schema", schemas.CreateTemplateInputs).Methods("POST")
+		}
+		if deps.Workflows != nil {
+			r.HandleFunc("/workflows/{workflowId}/schema", schemas.GetWorkflowSchema).Methods("GET")
+			r.HandleFunc("/workflows/{workflowId}/schema", schemas.CreateWorkflowInputs).Methods("POST")
+		}
+	}
+	if deps.Projects != nil {
+		policies := handler.NewPolicyHandler(deps.Projects, deps.Users)
+		r.HandleFunc("/projects/{projectId}/policies", policies.CreatePolicy).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/policies", policies.ListPolicies).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/policies/{policyId}", policies.GetPolicy).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/policies/{policyId}", policies.UpdatePolicy).Methods("PUT")
+		r.HandleFunc("/projects/{projectId}/policies/{policyId}", policies.DeletePolicy).Methods("DELETE")
+
+		manifests := handler.NewManifestHandler(deps.Projects, deps.Templates, deps.Workflows, deps.Sources, deps.Secrets, deps.MasterKey, deps.Users)
+		r.HandleFunc("/projects/import", manifests.ImportProject).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/export", manifests.ExportProject).Methods("GET")
+	}
+	if deps.Users != nil && deps.Groups != nil {
+		groups := handler.NewGroupHandler(deps.Groups, deps.Users, deps.Projects)
+		r.HandleFunc("/groups", groups.CreateGroup).Methods("POST")
+		r.HandleFunc("/groups", groups.ListGroups).Methods("GET")
+		r.HandleFunc("/groups/{groupId}", groups.GetGroup).Methods("GET")
+		r.HandleFunc("/groups/{groupId}", groups.DeleteGroup).Methods("DELETE")
+		r.HandleFunc("/groups/{groupId}/members/{userId}", groups.AddMember).Methods("PUT")
+		r.HandleFunc("/groups/{groupId}/members/{userId}", groups.RemoveMember).Methods("DELETE")
+		if deps.Projects != nil {
+			r.HandleFunc("/groups/{groupId}/policies/{policyId}", groups.AttachPolicy).Methods("PUT")
+			r.HandleFunc("/groups/{groupId}/policies/{policyId}", groups.DetachPolicy).Methods("DELETE")
+		}
+		r.HandleFunc("/users/{userId}/policies", groups.ListEffectivePolicies).Methods("GET")
+	}
+	if deps.Users != nil && deps.Projects != nil {
+		users := handler.NewUserHandler(deps.Users, deps.Projects)
+		r.HandleFunc("/users/{userId}/policies/{policyId}", users.AttachPolicy).Methods("PUT")
+		r.HandleFunc("/users/{userId}/policies/{policyId}", users.DetachPolicy).Methods("DELETE")
+		r.HandleFunc("/users/{userId}/boundary", users.GetBoundary).Methods("GET")
+		r.HandleFunc("/users/{userId}/boundary/{policyId}", users.SetBoundary).Methods("PUT")
+		r.HandleFunc("/users/{userId}/boundary", users.RemoveBoundary).Methods("DELETE")
+		r.HandleFunc("/users/{userId}/tags", users.SetTags).Methods("PUT")
+
+		reports := handler.NewAccessReportHandler(deps.Projects, deps.Users, deps.Groups, deps.Roles)
+		r.HandleFunc("/projects/{projectId}/users/{userId}/permissions", reports.GetUserReport).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/resources/{resourceId}/principals", reports.GetResourceReport).Methods("GET")
+	}
+	if deps.Users != nil && deps.Roles != nil && deps.RoleSessions != nil {
+		roles := handler.NewRoleHandler(deps.Roles, deps.RoleSessions, deps.Users, deps.Projects)
+		r.HandleFunc("/roles", roles.CreateRole).Methods("POST")
+		r.HandleFunc("/roles", roles.ListRoles).Methods("GET")
+		r.HandleFunc("/roles/{roleId}", roles.GetRole).Methods("GET")
+		r.HandleFunc("/roles/{roleId}", roles.DeleteRole).Methods("DELETE")
+		r.HandleFunc("/roles/{roleId}/trust/{principalId}", roles.TrustPrincipal).Methods("PUT")
+		r.HandleFunc("/roles/{roleId}/trust/{principalId}", roles.UntrustPrincipal).Methods("DELETE")
+		if deps.Projects != nil {
+			r.HandleFunc("/roles/{roleId}/policies/{policyId}", roles.AttachPolicy).Methods("PUT")
+			r.HandleFunc("/roles/{roleId}/policies/{policyId}", roles.DetachPolicy).Methods("DELETE")
+		}
+		r.HandleFunc("/roles/{roleId}/assume", roles.AssumeRole).Methods("POST")
+		r.HandleFunc("/roles/{roleId}/sessions", roles.ListSessions).Methods("GET")
+	}
+	simulations := handler.NewSimulationHandler(deps.Users)
+	r.HandleFunc("/policies/simulate", simulations.Simulate).Methods("POST")
+	lints := handler.NewLintHandler(deps.Projects, deps.Users, deps.Groups)
+	r.HandleFunc("/policies/lint", lints.Lint).Methods("POST")
+	if deps.Reconciler != nil {
+		syncs := handler.NewGitOpsHandler(deps.Reconciler, deps.Users)
+		r.HandleFunc("/gitops/syncs", syncs.ListSyncs).Methods("GET")
+		r.HandleFunc("/gitops/sync", syncs.Sync).Methods("POST")
+	}
+	if deps.Secrets != nil && deps.MasterKey != nil {
+		secrets := handler.NewSecretHandler(deps.Secrets, deps.MasterKey, deps.Users)
+		r.HandleFunc("/projects/{projectId}/secrets", secrets.CreateSecret).Methods("POST")
+		r.HandleFunc("/projects/{projectId}/secrets", secrets.ListSecrets).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/secrets/{secretId}", secrets.GetSecret).Methods("GET")
+		r.HandleFunc("/projects/{projectId}/secrets/{secretId}", secrets.DeleteSecret).Methods("DELETE")
+		r.HandleFunc("/projects/{projectId}/secrets/{secretId}/value", secrets.RotateSecret).Methods("PUT")
+	}
 	return r
 }
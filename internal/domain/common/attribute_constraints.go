@@ -0,0 +1,346 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConstraintViolationError lists every constraint of an attribute that a value violates.
+type ConstraintViolationError struct {
+	violations []string
+}
+
+// Error returns the violations separated by semicolons.
+func (e *ConstraintViolationError) Error() string {
+	return strings.Join(e.violations, "; ")
+}
+
+// ListViolations returns the description of each violated constraint (e.g. "must be at most 10").
+func (e *ConstraintViolationError) ListViolations() []string {
+	return append([]string(nil), e.violations...)
+}
+
+// AttributeConstraints declares the constraints the values of an attribute must satisfy, in addition to its type.
+// Each constraint only applies to some attribute types:
+//   - required (any type): the value must not be empty
+//   - allowed values (string, number): the value must be one of them
+//   - pattern, minimum and maximum length (string)
+//   - minimum and maximum (number)
+//   - minimum and maximum items, and element type (list)
+//   - JSON Schema (object)
+type AttributeConstraints struct {
+	required      bool
+	allowedValues []string
+	pattern       *regexp.Regexp
+	minLength     *int
+	maxLength     *int
+	minimum       *float64
+	maximum       *float64
+	minItems      *int
+	maxItems      *int
+	elementType   *AttributeType
+	schema        *JSONSchema
+}
+
+// NewAttributeConstraints creates AttributeConstraints without any constraint.
+func NewAttributeConstraints() *AttributeConstraints {
+	return &AttributeConstraints{}
+}
+
+// IsRequired returns true if the value must not be empty.
+func (c *AttributeConstraints) IsRequired() bool {
+	return c.required
+}
+
+// SetRequired sets whether the value must not be empty.
+func (c *AttributeConstraints) SetRequired(required bool) {
+	c.required = required
+}
+
+// ListAllowedValues returns the values the attribute is restricted to, or an empty list if it is not restricted.
+func (c *AttributeConstraints) ListAllowedValues() []string {
+	return append([]string(nil), c.allowedValues...)
+}
+
+// SetAllowedValues restricts the attribute to the given values, or removes the restriction if empty.
+func (c *AttributeConstraints) SetAllowedValues(values []string) {
+	c.allowedValues = nil
+	for _, value := range values {
+		c.allowedValues = append(c.allowedValues, strings.TrimSpace(value))
+	}
+}
+
+// GetPattern returns the regular expression the value must match, or an empty string if none.
+func (c *AttributeConstraints) GetPattern() string {
+	if c.pattern == nil {
+		return ""
+	}
+	return c.pattern.String()
+}
+
+// SetPattern sets the regular expression (RE2 syntax) the value must match, or removes it if empty.
+// The expression is not anchored: use ^ and $ to match the whole value.
+// Returns ErrInvalidConstraint if the expression is invalid.
+func (c *AttributeConstraints) SetPattern(pattern string) error {
+	if pattern == "" {
+		c.pattern = nil
+		return nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return ErrInvalidConstraint
+	}
+	c.pattern = compiled
+	return nil
+}
+
+// GetLength returns the minimum and maximum number of characters of the value, nil when unbounded.
+func (c *AttributeConstraints) GetLength() (*int, *int) {
+	return c.minLength, c.maxLength
+}
+
+// SetLength bounds the number of characters of the value. A nil bound removes it.
+// Returns ErrInvalidConstraint if a bound is negative or if the minimum exceeds the maximum.
+func (c *AttributeConstraints) SetLength(minLength *int, maxLength *int) error {
+	if !validCountBounds(minLength, maxLength) {
+		return ErrInvalidConstraint
+	}
+	c.minLength, c.maxLength = minLength, maxLength
+	return nil
+}
+
+// GetRange returns the minimum and maximum of the value, nil when unbounded.
+func (c *AttributeConstraints) GetRange() (*float64, *float64) {
+	return c.minimum, c.maximum
+}
+
+// SetRange bounds the value, both bounds being inclusive. A nil bound removes it.
+// Returns ErrInvalidConstraint if the minimum exceeds the maximum.
+func (c *AttributeConstraints) SetRange(minimum *float64, maximum *float64) error {
+	if minimum != nil && maximum != nil && *minimum > *maximum {
+		return ErrInvalidConstraint
+	}
+	c.minimum, c.maximum = minimum, maximum
+	return nil
+}
+
+// GetItems returns the minimum and maximum number of items of the value, nil when unbounded.
+func (c *AttributeConstraints) GetItems() (*int, *int) {
+	return c.minItems, c.maxItems
+}
+
+// SetItems bounds the number of items of the value. A nil bound removes it.
+// Returns ErrInvalidConstraint if a bound is negative or if the minimum exceeds the maximum.
+func (c *AttributeConstraints) SetItems(minItems *int, maxItems *int) error {
+	if !validCountBounds(minItems, maxItems) {
+		return ErrInvalidConstraint
+	}
+	c.minItems, c.maxItems = minItems, maxItems
+	return nil
+}
+
+// GetElementType returns the type of the items of the value, or nil if they can have any type.
+func (c *AttributeConstraints) GetElementType() *AttributeType {
+	return c.elementType
+}
+
+// SetElementType sets the type of the items of the value, or removes it if nil.
+// Returns ErrInvalidConstraint if the type is unknown.
+func (c *AttributeConstraints) SetElementType(elementType *AttributeType) error {
+	if elementType != nil && !elementType.IsValid() {
+		return ErrInvalidConstraint
+	}
+	c.elementType = elementType
+	return nil
+}
+
+// GetSchema returns the JSON Schema the value must satisfy, or nil if none.
+func (c *AttributeConstraints) GetSchema() *JSONSchema {
+	return c.schema
+}
+
+// SetSchema sets the JSON Schema the value must satisfy, or removes it if nil.
+func (c *AttributeConstraints) SetSchema(schema *JSONSchema) {
+	c.schema = schema
+}
+
+// IsEmpty returns true if no constraint is declared.
+func (c *AttributeConstraints) IsEmpty() bool {
+	return !c.required && len(c.allowedValues) == 0 && c.pattern == nil && c.minLength == nil && c.maxLength == nil &&
+		c.minimum == nil && c.maximum == nil && c.minItems == nil && c.maxItems == nil && c.elementType == nil && c.schema == nil
+}
+
+// CheckApplicable verifies that every declared constraint applies to the given attribute type.
+// Returns ErrConstraintNotApplicable otherwise.
+func (c *AttributeConstraints) CheckApplicable(attributeType AttributeType) error {
+	applicable := map[string]bool{
+		"allowed values": attributeType == STRING || attributeType == NUMBER,
+		"pattern":        attributeType == STRING,
+		"length":         attributeType == STRING,
+		"range":          attributeType == NUMBER,
+		"items":          attributeType == LIST,
+		"element type":   attributeType == LIST,
+		"schema":         attributeType == OBJECT,
+	}
+	declared := map[string]bool{
+		"allowed values": len(c.allowedValues) > 0,
+		"pattern":        c.pattern != nil,
+		"length":         c.minLength != nil || c.maxLength != nil,
+		"range":          c.minimum != nil || c.maximum != nil,
+		"items":          c.minItems != nil || c.maxItems != nil,
+		"element type":   c.elementType != nil,
+		"schema":         c.schema != nil,
+	}
+	for constraint, isDeclared := range declared {
+		if isDeclared && !applicable[constraint] {
+			return ErrConstraintNotApplicable
+		}
+	}
+	for _, allowed := range c.allowedValues {
+		if attributeType == NUMBER && NUMBER.Validate(allowed) != nil {
+			return ErrInvalidConstraint
+		}
+	}
+	return nil
+}
+
+// Validate checks a value, already validated against the given attribute type, against every declared constraint.
+// Returns a ConstraintViolationError listing every violated constraint, or nil if the value satisfies them.
+func (c *AttributeConstraints) Validate(attributeType AttributeType, value string) error {
+	value = strings.TrimSpace(value)
+	violations := []string{}
+	if value == "" {
+		if c.required {
+			violations = append(violations, "a value is required")
+		}
+		return newConstraintViolationError(violations)
+	}
+	switch attributeType {
+	case STRING:
+		if len(c.allowedValues) > 0 && !containsString(c.allowedValues, value) {
+			violations = append(violations, allowedValuesMessage(c.allowedValues))
+		}
+		if c.pattern != nil && !c.pattern.MatchString(value) {
+			violations = append(violations, patternMessage(c.pattern.String()))
+		}
+		violations = append(violations, countMessages(len([]rune(value)), c.minLength, c.maxLength, "characters")...)
+	case NUMBER:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			break
+		}
+		if len(c.allowedValues) > 0 && !containsNumber(c.allowedValues, number) {
+			violations = append(violations, allowedValuesMessage(c.allowedValues))
+		}
+		violations = append(violations, boundMessages(number, c.minimum, c.maximum)...)
+	case LIST:
+		var items []any
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			break
+		}
+		violations = append(violations, countMessages(len(items), c.minItems, c.maxItems, "items")...)
+		if c.elementType != nil {
+			for i, item := range items {
+				if !matchesAttributeType(*c.elementType, item) {
+					violations = append(violations, fmt.Sprintf("item %d must be %s", i, articleFor(c.elementType.ToString())))
+				}
+			}
+		}
+	case OBJECT:
+		var object any
+		if c.schema == nil || json.Unmarshal([]byte(value), &object) != nil {
+			break
+		}
+		violations = append(violations, c.schema.Validate(object)...)
+	}
+	return newConstraintViolationError(violations)
+}
+
+// newConstraintViolationError returns a ConstraintViolationError for the violations, or nil if there are none.
+func newConstraintViolationError(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ConstraintViolationError{violations: violations}
+}
+
+// matchesAttributeType returns true if a decoded JSON item has the given attribute type.
+func matchesAttributeType(attributeType AttributeType, item any) bool {
+	switch item.(type) {
+	case string:
+		return attributeType == STRING
+	case float64:
+		return attributeType == NUMBER
+	case bool:
+		return attributeType == BOOL
+	case []any:
+		return attributeType == LIST
+	case map[string]any:
+		return attributeType == OBJECT
+	default:
+		return false
+	}
+}
+
+// validCountBounds returns true if both bounds are non-negative and ordered.
+func validCountBounds(minimum *int, maximum *int) bool {
+	if (minimum != nil && *minimum < 0) || (maximum != nil && *maximum < 0) {
+		return false
+	}
+	return minimum == nil || maximum == nil || *minimum <= *maximum
+}
+
+// containsString returns true if the value is one of the values.
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsNumber returns true if the number equals one of the values, compared numerically.
+func containsNumber(values []string, number float64) bool {
+	for _, candidate := range values {
+		if parsed, err := strconv.ParseFloat(candidate, 64); err == nil && parsed == number {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedValuesMessage, patternMessage, countMessages and boundMessages format the violations
+// shared by AttributeConstraints and JSONSchema, so that both report them identically.
+func allowedValuesMessage(values []string) string {
+	return "must be one of: " + strings.Join(values, ", ")
+}
+
+func patternMessage(pattern string) string {
+	return "must match the pattern " + pattern
+}
+
+func countMessages(count int, minimum *int, maximum *int, unit string) []string {
+	messages := []string{}
+	if minimum != nil && count < *minimum {
+		messages = append(messages, fmt.Sprintf("must have at least %d %s", *minimum, unit))
+	}
+	if maximum != nil && count > *maximum {
+		messages = append(messages, fmt.Sprintf("must have at most %d %s", *maximum, unit))
+	}
+	return messages
+}
+
+func boundMessages(number float64, minimum *float64, maximum *float64) []string {
+	messages := []string{}
+	if minimum != nil && number < *minimum {
+		messages = append(messages, "must be at least "+strconv.FormatFloat(*minimum, 'f', -1, 64))
+	}
+	if maximum != nil && number > *maximum {
+		messages = append(messages, "must be at most "+strconv.FormatFloat(*maximum, 'f', -1, 64))
+	}
+	return messages
+}
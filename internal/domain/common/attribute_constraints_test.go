@@ -0,0 +1,126 @@
+package common
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAttributeConstraintsValidate(t *testing.T) {
+	one, three := 1, 3
+	minimum, maximum := 1.0, 65535.0
+
+	constraints := NewAttributeConstraints()
+	constraints.SetRequired(true)
+	constraints.SetAllowedValues([]string{"eu-west-1", "us-east-1"})
+	if err := constraints.SetPattern("^[a-z]+-[a-z]+-[0-9]$"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	constraints.SetLength(nil, &three)
+	if err := constraints.Validate(STRING, "eu-west-1"); err == nil {
+		t.Error("expected the length to be violated")
+	}
+	err := constraints.Validate(STRING, "EU")
+	var violationErr *ConstraintViolationError
+	if !errors.As(err, &violationErr) {
+		t.Fatalf("expected err to be a ConstraintViolationError, got %v", err)
+	}
+	expected := []string{"must be one of: eu-west-1, us-east-1", "must match the pattern ^[a-z]+-[a-z]+-[0-9]$"}
+	if !reflect.DeepEqual(violationErr.ListViolations(), expected) {
+		t.Errorf("expected every violation to be listed, got %v", violationErr.ListViolations())
+	}
+	if err := constraints.Validate(STRING, " "); err == nil || err.Error() != "a value is required" {
+		t.Errorf("expected a value to be required, got %v", err)
+	}
+
+	constraints = NewAttributeConstraints()
+	constraints.SetRange(&minimum, &maximum)
+	constraints.SetAllowedValues([]string{"80", "443"})
+	if err := constraints.Validate(NUMBER, "443.0"); err != nil {
+		t.Errorf("expected allowed numbers to be compared numerically, got %v", err)
+	}
+	if err := constraints.Validate(NUMBER, "0"); err == nil || err.Error() != "must be one of: 80, 443; must be at least 1" {
+		t.Errorf("unexpected violations %v", err)
+	}
+
+	constraints = NewAttributeConstraints()
+	constraints.SetItems(&one, &three)
+	number := NUMBER
+	constraints.SetElementType(&number)
+	if err := constraints.Validate(LIST, `[1, "a", 3, 4]`); err == nil || err.Error() != "must have at most 3 items; item 1 must be a number" {
+		t.Errorf("unexpected violations %v", err)
+	}
+
+	schema, _ := ParseJSONSchema(`{"type": "object", "required": ["name"], "properties": {"port": {"type": "integer", "maximum": 65535}}}`)
+	constraints = NewAttributeConstraints()
+	constraints.SetSchema(schema)
+	if err := constraints.Validate(OBJECT, `{"port": 70000}`); err == nil || err.Error() != `$: missing required property "name"; $.port: must be at most 65535` {
+		t.Errorf("unexpected violations %v", err)
+	}
+}
+
+func TestAttributeConstraintsSetters(t *testing.T) {
+	one, two, negative := 1, 2, -1
+	low, high := 1.0, 2.0
+	constraints := NewAttributeConstraints()
+	if !constraints.IsEmpty() {
+		t.Error("expected new constraints to be empty")
+	}
+	if err := constraints.SetPattern("[a-"); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+	if err := constraints.SetLength(&two, &one); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+	if err := constraints.SetItems(&negative, nil); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+	if err := constraints.SetRange(&high, &low); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+	invalid := AttributeType(100)
+	if err := constraints.SetElementType(&invalid); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+
+	constraints.SetItems(&one, nil)
+	if err := constraints.CheckApplicable(STRING); err != ErrConstraintNotApplicable {
+		t.Errorf("expected err to be ErrConstraintNotApplicable, got %v", err)
+	}
+	if err := constraints.CheckApplicable(LIST); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	constraints = NewAttributeConstraints()
+	constraints.SetAllowedValues([]string{"one"})
+	if err := constraints.CheckApplicable(NUMBER); err != ErrInvalidConstraint {
+		t.Errorf("expected err to be ErrInvalidConstraint, got %v", err)
+	}
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	schema, err := ParseJSONSchema(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"tags": {"type": "array", "minItems": 1, "items": {"type": "string", "pattern": "^[a-z]+$"}},
+			"tier": {"enum": ["free", "pro"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	violations := schema.Validate(map[string]any{"tags": []any{"a", "B"}, "tier": "gold", "other": true})
+	expected := []string{"$.other: additional properties are not allowed", "$.tags[1]: must match the pattern ^[a-z]+$", `$.tier: must be one of: "free", "pro"`}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("unexpected violations %v", violations)
+	}
+	if violations := schema.Validate([]any{}); len(violations) != 1 || violations[0] != "$: must be an object" {
+		t.Errorf("unexpected violations %v", violations)
+	}
+
+	for _, invalid := range []string{`[]`, `{"type": "date"}`, `{"oneOf": []}`, `{"minItems": -1}`, `{"properties": {"a": 1}}`} {
+		if _, err := ParseJSONSchema(invalid); !errors.Is(err, ErrInvalidJSONSchema) {
+			t.Errorf("expected err to be ErrInvalidJSONSchema for %s, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// AttributeType defines the type of value held by a template or workflow attribute.
+// Values are always carried as strings, and the type determines how they are validated and decoded.
+//
+// The supported types are:
+//   - STRING: a plain string
+//   - NUMBER: a numeric value (integer or float)
+//   - LIST: a JSON array of values (e.g., ["a", 1, {"k":"v"}])
+//   - BOOL: a boolean value ("true" or "false")
+//   - OBJECT: a JSON object (e.g., {"key": "value"})
+//
+// The ordinals are persisted with workflow attributes and must not change.
+type AttributeType int
+
+const (
+	STRING AttributeType = iota
+	NUMBER
+	LIST
+	BOOL
+	OBJECT
+)
+
+// ToString returns the string representation of the AttributeType, or "unknown" for an invalid type.
+func (t AttributeType) ToString() string {
+	switch t {
+	case STRING:
+		return "string"
+	case NUMBER:
+		return "number"
+	case BOOL:
+		return "bool"
+	case LIST:
+		return "list"
+	case OBJECT:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// IsValid returns true if the AttributeType is one of the supported types.
+func (t AttributeType) IsValid() bool {
+	return t >= STRING && t <= OBJECT
+}
+
+// ParseAttributeType converts a string ("string", "number", "bool", "list" or "object", case-insensitive) into an AttributeType.
+// Returns ErrInvalidAttributeType if the string does not match a known type.
+func ParseAttributeType(str string) (AttributeType, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "string":
+		return STRING, nil
+	case "number":
+		return NUMBER, nil
+	case "bool":
+		return BOOL, nil
+	case "list":
+		return LIST, nil
+	case "object":
+		return OBJECT, nil
+	default:
+		return -1, ErrInvalidAttributeType
+	}
+}
+
+// Validate checks that a value is valid for the type.
+//   - For STRING: any string is accepted.
+//   - For NUMBER: the value must be a valid float or integer.
+//   - For BOOL: the value must be "true" or "false".
+//   - For OBJECT: the value must be valid JSON of type object.
+//   - For LIST: the value must be valid JSON of type array, and no element must be nil.
+//
+// Returns an error if the value is invalid for the type.
+func (t AttributeType) Validate(value string) error {
+	value = strings.TrimSpace(value)
+	switch t {
+	case STRING:
+		return nil
+
+	case NUMBER:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return ErrInvalidNumber
+		}
+
+	case BOOL:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return ErrInvalidBool
+		}
+
+	case OBJECT:
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &obj); err != nil || obj == nil {
+			return ErrInvalidObjectFormat
+		}
+
+	case LIST:
+		var rawList []interface{}
+		if err := json.Unmarshal([]byte(value), &rawList); err != nil || rawList == nil {
+			return ErrInvalidListFormat
+		}
+
+		for _, item := range rawList {
+			if item == nil {
+				return ErrEmptyItemInList
+			}
+		}
+
+	default:
+		return ErrInvalidAttributeType
+	}
+	return nil
+}
+
+// IsAssignableTo returns true if any value of the type can be used where a value of the target type is expected,
+// e.g. to bind a template NUMBER output to a workflow STRING output.
+// Values of the same type are assignable, and every value can be assigned to STRING through its string representation.
+// Other conversions, like STRING to NUMBER, depend on the value (see Coerce).
+func (t AttributeType) IsAssignableTo(target AttributeType) bool {
+	return t.IsValid() && target.IsValid() && (t == target || target == STRING)
+}
+
+// Coerce converts a value of the type to the target type, returning its canonical representation:
+// booleans are "true" or "false", and lists and objects are compact JSON documents.
+// Besides assignable types (see IsAssignableTo), a STRING value is converted if it is valid for the target type (e.g. "3" to NUMBER).
+//
+// Returns an error if the value is invalid for the type, or ErrIncompatibleValue if it cannot be converted.
+func (t AttributeType) Coerce(value string, target AttributeType) (string, error) {
+	if err := t.Validate(value); err != nil {
+		return "", err
+	}
+	switch {
+	case !target.IsValid():
+		return "", ErrInvalidAttributeType
+	case t == STRING && target == STRING:
+		return value, nil
+	case t.IsAssignableTo(target):
+		return canonicalValue(t, value), nil
+	case t == STRING && target.Validate(value) == nil:
+		return canonicalValue(target, value), nil
+	default:
+		return "", ErrIncompatibleValue
+	}
+}
+
+// canonicalValue returns the canonical representation of a value valid for the type.
+func canonicalValue(t AttributeType, value string) string {
+	value = strings.TrimSpace(value)
+	switch t {
+	case BOOL:
+		parsed, _ := strconv.ParseBool(value)
+		return strconv.FormatBool(parsed)
+	case LIST, OBJECT:
+		compacted := bytes.Buffer{}
+		if json.Compact(&compacted, []byte(value)) != nil {
+			return value
+		}
+		return compacted.String()
+	default:
+		return value
+	}
+}
+
+// TypedValue is a value validated against its AttributeType.
+type TypedValue struct {
+	attributeType AttributeType
+	value         string
+}
+
+// NewTypedValue validates a value against the type.
+// Returns an error if the value is invalid for the type.
+func NewTypedValue(attributeType AttributeType, value string) (*TypedValue, error) {
+	if err := attributeType.Validate(value); err != nil {
+		return nil, err
+	}
+	if attributeType != STRING {
+		value = canonicalValue(attributeType, value)
+	}
+	return &TypedValue{
+		attributeType: attributeType,
+		value:         value,
+	}, nil
+}
+
+// ParseJSONValue creates a TypedValue from a JSON document, inferring its type:
+// a JSON string is a STRING, a number a NUMBER, a boolean a BOOL, an array a LIST and an object an OBJECT.
+// Returns ErrIncompatibleValue for null values and invalid documents.
+func ParseJSONValue(document []byte) (*TypedValue, error) {
+	var decoded any
+	if err := json.Unmarshal(document, &decoded); err != nil {
+		return nil, ErrIncompatibleValue
+	}
+	switch typed := decoded.(type) {
+	case string:
+		return NewTypedValue(STRING, typed)
+	case float64:
+		return NewTypedValue(NUMBER, strings.TrimSpace(string(document)))
+	case bool:
+		return NewTypedValue(BOOL, strconv.FormatBool(typed))
+	case []any:
+		return NewTypedValue(LIST, string(document))
+	case map[string]any:
+		return NewTypedValue(OBJECT, string(document))
+	default:
+		return nil, ErrIncompatibleValue
+	}
+}
+
+// GetType returns the type of the value.
+func (v *TypedValue) GetType() AttributeType {
+	return v.attributeType
+}
+
+// ToString returns the string representation of the value, as carried by attributes.
+func (v *TypedValue) ToString() string {
+	return v.value
+}
+
+// Decode returns the value as a Go value: a string, a float64, a bool, a []any or a map[string]any depending on its type.
+func (v *TypedValue) Decode() any {
+	switch v.attributeType {
+	case NUMBER:
+		number, _ := strconv.ParseFloat(strings.TrimSpace(v.value), 64)
+		return number
+	case BOOL:
+		parsed, _ := strconv.ParseBool(v.value)
+		return parsed
+	case LIST, OBJECT:
+		var decoded any
+		json.Unmarshal([]byte(v.value), &decoded)
+		return decoded
+	default:
+		return v.value
+	}
+}
+
+// ConvertTo converts the value to the target type (see AttributeType.Coerce).
+func (v *TypedValue) ConvertTo(target AttributeType) (*TypedValue, error) {
+	value, err := v.attributeType.Coerce(v.value, target)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedValue{attributeType: target, value: value}, nil
+}
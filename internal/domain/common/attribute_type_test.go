@@ -0,0 +1,121 @@
+package common
+
+import "testing"
+
+func TestParseAttributeType(t *testing.T) {
+	for _, attributeType := range []AttributeType{STRING, NUMBER, BOOL, LIST, OBJECT} {
+		parsed, err := ParseAttributeType(attributeType.ToString())
+		if err != nil || parsed != attributeType {
+			t.Errorf("expected %s, got %s (%v)", attributeType.ToString(), parsed.ToString(), err)
+		}
+	}
+	if parsed, err := ParseAttributeType(" List "); err != nil || parsed != LIST {
+		t.Errorf("expected list, got %s (%v)", parsed.ToString(), err)
+	}
+	if _, err := ParseAttributeType("S"); err != ErrInvalidAttributeType {
+		t.Errorf("expected err to be ErrInvalidAttributeType, got %v", err)
+	}
+	if AttributeType(42).IsValid() || AttributeType(42).ToString() != "unknown" {
+		t.Error("expected 42 to be an invalid type")
+	}
+}
+
+func TestAttributeTypeOrdinals(t *testing.T) {
+	for ordinal, attributeType := range []AttributeType{STRING, NUMBER, LIST, BOOL, OBJECT} {
+		if int(attributeType) != ordinal {
+			t.Errorf("expected %s to be persisted as %d, got %d", attributeType.ToString(), ordinal, attributeType)
+		}
+	}
+}
+
+func TestAttributeTypeValidate(t *testing.T) {
+	tests := []struct {
+		attributeType AttributeType
+		value         string
+		expected      error
+	}{
+		{STRING, "anything", nil},
+		{NUMBER, "3.5", nil},
+		{NUMBER, "three", ErrInvalidNumber},
+		{BOOL, "false", nil},
+		{BOOL, "maybe", ErrInvalidBool},
+		{LIST, `["a", "b"]`, nil},
+		{LIST, `["a", null]`, ErrEmptyItemInList},
+		{LIST, "null", ErrInvalidListFormat},
+		{OBJECT, `{"key": "value"}`, nil},
+		{OBJECT, `["a"]`, ErrInvalidObjectFormat},
+	}
+	for _, test := range tests {
+		if err := test.attributeType.Validate(test.value); err != test.expected {
+			t.Errorf("expected %v for %s %q, got %v", test.expected, test.attributeType.ToString(), test.value, err)
+		}
+	}
+}
+
+func TestAttributeTypeCoerce(t *testing.T) {
+	if !NUMBER.IsAssignableTo(STRING) || !LIST.IsAssignableTo(LIST) || STRING.IsAssignableTo(NUMBER) || NUMBER.IsAssignableTo(BOOL) {
+		t.Error("unexpected assignability")
+	}
+
+	tests := []struct {
+		from     AttributeType
+		value    string
+		to       AttributeType
+		expected string
+	}{
+		{NUMBER, "3", STRING, "3"},
+		{BOOL, "1", STRING, "true"},
+		{LIST, `[ "a", "b" ]`, STRING, `["a","b"]`},
+		{STRING, " 3 ", NUMBER, "3"},
+		{STRING, "T", BOOL, "true"},
+		{STRING, " padded ", STRING, " padded "},
+	}
+	for _, test := range tests {
+		coerced, err := test.from.Coerce(test.value, test.to)
+		if err != nil || coerced != test.expected {
+			t.Errorf("expected %q for %s %q to %s, got %q (%v)", test.expected, test.from.ToString(), test.value, test.to.ToString(), coerced, err)
+		}
+	}
+
+	if _, err := NUMBER.Coerce("3", BOOL); err != ErrIncompatibleValue {
+		t.Errorf("expected err to be ErrIncompatibleValue, got %v", err)
+	}
+	if _, err := STRING.Coerce("three", NUMBER); err != ErrIncompatibleValue {
+		t.Errorf("expected err to be ErrIncompatibleValue, got %v", err)
+	}
+	if _, err := NUMBER.Coerce("three", STRING); err != ErrInvalidNumber {
+		t.Errorf("expected err to be ErrInvalidNumber, got %v", err)
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	tests := []struct {
+		document string
+		expected AttributeType
+		value    string
+	}{
+		{`"eu-west-1"`, STRING, "eu-west-1"},
+		{`42`, NUMBER, "42"},
+		{`true`, BOOL, "true"},
+		{`[ "a" ]`, LIST, `["a"]`},
+		{`{ "a": 1 }`, OBJECT, `{"a":1}`},
+	}
+	for _, test := range tests {
+		value, err := ParseJSONValue([]byte(test.document))
+		if err != nil || value.GetType() != test.expected || value.ToString() != test.value {
+			t.Errorf("expected %s %q for %s, got %v (%v)", test.expected.ToString(), test.value, test.document, value, err)
+		}
+	}
+	if _, err := ParseJSONValue([]byte("null")); err != ErrIncompatibleValue {
+		t.Errorf("expected err to be ErrIncompatibleValue, got %v", err)
+	}
+
+	number, _ := NewTypedValue(STRING, "12")
+	converted, err := number.ConvertTo(NUMBER)
+	if err != nil || converted.GetType() != NUMBER || converted.Decode() != float64(12) {
+		t.Errorf("expected the number 12, got %v (%v)", converted, err)
+	}
+	if _, err := NewTypedValue(BOOL, "maybe"); err != ErrInvalidBool {
+		t.Errorf("expected err to be ErrInvalidBool, got %v", err)
+	}
+}
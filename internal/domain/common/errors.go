@@ -13,4 +13,26 @@ var (
 	ErrInvalidPathOrUrl        = errors.New("the provided string does not correspond to a path or a url")
 	ErrInvalidResourceType     = errors.New("invalid resource type")
 	ErrInvalidIdentifierFormat = errors.New("identifier must match the  following format: 'autops::project:<project-id>[:<resource-type>:<resource-id>]'")
+	ErrIdentifierHasNoParent   = errors.New("the identifier does not have a parent resource")
+	ErrInvalidJSONPath         = errors.New("invalid JSONPath expression: expected a format like $.key[0].other")
+	ErrJSONPathNotFound        = errors.New("the JSONPath expression does not match any value in the document")
+	ErrInvalidGitSource        = errors.New("git sources must match the following format: 'git::<repository>[//<subdirectory>][?ref=<ref>]'")
+	ErrInvalidGitCommit        = errors.New("the commit must be a full hexadecimal SHA-1 identifier")
+	ErrInvalidContentHash      = errors.New("the content hash must match the following format: 'sha256:<hex-digest>'")
+	ErrInvalidAuthor           = errors.New("the author of a version must be a user identifier")
+	ErrInvalidChangelog        = errors.New("changelog must be less than 4096 characters")
+	ErrInvalidSemanticVersion  = errors.New("semantic versions must match the following format: 'MAJOR.MINOR.PATCH[-<pre-release>]'")
+	ErrInvalidVersionRange     = errors.New("invalid version range: expected a format like '^1.2.0', '~1.2.0', '1.x' or '>=1.0.0 <2.0.0'")
+	ErrInvalidAttributeType    = errors.New("invalid attribute type: correct values are 'string', 'number', 'bool', 'list' or 'object'")
+	ErrInvalidNumber           = errors.New("invalid NUMBER format: expected an integer or a decimal number")
+	ErrInvalidBool             = errors.New("invalid BOOL format: expected true or false")
+	ErrInvalidListFormat       = errors.New("invalid LIST format: expected format like [a, b, c]")
+	ErrEmptyItemInList         = errors.New("invalid LIST format: empty item")
+	ErrInvalidObjectFormat     = errors.New("invalid OBJECT format: expected a JSON object like {\"key\": \"value\"}")
+	ErrIncompatibleValue       = errors.New("the value cannot be converted to the expected attribute type")
+	ErrIncompatibleTypes       = errors.New("the attribute types are not compatible")
+	ErrInvalidJSONSchema       = errors.New("invalid JSON schema")
+	ErrInvalidInputSchema      = errors.New("invalid input schema")
+	ErrInvalidConstraint       = errors.New("invalid attribute constraint: bounds must be non-negative and ordered, patterns valid regular expressions, and allowed numbers valid numbers")
+	ErrConstraintNotApplicable = errors.New("the attribute constraint does not apply to the attribute type")
 )
@@ -0,0 +1,165 @@
+package common
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// GIT_SOURCE_PREFIX marks the source paths fetched from a git repository,
+// formatted as git::<repository>[//<subdirectory>][?ref=<ref>].
+const GIT_SOURCE_PREFIX = "git::"
+
+// GIT_DEFAULT_REF is the ref checked out when a git source does not specify one.
+const GIT_DEFAULT_REF = "HEAD"
+
+var (
+	scpLikeRepositoryRegex = regexp.MustCompile(`^[a-zA-Z0-9._\-]+@[a-zA-Z0-9.\-]+:[a-zA-Z0-9._/\-~]+$`)
+	gitRefRegex            = regexp.MustCompile(`^[a-zA-Z0-9._/\-]+$`)
+	gitCommitRegex         = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// GitSource is a source path pointing to a subdirectory of a git repository, at a given ref (branch, tag or commit).
+type GitSource struct {
+	repository   string
+	subdirectory string
+	ref          string
+}
+
+// IsGitSource returns true if the source path designates a git repository.
+func IsGitSource(sourcePath string) bool {
+	return strings.HasPrefix(sourcePath, GIT_SOURCE_PREFIX)
+}
+
+// ParseGitSource parses a source path formatted as git::<repository>[//<subdirectory>][?ref=<ref>].
+// The repository is an https, ssh or file URL, an scp-like address (git@host:org/repo.git) or an absolute local path.
+//
+// Returns ErrInvalidGitSource if the source path is malformed, or if the subdirectory escapes the repository.
+func ParseGitSource(sourcePath string) (*GitSource, error) {
+	if !IsGitSource(sourcePath) {
+		return nil, ErrInvalidGitSource
+	}
+	location := strings.TrimPrefix(sourcePath, GIT_SOURCE_PREFIX)
+	ref := GIT_DEFAULT_REF
+	if index := strings.LastIndex(location, "?"); index >= 0 {
+		query, err := url.ParseQuery(location[index+1:])
+		if err != nil || len(query) != 1 || len(query["ref"]) != 1 {
+			return nil, ErrInvalidGitSource
+		}
+		ref = query.Get("ref")
+		location = location[:index]
+	}
+
+	subdirectory := ""
+	start := 0
+	if index := strings.Index(location, "://"); index >= 0 {
+		start = index + len("://")
+	}
+	if index := strings.Index(location[start:], "//"); index >= 0 {
+		subdirectory = location[start+index+len("//"):]
+		location = location[:start+index]
+	}
+	return NewGitSource(location, subdirectory, ref)
+}
+
+// NewGitSource creates a GitSource from its parts. An empty ref stands for GIT_DEFAULT_REF.
+// Returns ErrInvalidGitSource if the repository, subdirectory or ref is invalid.
+func NewGitSource(repository string, subdirectory string, ref string) (*GitSource, error) {
+	if !isValidGitRepository(repository) {
+		return nil, ErrInvalidGitSource
+	}
+	if subdirectory != "" {
+		cleaned := path.Clean(subdirectory)
+		if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || !IsSyntacticallySafePath(cleaned) {
+			return nil, ErrInvalidGitSource
+		}
+		subdirectory = strings.TrimPrefix(cleaned, ".")
+	}
+	if ref == "" {
+		ref = GIT_DEFAULT_REF
+	}
+	if !gitRefRegex.MatchString(ref) || strings.HasPrefix(ref, "-") || strings.Contains(ref, "..") {
+		return nil, ErrInvalidGitSource
+	}
+	return &GitSource{
+		repository:   repository,
+		subdirectory: subdirectory,
+		ref:          ref,
+	}, nil
+}
+
+// isValidGitRepository returns true if the repository is a supported URL, an scp-like address or an absolute local path.
+// Repositories starting with a dash are rejected, since they would be interpreted as options by git.
+func isValidGitRepository(repository string) bool {
+	if repository == "" || strings.HasPrefix(repository, "-") || strings.ContainsAny(repository, " \t\r\n") {
+		return false
+	}
+	if scpLikeRepositoryRegex.MatchString(repository) {
+		return true
+	}
+	if strings.HasPrefix(repository, "/") {
+		return IsSyntacticallySafePath(repository) && IsPlausibleLocalPath(repository)
+	}
+	u, err := url.Parse(repository)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh":
+		return u.Host != "" && u.Path != ""
+	case "file":
+		return u.Path != ""
+	default:
+		return false
+	}
+}
+
+// GetRepository returns the address of the git repository.
+func (s *GitSource) GetRepository() string {
+	return s.repository
+}
+
+// GetSubdirectory returns the subdirectory of the repository holding the sources, or an empty string for the repository root.
+func (s *GitSource) GetSubdirectory() string {
+	return s.subdirectory
+}
+
+// GetRef returns the branch, tag or commit to check out.
+func (s *GitSource) GetRef() string {
+	return s.ref
+}
+
+// IsSSH returns true if the repository is accessed through SSH, and thus authenticated with a private key.
+func (s *GitSource) IsSSH() bool {
+	return strings.HasPrefix(s.repository, "ssh://") || scpLikeRepositoryRegex.MatchString(s.repository)
+}
+
+// IsHTTPS returns true if the repository is accessed through HTTPS, and thus authenticated with a username and a token.
+// Plain HTTP repositories are not, so that tokens are never sent in clear text.
+func (s *GitSource) IsHTTPS() bool {
+	return strings.HasPrefix(s.repository, "https://")
+}
+
+// AcceptsCredentials returns true if the repository is accessed through HTTPS or SSH.
+// Credentials are never sent to plain HTTP, file or local repositories.
+func (s *GitSource) AcceptsCredentials() bool {
+	return s.IsHTTPS() || s.IsSSH()
+}
+
+// ToString returns the source path of the GitSource, which can be parsed back using ParseGitSource.
+func (s *GitSource) ToString() string {
+	str := GIT_SOURCE_PREFIX + s.repository
+	if s.subdirectory != "" {
+		str += "//" + s.subdirectory
+	}
+	if s.ref != GIT_DEFAULT_REF {
+		str += "?ref=" + s.ref
+	}
+	return str
+}
+
+// IsValidGitCommit returns true if the string is a full hexadecimal SHA-1 commit identifier.
+func IsValidGitCommit(commit string) bool {
+	return gitCommitRegex.MatchString(commit)
+}
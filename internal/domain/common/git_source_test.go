@@ -0,0 +1,49 @@
+package common
+
+import "testing"
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		sourcePath   string
+		repository   string
+		subdirectory string
+		ref          string
+		ssh          bool
+	}{
+		{"git::https://host/repo.git//modules/vpc?ref=v1.2.0", "https://host/repo.git", "modules/vpc", "v1.2.0", false},
+		{"git::https://host/repo.git", "https://host/repo.git", "", GIT_DEFAULT_REF, false},
+		{"git::ssh://git@host/org/repo.git?ref=main", "ssh://git@host/org/repo.git", "", "main", true},
+		{"git::git@host:org/repo.git//modules/./vpc/", "git@host:org/repo.git", "modules/vpc", GIT_DEFAULT_REF, true},
+		{"git::/srv/git/repo.git//vpc?ref=feature/vpc", "/srv/git/repo.git", "vpc", "feature/vpc", false},
+	}
+	for _, test := range tests {
+		source, err := ParseGitSource(test.sourcePath)
+		if err != nil {
+			t.Errorf("%s: expected err to be nil, got %v", test.sourcePath, err)
+			continue
+		}
+		if source.GetRepository() != test.repository || source.GetSubdirectory() != test.subdirectory || source.GetRef() != test.ref || source.IsSSH() != test.ssh {
+			t.Errorf("%s: unexpected source %+v", test.sourcePath, source)
+		}
+		if reparsed, _ := ParseGitSource(source.ToString()); *reparsed != *source {
+			t.Errorf("%s: expected %s to be parsed back", test.sourcePath, source.ToString())
+		}
+	}
+
+	invalid := []string{
+		"https://host/repo.git",
+		"git::",
+		"git::-uhost/repo.git",
+		"git::ext::sh -c touch% /tmp/pwned",
+		"git::https://host/repo.git//../secrets",
+		"git::https://host/repo.git?ref=--upload-pack=touch",
+		"git::https://host/repo.git?ref=a..b",
+		"git::https://host/repo.git?ref=main&depth=1",
+		"git::relative/repo.git",
+	}
+	for _, sourcePath := range invalid {
+		if _, err := ParseGitSource(sourcePath); err != ErrInvalidGitSource {
+			t.Errorf("%s: expected err to be ErrInvalidGitSource, got %v", sourcePath, err)
+		}
+	}
+}
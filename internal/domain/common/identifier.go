@@ -45,7 +45,7 @@ func ValidateIdentifier(str string) error {
 	if err != nil {
 		return err
 	}
-	if prefix != PROJECT && (prefix != USER && len(segments) > 2) {
+	if prefix != PROJECT && prefix != ROLE && (prefix != USER && len(segments) > 2) {
 		return ErrInvalidIdentifierFormat
 	}
 
@@ -54,10 +54,17 @@ func ValidateIdentifier(str string) error {
 		if err != nil {
 			return err
 		}
-		if resourceType == PROJECT || resourceType == USER {
+		if resourceType == PROJECT || resourceType == USER || resourceType == GROUP || resourceType == ROLE {
+			return ErrInvalidIdentifierFormat
+		}
+		// Sessions only belong to roles, which have no other children.
+		if (resourceType == SESSION) != (prefix == ROLE) {
 			return ErrInvalidIdentifierFormat
 		}
 	}
+	if prefix == ROLE && len(segments) > 4 {
+		return ErrInvalidIdentifierFormat
+	}
 
 	for i := 1; i < len(segments); i += 2 {
 		if !isValidNanoID(segments[i]) {
@@ -101,6 +108,17 @@ func (i *Identifier) GetType() ResourceType {
 	return t
 }
 
+// GetParent returns the Identifier of the resource owning this one, by removing its last type and id segments.
+//
+// Returns an error if the Identifier has no parent (e.g. a project or user identifier).
+func (i *Identifier) GetParent() (*Identifier, error) {
+	segments := i.Segments()
+	if len(segments) <= 4 {
+		return nil, ErrIdentifierHasNoParent
+	}
+	return NewIdentifier(strings.Join(segments[:len(segments)-2], ":"))
+}
+
 // GenerateNanoID generates a random NanoID of fixed length.
 func GenerateNanoID() (string, error) {
 	return gonanoid.New(NANO_ID_LENGTH)
@@ -120,6 +138,21 @@ func BuildUserIdentifier() (*Identifier, error) {
 	return BuildIdentifier("autops:", "user")
 }
 
+// BuildGroupIdentifier creates a new Identifier for a group of users.
+func BuildGroupIdentifier() (*Identifier, error) {
+	return BuildIdentifier("autops:", "group")
+}
+
+// BuildRoleIdentifier creates a new Identifier for a role.
+func BuildRoleIdentifier() (*Identifier, error) {
+	return BuildIdentifier("autops:", "role")
+}
+
+// BuildSessionIdentifier creates a new Identifier for a session of the given role.
+func BuildSessionIdentifier(roleId string) (*Identifier, error) {
+	return BuildIdentifier(roleId, "session")
+}
+
 // BuildProjectIdentifier creates a new Identifier for a project resource.
 func BuildProjectIdentifier() (*Identifier, error) {
 	return BuildIdentifier("autops:", "project")
@@ -140,6 +173,16 @@ func BuildPolicyIdentifier(projectId string) (*Identifier, error) {
 	return BuildIdentifier(projectId, "policy")
 }
 
+// BuildSecretIdentifier creates a new Identifier for a secret under the given project.
+func BuildSecretIdentifier(projectId string) (*Identifier, error) {
+	return BuildIdentifier(projectId, "secret")
+}
+
+// BuildEnvironmentIdentifier creates a new Identifier for an environment under the given project.
+func BuildEnvironmentIdentifier(projectId string) (*Identifier, error) {
+	return BuildIdentifier(projectId, "environment")
+}
+
 // BuildAttributeIdentifier creates a new Identifier for an attribute under the given parent resource and attribute type.
 func BuildAttributeIdentifier(parentId string, attributeType string) (*Identifier, error) {
 	return BuildIdentifier(parentId, attributeType)
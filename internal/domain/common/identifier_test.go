@@ -10,6 +10,7 @@ func TestValidateIdentifier_Valid(t *testing.T) {
 		"autops::project:abcDEF1234:template:XYZxyz7890",
 		"autops::project:abcDEF1234:workflow:testID1234",
 		"autops::project:abcDEF1234:policy:abcdEFG789",
+		"autops::role:abcDEF1234:session:1234567890",
 	}
 
 	for _, id := range valids {
@@ -30,6 +31,11 @@ func TestValidateIdentifier_Invalid(t *testing.T) {
 		"autops::invalidType:abcDEF1234", // invalid resource type
 		"autops::template:abcDEF1234:workflow:1234567890",
 		"autops::project:abcDEF1234:something:1234567890",
+		"autops::project:abcDEF1234:role:1234567890", // nested role
+		"autops::role:abcDEF1234:workflow:1234567890",
+		"autops::role:abcDEF1234:secret:1234567890",
+		"autops::role:abcDEF1234:session:1234567890:template:1234567890",
+		"autops::project:abcDEF1234:session:1234567890",
 	}
 
 	for _, id := range invalids {
@@ -93,10 +99,41 @@ func TestBuildIdentifiers(t *testing.T) {
 		t.Fatalf("BuildAttributeIdentifier failed: %v", err)
 	}
 
-	identifiers := []*Identifier{projId, tplId, wfId, polId, attrId}
+	groupId, err := BuildGroupIdentifier()
+	if err != nil || groupId.GetType() != GROUP {
+		t.Fatalf("BuildGroupIdentifier failed: %v", err)
+	}
+
+	roleId, err := BuildRoleIdentifier()
+	if err != nil || roleId.GetType() != ROLE {
+		t.Fatalf("BuildRoleIdentifier failed: %v", err)
+	}
+
+	sessionId, err := BuildSessionIdentifier(roleId.ToString())
+	if err != nil || sessionId.GetType() != SESSION {
+		t.Fatalf("BuildSessionIdentifier failed: %v", err)
+	}
+
+	identifiers := []*Identifier{projId, tplId, wfId, polId, attrId, groupId, roleId, sessionId}
 	for _, id := range identifiers {
 		if err := ValidateIdentifier(id.ToString()); err != nil {
 			t.Errorf("Built identifier is invalid: %s (%v)", id.ToString(), err)
 		}
 	}
 }
+
+func TestGetParent(t *testing.T) {
+	identifier, _ := NewIdentifier("autops::project:abcDEF1234:workflow:XYZxyz7890:trigger:1234567890")
+	parent, err := identifier.GetParent()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parent.ToString() != "autops::project:abcDEF1234:workflow:XYZxyz7890" {
+		t.Errorf("expected workflow identifier, got %s", parent.ToString())
+	}
+
+	project, _ := NewIdentifier("autops::project:abcDEF1234")
+	if _, err := project.GetParent(); err != ErrIdentifierHasNoParent {
+		t.Errorf("expected ErrIdentifierHasNoParent, got %v", err)
+	}
+}
@@ -0,0 +1,473 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// JSON_SCHEMA_DIALECT is the JSON Schema draft of the input schemas.
+const JSON_SCHEMA_DIALECT = "https://json-schema.org/draft/2020-12/schema"
+
+// ATTRIBUTE_TYPE_KEYWORD is the annotation keeping the attribute type of sensitive properties,
+// whose values are secret references and therefore always strings.
+const ATTRIBUTE_TYPE_KEYWORD = "x-autops-type"
+
+// SchemaProperty describes an attribute as a property of an InputSchema.
+type SchemaProperty struct {
+	name          string
+	description   string
+	attributeType AttributeType
+	defaultValue  string
+	sensitive     bool
+	constraints   *AttributeConstraints
+}
+
+// NewSchemaProperty creates a SchemaProperty describing an attribute.
+// Constraints default to no constraint if nil.
+func NewSchemaProperty(name string, description string, attributeType AttributeType, defaultValue string, sensitive bool, constraints *AttributeConstraints) *SchemaProperty {
+	if constraints == nil {
+		constraints = NewAttributeConstraints()
+	}
+	return &SchemaProperty{
+		name:          name,
+		description:   description,
+		attributeType: attributeType,
+		defaultValue:  defaultValue,
+		sensitive:     sensitive,
+		constraints:   constraints,
+	}
+}
+
+// GetName returns the name of the attribute.
+func (p *SchemaProperty) GetName() string {
+	return p.name
+}
+
+// GetDescription returns the description of the attribute.
+func (p *SchemaProperty) GetDescription() string {
+	return p.description
+}
+
+// GetType returns the type of the attribute.
+func (p *SchemaProperty) GetType() AttributeType {
+	return p.attributeType
+}
+
+// GetDefaultValue returns the default value of the attribute, or an empty string if it has none.
+func (p *SchemaProperty) GetDefaultValue() string {
+	return p.defaultValue
+}
+
+// IsSensitive returns true if the values of the attribute are secret references.
+func (p *SchemaProperty) IsSensitive() bool {
+	return p.sensitive
+}
+
+// GetConstraints returns the constraints the values of the attribute must satisfy.
+func (p *SchemaProperty) GetConstraints() *AttributeConstraints {
+	return p.constraints
+}
+
+// IsRequired returns true if a value must be provided for the attribute, i.e. it has no default value or a value is required by its constraints.
+func (p *SchemaProperty) IsRequired() bool {
+	return p.defaultValue == "" || p.constraints.IsRequired()
+}
+
+// InputSchema is a JSON Schema (draft 2020-12) document describing the inputs of a template or a workflow,
+// used by clients to render the form providing their values.
+//
+// Each input is a property of the root object, with the JSON Schema type of its attribute type, its description, its default value and its constraints.
+// Sensitive inputs are write-only string properties expecting a secret reference, keeping their attribute type in the x-autops-type annotation,
+// and their constraints are not exported since they only apply to the revealed values.
+type InputSchema struct {
+	title       string
+	description string
+	properties  []*SchemaProperty
+}
+
+// NewInputSchema creates an InputSchema with a property for each attribute, sorted by name.
+func NewInputSchema(title string, description string, properties []*SchemaProperty) *InputSchema {
+	sorted := append([]*SchemaProperty(nil), properties...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	return &InputSchema{
+		title:       title,
+		description: description,
+		properties:  sorted,
+	}
+}
+
+// GetTitle returns the title of the schema, usually the name of the template or workflow.
+func (s *InputSchema) GetTitle() string {
+	return s.title
+}
+
+// GetDescription returns the description of the schema.
+func (s *InputSchema) GetDescription() string {
+	return s.description
+}
+
+// ListProperties returns the properties of the schema, sorted by name.
+func (s *InputSchema) ListProperties() []*SchemaProperty {
+	return append([]*SchemaProperty(nil), s.properties...)
+}
+
+// ToDocument returns the JSON Schema document as a decoded JSON object.
+func (s *InputSchema) ToDocument() map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, property := range s.properties {
+		properties[property.name] = property.toDocument()
+		if property.IsRequired() {
+			required = append(required, property.name)
+		}
+	}
+	document := map[string]any{
+		"$schema":              JSON_SCHEMA_DIALECT,
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if s.title != "" {
+		document["title"] = s.title
+	}
+	if s.description != "" {
+		document["description"] = s.description
+	}
+	return document
+}
+
+// ToString returns the compact JSON Schema document.
+func (s *InputSchema) ToString() string {
+	encoded, _ := json.Marshal(s.ToDocument())
+	return string(encoded)
+}
+
+// toDocument returns the JSON Schema of the property.
+func (p *SchemaProperty) toDocument() map[string]any {
+	document := map[string]any{}
+	if p.description != "" {
+		document["description"] = p.description
+	}
+	if p.sensitive {
+		document["type"] = "string"
+		document["writeOnly"] = true
+		document[ATTRIBUTE_TYPE_KEYWORD] = p.attributeType.ToString()
+		if p.defaultValue != "" {
+			document["default"] = p.defaultValue
+		}
+		return document
+	}
+
+	if p.constraints.GetSchema() != nil {
+		var schema map[string]any
+		json.Unmarshal([]byte(p.constraints.GetSchema().ToString()), &schema)
+		for keyword, value := range schema {
+			if keyword != "$schema" && keyword != "$id" {
+				document[keyword] = value
+			}
+		}
+	}
+	document["type"] = jsonSchemaTypeOf(p.attributeType)
+	if p.defaultValue != "" {
+		if value, err := NewTypedValue(p.attributeType, p.defaultValue); err == nil {
+			document["default"] = value.Decode()
+		}
+	}
+
+	constraints := p.constraints
+	if allowed := constraints.ListAllowedValues(); len(allowed) > 0 {
+		enum := make([]any, 0, len(allowed))
+		for _, value := range allowed {
+			if p.attributeType == NUMBER {
+				number, _ := strconv.ParseFloat(value, 64)
+				enum = append(enum, number)
+			} else {
+				enum = append(enum, value)
+			}
+		}
+		document["enum"] = enum
+	}
+	if constraints.GetPattern() != "" {
+		document["pattern"] = constraints.GetPattern()
+	}
+	setCount := func(keyword string, count *int) {
+		if count != nil {
+			document[keyword] = *count
+		}
+	}
+	minLength, maxLength := constraints.GetLength()
+	setCount("minLength", minLength)
+	setCount("maxLength", maxLength)
+	minItems, maxItems := constraints.GetItems()
+	setCount("minItems", minItems)
+	setCount("maxItems", maxItems)
+	minimum, maximum := constraints.GetRange()
+	if minimum != nil {
+		document["minimum"] = *minimum
+	}
+	if maximum != nil {
+		document["maximum"] = *maximum
+	}
+	if constraints.GetElementType() != nil {
+		document["items"] = map[string]any{"type": jsonSchemaTypeOf(*constraints.GetElementType())}
+	}
+	return document
+}
+
+// jsonSchemaTypeOf returns the JSON Schema type of the values of an attribute type.
+func jsonSchemaTypeOf(attributeType AttributeType) string {
+	switch attributeType {
+	case NUMBER:
+		return "number"
+	case BOOL:
+		return "boolean"
+	case LIST:
+		return "array"
+	case OBJECT:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// attributeTypeOf returns the attribute type holding the values of a JSON Schema type.
+func attributeTypeOf(schemaType string) (AttributeType, bool) {
+	switch schemaType {
+	case "string":
+		return STRING, true
+	case "number", "integer":
+		return NUMBER, true
+	case "boolean":
+		return BOOL, true
+	case "array":
+		return LIST, true
+	case "object":
+		return OBJECT, true
+	default:
+		return -1, false
+	}
+}
+
+// inputSchemaKeywords are the keywords accepted at the root of an input schema.
+var inputSchemaKeywords = map[string]bool{"$schema": true, "$id": true, "title": true, "description": true, "type": true, "properties": true, "required": true, "additionalProperties": true}
+
+// ParseInputSchema parses a JSON Schema document describing inputs, as returned by InputSchema.ToDocument.
+//
+// The root must be an object schema whose properties describe the inputs: their type is mapped to an attribute type
+// (integer being a NUMBER), and their enum, pattern, length, range, items and item type keywords to constraints.
+// The other keywords of object properties form the JSON Schema of their values (see ParseJSONSchema).
+// Write-only properties are sensitive, and properties listed as required despite having a default value require a non-empty value.
+//
+// Returns an error wrapping ErrInvalidInputSchema with the location of the invalid keyword, e.g. "$.properties.region.minLength".
+func ParseInputSchema(document []byte) (*InputSchema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(document, &root); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidInputSchema, err.Error())
+	}
+	invalid := func(location string, expected string) error {
+		return fmt.Errorf("%w: %s: expected %s", ErrInvalidInputSchema, location, expected)
+	}
+	for _, keyword := range sortedKeys(root) {
+		if !inputSchemaKeywords[keyword] {
+			return nil, fmt.Errorf("%w: $: unsupported keyword %q", ErrInvalidInputSchema, keyword)
+		}
+	}
+	if dialect, found := root["$schema"]; found && dialect != JSON_SCHEMA_DIALECT {
+		return nil, invalid("$.$schema", JSON_SCHEMA_DIALECT)
+	}
+	if schemaType, found := root["type"]; found && schemaType != "object" {
+		return nil, invalid("$.type", "object")
+	}
+	title, _ := root["title"].(string)
+	description, _ := root["description"].(string)
+
+	required := map[string]bool{}
+	if names, found := root["required"]; found {
+		list, ok := names.([]any)
+		if !ok {
+			return nil, invalid("$.required", "an array of strings")
+		}
+		for _, name := range list {
+			str, ok := name.(string)
+			if !ok {
+				return nil, invalid("$.required", "an array of strings")
+			}
+			required[str] = true
+		}
+	}
+	nodes, ok := root["properties"].(map[string]any)
+	if _, found := root["properties"]; found && !ok {
+		return nil, invalid("$.properties", "an object")
+	}
+	properties := make([]*SchemaProperty, 0, len(nodes))
+	for _, name := range sortedKeys(nodes) {
+		property, err := parseSchemaProperty(name, nodes[name], "$.properties."+name)
+		if err != nil {
+			return nil, err
+		}
+		if required[name] && property.defaultValue != "" {
+			property.constraints.SetRequired(true)
+		}
+		properties = append(properties, property)
+	}
+	for name := range required {
+		if _, declared := nodes[name]; !declared {
+			return nil, fmt.Errorf("%w: $.required: %q is not a property", ErrInvalidInputSchema, name)
+		}
+	}
+	return NewInputSchema(title, description, properties), nil
+}
+
+// parseSchemaProperty parses the property of an input schema at the given location.
+func parseSchemaProperty(name string, raw any, location string) (*SchemaProperty, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: a property must be an object", ErrInvalidInputSchema, location)
+	}
+	invalid := func(keyword string, expected string) error {
+		return fmt.Errorf("%w: %s.%s: expected %s", ErrInvalidInputSchema, location, keyword, expected)
+	}
+	schemaType, _ := node["type"].(string)
+	attributeType, ok := attributeTypeOf(schemaType)
+	if !ok {
+		return nil, invalid("type", "one of string, number, integer, boolean, array or object")
+	}
+	description, _ := node["description"].(string)
+	sensitive, _ := node["writeOnly"].(bool)
+	property := NewSchemaProperty(name, description, attributeType, "", sensitive, nil)
+
+	if value, found := node["default"]; found {
+		property.defaultValue = encodeJSONValue(value)
+	}
+	if sensitive {
+		if attributeType != STRING {
+			return nil, invalid("type", "string for a write-only property")
+		}
+		if typeName, found := node[ATTRIBUTE_TYPE_KEYWORD]; found {
+			str, _ := typeName.(string)
+			parsed, err := ParseAttributeType(str)
+			if err != nil {
+				return nil, invalid(ATTRIBUTE_TYPE_KEYWORD, "one of string, number, bool, list or object")
+			}
+			property.attributeType = parsed
+		}
+		for _, keyword := range sortedKeys(node) {
+			if !jsonSchemaAnnotations[keyword] && keyword != "type" && keyword != "writeOnly" && keyword != ATTRIBUTE_TYPE_KEYWORD {
+				return nil, fmt.Errorf("%w: %s: unsupported keyword %q for a write-only property", ErrInvalidInputSchema, location, keyword)
+			}
+		}
+		return property, nil
+	}
+
+	constraints := property.constraints
+	if attributeType == OBJECT {
+		schema := map[string]any{}
+		for keyword, value := range node {
+			if keyword != "description" && keyword != "default" && keyword != "writeOnly" {
+				schema[keyword] = value
+			}
+		}
+		if len(schema) > 1 {
+			encoded, _ := json.Marshal(schema)
+			parsed, err := ParseJSONSchema(string(encoded))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %s", ErrInvalidInputSchema, location, err.Error())
+			}
+			constraints.SetSchema(parsed)
+		}
+		return property, nil
+	}
+	for _, keyword := range sortedKeys(node) {
+		value := node[keyword]
+		switch keyword {
+		case "type", "writeOnly":
+		case "enum":
+			values, ok := value.([]any)
+			if !ok || len(values) == 0 {
+				return nil, invalid(keyword, "a non-empty array")
+			}
+			allowed := make([]string, 0, len(values))
+			for _, item := range values {
+				allowed = append(allowed, encodeJSONValue(item))
+			}
+			constraints.SetAllowedValues(allowed)
+		case "pattern":
+			str, _ := value.(string)
+			if str == "" || constraints.SetPattern(str) != nil {
+				return nil, invalid(keyword, "a regular expression")
+			}
+		case "minimum", "maximum":
+			number, ok := value.(float64)
+			if !ok {
+				return nil, invalid(keyword, "a number")
+			}
+			minimum, maximum := constraints.GetRange()
+			if keyword == "minimum" {
+				minimum = &number
+			} else {
+				maximum = &number
+			}
+			if constraints.SetRange(minimum, maximum) != nil {
+				return nil, invalid(keyword, "ordered bounds")
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			number, ok := value.(float64)
+			if !ok || number < 0 || number != math.Trunc(number) {
+				return nil, invalid(keyword, "a non-negative integer")
+			}
+			if err := setSchemaCount(constraints, keyword, int(number)); err != nil {
+				return nil, invalid(keyword, "ordered bounds")
+			}
+		case "items":
+			items, _ := value.(map[string]any)
+			itemType, _ := items["type"].(string)
+			elementType, ok := attributeTypeOf(itemType)
+			if !ok || len(items) != 1 {
+				return nil, invalid(keyword, `a schema with a single type keyword, like {"type": "string"}`)
+			}
+			if err := constraints.SetElementType(&elementType); err != nil {
+				return nil, invalid(keyword, `a schema with a single type keyword, like {"type": "string"}`)
+			}
+		default:
+			if !jsonSchemaAnnotations[keyword] {
+				return nil, fmt.Errorf("%w: %s: unsupported keyword %q", ErrInvalidInputSchema, location, keyword)
+			}
+		}
+	}
+	if err := constraints.CheckApplicable(attributeType); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidInputSchema, location, err.Error())
+	}
+	return property, nil
+}
+
+// setSchemaCount sets the length or items bound of the constraints matching the keyword.
+func setSchemaCount(constraints *AttributeConstraints, keyword string, count int) error {
+	minLength, maxLength := constraints.GetLength()
+	minItems, maxItems := constraints.GetItems()
+	switch keyword {
+	case "minLength":
+		return constraints.SetLength(&count, maxLength)
+	case "maxLength":
+		return constraints.SetLength(minLength, &count)
+	case "minItems":
+		return constraints.SetItems(&count, maxItems)
+	default:
+		return constraints.SetItems(minItems, &count)
+	}
+}
+
+// encodeJSONValue returns the string representation of a decoded JSON value, as carried by attributes:
+// strings are kept as is, and other values are encoded as JSON.
+func encodeJSONValue(value any) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newRegionProperty() *SchemaProperty {
+	constraints := NewAttributeConstraints()
+	constraints.SetAllowedValues([]string{"eu-west-1", "us-east-1"})
+	return NewSchemaProperty("region", "AWS region", STRING, "eu-west-1", false, constraints)
+}
+
+func TestInputSchemaToDocument(t *testing.T) {
+	minimum, maximum := 1.0, 10.0
+	replicas := NewAttributeConstraints()
+	replicas.SetRange(&minimum, &maximum)
+	elementType := STRING
+	tags := NewAttributeConstraints()
+	tags.SetElementType(&elementType)
+	schema := NewInputSchema("network", "", []*SchemaProperty{
+		newRegionProperty(),
+		NewSchemaProperty("replicas", "", NUMBER, "", false, replicas),
+		NewSchemaProperty("tags", "", LIST, `["a"]`, false, tags),
+		NewSchemaProperty("token", "", STRING, "", true, nil),
+	})
+
+	document := schema.ToDocument()
+	if document["$schema"] != JSON_SCHEMA_DIALECT || document["title"] != "network" || document["additionalProperties"] != false {
+		t.Errorf("unexpected root %v", document)
+	}
+	if required := document["required"]; !reflect.DeepEqual(required, []string{"replicas", "token"}) {
+		t.Errorf("expected replicas and token to be required, got %v", required)
+	}
+	properties := document["properties"].(map[string]any)
+	region := properties["region"].(map[string]any)
+	if region["type"] != "string" || region["default"] != "eu-west-1" || region["description"] != "AWS region" || !reflect.DeepEqual(region["enum"], []any{"eu-west-1", "us-east-1"}) {
+		t.Errorf("unexpected region property %v", region)
+	}
+	if property := properties["replicas"].(map[string]any); property["type"] != "number" || property["minimum"] != 1.0 || property["maximum"] != 10.0 {
+		t.Errorf("unexpected replicas property %v", property)
+	}
+	if property := properties["tags"].(map[string]any); property["type"] != "array" || !reflect.DeepEqual(property["default"], []any{"a"}) || !reflect.DeepEqual(property["items"], map[string]any{"type": "string"}) {
+		t.Errorf("unexpected tags property %v", property)
+	}
+	if property := properties["token"].(map[string]any); property["writeOnly"] != true || property[ATTRIBUTE_TYPE_KEYWORD] != "string" {
+		t.Errorf("unexpected token property %v", property)
+	}
+}
+
+func TestParseInputSchema(t *testing.T) {
+	document := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["region", "replicas"],
+		"properties": {
+			"region": {"type": "string", "enum": ["eu-west-1", "us-east-1"], "default": "eu-west-1"},
+			"replicas": {"type": "integer", "minimum": 1, "maximum": 10},
+			"public": {"type": "boolean", "default": false},
+			"settings": {"type": "object", "properties": {"size": {"type": "number"}}, "required": ["size"]},
+			"token": {"type": "string", "writeOnly": true, "x-autops-type": "number"}
+		}
+	}`
+	schema, err := ParseInputSchema([]byte(document))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	properties := map[string]*SchemaProperty{}
+	for _, property := range schema.ListProperties() {
+		properties[property.GetName()] = property
+	}
+	if len(properties) != 5 {
+		t.Fatalf("expected 5 properties, got %d", len(properties))
+	}
+	region := properties["region"]
+	if region.GetDefaultValue() != "eu-west-1" || !region.GetConstraints().IsRequired() || len(region.GetConstraints().ListAllowedValues()) != 2 {
+		t.Errorf("unexpected region property %+v", region)
+	}
+	if minimum, maximum := properties["replicas"].GetConstraints().GetRange(); properties["replicas"].GetType() != NUMBER || *minimum != 1 || *maximum != 10 {
+		t.Errorf("unexpected replicas property %+v", properties["replicas"])
+	}
+	if public := properties["public"]; public.GetType() != BOOL || public.GetDefaultValue() != "false" {
+		t.Errorf("unexpected public property %+v", public)
+	}
+	if settings := properties["settings"]; settings.GetConstraints().GetSchema() == nil || len(settings.GetConstraints().GetSchema().Validate(map[string]any{})) != 1 {
+		t.Errorf("expected the settings schema to require size")
+	}
+	if token := properties["token"]; !token.IsSensitive() || token.GetType() != NUMBER {
+		t.Errorf("unexpected token property %+v", token)
+	}
+
+	exported, err := ParseInputSchema([]byte(schema.ToString()))
+	if err != nil || !reflect.DeepEqual(exported.ToDocument(), schema.ToDocument()) {
+		t.Errorf("expected the exported schema to parse to the same schema, got %v", err)
+	}
+}
+
+func TestParseInputSchema_Invalid(t *testing.T) {
+	invalid := map[string]string{
+		"not json":          `{`,
+		"root type":         `{"type": "array"}`,
+		"draft":             `{"$schema": "http://json-schema.org/draft-07/schema#"}`,
+		"root keyword":      `{"oneOf": []}`,
+		"property type":     `{"properties": {"a": {"type": "null"}}}`,
+		"keyword":           `{"properties": {"a": {"type": "string", "format": "email"}}}`,
+		"not applicable":    `{"properties": {"a": {"type": "number", "pattern": "^a$"}}}`,
+		"bounds":            `{"properties": {"a": {"type": "string", "minLength": 3, "maxLength": 2}}}`,
+		"items":             `{"properties": {"a": {"type": "array", "items": {"type": "string", "minLength": 1}}}}`,
+		"object schema":     `{"properties": {"a": {"type": "object", "oneOf": []}}}`,
+		"unknown required":  `{"properties": {}, "required": ["a"]}`,
+		"write-only number": `{"properties": {"a": {"type": "number", "writeOnly": true}}}`,
+	}
+	for name, document := range invalid {
+		if _, err := ParseInputSchema([]byte(document)); !errors.Is(err, ErrInvalidInputSchema) {
+			t.Errorf("expected err to be ErrInvalidInputSchema for %s, got %v", name, err)
+		}
+	}
+}
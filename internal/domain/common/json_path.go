@@ -0,0 +1,110 @@
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSONPath represents a compiled JSONPath expression used to select a single value in a decoded JSON document.
+//
+// Only the subset required to address one value is supported:
+//   - $ refers to the root of the document
+//   - .key or ['key'] selects a member of an object
+//   - [n] selects the n-th element of an array
+type JSONPath struct {
+	expression string
+	selectors  []jsonPathSelector
+}
+
+// jsonPathSelector represents a single step of a JSONPath, either an object key or an array index.
+type jsonPathSelector struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ParseJSONPath compiles a JSONPath expression.
+//
+// The leading '$' is optional, so "$.ref" and "ref" are equivalent.
+// Returns an error if the expression is malformed.
+func ParseJSONPath(expression string) (*JSONPath, error) {
+	str := strings.TrimSpace(expression)
+	if strings.HasPrefix(str, "$") {
+		str = str[1:]
+		if str != "" && str[0] != '.' && str[0] != '[' {
+			return nil, ErrInvalidJSONPath
+		}
+	} else if str != "" && str[0] != '[' {
+		str = "." + str
+	}
+	selectors := []jsonPathSelector{}
+	for len(str) > 0 {
+		switch str[0] {
+		case '.':
+			end := strings.IndexAny(str[1:], ".[")
+			if end == -1 {
+				end = len(str) - 1
+			}
+			key := str[1 : end+1]
+			if key == "" {
+				return nil, ErrInvalidJSONPath
+			}
+			selectors = append(selectors, jsonPathSelector{key: key})
+			str = str[end+1:]
+		case '[':
+			end := strings.Index(str, "]")
+			if end == -1 {
+				return nil, ErrInvalidJSONPath
+			}
+			content := strings.TrimSpace(str[1:end])
+			if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+				selectors = append(selectors, jsonPathSelector{key: content[1 : len(content)-1]})
+			} else {
+				index, err := strconv.Atoi(content)
+				if err != nil || index < 0 {
+					return nil, ErrInvalidJSONPath
+				}
+				selectors = append(selectors, jsonPathSelector{index: index, isIndex: true})
+			}
+			str = str[end+1:]
+		default:
+			return nil, ErrInvalidJSONPath
+		}
+	}
+	return &JSONPath{
+		expression: strings.TrimSpace(expression),
+		selectors:  selectors,
+	}, nil
+}
+
+// ToString returns the original expression of the JSONPath.
+func (p *JSONPath) ToString() string {
+	return p.expression
+}
+
+// Evaluate returns the value addressed by the JSONPath in a document decoded with encoding/json.
+//
+// Returns ErrJSONPathNotFound if any step of the path does not exist in the document.
+func (p *JSONPath) Evaluate(document interface{}) (interface{}, error) {
+	current := document
+	for _, selector := range p.selectors {
+		if selector.isIndex {
+			list, ok := current.([]interface{})
+			if !ok || selector.index >= len(list) {
+				return nil, ErrJSONPathNotFound
+			}
+			current = list[selector.index]
+			continue
+		}
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, ErrJSONPathNotFound
+		}
+		value, found := object[selector.key]
+		if !found {
+			return nil, ErrJSONPathNotFound
+		}
+		current = value
+	}
+	return current, nil
+}
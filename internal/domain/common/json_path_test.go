@@ -0,0 +1,68 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	invalids := []string{
+		"$..ref",
+		"$.items[",
+		"$.items[-1]",
+		"$.items[abc]",
+		"$ref",
+	}
+
+	for _, path := range invalids {
+		if _, err := ParseJSONPath(path); err != ErrInvalidJSONPath {
+			t.Errorf("expected ErrInvalidJSONPath for %q, got %v", path, err)
+		}
+	}
+}
+
+func TestJSONPathEvaluate(t *testing.T) {
+	var document interface{}
+	payload := `{"ref": "refs/heads/main", "repository": {"full-name": "autops/api"}, "commits": [{"id": "abc"}, {"id": "def"}]}`
+	if err := json.Unmarshal([]byte(payload), &document); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"$.ref", "refs/heads/main"},
+		{"ref", "refs/heads/main"},
+		{"$.repository['full-name']", "autops/api"},
+		{"$.commits[1].id", "def"},
+		{"commits[0][\"id\"]", "abc"},
+	}
+
+	for _, tt := range tests {
+		path, err := ParseJSONPath(tt.path)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.path, err)
+			continue
+		}
+		value, err := path.Evaluate(document)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.path, err)
+		} else if value != tt.expected {
+			t.Errorf("expected %v for %q, got %v", tt.expected, tt.path, value)
+		}
+	}
+
+	notFound := []string{"$.missing", "$.commits[5]", "$.ref.sub", "$.repository[0]"}
+	for _, str := range notFound {
+		path, _ := ParseJSONPath(str)
+		if _, err := path.Evaluate(document); err != ErrJSONPathNotFound {
+			t.Errorf("expected ErrJSONPathNotFound for %q, got %v", str, err)
+		}
+	}
+
+	root, _ := ParseJSONPath("$")
+	if value, err := root.Evaluate(document); err != nil || value == nil {
+		t.Errorf("expected root document, got %v (%v)", value, err)
+	}
+}
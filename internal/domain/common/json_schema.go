@@ -0,0 +1,278 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// jsonSchemaAnnotations are the keywords accepted in a JSONSchema without effect on validation.
+var jsonSchemaAnnotations = map[string]bool{"$schema": true, "$id": true, "title": true, "description": true, "default": true, "examples": true}
+
+// JSONSchema is a subset of JSON Schema used to constrain the values of OBJECT attributes.
+//
+// The supported keywords are type (a single type name), properties, required, additionalProperties (a boolean),
+// items (a single schema), enum, minimum, maximum, minLength, maxLength, pattern, minItems and maxItems.
+// Other keywords are rejected rather than ignored, so that a schema never validates less than its author expects.
+type JSONSchema struct {
+	document             string
+	schemaType           string
+	properties           map[string]*JSONSchema
+	required             []string
+	additionalProperties *bool
+	items                *JSONSchema
+	enum                 []any
+	minimum              *float64
+	maximum              *float64
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	minItems             *int
+	maxItems             *int
+}
+
+// ParseJSONSchema parses a JSON Schema document.
+// Returns an error wrapping ErrInvalidJSONSchema if the document is malformed or uses unsupported keywords.
+func ParseJSONSchema(document string) (*JSONSchema, error) {
+	var raw any
+	if err := json.Unmarshal([]byte(document), &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidJSONSchema, err.Error())
+	}
+	schema, err := parseJSONSchemaNode(raw, "$")
+	if err != nil {
+		return nil, err
+	}
+	normalized, _ := json.Marshal(raw)
+	schema.document = string(normalized)
+	return schema, nil
+}
+
+// parseJSONSchemaNode parses the schema at the given location of the document.
+func parseJSONSchemaNode(raw any, location string) (*JSONSchema, error) {
+	node, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: a schema must be an object", ErrInvalidJSONSchema, location)
+	}
+	invalid := func(keyword string, expected string) error {
+		return fmt.Errorf("%w: %s.%s: expected %s", ErrInvalidJSONSchema, location, keyword, expected)
+	}
+	schema := &JSONSchema{}
+	for _, keyword := range sortedKeys(node) {
+		value := node[keyword]
+		var err error
+		switch keyword {
+		case "type":
+			name, _ := value.(string)
+			if !isJSONSchemaType(name) {
+				return nil, invalid(keyword, "one of object, array, string, number, integer, boolean or null")
+			}
+			schema.schemaType = name
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				return nil, invalid(keyword, "an object")
+			}
+			schema.properties = map[string]*JSONSchema{}
+			for name, property := range properties {
+				if schema.properties[name], err = parseJSONSchemaNode(property, location+".properties."+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			names, ok := value.([]any)
+			if !ok {
+				return nil, invalid(keyword, "an array of strings")
+			}
+			for _, name := range names {
+				str, ok := name.(string)
+				if !ok {
+					return nil, invalid(keyword, "an array of strings")
+				}
+				schema.required = append(schema.required, str)
+			}
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				return nil, invalid(keyword, "a boolean")
+			}
+			schema.additionalProperties = &allowed
+		case "items":
+			if schema.items, err = parseJSONSchemaNode(value, location+".items"); err != nil {
+				return nil, err
+			}
+		case "enum":
+			values, ok := value.([]any)
+			if !ok || len(values) == 0 {
+				return nil, invalid(keyword, "a non-empty array")
+			}
+			schema.enum = values
+		case "minimum", "maximum":
+			number, ok := value.(float64)
+			if !ok {
+				return nil, invalid(keyword, "a number")
+			}
+			if keyword == "minimum" {
+				schema.minimum = &number
+			} else {
+				schema.maximum = &number
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			number, ok := value.(float64)
+			if !ok || number < 0 || number != math.Trunc(number) {
+				return nil, invalid(keyword, "a non-negative integer")
+			}
+			count := int(number)
+			switch keyword {
+			case "minLength":
+				schema.minLength = &count
+			case "maxLength":
+				schema.maxLength = &count
+			case "minItems":
+				schema.minItems = &count
+			default:
+				schema.maxItems = &count
+			}
+		case "pattern":
+			str, _ := value.(string)
+			if schema.pattern, err = regexp.Compile(str); err != nil || str == "" {
+				return nil, invalid(keyword, "a regular expression")
+			}
+		default:
+			if !jsonSchemaAnnotations[keyword] {
+				return nil, fmt.Errorf("%w: %s: unsupported keyword %q", ErrInvalidJSONSchema, location, keyword)
+			}
+		}
+	}
+	return schema, nil
+}
+
+// isJSONSchemaType returns true if the name is a JSON Schema type.
+func isJSONSchemaType(name string) bool {
+	switch name {
+	case "object", "array", "string", "number", "integer", "boolean", "null":
+		return true
+	default:
+		return false
+	}
+}
+
+// sortedKeys returns the keys of a JSON object in lexical order.
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ToString returns the compact JSON document of the schema.
+func (s *JSONSchema) ToString() string {
+	return s.document
+}
+
+// Validate checks a decoded JSON value against the schema.
+// Returns every violation, prefixed by the JSONPath of the invalid value (e.g. "$.ports[1]: must be at most 65535").
+func (s *JSONSchema) Validate(value any) []string {
+	return s.validate(value, "$")
+}
+
+// validate checks the value at the given path of the document.
+func (s *JSONSchema) validate(value any, path string) []string {
+	violations := []string{}
+	add := func(message string) {
+		violations = append(violations, path+": "+message)
+	}
+	if s.schemaType != "" && !matchesJSONSchemaType(s.schemaType, value) {
+		add("must be " + articleFor(s.schemaType))
+		return violations
+	}
+	if len(s.enum) > 0 && !containsJSONValue(s.enum, value) {
+		values := make([]string, 0, len(s.enum))
+		for _, allowed := range s.enum {
+			encoded, _ := json.Marshal(allowed)
+			values = append(values, string(encoded))
+		}
+		add(allowedValuesMessage(values))
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, found := typed[name]; !found {
+				add(fmt.Sprintf("missing required property %q", name))
+			}
+		}
+		for _, name := range sortedKeys(typed) {
+			property, declared := s.properties[name]
+			switch {
+			case declared:
+				violations = append(violations, property.validate(typed[name], path+"."+name)...)
+			case s.additionalProperties != nil && !*s.additionalProperties:
+				violations = append(violations, path+"."+name+": additional properties are not allowed")
+			}
+		}
+	case []any:
+		violations = append(violations, prefixViolations(path, countMessages(len(typed), s.minItems, s.maxItems, "items"))...)
+		if s.items != nil {
+			for i, item := range typed {
+				violations = append(violations, s.items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case string:
+		violations = append(violations, prefixViolations(path, countMessages(len([]rune(typed)), s.minLength, s.maxLength, "characters"))...)
+		if s.pattern != nil && !s.pattern.MatchString(typed) {
+			add(patternMessage(s.pattern.String()))
+		}
+	case float64:
+		violations = append(violations, prefixViolations(path, boundMessages(typed, s.minimum, s.maximum))...)
+	}
+	return violations
+}
+
+// matchesJSONSchemaType returns true if the decoded JSON value has the given JSON Schema type.
+func matchesJSONSchemaType(schemaType string, value any) bool {
+	switch typed := value.(type) {
+	case map[string]any:
+		return schemaType == "object"
+	case []any:
+		return schemaType == "array"
+	case string:
+		return schemaType == "string"
+	case float64:
+		return schemaType == "number" || (schemaType == "integer" && typed == math.Trunc(typed))
+	case bool:
+		return schemaType == "boolean"
+	default:
+		return schemaType == "null" && value == nil
+	}
+}
+
+// containsJSONValue returns true if one of the decoded JSON values equals the value.
+func containsJSONValue(values []any, value any) bool {
+	encoded, _ := json.Marshal(value)
+	for _, candidate := range values {
+		if other, _ := json.Marshal(candidate); string(other) == string(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixViolations prefixes each violation by the JSONPath of the invalid value.
+func prefixViolations(path string, messages []string) []string {
+	for i, message := range messages {
+		messages[i] = path + ": " + message
+	}
+	return messages
+}
+
+// articleFor returns the name of a type with its indefinite article (e.g. "an object").
+func articleFor(name string) string {
+	if strings.ContainsAny(name[:1], "aeiou") {
+		return "an " + name
+	}
+	return "a " + name
+}
@@ -16,6 +16,16 @@ const (
 	TEMPLATE
 	// POLICY represents a policy resource.
 	POLICY
+	// SECRET represents a secret resource.
+	SECRET
+	// ENVIRONMENT represents a deployment environment of a project.
+	ENVIRONMENT
+	// GROUP represents a group of users sharing policies.
+	GROUP
+	// ROLE represents a set of policies assumed temporarily by trusted users.
+	ROLE
+	// SESSION represents the temporary credentials obtained by assuming a role.
+	SESSION
 )
 
 // ToString converts a ResourceType to its string representation.
@@ -31,6 +41,16 @@ func (t ResourceType) ToString() (string, error) {
 		return "template", nil
 	case POLICY:
 		return "policy", nil
+	case SECRET:
+		return "secret", nil
+	case ENVIRONMENT:
+		return "environment", nil
+	case GROUP:
+		return "group", nil
+	case ROLE:
+		return "role", nil
+	case SESSION:
+		return "session", nil
 	default:
 		return "", ErrInvalidResourceType
 	}
@@ -50,6 +70,16 @@ func ParseResourceType(str string) (ResourceType, error) {
 		return TEMPLATE, nil
 	case "policy":
 		return POLICY, nil
+	case "secret":
+		return SECRET, nil
+	case "environment":
+		return ENVIRONMENT, nil
+	case "group":
+		return GROUP, nil
+	case "role":
+		return ROLE, nil
+	case "session":
+		return SESSION, nil
 	default:
 		return -1, ErrInvalidResourceType
 	}
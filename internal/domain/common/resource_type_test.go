@@ -15,6 +15,11 @@ func TestResourceType_ToString(t *testing.T) {
 		{"Workflow", WORKFLOW, "workflow", false},
 		{"Template", TEMPLATE, "template", false},
 		{"Policy", POLICY, "policy", false},
+		{"Secret", SECRET, "secret", false},
+		{"Environment", ENVIRONMENT, "environment", false},
+		{"Group", GROUP, "group", false},
+		{"Role", ROLE, "role", false},
+		{"Session", SESSION, "session", false},
 		{"Invalid", ResourceType(100), "", true},
 	}
 
@@ -48,6 +53,11 @@ func TestParseResourceType(t *testing.T) {
 		{"ParseWorkflow", "workflow", WORKFLOW, false},
 		{"ParseTemplate", "template", TEMPLATE, false},
 		{"ParsePolicy", "policy", POLICY, false},
+		{"ParseSecret", "secret", SECRET, false},
+		{"ParseEnvironment", "environment", ENVIRONMENT, false},
+		{"ParseGroup", "group", GROUP, false},
+		{"ParseRole", "role", ROLE, false},
+		{"ParseSession", "session", SESSION, false},
 		{"ParseInvalid", "invalid", -1, true},
 	}
 
@@ -0,0 +1,363 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ChangeLevel classifies the changes introduced by a version, following semantic versioning.
+type ChangeLevel int
+
+const (
+	// PATCH changes are backward compatible fixes.
+	PATCH ChangeLevel = iota
+	// MINOR changes add backward compatible functionality.
+	MINOR
+	// MAJOR changes break the consumers of the previous version.
+	MAJOR
+)
+
+// ToString returns the string representation of the ChangeLevel.
+func (l ChangeLevel) ToString() string {
+	switch l {
+	case MAJOR:
+		return "major"
+	case MINOR:
+		return "minor"
+	default:
+		return "patch"
+	}
+}
+
+var semanticVersionRegex = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// SemanticVersion is a MAJOR.MINOR.PATCH version, optionally followed by a pre-release (e.g. 1.4.0-rc.1).
+type SemanticVersion struct {
+	major      int
+	minor      int
+	patch      int
+	prerelease string
+}
+
+// NewSemanticVersion creates a release SemanticVersion.
+func NewSemanticVersion(major int, minor int, patch int) *SemanticVersion {
+	return &SemanticVersion{
+		major: max(major, 0),
+		minor: max(minor, 0),
+		patch: max(patch, 0),
+	}
+}
+
+// ParseSemanticVersion parses a version like 1.4.2 or 2.0.0-beta.1, optionally prefixed by "v".
+// Build metadata is not supported.
+// Returns ErrInvalidSemanticVersion if the version is malformed.
+func ParseSemanticVersion(str string) (*SemanticVersion, error) {
+	matches := semanticVersionRegex.FindStringSubmatch(strings.TrimSpace(str))
+	if matches == nil {
+		return nil, ErrInvalidSemanticVersion
+	}
+	numbers := [3]int{}
+	for i := range numbers {
+		number, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return nil, ErrInvalidSemanticVersion
+		}
+		numbers[i] = number
+	}
+	return &SemanticVersion{
+		major:      numbers[0],
+		minor:      numbers[1],
+		patch:      numbers[2],
+		prerelease: matches[4],
+	}, nil
+}
+
+// GetMajor returns the major version number.
+func (v *SemanticVersion) GetMajor() int {
+	return v.major
+}
+
+// GetMinor returns the minor version number.
+func (v *SemanticVersion) GetMinor() int {
+	return v.minor
+}
+
+// GetPatch returns the patch version number.
+func (v *SemanticVersion) GetPatch() int {
+	return v.patch
+}
+
+// GetPrerelease returns the pre-release of the version (e.g. rc.1), or an empty string for a release.
+func (v *SemanticVersion) GetPrerelease() string {
+	return v.prerelease
+}
+
+// IsPrerelease returns true if the version is a pre-release.
+func (v *SemanticVersion) IsPrerelease() bool {
+	return v.prerelease != ""
+}
+
+// ToString returns the string representation of the version, without "v" prefix.
+func (v *SemanticVersion) ToString() string {
+	str := fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.prerelease != "" {
+		str += "-" + v.prerelease
+	}
+	return str
+}
+
+// Bump returns the release following the version for changes of the given level.
+// A pre-release is bumped to its own release, unless the changes require a higher version.
+func (v *SemanticVersion) Bump(level ChangeLevel) *SemanticVersion {
+	if v.prerelease != "" {
+		release := NewSemanticVersion(v.major, v.minor, v.patch)
+		if (level == MAJOR && v.minor == 0 && v.patch == 0) || (level == MINOR && v.patch == 0) || level == PATCH {
+			return release
+		}
+		return release.Bump(level)
+	}
+	switch level {
+	case MAJOR:
+		return NewSemanticVersion(v.major+1, 0, 0)
+	case MINOR:
+		return NewSemanticVersion(v.major, v.minor+1, 0)
+	default:
+		return NewSemanticVersion(v.major, v.minor, v.patch+1)
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether the version precedes, equals or follows the other one,
+// following the precedence rules of semantic versioning.
+func (v *SemanticVersion) Compare(other *SemanticVersion) int {
+	if c := v.compareCore(other); c != 0 {
+		return c
+	}
+	return comparePrereleases(v.prerelease, other.prerelease)
+}
+
+// compareCore compares the MAJOR.MINOR.PATCH numbers of two versions, ignoring their pre-release.
+func (v *SemanticVersion) compareCore(other *SemanticVersion) int {
+	for _, pair := range [][2]int{{v.major, other.major}, {v.minor, other.minor}, {v.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// comparePrereleases compares two pre-releases of the same version. A release follows all its pre-releases.
+func comparePrereleases(a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil && aNumber != bNumber:
+			if aNumber < bNumber {
+				return -1
+			}
+			return 1
+		case aErr == nil && bErr != nil:
+			return -1
+		case aErr != nil && bErr == nil:
+			return 1
+		case aErr != nil && bErr != nil && aParts[i] != bParts[i]:
+			return strings.Compare(aParts[i], bParts[i])
+		}
+	}
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	default:
+		return 0
+	}
+}
+
+var partialVersionRegex = regexp.MustCompile(`^v?(x|X|\*|0|[1-9][0-9]*)(?:\.(x|X|\*|0|[1-9][0-9]*))?(?:\.(x|X|\*|0|[1-9][0-9]*))?(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?$`)
+
+// versionComparator is a single constraint of a VersionRange, like >=1.2.0.
+type versionComparator struct {
+	operator string
+	version  *SemanticVersion
+}
+
+// matches returns true if the version satisfies the constraint.
+func (c versionComparator) matches(version *SemanticVersion) bool {
+	comparison := version.Compare(c.version)
+	switch c.operator {
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	default:
+		return comparison == 0
+	}
+}
+
+// VersionRange is a set of semantic versions, used to pin a template while accepting compatible upgrades.
+//
+// A range is a list of alternatives separated by "||", each being a list of space-separated constraints that must all match:
+//   - 1.2.3 or =1.2.3 matches exactly one version
+//   - >1.2.3, >=1.2.3, <2.0.0 and <=1.2.3 compare versions
+//   - 1.2.x, 1.2, 1.x and 1 match any version with the given prefix, and * or x match any version
+//   - ~1.2.3 matches patch upgrades (>=1.2.3 <1.3.0)
+//   - ^1.2.3 matches upgrades that do not change the leftmost non-zero number (>=1.2.3 <2.0.0, ^0.2.3 is >=0.2.3 <0.3.0)
+//
+// Pre-releases are only matched by alternatives with a constraint on a pre-release of the same MAJOR.MINOR.PATCH version.
+type VersionRange struct {
+	expression   string
+	alternatives [][]versionComparator
+}
+
+// ParseVersionRange parses a VersionRange expression like "^1.2.0" or ">=1.0.0 <3.0.0 || 4.x".
+// Returns ErrInvalidVersionRange if the expression is malformed.
+func ParseVersionRange(expression string) (*VersionRange, error) {
+	expression = strings.Join(strings.Fields(expression), " ")
+	if expression == "" {
+		return nil, ErrInvalidVersionRange
+	}
+	versionRange := &VersionRange{expression: expression}
+	for _, alternative := range strings.Split(expression, "||") {
+		tokens := strings.Fields(alternative)
+		if len(tokens) == 0 {
+			return nil, ErrInvalidVersionRange
+		}
+		comparators := []versionComparator{}
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			// Allow a space between an operator and its version, like ">= 1.2.0".
+			if strings.Trim(token, "<>=~^") == "" && i+1 < len(tokens) {
+				i++
+				token += tokens[i]
+			}
+			parsed, err := parseVersionConstraint(token)
+			if err != nil {
+				return nil, err
+			}
+			comparators = append(comparators, parsed...)
+		}
+		versionRange.alternatives = append(versionRange.alternatives, comparators)
+	}
+	return versionRange, nil
+}
+
+// parseVersionConstraint converts a single constraint of a range to the comparators it stands for.
+func parseVersionConstraint(token string) ([]versionComparator, error) {
+	operator := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(token, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	matches := partialVersionRegex.FindStringSubmatch(strings.TrimPrefix(token, operator))
+	if matches == nil {
+		return nil, ErrInvalidVersionRange
+	}
+	numbers := [3]int{-1, -1, -1}
+	for i := range numbers {
+		if matches[i+1] == "" || matches[i+1] == "x" || matches[i+1] == "X" || matches[i+1] == "*" {
+			break
+		}
+		numbers[i], _ = strconv.Atoi(matches[i+1])
+	}
+	major, minor, patch, prerelease := numbers[0], numbers[1], numbers[2], matches[4]
+	if prerelease != "" && patch < 0 {
+		return nil, ErrInvalidVersionRange
+	}
+	lower := &SemanticVersion{major: max(major, 0), minor: max(minor, 0), patch: max(patch, 0), prerelease: prerelease}
+	between := func(upper *SemanticVersion) []versionComparator {
+		return []versionComparator{{operator: ">=", version: lower}, {operator: "<", version: upper}}
+	}
+	if major < 0 {
+		if operator != "" && operator != "=" {
+			return nil, ErrInvalidVersionRange
+		}
+		return []versionComparator{{operator: ">=", version: NewSemanticVersion(0, 0, 0)}}, nil
+	}
+
+	switch operator {
+	case ">=", "<":
+		return []versionComparator{{operator: operator, version: lower}}, nil
+	case ">", "<=":
+		if patch >= 0 {
+			return []versionComparator{{operator: operator, version: lower}}, nil
+		}
+		// A partial version stands for every version with its prefix, so >1.2 excludes every 1.2.x version.
+		next := NewSemanticVersion(major+1, 0, 0)
+		if minor >= 0 {
+			next = NewSemanticVersion(major, minor+1, 0)
+		}
+		if operator == ">" {
+			return []versionComparator{{operator: ">=", version: next}}, nil
+		}
+		return []versionComparator{{operator: "<", version: next}}, nil
+	case "~":
+		if minor < 0 {
+			return between(NewSemanticVersion(major+1, 0, 0)), nil
+		}
+		return between(NewSemanticVersion(major, minor+1, 0)), nil
+	case "^":
+		switch {
+		case minor < 0 || major > 0:
+			return between(NewSemanticVersion(major+1, 0, 0)), nil
+		case patch < 0 || minor > 0:
+			return between(NewSemanticVersion(0, minor+1, 0)), nil
+		default:
+			return between(NewSemanticVersion(0, 0, patch+1)), nil
+		}
+	default:
+		switch {
+		case minor < 0:
+			return between(NewSemanticVersion(major+1, 0, 0)), nil
+		case patch < 0:
+			return between(NewSemanticVersion(major, minor+1, 0)), nil
+		default:
+			return []versionComparator{{operator: "=", version: lower}}, nil
+		}
+	}
+}
+
+// ToString returns the normalized expression of the range.
+func (r *VersionRange) ToString() string {
+	return r.expression
+}
+
+// Contains returns true if the version belongs to the range.
+func (r *VersionRange) Contains(version *SemanticVersion) bool {
+	for _, alternative := range r.alternatives {
+		matches, allowsPrerelease := true, false
+		for _, comparator := range alternative {
+			if !comparator.matches(version) {
+				matches = false
+				break
+			}
+			if comparator.version.prerelease != "" && comparator.version.compareCore(version) == 0 {
+				allowsPrerelease = true
+			}
+		}
+		if matches && (!version.IsPrerelease() || allowsPrerelease) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package common
+
+import "testing"
+
+func TestParseSemanticVersion(t *testing.T) {
+	version, err := ParseSemanticVersion("v1.4.2-rc.1")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if version.GetMajor() != 1 || version.GetMinor() != 4 || version.GetPatch() != 2 || version.GetPrerelease() != "rc.1" {
+		t.Errorf("unexpected version %s", version.ToString())
+	}
+	for _, invalid := range []string{"", "1.2", "01.2.3", "1.2.3-", "1.2.3+build", "a.b.c"} {
+		if _, err := ParseSemanticVersion(invalid); err != ErrInvalidSemanticVersion {
+			t.Errorf("expected err to be ErrInvalidSemanticVersion for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestSemanticVersionCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseSemanticVersion(ordered[i])
+		b, _ := ParseSemanticVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 || a.Compare(a) != 0 {
+			t.Errorf("expected %s to precede %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestSemanticVersionBump(t *testing.T) {
+	tests := []struct {
+		version  string
+		level    ChangeLevel
+		expected string
+	}{
+		{"1.4.2", MAJOR, "2.0.0"},
+		{"1.4.2", MINOR, "1.5.0"},
+		{"1.4.2", PATCH, "1.4.3"},
+		{"2.0.0-rc.1", MAJOR, "2.0.0"},
+		{"1.5.0-rc.1", MAJOR, "2.0.0"},
+		{"1.5.0-rc.1", MINOR, "1.5.0"},
+		{"1.4.3-rc.1", PATCH, "1.4.3"},
+	}
+	for _, test := range tests {
+		version, _ := ParseSemanticVersion(test.version)
+		if bumped := version.Bump(test.level).ToString(); bumped != test.expected {
+			t.Errorf("expected %s %s bump to be %s, got %s", test.version, test.level.ToString(), test.expected, bumped)
+		}
+	}
+}
+
+func TestVersionRange(t *testing.T) {
+	tests := []struct {
+		expression string
+		matching   []string
+		excluded   []string
+	}{
+		{"^1.2.0", []string{"1.2.0", "1.9.3"}, []string{"1.1.9", "2.0.0", "1.3.0-rc.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.8"}, []string{"1.3.0", "1.2.2"}},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"*", []string{"0.0.1", "7.3.0"}, []string{"7.3.0-beta"}},
+		{">= 1.0.0 <3.0.0 || 4.x", []string{"1.0.0", "2.9.9", "4.1.0"}, []string{"3.0.0", "5.0.0"}},
+		{">1.2 <=2", []string{"1.3.0", "2.8.0"}, []string{"1.2.9", "3.0.0"}},
+		{"=2.0.0-rc.1", []string{"2.0.0-rc.1"}, []string{"2.0.0", "2.0.0-rc.2"}},
+		{">=2.0.0-rc.1", []string{"2.0.0-rc.2", "2.0.0", "3.0.0"}, []string{"2.0.0-beta", "3.0.0-rc.1"}},
+	}
+	for _, test := range tests {
+		versionRange, err := ParseVersionRange(test.expression)
+		if err != nil {
+			t.Errorf("expected err to be nil for %q, got %v", test.expression, err)
+			continue
+		}
+		for _, str := range test.matching {
+			if version, _ := ParseSemanticVersion(str); !versionRange.Contains(version) {
+				t.Errorf("expected %q to contain %s", test.expression, str)
+			}
+		}
+		for _, str := range test.excluded {
+			if version, _ := ParseSemanticVersion(str); versionRange.Contains(version) {
+				t.Errorf("expected %q not to contain %s", test.expression, str)
+			}
+		}
+	}
+	for _, invalid := range []string{"", "||", "^", ">=x", "1.2-rc.1", "1.2.3.4", "~>1.2"} {
+		if _, err := ParseVersionRange(invalid); err != ErrInvalidVersionRange {
+			t.Errorf("expected err to be ErrInvalidVersionRange for %q, got %v", invalid, err)
+		}
+	}
+}
@@ -19,6 +19,8 @@ const (
 	SUCCESS
 	// FAILURE indicates that execution has failed.
 	FAILURE
+	// WAITING indicates that execution is paused until an external decision, such as a manual approval.
+	WAITING
 )
 
 // ToString returns the string representation of the Status.
@@ -30,6 +32,8 @@ func (s Status) ToString() string {
 		return "running"
 	case SUCCESS:
 		return "success"
+	case WAITING:
+		return "waiting"
 	default:
 		return "failure"
 	}
@@ -49,6 +53,8 @@ func ParseStatus(str string) (Status, error) {
 		return SUCCESS, nil
 	case "failure":
 		return FAILURE, nil
+	case "waiting":
+		return WAITING, nil
 	default:
 		return FAILURE, ErrStatusParseError
 	}
@@ -21,6 +21,10 @@ func TestStatusToString(t *testing.T) {
 	if state.ToString() != "failure" {
 		t.Errorf("expected failure, got %s", state.ToString())
 	}
+	state = WAITING
+	if state.ToString() != "waiting" {
+		t.Errorf("expected waiting, got %s", state.ToString())
+	}
 }
 
 func TestStatusParsing(t *testing.T) {
@@ -56,6 +60,14 @@ func TestStatusParsing(t *testing.T) {
 		t.Errorf("expected state to be %d, got %d", FAILURE, state)
 	}
 
+	state, err = ParseStatus("Waiting")
+	if err != nil {
+		t.Errorf("expected error to be nil, got %s", err.Error())
+	}
+	if state != WAITING {
+		t.Errorf("expected state to be %d, got %d", WAITING, state)
+	}
+
 	state, err = ParseStatus("not-existing")
 	if err != ErrStatusParseError {
 		t.Errorf("expected error to be %s, got %s", ErrStatusParseError.Error(), err.Error())
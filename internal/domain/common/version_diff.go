@@ -0,0 +1,136 @@
+package common
+
+import "sort"
+
+// ChangeType defines how an element changed between two versions.
+type ChangeType int
+
+const (
+	// ADDED indicates that the element only exists in the newer version.
+	ADDED ChangeType = iota
+	// REMOVED indicates that the element only exists in the older version.
+	REMOVED
+	// MODIFIED indicates that the element exists in both versions with different values.
+	MODIFIED
+)
+
+// ToString returns the string representation of the ChangeType.
+func (c ChangeType) ToString() string {
+	switch c {
+	case ADDED:
+		return "added"
+	case REMOVED:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+// AttributeChange describes how an input or output, identified by its name, changed between two versions.
+type AttributeChange struct {
+	name          string
+	changeType    ChangeType
+	changedFields []string
+}
+
+// NewAttributeChange creates an AttributeChange, listing the changed fields of a MODIFIED attribute.
+func NewAttributeChange(name string, changeType ChangeType, changedFields []string) *AttributeChange {
+	return &AttributeChange{
+		name:          name,
+		changeType:    changeType,
+		changedFields: append([]string(nil), changedFields...),
+	}
+}
+
+// GetName returns the name of the attribute.
+func (c *AttributeChange) GetName() string {
+	return c.name
+}
+
+// GetChangeType returns how the attribute changed.
+func (c *AttributeChange) GetChangeType() ChangeType {
+	return c.changeType
+}
+
+// ListChangedFields returns the fields of a MODIFIED attribute whose value changed (e.g. "type" or "default_value").
+func (c *AttributeChange) ListChangedFields() []string {
+	return append([]string(nil), c.changedFields...)
+}
+
+// VersionDiff describes the changes between two versions of a template or a workflow.
+type VersionDiff struct {
+	fromVersion    int
+	toVersion      int
+	contentChanged bool
+	inputs         []*AttributeChange
+	outputs        []*AttributeChange
+}
+
+// NewVersionDiff creates the VersionDiff between two versioned sources with the given input and output changes.
+// The content is considered changed if the content hashes differ, or if they are not both recorded and the sources differ.
+func NewVersionDiff(from *VersionedSource, to *VersionedSource, inputs []*AttributeChange, outputs []*AttributeChange) *VersionDiff {
+	contentChanged := from.sourcePath != to.sourcePath || from.commit != to.commit
+	if from.contentHash != "" && to.contentHash != "" {
+		contentChanged = from.contentHash != to.contentHash
+	}
+	return &VersionDiff{
+		fromVersion:    from.version,
+		toVersion:      to.version,
+		contentChanged: contentChanged,
+		inputs:         inputs,
+		outputs:        outputs,
+	}
+}
+
+// GetFromVersion returns the older version of the comparison.
+func (d *VersionDiff) GetFromVersion() int {
+	return d.fromVersion
+}
+
+// GetToVersion returns the newer version of the comparison.
+func (d *VersionDiff) GetToVersion() int {
+	return d.toVersion
+}
+
+// IsContentChanged returns true if the source content differs between the two versions.
+func (d *VersionDiff) IsContentChanged() bool {
+	return d.contentChanged
+}
+
+// ListInputChanges returns the changes of the inputs, sorted by name.
+func (d *VersionDiff) ListInputChanges() []*AttributeChange {
+	return d.inputs
+}
+
+// ListOutputChanges returns the changes of the outputs, sorted by name.
+func (d *VersionDiff) ListOutputChanges() []*AttributeChange {
+	return d.outputs
+}
+
+// DiffAttributes compares two sets of attributes by name, returning the changes sorted by name.
+// The changedFields function lists the fields that differ between two attributes with the same name.
+func DiffAttributes[T any](previous []T, current []T, name func(T) string, changedFields func(T, T) []string) []*AttributeChange {
+	previousByName := make(map[string]T, len(previous))
+	for _, attribute := range previous {
+		previousByName[name(attribute)] = attribute
+	}
+	changes := []*AttributeChange{}
+	for _, attribute := range current {
+		old, found := previousByName[name(attribute)]
+		if !found {
+			changes = append(changes, NewAttributeChange(name(attribute), ADDED, nil))
+			continue
+		}
+		delete(previousByName, name(attribute))
+		if fields := changedFields(old, attribute); len(fields) > 0 {
+			changes = append(changes, NewAttributeChange(name(attribute), MODIFIED, fields))
+		}
+	}
+	for removed := range previousByName {
+		changes = append(changes, NewAttributeChange(removed, REMOVED, nil))
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].name < changes[j].name
+	})
+	return changes
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffAttributes(t *testing.T) {
+	name := func(s string) string { return strings.Split(s, "=")[0] }
+	changedFields := func(a string, b string) []string {
+		if a != b {
+			return []string{"value"}
+		}
+		return nil
+	}
+	changes := DiffAttributes([]string{"a=1", "b=1", "c=1"}, []string{"d=1", "c=2", "a=1"}, name, changedFields)
+	expected := []struct {
+		name       string
+		changeType ChangeType
+	}{
+		{"b", REMOVED},
+		{"c", MODIFIED},
+		{"d", ADDED},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d", len(expected), len(changes))
+	}
+	for i, change := range changes {
+		if change.GetName() != expected[i].name || change.GetChangeType() != expected[i].changeType {
+			t.Errorf("expected %s to be %s, got %s %s", expected[i].name, expected[i].changeType.ToString(), change.GetName(), change.GetChangeType().ToString())
+		}
+	}
+	if fields := changes[1].ListChangedFields(); len(fields) != 1 || fields[0] != "value" {
+		t.Errorf("unexpected changed fields %v", fields)
+	}
+}
+
+func TestNewVersionDiff(t *testing.T) {
+	from, _ := NewVersionedSource("/path/to/file.zip", 1)
+	to, _ := from.ForkWithNewVersion("/path/to/file.zip")
+	if diff := NewVersionDiff(from, to, nil, nil); diff.IsContentChanged() || diff.GetFromVersion() != 1 || diff.GetToVersion() != 2 {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+	from.SetContentHash("sha256:" + strings.Repeat("0", 64))
+	to.SetContentHash("sha256:" + strings.Repeat("1", 64))
+	if !NewVersionDiff(from, to, nil, nil).IsContentChanged() {
+		t.Error("expected different content hashes to change the content")
+	}
+}
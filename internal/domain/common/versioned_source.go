@@ -1,11 +1,30 @@
 package common
 
+import (
+	"regexp"
+	"strings"
+)
+
+// MAX_CHANGELOG_LENGTH bounds the length of the changelog of a version.
+const MAX_CHANGELOG_LENGTH = 4096
+
+var contentHashRegex = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
 // VersionedSource encapsulates a source path and its version number.
 // It is used by entities like Template and Workflow to manage versioning
-// and track the source location (either a local file path or a URL).
+// and track the source location (either a local file path, a URL or a git source).
+//
+// The sources of a git source are pinned by the commit its ref resolved to, so that a version always designates the same sources.
+// Each version also records the hash of its resolved source content, its author and its changelog,
+// and may carry a semantic version alongside its version number.
 type VersionedSource struct {
-	sourcePath string
-	version    int
+	sourcePath      string
+	version         int
+	semanticVersion *SemanticVersion
+	commit          string
+	contentHash     string
+	author          *Identifier
+	changelog       string
 }
 
 // NewVersionnedSource creates a new VersionedSource with the provided sourcePath and version.
@@ -33,19 +52,117 @@ func (t *VersionedSource) GetVersion() int {
 	return t.version
 }
 
-// setSourcePath validates and sets the template's source path.
-// The path must be either a valid local file path or a URL.
-// This is an internal method not exported outside the domain.
+// GetSemanticVersion returns the semantic version of the entity, or nil if it has none.
+func (t *VersionedSource) GetSemanticVersion() *SemanticVersion {
+	return t.semanticVersion
+}
+
+// GetReleaseVersion returns the semantic version of the entity,
+// or <version>.0.0 if it has none, so that versions without semantic version can be compared to the others.
+func (t *VersionedSource) GetReleaseVersion() *SemanticVersion {
+	if t.semanticVersion != nil {
+		return t.semanticVersion
+	}
+	return NewSemanticVersion(t.version, 0, 0)
+}
+
+// SetSemanticVersion sets the semantic version of the entity, or clears it if nil.
+func (t *VersionedSource) SetSemanticVersion(version *SemanticVersion) {
+	t.semanticVersion = version
+}
+
+// SetSourcePath validates and sets the template's source path, clearing the commit and content hash recorded for the previous path.
+// The path must be either a valid local file path, a URL, or a git source (see ParseGitSource).
 func (t *VersionedSource) SetSourcePath(path string) error {
-	if !IsSyntacticallySafePath(path) || (!IsValidURL(path) && !IsPlausibleLocalPath(path)) {
+	if IsGitSource(path) {
+		if _, err := ParseGitSource(path); err != nil {
+			return err
+		}
+	} else if !IsSyntacticallySafePath(path) || (!IsValidURL(path) && !IsPlausibleLocalPath(path)) {
 		return ErrInvalidPathOrUrl
 	}
 	t.sourcePath = path
+	t.commit = ""
+	t.contentHash = ""
+	return nil
+}
+
+// GetGitSource returns the git source of the entity, or nil if its sources are not fetched from a git repository.
+func (t *VersionedSource) GetGitSource() *GitSource {
+	source, err := ParseGitSource(t.sourcePath)
+	if err != nil {
+		return nil
+	}
+	return source
+}
+
+// GetCommit returns the commit the git source ref resolved to, or an empty string if it has not been resolved yet.
+func (t *VersionedSource) GetCommit() string {
+	return t.commit
+}
+
+// SetCommit pins the sources of the version to the commit the git source ref resolved to.
+// Returns an error if the source is not a git source or if the commit is not a full SHA-1 identifier.
+func (t *VersionedSource) SetCommit(commit string) error {
+	if !IsGitSource(t.sourcePath) {
+		return ErrInvalidGitSource
+	}
+	if !IsValidGitCommit(commit) {
+		return ErrInvalidGitCommit
+	}
+	t.commit = commit
+	return nil
+}
+
+// GetContentHash returns the hash of the resolved source content of the version, or an empty string if it has not been recorded yet.
+func (t *VersionedSource) GetContentHash() string {
+	return t.contentHash
+}
+
+// SetContentHash records the hash of the resolved source content of the version, formatted as sha256:<hex digest>.
+// Returns an error if the hash is malformed.
+func (t *VersionedSource) SetContentHash(hash string) error {
+	if !contentHashRegex.MatchString(hash) {
+		return ErrInvalidContentHash
+	}
+	t.contentHash = hash
+	return nil
+}
+
+// GetAuthor returns the identifier of the user who created the version, or nil if unknown.
+func (t *VersionedSource) GetAuthor() *Identifier {
+	return t.author
+}
+
+// SetAuthor sets the user who created the version.
+// Returns an error if the identifier is not a user identifier.
+func (t *VersionedSource) SetAuthor(author *Identifier) error {
+	if author == nil || author.GetType() != USER {
+		return ErrInvalidAuthor
+	}
+	t.author = author
+	return nil
+}
+
+// GetChangelog returns the description of the changes introduced by the version.
+func (t *VersionedSource) GetChangelog() string {
+	return t.changelog
+}
+
+// SetChangelog sets the description of the changes introduced by the version.
+// Returns an error if the changelog exceeds MAX_CHANGELOG_LENGTH characters.
+func (t *VersionedSource) SetChangelog(changelog string) error {
+	changelog = strings.TrimSpace(changelog)
+	if len(changelog) > MAX_CHANGELOG_LENGTH {
+		return ErrInvalidChangelog
+	}
+	t.changelog = changelog
 	return nil
 }
 
 // ForkWithNewVersion creates a deep copy of the current template with an incremented version,
-// and assigns it a new source path.
+// and assigns it a new source path, whose git ref and content are resolved again.
+// The semantic version, author and changelog of the new version are left empty.
 // Returns an error if the new source path is invalid.
 func (v *VersionedSource) ForkWithNewVersion(newSourcePath string) (*VersionedSource, error) {
 	newVersion := *v
@@ -54,5 +171,8 @@ func (v *VersionedSource) ForkWithNewVersion(newSourcePath string) (*VersionedSo
 		return nil, err
 	}
 	newVersion.version = v.version + 1
+	newVersion.semanticVersion = nil
+	newVersion.author = nil
+	newVersion.changelog = ""
 	return &newVersion, nil
 }
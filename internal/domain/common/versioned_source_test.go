@@ -1,6 +1,9 @@
 package common
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestNewVersionedSourceErr(t *testing.T) {
 	path := "Invalid file path"
@@ -33,3 +36,68 @@ func TestForkTemplate(t *testing.T) {
 		t.Error("expected err to be ErrInvalidPathOrUrl")
 	}
 }
+
+func TestVersionedSourceGitCommit(t *testing.T) {
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	local, _ := NewVersionedSource("/path/to/file.zip", 1)
+	if local.GetGitSource() != nil {
+		t.Error("expected a local path not to be a git source")
+	}
+	if err := local.SetCommit(commit); err != ErrInvalidGitSource {
+		t.Errorf("expected err to be ErrInvalidGitSource, got %v", err)
+	}
+
+	versionedSource, err := NewVersionedSource("git::https://host/repo.git//modules/vpc?ref=v1.2.0", 1)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if versionedSource.GetGitSource() == nil {
+		t.Error("expected the git source to be parsed")
+	}
+	if err := versionedSource.SetCommit("v1.2.0"); err != ErrInvalidGitCommit {
+		t.Errorf("expected err to be ErrInvalidGitCommit, got %v", err)
+	}
+	if err := versionedSource.SetCommit(commit); err != nil || versionedSource.GetCommit() != commit {
+		t.Errorf("expected the commit to be pinned, got %s (%v)", versionedSource.GetCommit(), err)
+	}
+
+	newVersion, _ := versionedSource.ForkWithNewVersion("git::https://host/repo.git//modules/vpc?ref=v1.3.0")
+	if newVersion.GetCommit() != "" {
+		t.Error("expected the commit to be resolved again for the new version")
+	}
+	if _, err := versionedSource.ForkWithNewVersion("git::https://host/repo.git?ref=-x"); err != ErrInvalidGitSource {
+		t.Errorf("expected err to be ErrInvalidGitSource, got %v", err)
+	}
+}
+
+func TestVersionedSourceHistory(t *testing.T) {
+	versionedSource, _ := NewVersionedSource("/path/to/file.zip", 1)
+	hash := "sha256:" + strings.Repeat("ab", 32)
+	if err := versionedSource.SetContentHash("md5:abc"); err != ErrInvalidContentHash {
+		t.Errorf("expected err to be ErrInvalidContentHash, got %v", err)
+	}
+	if err := versionedSource.SetContentHash(hash); err != nil || versionedSource.GetContentHash() != hash {
+		t.Errorf("expected the content hash to be recorded, got %v", err)
+	}
+
+	author, _ := NewIdentifier("autops::user:1234567890")
+	project, _ := NewIdentifier("autops::project:1234567890")
+	if err := versionedSource.SetAuthor(project); err != ErrInvalidAuthor {
+		t.Errorf("expected err to be ErrInvalidAuthor, got %v", err)
+	}
+	if err := versionedSource.SetAuthor(author); err != nil || versionedSource.GetAuthor() != author {
+		t.Errorf("expected the author to be set, got %v", err)
+	}
+	if err := versionedSource.SetChangelog(strings.Repeat("a", MAX_CHANGELOG_LENGTH+1)); err != ErrInvalidChangelog {
+		t.Errorf("expected err to be ErrInvalidChangelog, got %v", err)
+	}
+	versionedSource.SetChangelog("  Add the vpc module ")
+	if versionedSource.GetChangelog() != "Add the vpc module" {
+		t.Errorf("unexpected changelog %q", versionedSource.GetChangelog())
+	}
+
+	newVersion, _ := versionedSource.ForkWithNewVersion("/new/path/to/file")
+	if newVersion.GetContentHash() != "" || newVersion.GetAuthor() != nil || newVersion.GetChangelog() != "" {
+		t.Error("expected the new version to have its own content hash, author and changelog")
+	}
+}
@@ -3,7 +3,20 @@ package identity
 import "errors"
 
 var (
-	ErrAttachedPolicyNotFound = errors.New("cannot find a policy with the provided identifer attached to the current restricted entity")
-	ErrInvalidEmail           = errors.New("the provided string does not match a valid email address")
-	ErrInvalidUsername        = errors.New("username length must be 3-30 characters, and only composed of letters, number and underscores '_'")
+	ErrAttachedPolicyNotFound   = errors.New("cannot find a policy with the provided identifer attached to the current restricted entity")
+	ErrInvalidEmail             = errors.New("the provided string does not match a valid email address")
+	ErrInvalidUsername          = errors.New("username length must be 3-30 characters, and only composed of letters, number and underscores '_'")
+	ErrMemberAlreadyPresent     = errors.New("the user is already a member of the group")
+	ErrMemberNotFound           = errors.New("the user is not a member of the group")
+	ErrPolicyAlreadyAttached    = errors.New("the policy is already attached to the current restricted entity")
+	ErrInvalidTrustedPrincipal  = errors.New("only users and groups can be trusted to assume a role")
+	ErrPrincipalAlreadyTrusted  = errors.New("the principal is already trusted to assume the role")
+	ErrTrustedPrincipalNotFound = errors.New("the principal is not trusted to assume the role")
+	ErrRoleNotTrusted           = errors.New("the user is not trusted to assume the role")
+	ErrRoleChaining             = errors.New("a role cannot be assumed with the credentials of another role")
+	ErrPolicyExceedsBoundary    = errors.New("the policy allows actions outside of the permission boundary of the authenticated user")
+	ErrBoundaryNotFound         = errors.New("the user has no permission boundary")
+	ErrBoundaryRequired         = errors.New("a user limited by a permission boundary cannot remove permission boundaries")
+	ErrTagsRequireNoBoundary    = errors.New("a user limited by a permission boundary cannot change the tags of users")
+	ErrInvalidSessionDuration   = errors.New("the session duration must be between 15m and the maximum session duration of the role, at most 12h")
 )
@@ -0,0 +1,79 @@
+package identity
+
+import (
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// Group represents a set of users sharing the policies attached to the group, in addition to their own policies.
+type Group struct {
+	common.NamedEntity
+	common.TaggedEntity
+	RestrictedEntity
+	members *common.List[*common.Identifier]
+}
+
+// GroupComparator is used to compare two Group instances based on their identifier.
+type GroupComparator struct{}
+
+// Compare returns a comparison between two Group identifiers.
+func (GroupComparator) Compare(a *Group, b *Group) int {
+	return strings.Compare(a.GetIdentifier().ToString(), b.GetIdentifier().ToString())
+}
+
+// NewGroup creates a new Group with a generated identifier, no member and no attached policy.
+// Returns an error if the name or description is invalid.
+func NewGroup(name string, description string) (*Group, error) {
+	date := common.CurrentTimestamp()
+	identifier, err := common.BuildGroupIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return ExistingGroup(identifier.ToString(), name, description, []*common.Identifier{}, []*policy.Policy{}, date, date)
+}
+
+// ExistingGroup reconstructs a Group from stored data, with the identifiers of its members and its attached policies.
+func ExistingGroup(identifier string, name string, description string, members []*common.Identifier, attachedPolicies []*policy.Policy, createdAt string, updatedAt string) (*Group, error) {
+	namedEntity, err := common.ExistingNamedEntity(identifier, name, description, createdAt, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &Group{
+		NamedEntity:      *namedEntity,
+		TaggedEntity:     *common.NewTaggedEntity(),
+		RestrictedEntity: *ExistingRestrictedEntity(attachedPolicies),
+		members:          common.NewList(common.IdentifierComparator{}, members),
+	}, nil
+}
+
+// ListMembers returns the identifiers of the users of the group.
+func (g *Group) ListMembers() []*common.Identifier {
+	return g.members.Items()
+}
+
+// HasMember returns true if the user is a member of the group.
+func (g *Group) HasMember(userId *common.Identifier) bool {
+	return g.members.Contains(userId)
+}
+
+// AddMember adds the user to the group.
+// Returns ErrMemberAlreadyPresent if the user is already a member.
+func (g *Group) AddMember(userId *common.Identifier) error {
+	if g.HasMember(userId) {
+		return ErrMemberAlreadyPresent
+	}
+	g.members.Append(userId)
+	return nil
+}
+
+// RemoveMember removes the user from the group.
+// Returns ErrMemberNotFound if the user is not a member.
+func (g *Group) RemoveMember(userId *common.Identifier) error {
+	if !g.HasMember(userId) {
+		return ErrMemberNotFound
+	}
+	g.members.Remove(userId)
+	return nil
+}
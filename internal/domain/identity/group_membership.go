@@ -0,0 +1,78 @@
+package identity
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// membershipPageSize is the number of groups loaded at once when loading the groups of a user.
+const membershipPageSize = 100
+
+// membershipRepository is a UserRepository loading the groups of the users it finds.
+type membershipRepository struct {
+	UserRepository
+	groups GroupRepository
+}
+
+// WithGroupMemberships returns a UserRepository finding users in the given repository,
+// and setting the groups they are a member of so that their group policies are evaluated.
+func WithGroupMemberships(users UserRepository, groups GroupRepository) UserRepository {
+	return &membershipRepository{
+		UserRepository: users,
+		groups:         groups,
+	}
+}
+
+// loadGroups sets the groups of the user, if any.
+func (r *membershipRepository) loadGroups(user *User) (*User, error) {
+	if user == nil {
+		return nil, nil
+	}
+	groups := []*Group{}
+	for offset := 0; ; offset += membershipPageSize {
+		page, err := r.groups.FindByMember(*user.GetIdentifier(), offset, membershipPageSize)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page...)
+		if len(page) < membershipPageSize {
+			break
+		}
+	}
+	user.SetGroups(groups)
+	return user, nil
+}
+
+func (r *membershipRepository) FindById(id common.Identifier, offset int, limit int) (*User, error) {
+	user, err := r.UserRepository.FindById(id, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadGroups(user)
+}
+
+func (r *membershipRepository) FindAll(offset int, limit int) ([]*User, error) {
+	users, err := r.UserRepository.FindAll(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if _, err := r.loadGroups(user); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (r *membershipRepository) FindByUsername(username string, offset int, limit int) (*User, error) {
+	user, err := r.UserRepository.FindByUsername(username, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadGroups(user)
+}
+
+func (r *membershipRepository) FindByEmail(email string, offset int, limit int) (*User, error) {
+	user, err := r.UserRepository.FindByEmail(email, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.loadGroups(user)
+}
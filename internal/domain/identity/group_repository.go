@@ -0,0 +1,14 @@
+package identity
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+type GroupRepository interface {
+	Create(group *Group) error
+	Update(group *Group) error
+	Delete(groupId common.Identifier) error
+
+	FindById(id common.Identifier) (*Group, error)
+	FindAll(offset int, limit int) ([]*Group, error)
+	// FindByMember returns the groups the user is a member of.
+	FindByMember(userId common.Identifier, offset int, limit int) ([]*Group, error)
+}
@@ -0,0 +1,109 @@
+package identity_test
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+func TestGroupMembers(t *testing.T) {
+	group, err := identity.NewGroup("platform", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if group.GetIdentifier().GetType() != common.GROUP {
+		t.Errorf("expected a group identifier, got %s", group.GetIdentifier().ToString())
+	}
+	user, _ := identity.NewUser("user@example.com", "user")
+	if err := group.AddMember(user.GetIdentifier()); err != nil || !group.HasMember(user.GetIdentifier()) {
+		t.Fatalf("expected the user to be a member, got %v", err)
+	}
+	if err := group.AddMember(user.GetIdentifier()); err != identity.ErrMemberAlreadyPresent {
+		t.Errorf("expected ErrMemberAlreadyPresent, got %v", err)
+	}
+	if err := group.RemoveMember(user.GetIdentifier()); err != nil || len(group.ListMembers()) != 0 {
+		t.Errorf("expected the user to be removed, got %v", err)
+	}
+	if err := group.RemoveMember(user.GetIdentifier()); err != identity.ErrMemberNotFound {
+		t.Errorf("expected ErrMemberNotFound, got %v", err)
+	}
+}
+
+func TestUserGroupPolicies(t *testing.T) {
+	projectId, _ := common.BuildProjectIdentifier()
+	run, _ := policy.ParsePolicyAction("workflow:Run")
+	workflowId, _ := common.BuildWorkflowIdentifier(projectId.ToString())
+	allow, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{workflowId.ToString()})
+	deny, _ := policy.ParsePolicyStatement(0, "Deny", []string{"workflow:Run"}, []string{workflowId.ToString()})
+	runners, _ := policy.NewPolicy(projectId.ToString(), "runners", "", []*policy.PolicyStatement{allow})
+	freeze, _ := policy.NewPolicy(projectId.ToString(), "freeze", "", []*policy.PolicyStatement{deny})
+
+	user, _ := identity.NewUser("user@example.com", "user")
+	developers, _ := identity.NewGroup("developers", "")
+	developers.AttachPolicy(runners)
+	if user.IsAllowed(workflowId, run, nil) {
+		t.Errorf("expected the user not to be allowed without groups")
+	}
+	user.AttachPolicy(runners)
+	user.SetGroups([]*identity.Group{developers})
+	if !user.IsAllowed(workflowId, run, nil) || len(user.ListEffectivePolicies()) != 1 {
+		t.Errorf("expected the policy attached to the user and its group to apply once, got %d", len(user.ListEffectivePolicies()))
+	}
+
+	frozen, _ := identity.NewGroup("frozen", "")
+	frozen.AttachPolicy(freeze)
+	user.SetGroups([]*identity.Group{developers, frozen})
+	if user.GetPermission(workflowId, run, nil) != policy.DENY || user.ExplainPermission(workflowId, run, nil).GetReason() != policy.EXPLICIT_DENY {
+		t.Errorf("expected the deny of a group to take precedence")
+	}
+	if user.RestrictedEntity.GetPermission(workflowId, run, nil) != policy.ALLOW {
+		t.Errorf("expected the attached policies alone to allow the action")
+	}
+}
+
+// fakeUserRepository and fakeGroupRepository are in-memory repositories used to test WithGroupMemberships.
+type fakeUserRepository struct {
+	identity.UserRepository
+	user *identity.User
+}
+
+func (f *fakeUserRepository) FindById(id common.Identifier, offset int, limit int) (*identity.User, error) {
+	if f.user.GetIdentifier().ToString() != id.ToString() {
+		return nil, nil
+	}
+	return f.user, nil
+}
+
+type fakeGroupRepository struct {
+	identity.GroupRepository
+	groups []*identity.Group
+}
+
+func (f *fakeGroupRepository) FindByMember(userId common.Identifier, offset int, limit int) ([]*identity.Group, error) {
+	groups := []*identity.Group{}
+	for _, group := range f.groups {
+		if group.HasMember(&userId) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func TestWithGroupMemberships(t *testing.T) {
+	user, _ := identity.NewUser("user@example.com", "user")
+	member, _ := identity.NewGroup("member", "")
+	member.AddMember(user.GetIdentifier())
+	other, _ := identity.NewGroup("other", "")
+	users := identity.WithGroupMemberships(&fakeUserRepository{user: user}, &fakeGroupRepository{groups: []*identity.Group{member, other}})
+
+	found, err := users.FindById(*user.GetIdentifier(), 0, 1)
+	if err != nil || found != user || len(found.ListGroups()) != 1 || found.ListGroups()[0] != member {
+		t.Errorf("expected the user to be loaded with its group, got %v", err)
+	}
+	stranger, _ := common.BuildUserIdentifier()
+	if found, err := users.FindById(*stranger, 0, 1); found != nil || err != nil {
+		t.Errorf("expected no user, got %v", err)
+	}
+}
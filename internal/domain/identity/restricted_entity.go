@@ -57,3 +57,38 @@ func (r *RestrictedEntity) DetachPolicy(id *common.Identifier) error {
 func (r *RestrictedEntity) AttachPolicy(policy *policy.Policy) {
 	r.attachedPolicies.Append(policy)
 }
+
+// GetPermission determines the effect of every attached policy for the given action on the specified resource,
+// in the context of the request against which the statement conditions are evaluated.
+// An explicit DENY in any policy takes precedence over ALLOW, and UNSPECIFIED is returned if no policy applies.
+func (r *RestrictedEntity) GetPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) policy.PolicyEffect {
+	return getPermission(r.attachedPolicies.Items(), resourceIdentifier, action, context)
+}
+
+// getPermission determines the effect of the policies for the given action on the specified resource, in the context of the request.
+func getPermission(policies []*policy.Policy, resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) policy.PolicyEffect {
+	allowed := false
+	for _, p := range policies {
+		effect := p.GetPermission(resourceIdentifier, action, context)
+		if effect == policy.DENY {
+			return policy.DENY
+		} else if effect == policy.ALLOW {
+			allowed = true
+		}
+	}
+	if allowed {
+		return policy.ALLOW
+	}
+	return policy.UNSPECIFIED
+}
+
+// IsAllowed returns true if the attached policies explicitly allow the action on the resource without denying it.
+func (r *RestrictedEntity) IsAllowed(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) bool {
+	return r.GetPermission(resourceIdentifier, action, context) == policy.ALLOW
+}
+
+// ExplainPermission explains the effect of the attached policies for the given action on the specified resource,
+// listing every statement that applies.
+func (r *RestrictedEntity) ExplainPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) *policy.Decision {
+	return policy.Explain(r.attachedPolicies.Items(), resourceIdentifier, action, context)
+}
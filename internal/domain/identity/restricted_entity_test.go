@@ -65,3 +65,29 @@ func TestDetachPolicyNotFound(t *testing.T) {
 		t.Errorf("expected ErrAttachedPolicyNotFound, got %v", err)
 	}
 }
+
+func TestRestrictedEntityGetPermission(t *testing.T) {
+	resource, _ := common.NewIdentifier("autops::project:1234567890:workflow:abcdefghij")
+	allow, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{resource}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	deny, _ := policy.NewPolicyStatement(policy.DENY, []*common.Identifier{resource}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	allowPolicy, _ := policy.NewPolicy("autops::project:1234567890", "allow", "", []*policy.PolicyStatement{allow})
+	denyPolicy, _ := policy.NewPolicy("autops::project:1234567890", "deny", "", []*policy.PolicyStatement{deny})
+
+	entity := identity.NewRestrictedEntity()
+	if entity.GetPermission(resource, policy.RUN_WORKFLOW, nil) != policy.UNSPECIFIED {
+		t.Error("expected UNSPECIFIED without attached policies")
+	}
+
+	entity.AttachPolicy(allowPolicy)
+	if !entity.IsAllowed(resource, policy.RUN_WORKFLOW, nil) {
+		t.Error("expected action to be allowed")
+	}
+	if entity.IsAllowed(resource, policy.DELETE_WORKFLOW, nil) {
+		t.Error("expected other actions not to be allowed")
+	}
+
+	entity.AttachPolicy(denyPolicy)
+	if entity.GetPermission(resource, policy.RUN_WORKFLOW, nil) != policy.DENY {
+		t.Error("expected DENY to take precedence over ALLOW")
+	}
+}
@@ -0,0 +1,153 @@
+package identity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+const (
+	// MIN_SESSION_DURATION is the shortest duration of the credentials obtained by assuming a role.
+	MIN_SESSION_DURATION = 15 * time.Minute
+	// MAX_SESSION_DURATION is the longest duration of the credentials obtained by assuming a role.
+	MAX_SESSION_DURATION = 12 * time.Hour
+	// DEFAULT_SESSION_DURATION is the maximum session duration of a role when none is provided.
+	DEFAULT_SESSION_DURATION = time.Hour
+)
+
+// Role represents a set of policies that trusted users can temporarily assume, instead of having them attached permanently.
+// The trust policy of the role lists the users and groups whose members may assume it.
+type Role struct {
+	common.NamedEntity
+	common.TaggedEntity
+	RestrictedEntity
+	trustedPrincipals  *common.List[*common.Identifier]
+	maxSessionDuration time.Duration
+}
+
+// RoleComparator is used to compare two Role instances based on their identifier.
+type RoleComparator struct{}
+
+// Compare returns a comparison between two Role identifiers.
+func (RoleComparator) Compare(a *Role, b *Role) int {
+	return strings.Compare(a.GetIdentifier().ToString(), b.GetIdentifier().ToString())
+}
+
+// NewRole creates a new Role with a generated identifier, no trusted principal and no attached policy.
+// A zero maxSessionDuration defaults to DEFAULT_SESSION_DURATION.
+// Returns an error if the name, description or maximum session duration is invalid.
+func NewRole(name string, description string, maxSessionDuration time.Duration) (*Role, error) {
+	date := common.CurrentTimestamp()
+	identifier, err := common.BuildRoleIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return ExistingRole(identifier.ToString(), name, description, []*common.Identifier{}, []*policy.Policy{}, maxSessionDuration, date, date)
+}
+
+// ExistingRole reconstructs a Role from stored data, with the identifiers of its trusted principals and its attached policies.
+func ExistingRole(identifier string, name string, description string, trustedPrincipals []*common.Identifier, attachedPolicies []*policy.Policy, maxSessionDuration time.Duration, createdAt string, updatedAt string) (*Role, error) {
+	namedEntity, err := common.ExistingNamedEntity(identifier, name, description, createdAt, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	role := &Role{
+		NamedEntity:       *namedEntity,
+		TaggedEntity:      *common.NewTaggedEntity(),
+		RestrictedEntity:  *ExistingRestrictedEntity(attachedPolicies),
+		trustedPrincipals: common.NewList(common.IdentifierComparator{}, []*common.Identifier{}),
+	}
+	for _, principal := range trustedPrincipals {
+		if err := role.Trust(principal); err != nil {
+			return nil, err
+		}
+	}
+	if err := role.SetMaxSessionDuration(maxSessionDuration); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// GetMaxSessionDuration returns the longest duration of the credentials obtained by assuming the role.
+func (r *Role) GetMaxSessionDuration() time.Duration {
+	return r.maxSessionDuration
+}
+
+// SetMaxSessionDuration sets the longest duration of the credentials obtained by assuming the role.
+// A zero duration defaults to DEFAULT_SESSION_DURATION.
+// Returns ErrInvalidSessionDuration if the duration is not between MIN_SESSION_DURATION and MAX_SESSION_DURATION.
+func (r *Role) SetMaxSessionDuration(duration time.Duration) error {
+	if duration == 0 {
+		duration = DEFAULT_SESSION_DURATION
+	}
+	if duration < MIN_SESSION_DURATION || duration > MAX_SESSION_DURATION {
+		return ErrInvalidSessionDuration
+	}
+	r.maxSessionDuration = duration
+	return nil
+}
+
+// ListTrustedPrincipals returns the identifiers of the users and groups trusted to assume the role.
+func (r *Role) ListTrustedPrincipals() []*common.Identifier {
+	return r.trustedPrincipals.Items()
+}
+
+// Trust allows the user, or the members of the group, to assume the role.
+// Returns ErrInvalidTrustedPrincipal if the identifier is not a user or group one, or ErrPrincipalAlreadyTrusted.
+func (r *Role) Trust(principalId *common.Identifier) error {
+	if principalId.GetType() != common.USER && principalId.GetType() != common.GROUP {
+		return ErrInvalidTrustedPrincipal
+	}
+	if r.trustedPrincipals.Contains(principalId) {
+		return ErrPrincipalAlreadyTrusted
+	}
+	r.trustedPrincipals.Append(principalId)
+	return nil
+}
+
+// Untrust prevents the user, or the members of the group, from assuming the role.
+// Sessions already obtained remain valid until they expire.
+// Returns ErrTrustedPrincipalNotFound if the principal is not trusted.
+func (r *Role) Untrust(principalId *common.Identifier) error {
+	if !r.trustedPrincipals.Contains(principalId) {
+		return ErrTrustedPrincipalNotFound
+	}
+	r.trustedPrincipals.Remove(principalId)
+	return nil
+}
+
+// IsTrusted returns true if the user, or any of its groups, is trusted to assume the role.
+func (r *Role) IsTrusted(user *User) bool {
+	if r.trustedPrincipals.Contains(user.GetIdentifier()) {
+		return true
+	}
+	for _, group := range user.ListGroups() {
+		if r.trustedPrincipals.Contains(group.GetIdentifier()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Assume creates a session of the role for the user, expiring after the given duration, along with its secret token.
+// A zero duration defaults to the maximum session duration of the role, and the reason is recorded for auditing.
+//
+// Returns ErrRoleNotTrusted if the user is not trusted, ErrRoleChaining if the user is already acting with a role,
+// or ErrInvalidSessionDuration if the duration exceeds the maximum session duration of the role.
+func (r *Role) Assume(user *User, duration time.Duration, reason string) (*RoleSession, string, error) {
+	if user.GetAssumedRole() != nil {
+		return nil, "", ErrRoleChaining
+	}
+	if !r.IsTrusted(user) {
+		return nil, "", ErrRoleNotTrusted
+	}
+	if duration == 0 {
+		duration = r.maxSessionDuration
+	}
+	if duration < MIN_SESSION_DURATION || duration > r.maxSessionDuration {
+		return nil, "", ErrInvalidSessionDuration
+	}
+	return newRoleSession(r.GetIdentifier(), user.GetIdentifier(), reason, time.Now().Add(duration))
+}
@@ -0,0 +1,21 @@
+package identity
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+type RoleRepository interface {
+	Create(role *Role) error
+	Update(role *Role) error
+	Delete(roleId common.Identifier) error
+
+	FindById(id common.Identifier) (*Role, error)
+	FindAll(offset int, limit int) ([]*Role, error)
+}
+
+// RoleSessionRepository stores the sessions of the roles, which are never deleted so that every assumption is audited.
+type RoleSessionRepository interface {
+	Create(session *RoleSession) error
+
+	FindById(id common.Identifier) (*RoleSession, error)
+	// FindByRole returns the sessions of the role, most recent first.
+	FindByRole(roleId common.Identifier, offset int, limit int) ([]*RoleSession, error)
+}
@@ -0,0 +1,104 @@
+package identity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// SESSION_TOKEN_LENGTH defines the number of random bytes used to generate the token of a role session.
+const SESSION_TOKEN_LENGTH = 32
+
+// RoleSession records that a user assumed a role, and holds the temporary credentials obtained.
+// Only the hash of the secret token is kept, the token itself being disclosed once to the user.
+type RoleSession struct {
+	common.TimestampedEntity
+	role      *common.Identifier
+	principal *common.Identifier
+	tokenHash string
+	reason    string
+	expiresAt time.Time
+}
+
+// newRoleSession creates a session of the role for the user, with a generated identifier and token.
+// Returns the session along with its token.
+func newRoleSession(roleId *common.Identifier, userId *common.Identifier, reason string, expiresAt time.Time) (*RoleSession, string, error) {
+	identifier, err := common.BuildSessionIdentifier(roleId.ToString())
+	if err != nil {
+		return nil, "", err
+	}
+	bytes := make([]byte, SESSION_TOKEN_LENGTH)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, "", err
+	}
+	token := hex.EncodeToString(bytes)
+	date := common.CurrentTimestamp()
+	session, err := ExistingRoleSession(identifier.ToString(), roleId, userId, hashSessionToken(token), reason, expiresAt, date)
+	if err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// ExistingRoleSession reconstructs a RoleSession from stored data, with the hex encoded SHA-256 hash of its token.
+func ExistingRoleSession(identifier string, roleId *common.Identifier, userId *common.Identifier, tokenHash string, reason string, expiresAt time.Time, createdAt string) (*RoleSession, error) {
+	timedEntity, err := common.ExistingTimestampedEntity(identifier, createdAt, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &RoleSession{
+		TimestampedEntity: *timedEntity,
+		role:              roleId,
+		principal:         userId,
+		tokenHash:         tokenHash,
+		reason:            strings.TrimSpace(reason),
+		expiresAt:         expiresAt,
+	}, nil
+}
+
+// hashSessionToken returns the hex encoded SHA-256 hash of the token.
+func hashSessionToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetRole returns the identifier of the assumed role.
+func (s *RoleSession) GetRole() *common.Identifier {
+	return s.role
+}
+
+// GetPrincipal returns the identifier of the user who assumed the role.
+func (s *RoleSession) GetPrincipal() *common.Identifier {
+	return s.principal
+}
+
+// GetTokenHash returns the hex encoded SHA-256 hash of the token of the session.
+func (s *RoleSession) GetTokenHash() string {
+	return s.tokenHash
+}
+
+// GetReason returns the reason given by the user when assuming the role.
+func (s *RoleSession) GetReason() string {
+	return s.reason
+}
+
+// GetExpiresAt returns the time after which the credentials of the session are rejected.
+func (s *RoleSession) GetExpiresAt() time.Time {
+	return s.expiresAt
+}
+
+// IsExpired returns true if the session has expired at the given time.
+func (s *RoleSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.expiresAt)
+}
+
+// Authenticate returns true if the token is the one of the session and the session has not expired at the given time.
+func (s *RoleSession) Authenticate(token string, now time.Time) bool {
+	matches := subtle.ConstantTimeCompare([]byte(hashSessionToken(token)), []byte(s.tokenHash)) == 1
+	return matches && !s.IsExpired(now)
+}
@@ -0,0 +1,51 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// SessionUserRepository is a UserRepository also authenticating the users acting with the credentials of a role session.
+type SessionUserRepository interface {
+	UserRepository
+	// FindBySession returns the user who assumed the role of the session, acting with the role,
+	// or nil if the session does not exist, the token does not match or the session expired.
+	FindBySession(sessionId common.Identifier, token string) (*User, error)
+}
+
+// sessionRepository is a UserRepository authenticating role sessions.
+type sessionRepository struct {
+	UserRepository
+	roles    RoleRepository
+	sessions RoleSessionRepository
+}
+
+// WithRoleSessions returns a SessionUserRepository finding users in the given repository,
+// and the roles assumed by the users of the sessions in the role and session repositories.
+func WithRoleSessions(users UserRepository, roles RoleRepository, sessions RoleSessionRepository) SessionUserRepository {
+	return &sessionRepository{
+		UserRepository: users,
+		roles:          roles,
+		sessions:       sessions,
+	}
+}
+
+func (r *sessionRepository) FindBySession(sessionId common.Identifier, token string) (*User, error) {
+	session, err := r.sessions.FindById(sessionId)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if !session.Authenticate(token, time.Now()) {
+		return nil, nil
+	}
+	role, err := r.roles.FindById(*session.GetRole())
+	if err != nil || role == nil {
+		return nil, err
+	}
+	user, err := r.UserRepository.FindById(*session.GetPrincipal(), 0, 1)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return user.AssumeRole(role), nil
+}
@@ -0,0 +1,137 @@
+package identity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+func TestRoleTrust(t *testing.T) {
+	role, err := identity.NewRole("deployer", "", 0)
+	if err != nil || role.GetMaxSessionDuration() != identity.DEFAULT_SESSION_DURATION {
+		t.Fatalf("expected a role with the default session duration, got %v", err)
+	}
+	if _, err := identity.NewRole("deployer", "", 24*time.Hour); err != identity.ErrInvalidSessionDuration {
+		t.Errorf("expected ErrInvalidSessionDuration, got %v", err)
+	}
+	user, _ := identity.NewUser("ci@example.com", "ci_runner")
+	group, _ := identity.NewGroup("ci", "")
+	projectId, _ := common.BuildProjectIdentifier()
+	if err := role.Trust(projectId); err != identity.ErrInvalidTrustedPrincipal {
+		t.Errorf("expected ErrInvalidTrustedPrincipal, got %v", err)
+	}
+	if role.IsTrusted(user) {
+		t.Errorf("expected the user not to be trusted")
+	}
+	if err := role.Trust(group.GetIdentifier()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := role.Trust(group.GetIdentifier()); err != identity.ErrPrincipalAlreadyTrusted {
+		t.Errorf("expected ErrPrincipalAlreadyTrusted, got %v", err)
+	}
+	user.SetGroups([]*identity.Group{group})
+	if !role.IsTrusted(user) {
+		t.Errorf("expected the members of a trusted group to be trusted")
+	}
+	if err := role.Untrust(group.GetIdentifier()); err != nil || role.IsTrusted(user) {
+		t.Errorf("expected the group not to be trusted anymore, got %v", err)
+	}
+	if err := role.Untrust(group.GetIdentifier()); err != identity.ErrTrustedPrincipalNotFound {
+		t.Errorf("expected ErrTrustedPrincipalNotFound, got %v", err)
+	}
+}
+
+func TestRoleAssume(t *testing.T) {
+	projectId, _ := common.BuildProjectIdentifier()
+	workflowId, _ := common.BuildWorkflowIdentifier(projectId.ToString())
+	run, _ := policy.ParsePolicyAction("workflow:Run")
+	allow, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{workflowId.ToString()})
+	runners, _ := policy.NewPolicy(projectId.ToString(), "runners", "", []*policy.PolicyStatement{allow})
+	role, _ := identity.NewRole("deployer", "", 2*time.Hour)
+	role.AttachPolicy(runners)
+	user, _ := identity.NewUser("ci@example.com", "ci_runner")
+
+	if _, _, err := role.Assume(user, 0, ""); err != identity.ErrRoleNotTrusted {
+		t.Errorf("expected ErrRoleNotTrusted, got %v", err)
+	}
+	role.Trust(user.GetIdentifier())
+	if _, _, err := role.Assume(user, 3*time.Hour, ""); err != identity.ErrInvalidSessionDuration {
+		t.Errorf("expected ErrInvalidSessionDuration, got %v", err)
+	}
+	session, token, err := role.Assume(user, 0, " deploy release ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.GetIdentifier().GetType() != common.SESSION || session.GetRole() != role.GetIdentifier() || session.GetPrincipal() != user.GetIdentifier() || session.GetReason() != "deploy release" {
+		t.Errorf("unexpected session %s", session.GetIdentifier().ToString())
+	}
+	if session.GetTokenHash() == token || !session.Authenticate(token, time.Now()) || session.Authenticate("invalid", time.Now()) {
+		t.Errorf("expected only the token of the session to authenticate it")
+	}
+	if session.Authenticate(token, time.Now().Add(2*time.Hour)) || !session.IsExpired(time.Now().Add(2*time.Hour)) {
+		t.Errorf("expected the session to expire after the maximum session duration")
+	}
+
+	if user.IsAllowed(workflowId, run, nil) {
+		t.Errorf("expected the user not to be allowed with its own policies")
+	}
+	assumed := user.AssumeRole(role)
+	if !assumed.IsAllowed(workflowId, run, nil) || assumed.GetAssumedRole() != role || user.GetAssumedRole() != nil {
+		t.Errorf("expected the user acting with the role to be allowed")
+	}
+	if _, _, err := role.Assume(assumed, 0, ""); err != identity.ErrRoleChaining {
+		t.Errorf("expected ErrRoleChaining, got %v", err)
+	}
+}
+
+// fakeRoleRepository and fakeSessionRepository are in-memory repositories used to test WithRoleSessions.
+type fakeRoleRepository struct {
+	identity.RoleRepository
+	role *identity.Role
+}
+
+func (f *fakeRoleRepository) FindById(id common.Identifier) (*identity.Role, error) {
+	if f.role == nil || f.role.GetIdentifier().ToString() != id.ToString() {
+		return nil, nil
+	}
+	return f.role, nil
+}
+
+type fakeSessionRepository struct {
+	identity.RoleSessionRepository
+	session *identity.RoleSession
+}
+
+func (f *fakeSessionRepository) FindById(id common.Identifier) (*identity.RoleSession, error) {
+	if f.session.GetIdentifier().ToString() != id.ToString() {
+		return nil, nil
+	}
+	return f.session, nil
+}
+
+func TestWithRoleSessions(t *testing.T) {
+	user, _ := identity.NewUser("ci@example.com", "ci_runner")
+	role, _ := identity.NewRole("deployer", "", 0)
+	role.Trust(user.GetIdentifier())
+	session, token, _ := role.Assume(user, 0, "")
+	roles := &fakeRoleRepository{role: role}
+	users := identity.WithRoleSessions(&fakeUserRepository{user: user}, roles, &fakeSessionRepository{session: session})
+
+	found, err := users.FindBySession(*session.GetIdentifier(), token)
+	if err != nil || found == nil || found.GetIdentifier() != user.GetIdentifier() || found.GetAssumedRole() != role {
+		t.Fatalf("expected the user acting with the role, got %v", err)
+	}
+	if found, err := users.FindById(*user.GetIdentifier(), 0, 1); err != nil || found.GetAssumedRole() != nil {
+		t.Errorf("expected the user to act with its own policies outside of a session, got %v", err)
+	}
+	if found, _ := users.FindBySession(*session.GetIdentifier(), "invalid"); found != nil {
+		t.Errorf("expected an invalid token to be rejected")
+	}
+	roles.role = nil
+	if found, _ := users.FindBySession(*session.GetIdentifier(), token); found != nil {
+		t.Errorf("expected the session of a deleted role to be rejected")
+	}
+}
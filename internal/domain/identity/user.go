@@ -4,6 +4,7 @@ package identity
 import (
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
 	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
@@ -16,19 +17,27 @@ var (
 
 // User represents an individual user account in the AutOps platform. It includes user identity, email verification status,
 // a unique username, and associated security policies through inheritance from RestrictedEntity.
+// The permissions of a user are determined by its attached policies and the policies attached to its groups,
+// or only by the policies of the role it assumed when acting with the credentials of a role session.
+// In both cases, a permission boundary limits the actions these policies can allow.
+// The tags of a user, of its groups and of its assumed role are the principal tags the statement conditions are evaluated against.
 type User struct {
 	common.TimestampedEntity
+	common.TaggedEntity
 	RestrictedEntity
-	email    string
-	verified bool
-	username string
+	email       string
+	verified    bool
+	username    string
+	groups      *common.List[*Group]
+	assumedRole *Role
+	boundary    *policy.Policy
 }
 
 // NewUser creates a new User instance with a generated identifier and default timestamp.
 // It validates the provided email and username.
 func NewUser(email string, username string) (*User, error) {
 	date := common.CurrentTimestamp()
-	id, err := common.BuildIdentifier("autops:", "user")
+	id, err := common.BuildUserIdentifier()
 	if err != nil {
 		return nil, err
 	}
@@ -38,20 +47,18 @@ func NewUser(email string, username string) (*User, error) {
 // ExistingUser reconstructs a User from existing persisted data such as identifier, email, verification status,
 // username, associated policies, and timestamps. It validates the input data before returning the user.
 func ExistingUser(id string, email string, verified bool, username string, attachedPolicies []*policy.Policy, createdAt string, updatedAt string) (*User, error) {
-	identifier, err := common.BuildUserIdentifier()
-	if err != nil {
-		return nil, err
-	}
-	timedEntity, err := common.ExistingTimestampedEntity(identifier.ToString(), createdAt, updatedAt)
+	timedEntity, err := common.ExistingTimestampedEntity(id, createdAt, updatedAt)
 	if err != nil {
 		return nil, err
 	}
 	user := User{
 		TimestampedEntity: *timedEntity,
+		TaggedEntity:      *common.NewTaggedEntity(),
 		RestrictedEntity:  *ExistingRestrictedEntity(attachedPolicies),
 		email:             "",
 		verified:          false,
 		username:          "",
+		groups:            common.NewList(GroupComparator{}, []*Group{}),
 	}
 	err = user.SetEmail(email)
 	if err != nil {
@@ -105,3 +112,109 @@ func (u *User) GetEmail() string {
 func (u *User) GetUsername() string {
 	return u.username
 }
+
+// ListGroups returns the groups the user is a member of.
+func (u *User) ListGroups() []*Group {
+	return u.groups.Items()
+}
+
+// SetGroups replaces the groups the user is a member of, typically when reloading the user from a data store.
+func (u *User) SetGroups(groups []*Group) {
+	u.groups = common.NewList(GroupComparator{}, groups)
+}
+
+// GetAssumedRole returns the role the user is acting with, or nil if the user acts with its own policies.
+func (u *User) GetAssumedRole() *Role {
+	return u.assumedRole
+}
+
+// AssumeRole returns a copy of the user acting with the role, whose policies replace the policies of the user and its groups.
+// The user itself is left unchanged.
+func (u *User) AssumeRole(role *Role) *User {
+	assumed := *u
+	assumed.assumedRole = role
+	return &assumed
+}
+
+// GetPermissionBoundary returns the policy limiting the actions the policies of the user can allow, or nil if there is none.
+func (u *User) GetPermissionBoundary() *policy.Policy {
+	return u.boundary
+}
+
+// SetPermissionBoundary sets the policy limiting the actions the policies of the user can allow, nil removing the boundary.
+func (u *User) SetPermissionBoundary(boundary *policy.Policy) {
+	u.boundary = boundary
+}
+
+// CanGrant returns true if the policy does not allow any action outside of the permission boundary of the user,
+// so that users allowed to attach policies cannot grant more than they are allowed themselves.
+func (u *User) CanGrant(p *policy.Policy) bool {
+	return u.boundary == nil || p.IsWithin(u.boundary)
+}
+
+// ListEffectivePolicies returns the policies attached to the user, followed by the policies attached to its groups.
+// A policy attached several times is only returned once. When the user acts with a role, only the role policies are returned.
+func (u *User) ListEffectivePolicies() []*policy.Policy {
+	if u.assumedRole != nil {
+		return u.assumedRole.ListAttachedPolicies()
+	}
+	policies := common.NewList(policy.PolicyComparator{}, []*policy.Policy{})
+	add := func(attached []*policy.Policy) {
+		for _, p := range attached {
+			if !policies.Contains(p) {
+				policies.Append(p)
+			}
+		}
+	}
+	add(u.ListAttachedPolicies())
+	for _, group := range u.groups.Items() {
+		add(group.ListAttachedPolicies())
+	}
+	return policies.Items()
+}
+
+// ListPrincipalTags returns the tags of the user, followed by the tags of its groups whose key the user does not have.
+// When the user acts with a role, only the tags of the role are returned.
+func (u *User) ListPrincipalTags() []*common.Tag {
+	if u.assumedRole != nil {
+		return u.assumedRole.ListTags()
+	}
+	tags := common.NewTaggedEntity()
+	for _, group := range u.groups.Items() {
+		for _, tag := range group.ListTags() {
+			if !u.HasTag(tag.GetKey()) && !tags.HasTag(tag.GetKey()) {
+				tags.AddTag(tag)
+			}
+		}
+	}
+	return append(u.ListTags(), tags.ListTags()...)
+}
+
+// NewRequestContext creates the context of a request made by the user at the given date, with its principal tags.
+func (u *User) NewRequestContext(at time.Time) *policy.RequestContext {
+	context := policy.NewRequestContext(u.GetIdentifier(), at)
+	context.SetPrincipalTags(u.ListPrincipalTags())
+	return context
+}
+
+// GetPermission determines the effect of the effective policies of the user for the given action on the specified resource,
+// in the context of the request. An explicit DENY in any policy, including the policies of its groups, takes precedence over ALLOW,
+// and an ALLOW is UNSPECIFIED if the permission boundary of the user does not allow the action too.
+func (u *User) GetPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) policy.PolicyEffect {
+	effect := getPermission(u.ListEffectivePolicies(), resourceIdentifier, action, context)
+	if effect == policy.ALLOW && u.boundary != nil && u.boundary.GetPermission(resourceIdentifier, action, context) != policy.ALLOW {
+		return policy.UNSPECIFIED
+	}
+	return effect
+}
+
+// IsAllowed returns true if the effective policies of the user explicitly allow the action on the resource without denying it.
+func (u *User) IsAllowed(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) bool {
+	return u.GetPermission(resourceIdentifier, action, context) == policy.ALLOW
+}
+
+// ExplainPermission explains the effect of the effective policies of the user for the given action on the specified resource,
+// listing every statement that applies and whether the permission boundary denies the action.
+func (u *User) ExplainPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) *policy.Decision {
+	return policy.ExplainWithinBoundary(u.ListEffectivePolicies(), u.boundary, resourceIdentifier, action, context)
+}
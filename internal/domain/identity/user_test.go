@@ -2,8 +2,11 @@ package identity_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
 	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
 )
 
 func TestNewUser_Success(t *testing.T) {
@@ -105,3 +108,80 @@ func createTestUser(t *testing.T) *identity.User {
 	}
 	return user
 }
+
+func TestExistingUser_KeepsIdentifier(t *testing.T) {
+	user, err := identity.ExistingUser("autops::user:1234567890", "user@example.com", true, "validuser", nil, "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.GetIdentifier().ToString() != "autops::user:1234567890" {
+		t.Errorf("expected identifier to be autops::user:1234567890, got %s", user.GetIdentifier().ToString())
+	}
+	if !user.IsVerified() {
+		t.Error("expected user to be verified")
+	}
+}
+
+func TestUserPermissionBoundary(t *testing.T) {
+	projectId, _ := common.BuildProjectIdentifier()
+	workflowId, _ := common.BuildWorkflowIdentifier(projectId.ToString())
+	run, _ := policy.ParsePolicyAction("workflow:Run")
+	read, _ := policy.ParsePolicyAction("workflow:Read")
+	allow, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run", "workflow:Read"}, []string{workflowId.ToString()})
+	operators, _ := policy.NewPolicy(projectId.ToString(), "operators", "", []*policy.PolicyStatement{allow})
+	readOnly, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read"}, []string{workflowId.ToString()})
+	readers, _ := policy.NewPolicy(projectId.ToString(), "readers", "", []*policy.PolicyStatement{readOnly})
+
+	user, _ := identity.NewUser("user@example.com", "valid_user")
+	user.AttachPolicy(operators)
+	if !user.IsAllowed(workflowId, run, nil) || !user.CanGrant(operators) {
+		t.Errorf("expected a user without boundary to be allowed and to grant its policies")
+	}
+	user.SetPermissionBoundary(readers)
+	if user.GetPermissionBoundary() != readers || user.IsAllowed(workflowId, run, nil) || !user.IsAllowed(workflowId, read, nil) {
+		t.Errorf("expected the permissions of the user to be the intersection of its policies and its boundary")
+	}
+	if user.ExplainPermission(workflowId, run, nil).GetReason() != policy.BOUNDARY_DENY {
+		t.Errorf("expected the boundary to explain the denial")
+	}
+	if user.CanGrant(operators) || !user.CanGrant(readers) {
+		t.Errorf("expected the user to only grant policies within its boundary")
+	}
+
+	role, _ := identity.NewRole("operator", "", 0)
+	role.AttachPolicy(operators)
+	if user.AssumeRole(role).IsAllowed(workflowId, run, nil) {
+		t.Errorf("expected the boundary to limit the policies of an assumed role")
+	}
+	user.SetPermissionBoundary(nil)
+	if !user.IsAllowed(workflowId, run, nil) {
+		t.Errorf("expected the boundary to be removed")
+	}
+}
+
+func TestUserPrincipalTags(t *testing.T) {
+	user, _ := identity.NewUser("user@example.com", "user")
+	user.AddTag(common.NewTag("team", "platform"))
+	group, _ := identity.NewGroup("operators", "")
+	group.AddTag(common.NewTag("team", "operations"))
+	group.AddTag(common.NewTag("level", "senior"))
+	user.SetGroups([]*identity.Group{group})
+
+	context := user.NewRequestContext(time.Now())
+	if team, _ := context.Get(policy.PrincipalTagKey("team")); team != "platform" {
+		t.Errorf("expected the tags of the user to take precedence over the tags of its groups, got %q", team)
+	}
+	if level, _ := context.Get(policy.PrincipalTagKey("level")); level != "senior" {
+		t.Errorf("expected the tags of the groups of the user, got %q", level)
+	}
+	if id, _ := context.Get(policy.PRINCIPAL_ID_KEY); id != user.GetIdentifier().ToString() {
+		t.Errorf("expected the identifier of the user, got %q", id)
+	}
+
+	role, _ := identity.NewRole("deployer", "", 0)
+	role.AddTag(common.NewTag("env", "prod"))
+	tags := user.AssumeRole(role).ListPrincipalTags()
+	if len(tags) != 1 || tags[0].GetKey() != "env" {
+		t.Errorf("expected only the tags of the assumed role, got %d tags", len(tags))
+	}
+}
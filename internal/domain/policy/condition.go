@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ConditionOperator compares the value of a request context key to the values of a condition.
+type ConditionOperator int
+
+const (
+	STRING_EQUALS ConditionOperator = iota
+	STRING_NOT_EQUALS
+	// STRING_LIKE matches patterns where '*' matches any sequence of characters and '?' any single character.
+	STRING_LIKE
+	STRING_NOT_LIKE
+	// TAG_KEY_EXISTS checks the presence of a tag key, with the value "true" or "false".
+	TAG_KEY_EXISTS
+	DATE_LESS_THAN
+	DATE_LESS_THAN_EQUALS
+	DATE_GREATER_THAN
+	DATE_GREATER_THAN_EQUALS
+)
+
+var conditionOperators = map[ConditionOperator]string{
+	STRING_EQUALS:            "StringEquals",
+	STRING_NOT_EQUALS:        "StringNotEquals",
+	STRING_LIKE:              "StringLike",
+	STRING_NOT_LIKE:          "StringNotLike",
+	TAG_KEY_EXISTS:           "TagKeyExists",
+	DATE_LESS_THAN:           "DateLessThan",
+	DATE_LESS_THAN_EQUALS:    "DateLessThanEquals",
+	DATE_GREATER_THAN:        "DateGreaterThan",
+	DATE_GREATER_THAN_EQUALS: "DateGreaterThanEquals",
+}
+
+// ToString returns the string representation of a ConditionOperator, e.g. "StringEquals".
+func (o ConditionOperator) ToString() (string, error) {
+	str, ok := conditionOperators[o]
+	if !ok {
+		return "", ErrInvalidConditionOperator
+	}
+	return str, nil
+}
+
+// ParseConditionOperator converts a string into a ConditionOperator, ignoring case.
+func ParseConditionOperator(str string) (ConditionOperator, error) {
+	for operator, name := range conditionOperators {
+		if strings.EqualFold(name, str) {
+			return operator, nil
+		}
+	}
+	return -1, ErrInvalidConditionOperator
+}
+
+// isDate returns true if the operator compares dates.
+func (o ConditionOperator) isDate() bool {
+	return o >= DATE_LESS_THAN && o <= DATE_GREATER_THAN_EQUALS
+}
+
+// Condition restricts a policy statement to the requests whose context satisfies it, e.g. the requests on resources
+// tagged "env=dev" with StringEquals, the key "resource:tag/env" and the value "dev".
+//
+// The condition is satisfied if the value of its key matches any of its values, or for the negated operators if it matches none.
+// A value like "${principal:tag/team}" references the value of another key of the context, and never matches if that key is not set.
+// A condition whose key is not set in the context is not satisfied, unless it checks with TagKeyExists that the key is absent.
+type Condition struct {
+	operator ConditionOperator
+	key      string
+	values   []string
+}
+
+// NewCondition creates a condition on the key of the request context.
+// Returns an error if the key has no known prefix, if there is no value, or if a value does not suit the operator:
+// dates must be formatted as RFC 3339, and TagKeyExists takes a single "true" or "false" value on a tag key.
+func NewCondition(operator ConditionOperator, key string, values []string) (*Condition, error) {
+	if _, err := operator.ToString(); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(key, "principal:") && !strings.HasPrefix(key, "resource:") && !strings.HasPrefix(key, "request:") {
+		return nil, ErrInvalidConditionKey
+	}
+	if len(values) == 0 {
+		return nil, ErrInvalidConditionValue
+	}
+	if operator == TAG_KEY_EXISTS {
+		if !strings.Contains(key, ":tag/") || len(values) != 1 || (values[0] != "true" && values[0] != "false") {
+			return nil, ErrInvalidConditionValue
+		}
+	}
+	if operator.isDate() {
+		for _, value := range values {
+			if _, reference := referencedKey(value); reference {
+				continue
+			}
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return nil, ErrInvalidConditionValue
+			}
+		}
+	}
+	return &Condition{
+		operator: operator,
+		key:      key,
+		values:   append([]string(nil), values...),
+	}, nil
+}
+
+// GetOperator returns the operator of the condition.
+func (c *Condition) GetOperator() ConditionOperator {
+	return c.operator
+}
+
+// GetKey returns the request context key the condition applies to.
+func (c *Condition) GetKey() string {
+	return c.key
+}
+
+// ListValues returns the values the condition compares the key to.
+func (c *Condition) ListValues() []string {
+	return append([]string(nil), c.values...)
+}
+
+// IsSatisfied evaluates the condition against the request context, which may be nil.
+func (c *Condition) IsSatisfied(context *RequestContext) bool {
+	actual, ok := context.Get(c.key)
+	if c.operator == TAG_KEY_EXISTS {
+		return ok == (c.values[0] == "true")
+	}
+	if !ok {
+		return false
+	}
+	matched := false
+	for _, value := range c.values {
+		if key, reference := referencedKey(value); reference {
+			if value, ok = context.Get(key); !ok {
+				continue
+			}
+		}
+		if c.matches(actual, value) {
+			matched = true
+			break
+		}
+	}
+	if c.operator == STRING_NOT_EQUALS || c.operator == STRING_NOT_LIKE {
+		return !matched
+	}
+	return matched
+}
+
+// matches compares the value of the context to a value of the condition, ignoring the negation of the operator.
+func (c *Condition) matches(actual string, value string) bool {
+	switch c.operator {
+	case STRING_EQUALS, STRING_NOT_EQUALS:
+		return actual == value
+	case STRING_LIKE, STRING_NOT_LIKE:
+		pattern := regexp.QuoteMeta(value)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+		matched, _ := regexp.MatchString("^"+pattern+"$", actual)
+		return matched
+	}
+	actualDate, err := time.Parse(time.RFC3339, actual)
+	if err != nil {
+		return false
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	switch c.operator {
+	case DATE_LESS_THAN:
+		return actualDate.Before(date)
+	case DATE_LESS_THAN_EQUALS:
+		return !actualDate.After(date)
+	case DATE_GREATER_THAN:
+		return actualDate.After(date)
+	default:
+		return !actualDate.Before(date)
+	}
+}
+
+// referencedKey returns the key referenced by a value like "${principal:tag/team}", and false if the value is a literal.
+func referencedKey(value string) (string, bool) {
+	if strings.HasPrefix(value, "${") && strings.HasSuffix(value, "}") {
+		return value[2 : len(value)-1], true
+	}
+	return "", false
+}
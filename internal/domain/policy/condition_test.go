@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestConditionIsSatisfied(t *testing.T) {
+	principal, _ := common.NewIdentifier("autops::user:1234567890")
+	context := NewRequestContext(principal, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	context.SetPrincipalTags([]*common.Tag{common.NewTag("team", "platform")})
+	context.SetResourceTags([]*common.Tag{common.NewTag("env", "dev"), common.NewTag("team", "platform")})
+
+	tests := []struct {
+		operator  ConditionOperator
+		key       string
+		values    []string
+		satisfied bool
+	}{
+		{STRING_EQUALS, "resource:tag/env", []string{"prod", "dev"}, true},
+		{STRING_EQUALS, "resource:tag/env", []string{"prod"}, false},
+		{STRING_EQUALS, "resource:tag/team", []string{"${principal:tag/team}"}, true},
+		{STRING_EQUALS, "resource:tag/team", []string{"${principal:tag/missing}"}, false},
+		{STRING_NOT_EQUALS, "resource:tag/env", []string{"prod"}, true},
+		{STRING_NOT_EQUALS, "resource:tag/missing", []string{"prod"}, false},
+		{STRING_LIKE, "principal:id", []string{"autops::user:*"}, true},
+		{STRING_LIKE, "resource:tag/env", []string{"d?"}, false},
+		{STRING_NOT_LIKE, "resource:tag/env", []string{"prod*"}, true},
+		{TAG_KEY_EXISTS, "resource:tag/env", []string{"true"}, true},
+		{TAG_KEY_EXISTS, "resource:tag/owner", []string{"false"}, true},
+		{DATE_GREATER_THAN, REQUEST_TIME_KEY, []string{"2025-06-01T09:00:00Z"}, true},
+		{DATE_LESS_THAN, REQUEST_TIME_KEY, []string{"2025-06-01T09:00:00Z"}, false},
+		{DATE_LESS_THAN_EQUALS, REQUEST_TIME_KEY, []string{"2025-06-01T12:00:00Z"}, true},
+		{DATE_GREATER_THAN_EQUALS, REQUEST_TIME_KEY, []string{"2025-06-01T14:00:00+02:00"}, true},
+	}
+	for _, test := range tests {
+		operator, _ := test.operator.ToString()
+		condition, err := NewCondition(test.operator, test.key, test.values)
+		if err != nil {
+			t.Fatalf("%s %s: expected err to be nil, got %v", operator, test.key, err)
+		}
+		if condition.IsSatisfied(context) != test.satisfied {
+			t.Errorf("%s %s %v: expected %t", operator, test.key, test.values, test.satisfied)
+		}
+	}
+
+	condition, _ := NewCondition(STRING_EQUALS, "resource:tag/env", []string{"dev"})
+	if condition.IsSatisfied(nil) {
+		t.Errorf("expected a condition not to be satisfied without context")
+	}
+}
+
+func TestNewConditionErrors(t *testing.T) {
+	tests := []struct {
+		operator ConditionOperator
+		key      string
+		values   []string
+		err      error
+	}{
+		{ConditionOperator(42), "resource:tag/env", []string{"dev"}, ErrInvalidConditionOperator},
+		{STRING_EQUALS, "env", []string{"dev"}, ErrInvalidConditionKey},
+		{STRING_EQUALS, "resource:tag/env", []string{}, ErrInvalidConditionValue},
+		{TAG_KEY_EXISTS, "principal:id", []string{"true"}, ErrInvalidConditionValue},
+		{TAG_KEY_EXISTS, "resource:tag/env", []string{"yes"}, ErrInvalidConditionValue},
+		{DATE_LESS_THAN, REQUEST_TIME_KEY, []string{"tomorrow"}, ErrInvalidConditionValue},
+	}
+	for i, test := range tests {
+		if _, err := NewCondition(test.operator, test.key, test.values); !errors.Is(err, test.err) {
+			t.Errorf("test %d: expected %v, got %v", i, test.err, err)
+		}
+	}
+
+	operator, err := ParseConditionOperator("stringlike")
+	if err != nil || operator != STRING_LIKE {
+		t.Errorf("expected StringLike to be parsed ignoring case, got %v", err)
+	}
+	var statementErr *StatementError
+	if _, err := ParseStatementCondition(1, 2, "StringMatches", "resource:tag/env", []string{"dev"}); !errors.As(err, &statementErr) || statementErr.GetField() != "conditions[2]" {
+		t.Errorf("expected a statement error on conditions[2], got %v", err)
+	}
+}
+
+func TestPolicyStatementConditions(t *testing.T) {
+	workflowId, _ := common.NewIdentifier("autops::project:1234567890:workflow:ABCDEFGHIJ")
+	run, _ := ParsePolicyAction("workflow:Run")
+	statement, _ := ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{workflowId.ToString()})
+	dev, _ := NewCondition(STRING_EQUALS, "resource:tag/env", []string{"dev"})
+	statement.SetConditions([]*Condition{dev})
+
+	context := NewRequestContext(nil, time.Now())
+	if statement.GetPermission(workflowId, run, context) != UNSPECIFIED || statement.GetPermission(workflowId, run, nil) != UNSPECIFIED {
+		t.Errorf("expected the statement not to apply to untagged resources")
+	}
+	context.SetResourceTags([]*common.Tag{common.NewTag("env", "dev")})
+	if statement.GetPermission(workflowId, run, context) != ALLOW {
+		t.Errorf("expected the statement to allow running dev resources")
+	}
+}
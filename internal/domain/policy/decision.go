@@ -0,0 +1,129 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// DecisionReason explains the effect of a set of policies for an action on a resource.
+type DecisionReason int
+
+const (
+	// EXPLICIT_DENY indicates that a statement denies the action, which takes precedence over any allow.
+	EXPLICIT_DENY DecisionReason = iota
+	// EXPLICIT_ALLOW indicates that a statement allows the action, and none denies it.
+	EXPLICIT_ALLOW
+	// DEFAULT_DENY indicates that no statement applies, so the action is denied by default.
+	DEFAULT_DENY
+	// BOUNDARY_DENY indicates that the action is allowed, but not by the permission boundary, so it is denied.
+	BOUNDARY_DENY
+)
+
+// ToString returns the string representation of a DecisionReason.
+func (r DecisionReason) ToString() string {
+	switch r {
+	case EXPLICIT_DENY:
+		return "explicit_deny"
+	case EXPLICIT_ALLOW:
+		return "allow"
+	case BOUNDARY_DENY:
+		return "boundary_deny"
+	default:
+		return "default_deny"
+	}
+}
+
+// StatementMatch is a statement applying to the action on the resource of a decision.
+type StatementMatch struct {
+	policy *Policy
+	index  int
+	effect PolicyEffect
+}
+
+// GetPolicy returns the policy of the statement.
+func (m *StatementMatch) GetPolicy() *Policy {
+	return m.policy
+}
+
+// GetIndex returns the index of the statement in the statements of its policy.
+func (m *StatementMatch) GetIndex() int {
+	return m.index
+}
+
+// GetEffect returns the effect of the statement.
+func (m *StatementMatch) GetEffect() PolicyEffect {
+	return m.effect
+}
+
+// Decision explains the effect of a set of policies for an action on a resource.
+type Decision struct {
+	resource *common.Identifier
+	action   PolicyAction
+	effect   PolicyEffect
+	reason   DecisionReason
+	matches  []*StatementMatch
+}
+
+// Explain evaluates the policies for the action on the resource in the context of the request like GetPermission,
+// and records every statement that applies along with the reason of the final effect.
+func Explain(policies []*Policy, resourceIdentifier *common.Identifier, action PolicyAction, context *RequestContext) *Decision {
+	decision := &Decision{
+		resource: resourceIdentifier,
+		action:   action,
+		effect:   UNSPECIFIED,
+		reason:   DEFAULT_DENY,
+		matches:  []*StatementMatch{},
+	}
+	for _, p := range policies {
+		for i, statement := range p.ListStatements() {
+			effect := statement.GetPermission(resourceIdentifier, action, context)
+			if effect == UNSPECIFIED {
+				continue
+			}
+			decision.matches = append(decision.matches, &StatementMatch{policy: p, index: i, effect: effect})
+			if effect == DENY {
+				decision.effect, decision.reason = DENY, EXPLICIT_DENY
+			} else if decision.effect != DENY {
+				decision.effect, decision.reason = ALLOW, EXPLICIT_ALLOW
+			}
+		}
+	}
+	return decision
+}
+
+// ExplainWithinBoundary explains the effect of the policies like Explain, limited by the permission boundary if it is not nil:
+// an action allowed by the policies is only allowed if the boundary allows it too.
+func ExplainWithinBoundary(policies []*Policy, boundary *Policy, resourceIdentifier *common.Identifier, action PolicyAction, context *RequestContext) *Decision {
+	decision := Explain(policies, resourceIdentifier, action, context)
+	if boundary != nil && decision.effect == ALLOW && boundary.GetPermission(resourceIdentifier, action, context) != ALLOW {
+		decision.effect, decision.reason = UNSPECIFIED, BOUNDARY_DENY
+	}
+	return decision
+}
+
+// GetResource returns the resource of the decision.
+func (d *Decision) GetResource() *common.Identifier {
+	return d.resource
+}
+
+// GetAction returns the action of the decision.
+func (d *Decision) GetAction() PolicyAction {
+	return d.action
+}
+
+// GetEffect returns the final effect, which is UNSPECIFIED when the action is denied by default.
+func (d *Decision) GetEffect() PolicyEffect {
+	return d.effect
+}
+
+// GetReason returns why the final effect was reached.
+func (d *Decision) GetReason() DecisionReason {
+	return d.reason
+}
+
+// IsAllowed returns true if the action is allowed on the resource.
+func (d *Decision) IsAllowed() bool {
+	return d.effect == ALLOW
+}
+
+// ListMatches returns every statement applying to the action on the resource, in the order of the policies.
+func (d *Decision) ListMatches() []*StatementMatch {
+	return append([]*StatementMatch(nil), d.matches...)
+}
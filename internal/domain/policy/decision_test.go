@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestExplain(t *testing.T) {
+	project, _ := common.NewIdentifier("autops::project:1234567890")
+	other, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ")
+	read, _ := ParsePolicyAction("project:Read")
+	allow, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{project.ToString(), other.ToString()})
+	deny, _ := ParsePolicyStatement(0, "Deny", []string{"project:Read"}, []string{other.ToString()})
+	readers, _ := NewPolicy(project.ToString(), "readers", "", []*PolicyStatement{allow})
+	restricted, _ := NewPolicy(project.ToString(), "restricted", "", []*PolicyStatement{deny})
+	policies := []*Policy{readers, restricted}
+
+	decision := Explain(policies, project, read, nil)
+	if decision.GetEffect() != ALLOW || decision.GetReason() != EXPLICIT_ALLOW || !decision.IsAllowed() || len(decision.ListMatches()) != 1 {
+		t.Errorf("expected an explicit allow, got %s", decision.GetReason().ToString())
+	}
+	decision = Explain(policies, other, read, nil)
+	if decision.GetEffect() != DENY || decision.GetReason() != EXPLICIT_DENY || len(decision.ListMatches()) != 2 {
+		t.Errorf("expected an explicit deny with 2 matches, got %s", decision.GetReason().ToString())
+	}
+	if match := decision.ListMatches()[1]; match.GetPolicy() != restricted || match.GetIndex() != 0 || match.GetEffect() != DENY {
+		t.Errorf("expected the deny statement of the restricted policy to match")
+	}
+	for _, p := range policies {
+		if p.GetPermission(other, read, nil) != Explain([]*Policy{p}, other, read, nil).GetEffect() {
+			t.Errorf("expected Explain to agree with GetPermission")
+		}
+	}
+
+	update, _ := ParsePolicyAction("project:Update")
+	decision = Explain(policies, project, update, nil)
+	if decision.GetEffect() != UNSPECIFIED || decision.GetReason() != DEFAULT_DENY || decision.IsAllowed() || len(decision.ListMatches()) != 0 {
+		t.Errorf("expected a default deny, got %s", decision.GetReason().ToString())
+	}
+}
+
+func TestExplainWithinBoundary(t *testing.T) {
+	project, _ := common.NewIdentifier("autops::project:1234567890")
+	read, _ := ParsePolicyAction("project:Read")
+	update, _ := ParsePolicyAction("project:Update")
+	allow, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read", "project:Update"}, []string{project.ToString()})
+	admins, _ := NewPolicy(project.ToString(), "admins", "", []*PolicyStatement{allow})
+	bounded, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{project.ToString()})
+	boundary, _ := NewPolicy(project.ToString(), "boundary", "", []*PolicyStatement{bounded})
+
+	if decision := ExplainWithinBoundary([]*Policy{admins}, boundary, project, read, nil); !decision.IsAllowed() {
+		t.Errorf("expected an action allowed by the boundary to be allowed, got %s", decision.GetReason().ToString())
+	}
+	decision := ExplainWithinBoundary([]*Policy{admins}, boundary, project, update, nil)
+	if decision.GetEffect() != UNSPECIFIED || decision.GetReason() != BOUNDARY_DENY || len(decision.ListMatches()) != 1 {
+		t.Errorf("expected a boundary deny, got %s", decision.GetReason().ToString())
+	}
+	if decision := ExplainWithinBoundary([]*Policy{admins}, nil, project, update, nil); !decision.IsAllowed() {
+		t.Errorf("expected no boundary not to limit the policies")
+	}
+	if decision := ExplainWithinBoundary([]*Policy{boundary}, admins, project, update, nil); decision.GetReason() != DEFAULT_DENY {
+		t.Errorf("expected the boundary not to allow actions by itself, got %s", decision.GetReason().ToString())
+	}
+}
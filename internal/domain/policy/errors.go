@@ -5,4 +5,8 @@ import "errors"
 var (
 	ErrInvalidPolicyAction = errors.New("invalid action name for the specified resource type")
 	ErrInvalidPolicyEffect = errors.New("invalid policy effect : correct values are 'ALLOW' or 'DENY'")
+
+	ErrInvalidConditionOperator = errors.New("invalid condition operator")
+	ErrInvalidConditionKey      = errors.New("invalid condition key : keys must start with 'principal:', 'resource:' or 'request:'")
+	ErrInvalidConditionValue    = errors.New("invalid condition value for the operator")
 )
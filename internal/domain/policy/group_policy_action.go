@@ -0,0 +1,48 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// GroupPolicyAction defines the set of possible actions that can be performed on a group of users.
+type GroupPolicyAction int
+
+const (
+	READ_GROUP GroupPolicyAction = iota
+	// UPDATE_GROUP represents the action of changing the members of a group and the policies attached to it.
+	UPDATE_GROUP
+	DELETE_GROUP
+)
+
+// ToString returns the string representation of a GroupPolicyAction.
+// It returns an error if the action is not recognized.
+func (p GroupPolicyAction) ToString() (string, error) {
+	switch p {
+	case READ_GROUP:
+		return "Read", nil
+	case UPDATE_GROUP:
+		return "Update", nil
+	case DELETE_GROUP:
+		return "Delete", nil
+	default:
+		return "", ErrInvalidPolicyAction
+	}
+}
+
+// ResourceType returns the ResourceType associated with GroupPolicyAction, which is GROUP.
+func (p GroupPolicyAction) ResourceType() common.ResourceType {
+	return common.GROUP
+}
+
+// ParseGroupPolicyAction converts a string to a corresponding GroupPolicyAction.
+// Returns an error if the string does not match a known action.
+func ParseGroupPolicyAction(action string) (GroupPolicyAction, error) {
+	switch action {
+	case "Read":
+		return READ_GROUP, nil
+	case "Update":
+		return UPDATE_GROUP, nil
+	case "Delete":
+		return DELETE_GROUP, nil
+	default:
+		return -1, ErrInvalidPolicyAction
+	}
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestGroupPolicyAction(t *testing.T) {
+	tests := []struct {
+		action GroupPolicyAction
+		str    string
+	}{
+		{READ_GROUP, "Read"},
+		{UPDATE_GROUP, "Update"},
+		{DELETE_GROUP, "Delete"},
+	}
+	for _, test := range tests {
+		if test.action.ResourceType() != common.GROUP {
+			t.Errorf("expected %d, got %d", common.GROUP, test.action.ResourceType())
+		}
+		str, err := test.action.ToString()
+		if err != nil || str != test.str {
+			t.Errorf("expected '%s', got '%s' (%v)", test.str, str, err)
+		}
+		action, err := ParseGroupPolicyAction(test.str)
+		if err != nil || action != test.action {
+			t.Errorf("expected %d, got %d (%v)", test.action, action, err)
+		}
+		formatted, _ := FormatPolicyAction(test.action)
+		if parsed, err := ParsePolicyAction(formatted); err != nil || parsed != test.action {
+			t.Errorf("expected %s to be parsed back, got %v (%v)", formatted, parsed, err)
+		}
+	}
+
+	if _, err := GroupPolicyAction(999).ToString(); err != ErrInvalidPolicyAction {
+		t.Error("expected err to be ErrInvalidPolicyAction")
+	}
+	if action, err := ParseGroupPolicyAction("SomethingElse"); err != ErrInvalidPolicyAction || action != -1 {
+		t.Errorf("expected err to be ErrInvalidPolicyAction, got %d", action)
+	}
+}
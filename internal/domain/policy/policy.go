@@ -49,11 +49,11 @@ func (p *Policy) ListStatements() []*PolicyStatement {
 }
 
 // GetPermission determines the policy effect (ALLOW, DENY, or UNSPECIFIED) for a given action
-// on a specified resource identifier. DENY takes precedence over ALLOW.
-func (p *Policy) GetPermission(resourceIdentifier *common.Identifier, action PolicyAction) PolicyEffect {
+// on a specified resource identifier, in the context of the request. DENY takes precedence over ALLOW.
+func (p *Policy) GetPermission(resourceIdentifier *common.Identifier, action PolicyAction, context *RequestContext) PolicyEffect {
 	allowed := false
 	for _, p := range p.statements.Items() {
-		effect := p.GetPermission(resourceIdentifier, action)
+		effect := p.GetPermission(resourceIdentifier, action, context)
 		if effect == DENY {
 			return DENY
 		} else if effect == ALLOW {
@@ -65,3 +65,61 @@ func (p *Policy) GetPermission(resourceIdentifier *common.Identifier, action Pol
 	}
 	return UNSPECIFIED
 }
+
+// IsWithin returns true if every action allowed by the policy on a resource is also allowed by the boundary.
+// Only the boundary statements without condition are considered, as the requests the policy will apply to are unknown.
+func (p *Policy) IsWithin(boundary *Policy) bool {
+	for _, statement := range p.statements.Items() {
+		if statement.GetEffect() != ALLOW {
+			continue
+		}
+		for _, resource := range statement.ListResources() {
+			for _, action := range statement.ListActions() {
+				if boundary.GetPermission(resource, action, nil) != ALLOW {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Grant is an action allowed by a policy on a resource.
+type Grant struct {
+	resource *common.Identifier
+	action   PolicyAction
+}
+
+// GetResource returns the resource the action is allowed on.
+func (g *Grant) GetResource() *common.Identifier {
+	return g.resource
+}
+
+// GetAction returns the allowed action.
+func (g *Grant) GetAction() PolicyAction {
+	return g.action
+}
+
+// ListGrantsOutsideProject returns the actions the policy allows on resources outside of its project,
+// like the resources of other projects, users, groups and roles.
+func (p *Policy) ListGrantsOutsideProject() []*Grant {
+	grants := []*Grant{}
+	projectId, err := p.GetIdentifier().GetParent()
+	if err != nil {
+		return grants
+	}
+	for _, statement := range p.statements.Items() {
+		if statement.GetEffect() != ALLOW {
+			continue
+		}
+		for _, resource := range statement.ListResources() {
+			if resource.ToString() == projectId.ToString() || strings.HasPrefix(resource.ToString(), projectId.ToString()+":") {
+				continue
+			}
+			for _, action := range statement.ListActions() {
+				grants = append(grants, &Grant{resource: resource, action: action})
+			}
+		}
+	}
+	return grants
+}
@@ -36,8 +36,22 @@ func GetFullName(p PolicyAction) (string, error) {
 	return fmt.Sprintf("%s:%s", str, rt), nil
 }
 
+// FormatPolicyAction returns the "resource_type:action" representation of a policy action,
+// which can be converted back using ParsePolicyAction.
+func FormatPolicyAction(p PolicyAction) (string, error) {
+	str, err := p.ToString()
+	if err != nil {
+		return "", err
+	}
+	rt, err := p.ResourceType().ToString()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", rt, str), nil
+}
+
 // ParsePolicyAction parses a string formatted as "resource_type:action" and returns the corresponding PolicyAction.
-// It supports parsing actions for known resource types such as "project" and "workflow".
+// It supports parsing actions for the "project", "workflow", "template", "policy", "user", "group" and "role" resource types.
 func ParsePolicyAction(str string) (PolicyAction, error) {
 	parts := strings.SplitN(str, ":", 2)
 	if len(parts) != 2 {
@@ -50,7 +64,53 @@ func ParsePolicyAction(str string) (PolicyAction, error) {
 		return ParseProjectPolicyAction(action)
 	case "workflow":
 		return ParseWorkflowPolicyAction(action)
+	case "template":
+		return ParseTemplatePolicyAction(action)
+	case "policy":
+		return ParsePolicyPolicyAction(action)
+	case "user":
+		return ParseUserPolicyAction(action)
+	case "group":
+		return ParseGroupPolicyAction(action)
+	case "role":
+		return ParseRolePolicyAction(action)
 	default:
 		return nil, fmt.Errorf("unknown resource type: %s", resource)
 	}
 }
+
+// ListPolicyActions returns every action that can be performed on the resource type, or none if the type has no actions.
+func ListPolicyActions(resourceType common.ResourceType) []PolicyAction {
+	switch resourceType {
+	case common.PROJECT:
+		return listPolicyActions[ProjectPolicyAction]()
+	case common.WORKFLOW:
+		return listPolicyActions[WorkflowPolicyAction]()
+	case common.TEMPLATE:
+		return listPolicyActions[TemplatePolicyAction]()
+	case common.POLICY:
+		return listPolicyActions[PolicyPolicyAction]()
+	case common.USER:
+		return listPolicyActions[UserPolicyAction]()
+	case common.GROUP:
+		return listPolicyActions[GroupPolicyAction]()
+	case common.ROLE:
+		return listPolicyActions[RolePolicyAction]()
+	default:
+		return []PolicyAction{}
+	}
+}
+
+// listPolicyActions returns the actions of an enumeration starting at zero, up to the first value without string representation.
+func listPolicyActions[T interface {
+	PolicyAction
+	~int
+}]() []PolicyAction {
+	actions := []PolicyAction{}
+	for action := T(0); ; action++ {
+		if _, err := action.ToString(); err != nil {
+			return actions
+		}
+		actions = append(actions, action)
+	}
+}
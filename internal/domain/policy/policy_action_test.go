@@ -78,6 +78,21 @@ func TestParsePolicyAction(t *testing.T) {
 			input:   "workflow:Run",
 			wantErr: false,
 		},
+		{
+			name:    "Valid template action",
+			input:   "template:CreateVersion",
+			wantErr: false,
+		},
+		{
+			name:    "Valid policy action",
+			input:   "policy:Attach",
+			wantErr: false,
+		},
+		{
+			name:    "Valid user action",
+			input:   "user:ManageKeys",
+			wantErr: false,
+		},
 		{
 			name:    "Invalid format",
 			input:   "invalidFormat",
@@ -104,3 +119,37 @@ func TestParsePolicyAction(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatPolicyAction(t *testing.T) {
+	str, err := FormatPolicyAction(RUN_WORKFLOW)
+	if err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if str != "workflow:Run" {
+		t.Errorf("expected 'workflow:Run', got '%s'", str)
+	}
+	action, err := ParsePolicyAction(str)
+	if err != nil || action != RUN_WORKFLOW {
+		t.Errorf("expected %s to be parsed back to RUN_WORKFLOW, got %v (%v)", str, action, err)
+	}
+
+	_, err = FormatPolicyAction(&mockPolicyAction{name: "Read", rt: common.ResourceType(999)})
+	if err == nil {
+		t.Error("expected err to be not nil")
+	}
+}
+
+func TestListPolicyActions(t *testing.T) {
+	actions := ListPolicyActions(common.WORKFLOW)
+	if len(actions) != 4 || actions[0] != READ_WORKFLOW || actions[3] != RUN_WORKFLOW {
+		t.Errorf("expected the 4 workflow actions, got %v", actions)
+	}
+	for _, action := range ListPolicyActions(common.PROJECT) {
+		if _, err := FormatPolicyAction(action); err != nil || action.ResourceType() != common.PROJECT {
+			t.Errorf("unexpected project action %v", action)
+		}
+	}
+	if len(ListPolicyActions(common.SECRET)) != 0 {
+		t.Errorf("expected no secret action")
+	}
+}
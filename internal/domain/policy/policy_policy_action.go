@@ -0,0 +1,59 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// PolicyPolicyAction defines the set of possible actions that can be performed on a policy resource.
+type PolicyPolicyAction int
+
+const (
+	READ_POLICY PolicyPolicyAction = iota
+	UPDATE_POLICY
+	DELETE_POLICY
+	// ATTACH_POLICY represents the action of attaching a policy to a user.
+	ATTACH_POLICY
+	// DETACH_POLICY represents the action of detaching a policy from a user.
+	DETACH_POLICY
+)
+
+// ToString returns the string representation of a PolicyPolicyAction.
+// It returns an error if the action is not recognized.
+func (p PolicyPolicyAction) ToString() (string, error) {
+	switch p {
+	case READ_POLICY:
+		return "Read", nil
+	case UPDATE_POLICY:
+		return "Update", nil
+	case DELETE_POLICY:
+		return "Delete", nil
+	case ATTACH_POLICY:
+		return "Attach", nil
+	case DETACH_POLICY:
+		return "Detach", nil
+	default:
+		return "", ErrInvalidPolicyAction
+	}
+}
+
+// ResourceType returns the ResourceType associated with PolicyPolicyAction, which is POLICY.
+func (p PolicyPolicyAction) ResourceType() common.ResourceType {
+	return common.POLICY
+}
+
+// ParsePolicyPolicyAction converts a string to a corresponding PolicyPolicyAction.
+// Returns an error if the string does not match a known action.
+func ParsePolicyPolicyAction(action string) (PolicyPolicyAction, error) {
+	switch action {
+	case "Read":
+		return READ_POLICY, nil
+	case "Update":
+		return UPDATE_POLICY, nil
+	case "Delete":
+		return DELETE_POLICY, nil
+	case "Attach":
+		return ATTACH_POLICY, nil
+	case "Detach":
+		return DETACH_POLICY, nil
+	default:
+		return -1, ErrInvalidPolicyAction
+	}
+}
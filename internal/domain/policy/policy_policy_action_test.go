@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestPolicyPolicyAction(t *testing.T) {
+	tests := []struct {
+		action PolicyPolicyAction
+		str    string
+	}{
+		{READ_POLICY, "Read"},
+		{UPDATE_POLICY, "Update"},
+		{DELETE_POLICY, "Delete"},
+		{ATTACH_POLICY, "Attach"},
+		{DETACH_POLICY, "Detach"},
+	}
+	for _, test := range tests {
+		if test.action.ResourceType() != common.POLICY {
+			t.Errorf("expected %d, got %d", common.POLICY, test.action.ResourceType())
+		}
+		str, err := test.action.ToString()
+		if err != nil || str != test.str {
+			t.Errorf("expected '%s', got '%s' (%v)", test.str, str, err)
+		}
+		action, err := ParsePolicyPolicyAction(test.str)
+		if err != nil || action != test.action {
+			t.Errorf("expected %d, got %d (%v)", test.action, action, err)
+		}
+		formatted, _ := FormatPolicyAction(test.action)
+		if parsed, err := ParsePolicyAction(formatted); err != nil || parsed != test.action {
+			t.Errorf("expected %s to be parsed back, got %v (%v)", formatted, parsed, err)
+		}
+	}
+
+	if _, err := PolicyPolicyAction(999).ToString(); err != ErrInvalidPolicyAction {
+		t.Error("expected err to be ErrInvalidPolicyAction")
+	}
+	if action, err := ParsePolicyPolicyAction("SomethingElse"); err != ErrInvalidPolicyAction || action != -1 {
+		t.Errorf("expected err to be ErrInvalidPolicyAction, got %d", action)
+	}
+}
@@ -41,11 +41,13 @@ func ParsePolicyEffect(str string) (PolicyEffect, error) {
 }
 
 // PolicyStatement represents a statement within a policy.
-// It binds a set of resource identifiers, actions, and an effect (Allow or Deny).
+// It binds a set of resource identifiers, actions, and an effect (Allow or Deny),
+// optionally restricted to the requests satisfying all of its conditions.
 type PolicyStatement struct {
 	resourceIdentifiers *common.List[*common.Identifier]
 	actions             *common.List[PolicyAction]
 	effect              PolicyEffect
+	conditions          []*Condition
 }
 
 type PolicyStatementComparator struct{}
@@ -66,6 +68,7 @@ func NewPolicyStatement(effect PolicyEffect, resources []*common.Identifier, act
 		effect:              effect,
 		resourceIdentifiers: common.NewList(common.IdentifierComparator{}, resources),
 		actions:             common.NewList(PolicyActionComparator{}, actions),
+		conditions:          []*Condition{},
 	}, nil
 }
 
@@ -84,11 +87,27 @@ func (p *PolicyStatement) GetEffect() PolicyEffect {
 	return p.effect
 }
 
-// GetPermission determines the applicable effect for a given resource and action.
-// Returns UNSPECIFIED if the resource or action is not covered by the statement.
-func (p *PolicyStatement) GetPermission(resourceIdentifier *common.Identifier, action PolicyAction) PolicyEffect {
+// ListConditions returns the conditions the requests must satisfy for the statement to apply.
+func (p *PolicyStatement) ListConditions() []*Condition {
+	return append([]*Condition(nil), p.conditions...)
+}
+
+// SetConditions replaces the conditions of the statement.
+func (p *PolicyStatement) SetConditions(conditions []*Condition) {
+	p.conditions = append([]*Condition(nil), conditions...)
+}
+
+// GetPermission determines the applicable effect for a given resource and action, in the context of the request.
+// Returns UNSPECIFIED if the resource or action is not covered by the statement, or if the context does not satisfy
+// every condition of the statement. The context may be nil, in which case only statements without condition apply.
+func (p *PolicyStatement) GetPermission(resourceIdentifier *common.Identifier, action PolicyAction, context *RequestContext) PolicyEffect {
 	if !p.resourceIdentifiers.Contains(resourceIdentifier) || !p.actions.Contains(action) {
 		return UNSPECIFIED
 	}
+	for _, condition := range p.conditions {
+		if !condition.IsSatisfied(context) {
+			return UNSPECIFIED
+		}
+	}
 	return p.GetEffect()
 }
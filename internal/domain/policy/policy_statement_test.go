@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
@@ -105,3 +106,32 @@ func TestPolicyStatement_ListActions(t *testing.T) {
 		t.Errorf("ListActions returned unexpected result: %v", got)
 	}
 }
+
+func TestParsePolicyStatement(t *testing.T) {
+	statement, err := ParsePolicyStatement(0, "Allow", []string{"workflow:Run", "project:Read"}, []string{"autops::project:ABCDEFGHIJ"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if statement.GetEffect() != ALLOW || len(statement.ListActions()) != 2 || len(statement.ListResources()) != 1 {
+		t.Error("unexpected parsed statement")
+	}
+
+	tests := []struct {
+		effect    string
+		actions   []string
+		resources []string
+		field     string
+		expected  error
+	}{
+		{"Maybe", nil, nil, "effect", ErrInvalidPolicyEffect},
+		{"Deny", []string{"workflow:Run", "workflow:Fly"}, nil, "actions[1]", ErrInvalidPolicyAction},
+		{"Deny", []string{"workflow:Run"}, []string{"autops::project:ABCDEFGHIJ", "project"}, "resources[1]", common.ErrInvalidIdentifierFormat},
+	}
+	for _, test := range tests {
+		_, err := ParsePolicyStatement(2, test.effect, test.actions, test.resources)
+		statementErr, ok := err.(*StatementError)
+		if !ok || statementErr.GetIndex() != 2 || statementErr.GetField() != test.field || !errors.Is(err, test.expected) {
+			t.Errorf("expected the error to locate statements[2].%s, got %v", test.field, err)
+		}
+	}
+}
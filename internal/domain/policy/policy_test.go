@@ -40,26 +40,74 @@ func TestPolicy_GetPermission(t *testing.T) {
 	}
 
 	policy, _ := NewPolicy("autops::project:1234567890", "test", "test", []*PolicyStatement{})
-	effect := policy.GetPermission(id, action)
+	effect := policy.GetPermission(id, action, nil)
 	if effect != UNSPECIFIED {
 		t.Errorf("expected %d, got %d", UNSPECIFIED, effect)
 	}
 
 	policy.statements.Append(statementAllow)
-	effect = policy.GetPermission(id, action)
+	effect = policy.GetPermission(id, action, nil)
 	if effect != ALLOW {
 		t.Errorf("expected %d, got %d", ALLOW, effect)
 	}
 
 	policy.statements.Append(statementDeny)
-	effect = policy.GetPermission(id, action)
+	effect = policy.GetPermission(id, action, nil)
 	if effect != DENY {
 		t.Errorf("expected %d, got %d", DENY, effect)
 	}
 
 	policy.statements.Remove(statementAllow)
-	effect = policy.GetPermission(id, action)
+	effect = policy.GetPermission(id, action, nil)
 	if effect != DENY {
 		t.Errorf("expected %d, got %d", DENY, effect)
 	}
 }
+
+func TestPolicy_IsWithin(t *testing.T) {
+	project, _ := common.NewIdentifier("autops::project:1234567890")
+	other, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ")
+	readAll, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{project.ToString(), other.ToString()})
+	boundary, _ := NewPolicy(project.ToString(), "boundary", "", []*PolicyStatement{readAll})
+
+	read, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{project.ToString()})
+	denyUpdate, _ := ParsePolicyStatement(1, "Deny", []string{"project:Update"}, []string{project.ToString()})
+	readers, _ := NewPolicy(project.ToString(), "readers", "", []*PolicyStatement{read, denyUpdate})
+	if !readers.IsWithin(boundary) {
+		t.Errorf("expected a policy allowing a subset of the boundary to be within it")
+	}
+	update, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read", "project:Update"}, []string{project.ToString()})
+	writers, _ := NewPolicy(project.ToString(), "writers", "", []*PolicyStatement{update})
+	if writers.IsWithin(boundary) {
+		t.Errorf("expected a policy allowing an action outside of the boundary not to be within it")
+	}
+
+	conditional, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{project.ToString()})
+	condition, _ := NewCondition(STRING_EQUALS, "resource:tag/env", []string{"dev"})
+	conditional.SetConditions([]*Condition{condition})
+	conditionalBoundary, _ := NewPolicy(project.ToString(), "conditional", "", []*PolicyStatement{conditional})
+	if readers.IsWithin(conditionalBoundary) {
+		t.Errorf("expected conditional boundary statements not to allow granting an action")
+	}
+}
+
+func TestPolicy_ListGrantsOutsideProject(t *testing.T) {
+	project, _ := common.NewIdentifier("autops::project:1234567890")
+	workflow, _ := common.NewIdentifier("autops::project:1234567890:workflow:ABCDEFGHIJ")
+	other, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ")
+	user, _ := common.NewIdentifier("autops::user:1234567890")
+	inside, _ := ParsePolicyStatement(0, "Allow", []string{"project:Read", "workflow:Run"}, []string{project.ToString(), workflow.ToString()})
+	outside, _ := ParsePolicyStatement(1, "Allow", []string{"project:Update"}, []string{other.ToString(), user.ToString()})
+	deny, _ := ParsePolicyStatement(2, "Deny", []string{"project:Delete"}, []string{other.ToString()})
+	policy, _ := NewPolicy(project.ToString(), "mixed", "", []*PolicyStatement{inside, outside, deny})
+
+	grants := policy.ListGrantsOutsideProject()
+	if len(grants) != 2 {
+		t.Fatalf("expected the 2 actions allowed outside of the project, got %d", len(grants))
+	}
+	for _, grant := range grants {
+		if grant.GetAction() != UPDATE_PROJECT || (grant.GetResource().ToString() != other.ToString() && grant.GetResource().ToString() != user.ToString()) {
+			t.Errorf("expected project:Update on another project or a user, got %v on %s", grant.GetAction(), grant.GetResource().ToString())
+		}
+	}
+}
@@ -17,6 +17,12 @@ const (
 	LIST_WORKFLOWS
 	// LIST_TEMPLATES represents the action of listing all templates within a project.
 	LIST_TEMPLATES
+	// CREATE_WORKFLOW represents the action of creating a workflow within a project.
+	CREATE_WORKFLOW
+	// CREATE_TEMPLATE represents the action of creating a template within a project.
+	CREATE_TEMPLATE
+	// CREATE_POLICY represents the action of creating a policy within a project.
+	CREATE_POLICY
 )
 
 // ToString converts a ProjectPolicyAction to its string representation.
@@ -32,6 +38,12 @@ func (p ProjectPolicyAction) ToString() (string, error) {
 		return "ListWorkflows", nil
 	case LIST_TEMPLATES:
 		return "ListTemplates", nil
+	case CREATE_WORKFLOW:
+		return "CreateWorkflow", nil
+	case CREATE_TEMPLATE:
+		return "CreateTemplate", nil
+	case CREATE_POLICY:
+		return "CreatePolicy", nil
 	default:
 		return "", ErrInvalidPolicyAction
 	}
@@ -55,6 +67,12 @@ func ParseProjectPolicyAction(action string) (ProjectPolicyAction, error) {
 		return LIST_WORKFLOWS, nil
 	case "ListTemplates":
 		return LIST_TEMPLATES, nil
+	case "CreateWorkflow":
+		return CREATE_WORKFLOW, nil
+	case "CreateTemplate":
+		return CREATE_TEMPLATE, nil
+	case "CreatePolicy":
+		return CREATE_POLICY, nil
 	default:
 		return -1, ErrInvalidPolicyAction
 	}
@@ -104,6 +104,16 @@ func TestParseProjectPolicyAction(t *testing.T) {
 		t.Errorf("expected %d, got %d", LIST_WORKFLOWS, action)
 	}
 
+	for str, expected := range map[string]ProjectPolicyAction{"CreateWorkflow": CREATE_WORKFLOW, "CreateTemplate": CREATE_TEMPLATE, "CreatePolicy": CREATE_POLICY} {
+		action, err = ParseProjectPolicyAction(str)
+		if err != nil || action != expected {
+			t.Errorf("expected %d, got %d (%v)", expected, action, err)
+		}
+		if formatted, _ := action.ToString(); formatted != str {
+			t.Errorf("expected '%s', got '%s'", str, formatted)
+		}
+	}
+
 	action, err = ParseProjectPolicyAction("SomethingElse")
 	if err != ErrInvalidPolicyAction {
 		t.Errorf("expected err to be ErrInvalidPolicyAction")
@@ -0,0 +1,70 @@
+package policy
+
+import (
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+const (
+	// PRINCIPAL_ID_KEY is the context key of the identifier of the principal making the request.
+	PRINCIPAL_ID_KEY = "principal:id"
+	// REQUEST_TIME_KEY is the context key of the date of the request, formatted as RFC 3339.
+	REQUEST_TIME_KEY = "request:time"
+)
+
+// PrincipalTagKey returns the context key of the value of a tag of the principal, e.g. "principal:tag/team".
+func PrincipalTagKey(key string) string {
+	return "principal:tag/" + key
+}
+
+// ResourceTagKey returns the context key of the value of a tag of the resource, e.g. "resource:tag/env".
+func ResourceTagKey(key string) string {
+	return "resource:tag/" + key
+}
+
+// RequestContext holds the attributes of a request against which the conditions of policy statements are evaluated:
+// the principal and its tags, the tags of the resource, the date of the request, and any other value keyed like them.
+type RequestContext struct {
+	values map[string]string
+}
+
+// NewRequestContext creates the context of a request made by the principal at the given date.
+// The principal may be nil when the request is not made on behalf of an identity.
+func NewRequestContext(principalIdentifier *common.Identifier, at time.Time) *RequestContext {
+	c := &RequestContext{values: map[string]string{}}
+	if principalIdentifier != nil {
+		c.Set(PRINCIPAL_ID_KEY, principalIdentifier.ToString())
+	}
+	c.Set(REQUEST_TIME_KEY, at.Format(time.RFC3339))
+	return c
+}
+
+// SetPrincipalTags adds the tags of the principal to the context.
+func (c *RequestContext) SetPrincipalTags(tags []*common.Tag) {
+	for _, tag := range tags {
+		c.Set(PrincipalTagKey(tag.GetKey()), tag.GetValue())
+	}
+}
+
+// SetResourceTags adds the tags of the resource to the context.
+func (c *RequestContext) SetResourceTags(tags []*common.Tag) {
+	for _, tag := range tags {
+		c.Set(ResourceTagKey(tag.GetKey()), tag.GetValue())
+	}
+}
+
+// Set sets the value of a key of the context, replacing any previous value.
+func (c *RequestContext) Set(key string, value string) {
+	c.values[key] = value
+}
+
+// Get returns the value of a key of the context, and false if the key is not set.
+// A nil context has no key.
+func (c *RequestContext) Get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	value, ok := c.values[key]
+	return value, ok
+}
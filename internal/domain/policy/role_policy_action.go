@@ -0,0 +1,49 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// RolePolicyAction defines the set of possible actions that can be performed on an assumable role.
+type RolePolicyAction int
+
+const (
+	// READ_ROLE represents the action of reading a role and the audit records of its sessions.
+	READ_ROLE RolePolicyAction = iota
+	// UPDATE_ROLE represents the action of changing the principals trusted by a role and the policies attached to it.
+	UPDATE_ROLE
+	DELETE_ROLE
+)
+
+// ToString returns the string representation of a RolePolicyAction.
+// It returns an error if the action is not recognized.
+func (p RolePolicyAction) ToString() (string, error) {
+	switch p {
+	case READ_ROLE:
+		return "Read", nil
+	case UPDATE_ROLE:
+		return "Update", nil
+	case DELETE_ROLE:
+		return "Delete", nil
+	default:
+		return "", ErrInvalidPolicyAction
+	}
+}
+
+// ResourceType returns the ResourceType associated with RolePolicyAction, which is ROLE.
+func (p RolePolicyAction) ResourceType() common.ResourceType {
+	return common.ROLE
+}
+
+// ParseRolePolicyAction converts a string to a corresponding RolePolicyAction.
+// Returns an error if the string does not match a known action.
+func ParseRolePolicyAction(action string) (RolePolicyAction, error) {
+	switch action {
+	case "Read":
+		return READ_ROLE, nil
+	case "Update":
+		return UPDATE_ROLE, nil
+	case "Delete":
+		return DELETE_ROLE, nil
+	default:
+		return -1, ErrInvalidPolicyAction
+	}
+}
@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestRolePolicyAction(t *testing.T) {
+	tests := []struct {
+		action RolePolicyAction
+		str    string
+	}{
+		{READ_ROLE, "Read"},
+		{UPDATE_ROLE, "Update"},
+		{DELETE_ROLE, "Delete"},
+	}
+	for _, test := range tests {
+		if test.action.ResourceType() != common.ROLE {
+			t.Errorf("expected %d, got %d", common.ROLE, test.action.ResourceType())
+		}
+		str, err := test.action.ToString()
+		if err != nil || str != test.str {
+			t.Errorf("expected '%s', got '%s' (%v)", test.str, str, err)
+		}
+		action, err := ParseRolePolicyAction(test.str)
+		if err != nil || action != test.action {
+			t.Errorf("expected %d, got %d (%v)", test.action, action, err)
+		}
+		formatted, _ := FormatPolicyAction(test.action)
+		if parsed, err := ParsePolicyAction(formatted); err != nil || parsed != test.action {
+			t.Errorf("expected %s to be parsed back, got %v (%v)", formatted, parsed, err)
+		}
+	}
+
+	if _, err := RolePolicyAction(999).ToString(); err != ErrInvalidPolicyAction {
+		t.Error("expected err to be ErrInvalidPolicyAction")
+	}
+	if action, err := ParseRolePolicyAction("SomethingElse"); err != ErrInvalidPolicyAction || action != -1 {
+		t.Errorf("expected err to be ErrInvalidPolicyAction, got %d", action)
+	}
+}
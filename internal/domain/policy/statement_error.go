@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// StatementError locates the invalid field of a policy statement, e.g. the second action of the first statement.
+type StatementError struct {
+	index int
+	field string
+	err   error
+}
+
+// NewStatementError creates a StatementError for the field of the statement at the given index, e.g. "effect" or "actions[1]".
+func NewStatementError(index int, field string, err error) *StatementError {
+	return &StatementError{
+		index: index,
+		field: field,
+		err:   err,
+	}
+}
+
+// Error returns the location of the invalid field followed by the reason it was rejected, e.g. "statements[0].actions[1]: ...".
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("statements[%d].%s: %s", e.index, e.field, e.err.Error())
+}
+
+// Unwrap returns the reason the field was rejected.
+func (e *StatementError) Unwrap() error {
+	return e.err
+}
+
+// GetIndex returns the index of the invalid statement in the policy.
+func (e *StatementError) GetIndex() int {
+	return e.index
+}
+
+// GetField returns the invalid field of the statement, with the index of the invalid item for lists.
+func (e *StatementError) GetField() string {
+	return e.field
+}
+
+// ParsePolicyStatement creates the statement at the given index of a policy from its string representation:
+// an effect parsed by ParsePolicyEffect, actions formatted as "resource_type:action" and resource identifiers.
+//
+// Returns a StatementError locating the first invalid field.
+func ParsePolicyStatement(index int, effect string, actions []string, resources []string) (*PolicyStatement, error) {
+	parsedEffect, err := ParsePolicyEffect(effect)
+	if err != nil {
+		return nil, NewStatementError(index, "effect", err)
+	}
+	parsedActions := make([]PolicyAction, 0, len(actions))
+	for i, str := range actions {
+		action, err := ParsePolicyAction(str)
+		if err != nil {
+			return nil, NewStatementError(index, fmt.Sprintf("actions[%d]", i), err)
+		}
+		parsedActions = append(parsedActions, action)
+	}
+	parsedResources := make([]*common.Identifier, 0, len(resources))
+	for i, str := range resources {
+		resource, err := common.NewIdentifier(str)
+		if err != nil {
+			return nil, NewStatementError(index, fmt.Sprintf("resources[%d]", i), err)
+		}
+		parsedResources = append(parsedResources, resource)
+	}
+	return NewPolicyStatement(parsedEffect, parsedResources, parsedActions)
+}
+
+// ParseStatementCondition creates the condition at the given position of the statement at the given index of a policy,
+// from an operator parsed by ParseConditionOperator, a request context key and the values to compare it to.
+//
+// Returns a StatementError locating the condition, e.g. "conditions[0]".
+func ParseStatementCondition(index int, conditionIndex int, operator string, key string, values []string) (*Condition, error) {
+	field := fmt.Sprintf("conditions[%d]", conditionIndex)
+	parsedOperator, err := ParseConditionOperator(operator)
+	if err != nil {
+		return nil, NewStatementError(index, field, err)
+	}
+	condition, err := NewCondition(parsedOperator, key, values)
+	if err != nil {
+		return nil, NewStatementError(index, field, err)
+	}
+	return condition, nil
+}
@@ -0,0 +1,59 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// TemplatePolicyAction defines the set of possible actions that can be performed on a template resource.
+type TemplatePolicyAction int
+
+const (
+	READ_TEMPLATE TemplatePolicyAction = iota
+	UPDATE_TEMPLATE
+	DELETE_TEMPLATE
+	// CREATE_TEMPLATE_VERSION represents the action of publishing a new version of a template.
+	CREATE_TEMPLATE_VERSION
+	// USE_TEMPLATE represents the action of referencing a template from the steps of a workflow.
+	USE_TEMPLATE
+)
+
+// ToString returns the string representation of a TemplatePolicyAction.
+// It returns an error if the action is not recognized.
+func (p TemplatePolicyAction) ToString() (string, error) {
+	switch p {
+	case READ_TEMPLATE:
+		return "Read", nil
+	case UPDATE_TEMPLATE:
+		return "Update", nil
+	case DELETE_TEMPLATE:
+		return "Delete", nil
+	case CREATE_TEMPLATE_VERSION:
+		return "CreateVersion", nil
+	case USE_TEMPLATE:
+		return "Use", nil
+	default:
+		return "", ErrInvalidPolicyAction
+	}
+}
+
+// ResourceType returns the ResourceType associated with TemplatePolicyAction, which is TEMPLATE.
+func (p TemplatePolicyAction) ResourceType() common.ResourceType {
+	return common.TEMPLATE
+}
+
+// ParseTemplatePolicyAction converts a string to a corresponding TemplatePolicyAction.
+// Returns an error if the string does not match a known action.
+func ParseTemplatePolicyAction(action string) (TemplatePolicyAction, error) {
+	switch action {
+	case "Read":
+		return READ_TEMPLATE, nil
+	case "Update":
+		return UPDATE_TEMPLATE, nil
+	case "Delete":
+		return DELETE_TEMPLATE, nil
+	case "CreateVersion":
+		return CREATE_TEMPLATE_VERSION, nil
+	case "Use":
+		return USE_TEMPLATE, nil
+	default:
+		return -1, ErrInvalidPolicyAction
+	}
+}
@@ -0,0 +1,44 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestTemplatePolicyAction(t *testing.T) {
+	tests := []struct {
+		action TemplatePolicyAction
+		str    string
+	}{
+		{READ_TEMPLATE, "Read"},
+		{UPDATE_TEMPLATE, "Update"},
+		{DELETE_TEMPLATE, "Delete"},
+		{CREATE_TEMPLATE_VERSION, "CreateVersion"},
+		{USE_TEMPLATE, "Use"},
+	}
+	for _, test := range tests {
+		if test.action.ResourceType() != common.TEMPLATE {
+			t.Errorf("expected %d, got %d", common.TEMPLATE, test.action.ResourceType())
+		}
+		str, err := test.action.ToString()
+		if err != nil || str != test.str {
+			t.Errorf("expected '%s', got '%s' (%v)", test.str, str, err)
+		}
+		action, err := ParseTemplatePolicyAction(test.str)
+		if err != nil || action != test.action {
+			t.Errorf("expected %d, got %d (%v)", test.action, action, err)
+		}
+		formatted, _ := FormatPolicyAction(test.action)
+		if parsed, err := ParsePolicyAction(formatted); err != nil || parsed != test.action {
+			t.Errorf("expected %s to be parsed back, got %v (%v)", formatted, parsed, err)
+		}
+	}
+
+	if _, err := TemplatePolicyAction(999).ToString(); err != ErrInvalidPolicyAction {
+		t.Error("expected err to be ErrInvalidPolicyAction")
+	}
+	if action, err := ParseTemplatePolicyAction("SomethingElse"); err != ErrInvalidPolicyAction || action != -1 {
+		t.Errorf("expected err to be ErrInvalidPolicyAction, got %d", action)
+	}
+}
@@ -0,0 +1,72 @@
+package policy
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// UserPolicyAction defines the set of possible actions that can be performed on a user resource.
+type UserPolicyAction int
+
+const (
+	READ_USER UserPolicyAction = iota
+	UPDATE_USER
+	DELETE_USER
+	// MANAGE_USER_KEYS represents the action of creating, rotating and revoking the access keys of a user.
+	MANAGE_USER_KEYS
+	// CREATE_GROUP represents the action of creating groups, authorized on the user creating them since groups do not belong to a project.
+	CREATE_GROUP
+	// CREATE_ROLE represents the action of creating roles, authorized on the user creating them since roles do not belong to a project.
+	CREATE_ROLE
+	// MANAGE_GITOPS represents the action of listing and triggering the syncs of the GitOps repository,
+	// authorized on the user managing them since the repository does not belong to a project.
+	MANAGE_GITOPS
+)
+
+// ToString returns the string representation of a UserPolicyAction.
+// It returns an error if the action is not recognized.
+func (p UserPolicyAction) ToString() (string, error) {
+	switch p {
+	case READ_USER:
+		return "Read", nil
+	case UPDATE_USER:
+		return "Update", nil
+	case DELETE_USER:
+		return "Delete", nil
+	case MANAGE_USER_KEYS:
+		return "ManageKeys", nil
+	case CREATE_GROUP:
+		return "CreateGroup", nil
+	case CREATE_ROLE:
+		return "CreateRole", nil
+	case MANAGE_GITOPS:
+		return "ManageGitOps", nil
+	default:
+		return "", ErrInvalidPolicyAction
+	}
+}
+
+// ResourceType returns the ResourceType associated with UserPolicyAction, which is USER.
+func (p UserPolicyAction) ResourceType() common.ResourceType {
+	return common.USER
+}
+
+// ParseUserPolicyAction converts a string to a corresponding UserPolicyAction.
+// Returns an error if the string does not match a known action.
+func ParseUserPolicyAction(action string) (UserPolicyAction, error) {
+	switch action {
+	case "Read":
+		return READ_USER, nil
+	case "Update":
+		return UPDATE_USER, nil
+	case "Delete":
+		return DELETE_USER, nil
+	case "ManageKeys":
+		return MANAGE_USER_KEYS, nil
+	case "CreateGroup":
+		return CREATE_GROUP, nil
+	case "CreateRole":
+		return CREATE_ROLE, nil
+	case "ManageGitOps":
+		return MANAGE_GITOPS, nil
+	default:
+		return -1, ErrInvalidPolicyAction
+	}
+}
@@ -0,0 +1,46 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestUserPolicyAction(t *testing.T) {
+	tests := []struct {
+		action UserPolicyAction
+		str    string
+	}{
+		{READ_USER, "Read"},
+		{UPDATE_USER, "Update"},
+		{DELETE_USER, "Delete"},
+		{MANAGE_USER_KEYS, "ManageKeys"},
+		{CREATE_GROUP, "CreateGroup"},
+		{CREATE_ROLE, "CreateRole"},
+		{MANAGE_GITOPS, "ManageGitOps"},
+	}
+	for _, test := range tests {
+		if test.action.ResourceType() != common.USER {
+			t.Errorf("expected %d, got %d", common.USER, test.action.ResourceType())
+		}
+		str, err := test.action.ToString()
+		if err != nil || str != test.str {
+			t.Errorf("expected '%s', got '%s' (%v)", test.str, str, err)
+		}
+		action, err := ParseUserPolicyAction(test.str)
+		if err != nil || action != test.action {
+			t.Errorf("expected %d, got %d (%v)", test.action, action, err)
+		}
+		formatted, _ := FormatPolicyAction(test.action)
+		if parsed, err := ParsePolicyAction(formatted); err != nil || parsed != test.action {
+			t.Errorf("expected %s to be parsed back, got %v (%v)", formatted, parsed, err)
+		}
+	}
+
+	if _, err := UserPolicyAction(999).ToString(); err != ErrInvalidPolicyAction {
+		t.Error("expected err to be ErrInvalidPolicyAction")
+	}
+	if action, err := ParseUserPolicyAction("SomethingElse"); err != ErrInvalidPolicyAction || action != -1 {
+		t.Errorf("expected err to be ErrInvalidPolicyAction, got %d", action)
+	}
+}
@@ -0,0 +1,112 @@
+package project
+
+import (
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// Deployment records the version of a workflow, and of the templates used by its steps,
+// deployed in an environment by a successful run.
+type Deployment struct {
+	workflowIdentifier *common.Identifier
+	workflowVersion    int
+	templateVersions   map[string]int
+	runIdentifier      *common.Identifier
+	deployedAt         string
+}
+
+// DeploymentComparator is used to compare two Deployment instances based on their workflow identifier.
+type DeploymentComparator struct{}
+
+// Compare returns a comparison between the workflow identifiers of two Deployment instances.
+func (DeploymentComparator) Compare(a *Deployment, b *Deployment) int {
+	return strings.Compare(a.workflowIdentifier.ToString(), b.workflowIdentifier.ToString())
+}
+
+// NewDeployment creates the Deployment of the workflow version performed by the run.
+// Returns ErrRunNotDeployable if the run does not belong to the workflow, has not succeeded, or only computed a plan.
+func NewDeployment(wf *workflow.Workflow, run *workflow.WorkflowRun) (*Deployment, error) {
+	parent, err := run.GetIdentifier().GetParent()
+	if err != nil || parent.ToString() != wf.GetIdentifier().ToString() {
+		return nil, ErrRunNotDeployable
+	}
+	if run.GetStatus() != common.SUCCESS || run.GetMode() == workflow.PLAN_ONLY {
+		return nil, ErrRunNotDeployable
+	}
+	return ExistingDeployment(wf.GetIdentifier(), wf.GetVersion(), templateVersions(wf), run.GetIdentifier(), run.GetUpdatedAt()), nil
+}
+
+// ExistingDeployment creates a Deployment from its recorded versions.
+// This is typically used when reloading from a data store.
+func ExistingDeployment(workflowIdentifier *common.Identifier, workflowVersion int, templateVersions map[string]int, runIdentifier *common.Identifier, deployedAt string) *Deployment {
+	versions := make(map[string]int, len(templateVersions))
+	for id, version := range templateVersions {
+		versions[id] = version
+	}
+	return &Deployment{
+		workflowIdentifier: workflowIdentifier,
+		workflowVersion:    workflowVersion,
+		templateVersions:   versions,
+		runIdentifier:      runIdentifier,
+		deployedAt:         deployedAt,
+	}
+}
+
+// templateVersions returns the versions of the templates used by the steps of the workflow, indexed by template identifier.
+func templateVersions(wf *workflow.Workflow) map[string]int {
+	versions := map[string]int{}
+	for _, step := range wf.ListSteps() {
+		if task := step.GetTask(); task != nil {
+			versions[task.GetIdentifier().ToString()] = task.GetVersion()
+		}
+	}
+	return versions
+}
+
+// GetWorkflowIdentifier returns the identifier of the deployed workflow.
+func (d *Deployment) GetWorkflowIdentifier() *common.Identifier {
+	return d.workflowIdentifier
+}
+
+// GetWorkflowVersion returns the deployed version of the workflow.
+func (d *Deployment) GetWorkflowVersion() int {
+	return d.workflowVersion
+}
+
+// GetTemplateVersions returns a copy of the deployed versions of the templates, indexed by template identifier.
+func (d *Deployment) GetTemplateVersions() map[string]int {
+	versions := make(map[string]int, len(d.templateVersions))
+	for id, version := range d.templateVersions {
+		versions[id] = version
+	}
+	return versions
+}
+
+// GetRunIdentifier returns the identifier of the run that performed the deployment.
+func (d *Deployment) GetRunIdentifier() *common.Identifier {
+	return d.runIdentifier
+}
+
+// GetDeployedAt returns the date at which the deploying run completed.
+func (d *Deployment) GetDeployedAt() string {
+	return d.deployedAt
+}
+
+// Matches returns true if the workflow, and the templates used by its steps, are in the deployed versions.
+func (d *Deployment) Matches(wf *workflow.Workflow) bool {
+	if wf.GetIdentifier().ToString() != d.workflowIdentifier.ToString() || wf.GetVersion() != d.workflowVersion {
+		return false
+	}
+	versions := templateVersions(wf)
+	if len(versions) != len(d.templateVersions) {
+		return false
+	}
+	for id, version := range versions {
+		if deployed, found := d.templateVersions[id]; !found || deployed != version {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,241 @@
+package project
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// Environment represents a deployment target of a project (e.g. dev, staging or prod).
+// Its variable set is merged into the inputs of the runs targeting it, and its policies restrict what can be run against it.
+//
+// A protected environment only accepts runs from users explicitly allowed to run on the environment identifier,
+// while any user allowed to run a workflow can run it against an environment that is not protected.
+type Environment struct {
+	common.NamedEntity
+	protected   bool
+	variableSet *VariableSet
+	policies    *common.List[*policy.Policy]
+	deployments *common.List[*Deployment]
+}
+
+// EnvironmentComparator is used to compare two Environment instances based on their identifier.
+type EnvironmentComparator struct{}
+
+// Compare returns a comparison between two Environment identifiers.
+func (EnvironmentComparator) Compare(a *Environment, b *Environment) int {
+	return strings.Compare(a.GetIdentifier().ToString(), b.GetIdentifier().ToString())
+}
+
+// NewEnvironment creates a new Environment with a generated identifier under the given project, with an empty variable set.
+// Returns an error if the name or description is invalid.
+func NewEnvironment(projectId string, name string, description string, protected bool) (*Environment, error) {
+	identifier, err := common.BuildEnvironmentIdentifier(projectId)
+	if err != nil {
+		return nil, err
+	}
+	variableSet, err := NewVariableSet(name, []*Variable{})
+	if err != nil {
+		return nil, err
+	}
+	return ExistingEnvironment(identifier.ToString(), name, description, protected, variableSet, []*policy.Policy{})
+}
+
+// ExistingEnvironment creates an Environment with the provided identifier, variable set and policies.
+// This is typically used when reloading from a data store.
+//
+// Returns an error if the name or description is invalid.
+func ExistingEnvironment(identifier string, name string, description string, protected bool, variableSet *VariableSet, policies []*policy.Policy) (*Environment, error) {
+	namedEntity, err := common.NewNamedEntity(identifier, name, description)
+	if err != nil {
+		return nil, err
+	}
+	return &Environment{
+		NamedEntity: *namedEntity,
+		protected:   protected,
+		variableSet: variableSet,
+		policies:    common.NewList(policy.PolicyComparator{}, policies),
+		deployments: common.NewList(DeploymentComparator{}, []*Deployment{}),
+	}, nil
+}
+
+// IsProtected returns true if only explicitly allowed users can run workflows against the environment.
+func (e *Environment) IsProtected() bool {
+	return e.protected
+}
+
+// SetProtected sets whether only explicitly allowed users can run workflows against the environment.
+func (e *Environment) SetProtected(protected bool) {
+	e.protected = protected
+}
+
+// GetVariableSet returns the variable set of the environment.
+func (e *Environment) GetVariableSet() *VariableSet {
+	return e.variableSet
+}
+
+// SetVariableSet replaces the variable set of the environment.
+func (e *Environment) SetVariableSet(variableSet *VariableSet) {
+	e.variableSet = variableSet
+}
+
+// ListPolicies returns the policies applying to the workflows run against the environment.
+func (e *Environment) ListPolicies() []*policy.Policy {
+	return e.policies.Items()
+}
+
+// AttachPolicy adds a policy applying to the workflows run against the environment.
+// Returns an error if the policy is already attached to the environment.
+func (e *Environment) AttachPolicy(p *policy.Policy) error {
+	_, found := e.policies.SelectOne(func(attached *policy.Policy) bool {
+		return attached.GetIdentifier().ToString() == p.GetIdentifier().ToString()
+	})
+	if found {
+		return ErrPolicyAlreadyAttached
+	}
+	e.policies.Append(p)
+	return nil
+}
+
+// DetachPolicy removes a policy from the environment by its identifier.
+// Returns an error if the policy is not attached to the environment.
+func (e *Environment) DetachPolicy(id *common.Identifier) error {
+	attached, found := e.policies.SelectOne(func(p *policy.Policy) bool {
+		return p.GetIdentifier().ToString() == id.ToString()
+	})
+	if !found {
+		return ErrPolicyNotFound
+	}
+	e.policies.Remove(attached)
+	return nil
+}
+
+// ListDeployments returns the workflow versions deployed in the environment, one per workflow.
+func (e *Environment) ListDeployments() []*Deployment {
+	return e.deployments.Items()
+}
+
+// GetDeployment returns the deployment of the workflow with the given identifier, or nil if it has not been deployed.
+func (e *Environment) GetDeployment(workflowIdentifier *common.Identifier) *Deployment {
+	deployment, _ := e.deployments.SelectOne(func(d *Deployment) bool {
+		return d.workflowIdentifier.ToString() == workflowIdentifier.ToString()
+	})
+	return deployment
+}
+
+// SetDeployment records a deployment, replacing the previous deployment of the same workflow.
+func (e *Environment) SetDeployment(deployment *Deployment) {
+	if previous := e.GetDeployment(deployment.workflowIdentifier); previous != nil {
+		e.deployments.Remove(previous)
+	}
+	e.deployments.Append(deployment)
+}
+
+// SetDeployments replaces the deployments of the environment, typically when reloading it from a data store.
+func (e *Environment) SetDeployments(deployments []*Deployment) {
+	e.deployments = common.NewList(DeploymentComparator{}, deployments)
+}
+
+// GetPermission determines the effect of the environment policies for the action on the resource, in the context of the request.
+// An explicit DENY in any policy takes precedence over ALLOW, and UNSPECIFIED is returned if no policy applies.
+func (e *Environment) GetPermission(resourceIdentifier *common.Identifier, action policy.PolicyAction, context *policy.RequestContext) policy.PolicyEffect {
+	allowed := false
+	for _, p := range e.policies.Items() {
+		effect := p.GetPermission(resourceIdentifier, action, context)
+		if effect == policy.DENY {
+			return policy.DENY
+		} else if effect == policy.ALLOW {
+			allowed = true
+		}
+	}
+	if allowed {
+		return policy.ALLOW
+	}
+	return policy.UNSPECIFIED
+}
+
+// CanRun returns true if the user can run the workflow against the environment:
+//   - the user policies must allow running the workflow,
+//   - the environment policies must not deny running the workflow,
+//   - the user policies must not deny running on the environment, and must allow it if the environment is protected.
+//
+// The policies are evaluated in the context of the request, which may be nil.
+func (e *Environment) CanRun(user *identity.User, workflowIdentifier *common.Identifier, context *policy.RequestContext) bool {
+	if !user.IsAllowed(workflowIdentifier, policy.RUN_WORKFLOW, context) {
+		return false
+	}
+	if e.GetPermission(workflowIdentifier, policy.RUN_WORKFLOW, context) == policy.DENY {
+		return false
+	}
+	switch user.GetPermission(e.GetIdentifier(), policy.RUN_WORKFLOW, context) {
+	case policy.DENY:
+		return false
+	case policy.ALLOW:
+		return true
+	default:
+		return !e.protected
+	}
+}
+
+// StartRun starts a run of the workflow on behalf of the user, targeting the environment. The user cannot approve the run.
+// The variables of the environment matching the workflow inputs are merged into the provided values,
+// which take precedence, before being validated against the workflow inputs.
+//
+// Returns ErrWorkflowNotFound if the workflow belongs to another project, ErrRunNotAllowed if the user cannot run
+// the workflow against the environment, or an AttributeValidationError if the inputs are invalid.
+func (e *Environment) StartRun(user *identity.User, wf *workflow.Workflow, name string, description string, mode workflow.RunMode, values map[string]string) (*workflow.WorkflowRun, error) {
+	if err := e.checkRun(user, wf); err != nil {
+		return nil, err
+	}
+	run, err := wf.StartRun(name, description, mode, e.variableSet.MergeInputs(wf.ListInputs(), values))
+	if err != nil {
+		return nil, err
+	}
+	run.SetEnvironment(e.GetIdentifier())
+	run.SetStartedBy(user.GetIdentifier())
+	return run, nil
+}
+
+// ApplyPlan applies, on behalf of the user, the plan computed by a PLAN_ONLY run of the workflow targeting the environment.
+// The user cannot approve the run.
+//
+// Returns ErrWorkflowNotFound if the workflow belongs to another project, ErrRunNotAllowed if the plan run does not target
+// the environment or if the user cannot run the workflow against it, or any error of Workflow.ApplyPlan.
+func (e *Environment) ApplyPlan(user *identity.User, wf *workflow.Workflow, planRunIdentifier string) (*workflow.WorkflowRun, error) {
+	if err := e.checkRun(user, wf); err != nil {
+		return nil, err
+	}
+	planRun := wf.GetRun(planRunIdentifier)
+	if planRun != nil && (planRun.GetEnvironment() == nil || planRun.GetEnvironment().ToString() != e.GetIdentifier().ToString()) {
+		return nil, ErrRunNotAllowed
+	}
+	run, err := wf.ApplyPlan(planRunIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	run.SetStartedBy(user.GetIdentifier())
+	return run, nil
+}
+
+// checkRun returns ErrWorkflowNotFound if the workflow belongs to another project than the environment,
+// or ErrRunNotAllowed if the user cannot run the workflow against the environment.
+func (e *Environment) checkRun(user *identity.User, wf *workflow.Workflow) error {
+	environmentProject, err := e.GetIdentifier().GetParent()
+	if err != nil {
+		return err
+	}
+	workflowProject, err := wf.GetIdentifier().GetParent()
+	if err != nil || workflowProject.ToString() != environmentProject.ToString() {
+		return ErrWorkflowNotFound
+	}
+	context := user.NewRequestContext(time.Now())
+	context.SetResourceTags(wf.ListTags())
+	if !e.CanRun(user, wf.GetIdentifier(), context) {
+		return ErrRunNotAllowed
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+func newTestUser(t *testing.T, username string, effect policy.PolicyEffect, resources ...*common.Identifier) *identity.User {
+	t.Helper()
+	user, err := identity.NewUser(username+"@example.com", username)
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	statement, _ := policy.NewPolicyStatement(effect, resources, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	p, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", username, "", []*policy.PolicyStatement{statement})
+	user.AttachPolicy(p)
+	return user
+}
+
+func newTestWorkflow(t *testing.T, projectId string) *workflow.Workflow {
+	t.Helper()
+	wf, err := workflow.NewWorkflow(projectId, "deploy", "", "/path/to/file.yml")
+	if err != nil {
+		t.Fatalf("failed to create test workflow: %v", err)
+	}
+	region, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "region", "", workflow.STRING, "")
+	wf.AddInput(region)
+	return wf
+}
+
+func TestEnvironmentCanRun(t *testing.T) {
+	projectId := "autops::project:ABCDEFGHIJ"
+	wf := newTestWorkflow(t, projectId)
+	dev, _ := NewEnvironment(projectId, "dev", "", false)
+	prod, _ := NewEnvironment(projectId, "prod", "", true)
+
+	developer := newTestUser(t, "developer", policy.ALLOW, wf.GetIdentifier())
+	operator := newTestUser(t, "operator", policy.ALLOW, wf.GetIdentifier(), prod.GetIdentifier())
+	stranger := newTestUser(t, "stranger", policy.ALLOW)
+
+	if !dev.CanRun(developer, wf.GetIdentifier(), nil) {
+		t.Error("expected a user allowed on the workflow to run it against an unprotected environment")
+	}
+	if prod.CanRun(developer, wf.GetIdentifier(), nil) {
+		t.Error("expected a protected environment to require an explicit allow")
+	}
+	if !prod.CanRun(operator, wf.GetIdentifier(), nil) {
+		t.Error("expected an explicitly allowed user to run against a protected environment")
+	}
+	if dev.CanRun(stranger, wf.GetIdentifier(), nil) {
+		t.Error("expected a user not allowed on the workflow to be rejected")
+	}
+
+	statement, _ := policy.NewPolicyStatement(policy.DENY, []*common.Identifier{wf.GetIdentifier()}, []policy.PolicyAction{policy.RUN_WORKFLOW})
+	freeze, _ := policy.NewPolicy(projectId, "freeze", "", []*policy.PolicyStatement{statement})
+	if err := prod.AttachPolicy(freeze); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := prod.AttachPolicy(freeze); err != ErrPolicyAlreadyAttached {
+		t.Errorf("expected err to be ErrPolicyAlreadyAttached, got %v", err)
+	}
+	if prod.CanRun(operator, wf.GetIdentifier(), nil) {
+		t.Error("expected the environment policies to deny the run")
+	}
+	if err := prod.DetachPolicy(freeze.GetIdentifier()); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := prod.DetachPolicy(freeze.GetIdentifier()); err != ErrPolicyNotFound {
+		t.Errorf("expected err to be ErrPolicyNotFound, got %v", err)
+	}
+}
+
+func TestEnvironmentStartRun(t *testing.T) {
+	projectId := "autops::project:ABCDEFGHIJ"
+	wf := newTestWorkflow(t, projectId)
+	env, _ := NewEnvironment(projectId, "staging", "", false)
+	region, _ := NewVariable("region", workflow.STRING, "eu-west-1")
+	env.GetVariableSet().SetVariable(region)
+	user := newTestUser(t, "developer", policy.ALLOW, wf.GetIdentifier())
+
+	run, err := env.StartRun(user, wf, "deploy", "", workflow.STANDARD, map[string]string{})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetInputs()["region"] != "eu-west-1" {
+		t.Errorf("expected the environment variables to be merged, got %v", run.GetInputs())
+	}
+	if run.GetEnvironment() == nil || run.GetEnvironment().ToString() != env.GetIdentifier().ToString() {
+		t.Error("expected the run to be tagged with its environment")
+	}
+
+	run, _ = env.StartRun(user, wf, "deploy", "", workflow.STANDARD, map[string]string{"region": "us-east-1"})
+	if run.GetInputs()["region"] != "us-east-1" {
+		t.Error("expected the provided inputs to override the environment variables")
+	}
+
+	stranger := newTestUser(t, "stranger", policy.ALLOW)
+	if _, err := env.StartRun(stranger, wf, "deploy", "", workflow.STANDARD, nil); err != ErrRunNotAllowed {
+		t.Errorf("expected err to be ErrRunNotAllowed, got %v", err)
+	}
+	other := newTestWorkflow(t, "autops::project:KLMNOPQRST")
+	if _, err := env.StartRun(user, other, "deploy", "", workflow.STANDARD, nil); err != ErrWorkflowNotFound {
+		t.Errorf("expected err to be ErrWorkflowNotFound, got %v", err)
+	}
+}
+
+func TestEnvironmentApplyPlan(t *testing.T) {
+	projectId := "autops::project:ABCDEFGHIJ"
+	wf := newTestWorkflow(t, projectId)
+	dev, _ := NewEnvironment(projectId, "dev", "", false)
+	prod, _ := NewEnvironment(projectId, "prod", "", true)
+	developer := newTestUser(t, "developer", policy.ALLOW, wf.GetIdentifier())
+	operator := newTestUser(t, "operator", policy.ALLOW, wf.GetIdentifier(), prod.GetIdentifier())
+
+	planRun, err := prod.StartRun(operator, wf, "plan", "", workflow.PLAN_ONLY, map[string]string{"region": "eu-west-1"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	planRun.SetStatus(common.SUCCESS)
+	planRunId := planRun.GetIdentifier().ToString()
+
+	if _, err := prod.ApplyPlan(developer, wf, planRunId); err != ErrRunNotAllowed {
+		t.Errorf("expected err to be ErrRunNotAllowed for a user not allowed on the environment, got %v", err)
+	}
+	if _, err := dev.ApplyPlan(developer, wf, planRunId); err != ErrRunNotAllowed {
+		t.Errorf("expected err to be ErrRunNotAllowed for a plan of another environment, got %v", err)
+	}
+	run, err := prod.ApplyPlan(operator, wf, planRunId)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetEnvironment().ToString() != prod.GetIdentifier().ToString() || run.GetStartedBy().ToString() != operator.GetIdentifier().ToString() {
+		t.Error("expected the apply run to target the environment on behalf of the user")
+	}
+}
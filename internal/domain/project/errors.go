@@ -3,7 +3,24 @@ package project
 import "errors"
 
 var (
-	ErrPolicyNotFound   = errors.New("cannot find a policy with the provided id in the current project")
-	ErrTemplateNotFound = errors.New("cannot find a template with the provided id in the current project")
-	ErrWorkflowNotFound = errors.New("cannot find a template with the provided id i, the current project")
+	ErrPolicyNotFound       = errors.New("cannot find a policy with the provided id in the current project")
+	ErrTemplateNotFound     = errors.New("cannot find a template with the provided id in the current project")
+	ErrWorkflowNotFound     = errors.New("cannot find a template with the provided id i, the current project")
+	ErrPolicyAlreadyPresent = errors.New("a policy with the same name is already present in the current project")
+
+	ErrEnvironmentNotFound       = errors.New("cannot find an environment with the provided id in the current project")
+	ErrEnvironmentAlreadyPresent = errors.New("an environment with the same name is already present in the current project")
+	ErrInvalidVariableName       = errors.New("the variable name cannot be empty")
+	ErrInvalidVariableSetName    = errors.New("the variable set name cannot be empty")
+	ErrVariableAlreadyPresent    = errors.New("a variable with the same name is already present in the variable set")
+	ErrVariableNotFound          = errors.New("cannot find a variable with the provided name in the variable set")
+	ErrRunNotAllowed             = errors.New("the user is not allowed to run this workflow against this environment")
+	ErrPolicyAlreadyAttached     = errors.New("the policy is already attached to the environment")
+
+	ErrRunNotDeployable             = errors.New("only successful runs of the workflow can be recorded as deployments")
+	ErrEnvironmentAlreadyInPipeline = errors.New("an environment can only appear once in the promotion pipeline")
+	ErrNoNextEnvironment            = errors.New("the environment is not followed by another environment in the promotion pipeline")
+	ErrNothingToPromote             = errors.New("no version of the workflow is deployed in the environment")
+	ErrPromotionBlocked             = errors.New("the last run of the workflow in the environment failed")
+	ErrPromotedVersionMismatch      = errors.New("the workflow or its templates are not in the version deployed in the environment")
 )
@@ -9,14 +9,16 @@ import (
 	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
 )
 
-// Project represents an AutOps project, grouping templates, workflows, and policies
+// Project represents an AutOps project, grouping templates, workflows, policies and environments
 // under a single logical unit.
 type Project struct {
 	common.NamedEntity
 	common.TaggedEntity
-	templates *common.List[*template.Template]
-	workflows *common.List[*workflow.Workflow]
-	policies  *common.List[*policy.Policy]
+	templates    *common.List[*template.Template]
+	workflows    *common.List[*workflow.Workflow]
+	policies     *common.List[*policy.Policy]
+	environments *common.List[*Environment]
+	pipeline     []*common.Identifier
 }
 
 // NewProject creates a new Project with a generated identifier and current timestamps.
@@ -35,11 +37,12 @@ func NewProject(name string, description string) (*Project, error) {
 		[]*template.Template{},
 		[]*workflow.Workflow{},
 		[]*policy.Policy{},
+		[]*Environment{},
 	)
 }
 
-// ExistingProject rebuilds a Project from persisted data, including associated templates, workflows, and policies.
-func ExistingProject(id string, name string, description string, createdAt string, updatedAt string, templates []*template.Template, workflows []*workflow.Workflow, policies []*policy.Policy) (*Project, error) {
+// ExistingProject rebuilds a Project from persisted data, including associated templates, workflows, policies and environments.
+func ExistingProject(id string, name string, description string, createdAt string, updatedAt string, templates []*template.Template, workflows []*workflow.Workflow, policies []*policy.Policy, environments []*Environment) (*Project, error) {
 	namedEntity, err := common.ExistingNamedEntity(id, name, description, createdAt, updatedAt)
 	if err != nil {
 		return nil, err
@@ -50,6 +53,8 @@ func ExistingProject(id string, name string, description string, createdAt strin
 		templates:    common.NewList(template.TemplateComparator{}, templates),
 		workflows:    common.NewList(workflow.WorkflowComparator{}, workflows),
 		policies:     common.NewList(policy.PolicyComparator{}, policies),
+		environments: common.NewList(EnvironmentComparator{}, environments),
+		pipeline:     []*common.Identifier{},
 	}, nil
 }
 
@@ -139,3 +144,47 @@ func (p *Project) RemovePolicy(id *common.Identifier) error {
 	p.policies.Remove(policy)
 	return nil
 }
+
+// ListEnvironments returns all environments of the project.
+func (p *Project) ListEnvironments() []*Environment {
+	return p.environments.Items()
+}
+
+// GetEnvironment returns the environment with the given identifier, or nil if not found.
+func (p *Project) GetEnvironment(id *common.Identifier) *Environment {
+	environment, _ := p.environments.SelectOne((func(e *Environment) bool {
+		return strings.Compare(id.ToString(), e.GetIdentifier().ToString()) == 0
+	}))
+	return environment
+}
+
+// AddEnvironment adds an environment to the project.
+// Returns an error if an environment with the same name is already present.
+func (p *Project) AddEnvironment(environment *Environment) error {
+	_, found := p.environments.SelectOne(func(e *Environment) bool {
+		return e.GetName() == environment.GetName()
+	})
+	if found {
+		return ErrEnvironmentAlreadyPresent
+	}
+	p.environments.Append(environment)
+	return nil
+}
+
+// RemoveEnvironment removes an environment from the project, and from the promotion pipeline, by its identifier.
+// Returns an error if the environment is not found.
+func (p *Project) RemoveEnvironment(id *common.Identifier) error {
+	environment := p.GetEnvironment(id)
+	if environment == nil {
+		return ErrEnvironmentNotFound
+	}
+	p.environments.Remove(environment)
+	pipeline := []*common.Identifier{}
+	for _, stage := range p.pipeline {
+		if stage.ToString() != id.ToString() {
+			pipeline = append(pipeline, stage)
+		}
+	}
+	p.pipeline = pipeline
+	return nil
+}
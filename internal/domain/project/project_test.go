@@ -76,3 +76,27 @@ func TestAddAndRemovePolicy(t *testing.T) {
 		t.Error("expected policy to be removed")
 	}
 }
+
+func TestAddAndRemoveEnvironment(t *testing.T) {
+	p, _ := NewProject("Project", "Desc")
+
+	env, _ := NewEnvironment(p.GetIdentifier().ToString(), "prod", "Production", true)
+	if err := p.AddEnvironment(env); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if p.GetEnvironment(env.GetIdentifier()) == nil {
+		t.Error("expected to find added environment")
+	}
+
+	duplicate, _ := NewEnvironment(p.GetIdentifier().ToString(), "prod", "", false)
+	if err := p.AddEnvironment(duplicate); err != ErrEnvironmentAlreadyPresent {
+		t.Errorf("expected err to be ErrEnvironmentAlreadyPresent, got %v", err)
+	}
+
+	if err := p.RemoveEnvironment(env.GetIdentifier()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := p.RemoveEnvironment(env.GetIdentifier()); err != ErrEnvironmentNotFound {
+		t.Errorf("expected err to be ErrEnvironmentNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// ListPipeline returns the identifiers of the environments of the promotion pipeline, from the first stage to the last.
+func (p *Project) ListPipeline() []*common.Identifier {
+	return append([]*common.Identifier(nil), p.pipeline...)
+}
+
+// SetPipeline replaces the promotion pipeline with the environments with the given identifiers, from the first stage to the last.
+// Returns an error if an environment is not found or appears twice.
+func (p *Project) SetPipeline(environmentIdentifiers []*common.Identifier) error {
+	seen := map[string]bool{}
+	for _, id := range environmentIdentifiers {
+		if p.GetEnvironment(id) == nil {
+			return ErrEnvironmentNotFound
+		}
+		if seen[id.ToString()] {
+			return ErrEnvironmentAlreadyInPipeline
+		}
+		seen[id.ToString()] = true
+	}
+	p.pipeline = append([]*common.Identifier(nil), environmentIdentifiers...)
+	return nil
+}
+
+// GetNextEnvironment returns the environment following the given one in the promotion pipeline,
+// or nil if the environment is the last stage or is not part of the pipeline.
+func (p *Project) GetNextEnvironment(environmentIdentifier *common.Identifier) *Environment {
+	for i, id := range p.pipeline {
+		if id.ToString() == environmentIdentifier.ToString() && i+1 < len(p.pipeline) {
+			return p.GetEnvironment(p.pipeline[i+1])
+		}
+	}
+	return nil
+}
+
+// RecordDeployment records the workflow version deployed by a successful run in the environment targeted by the run.
+// Returns an error if the run does not target an environment of the project, or if it is not a successful run of the workflow.
+func (p *Project) RecordDeployment(wf *workflow.Workflow, run *workflow.WorkflowRun) (*Deployment, error) {
+	if run.GetEnvironment() == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+	environment := p.GetEnvironment(run.GetEnvironment())
+	if environment == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+	deployment, err := NewDeployment(wf, run)
+	if err != nil {
+		return nil, err
+	}
+	environment.SetDeployment(deployment)
+	return deployment, nil
+}
+
+// Promote starts a run of the workflow version deployed in the given environment against the next environment of the pipeline,
+// on behalf of the user. The inputs of the deploying run are reused, except those provided by the variables of either environment,
+// so that the run uses the variables of the next environment.
+//
+// Returns ErrNoNextEnvironment if the environment is the last stage of the pipeline, ErrNothingToPromote if no version of the workflow
+// is deployed in the environment, ErrPromotionBlocked if the last run of the workflow in the environment failed,
+// ErrPromotedVersionMismatch if the workflow is not in the deployed version, or any error of Environment.StartRun.
+func (p *Project) Promote(user *identity.User, wf *workflow.Workflow, environmentIdentifier *common.Identifier, name string, description string) (*workflow.WorkflowRun, error) {
+	from := p.GetEnvironment(environmentIdentifier)
+	if from == nil {
+		return nil, ErrEnvironmentNotFound
+	}
+	next := p.GetNextEnvironment(environmentIdentifier)
+	if next == nil {
+		return nil, ErrNoNextEnvironment
+	}
+	deployment := from.GetDeployment(wf.GetIdentifier())
+	if deployment == nil {
+		return nil, ErrNothingToPromote
+	}
+	if last := getLastRun(wf, environmentIdentifier); last != nil && last.GetStatus() == common.FAILURE {
+		return nil, ErrPromotionBlocked
+	}
+	if !deployment.Matches(wf) {
+		return nil, ErrPromotedVersionMismatch
+	}
+
+	values := map[string]string{}
+	if deployed := wf.GetRun(deployment.GetRunIdentifier().ToString()); deployed != nil {
+		for input, value := range deployed.GetInputs() {
+			if from.GetVariableSet().GetVariable(input) == nil && next.GetVariableSet().GetVariable(input) == nil {
+				values[input] = value
+			}
+		}
+	}
+	if description == "" {
+		description = fmt.Sprintf("Promotion of version %d from %s", deployment.GetWorkflowVersion(), from.GetName())
+	}
+	run, err := next.StartRun(user, wf, name, description, workflow.STANDARD, values)
+	if err != nil {
+		return nil, err
+	}
+	run.SetPromotedFrom(deployment.GetRunIdentifier())
+	return run, nil
+}
+
+// getLastRun returns the latest run of the workflow executed against the environment, or nil if there is none.
+// Plan-only runs are ignored since they do not change the environment, and a failed run wins over runs updated at the same time.
+func getLastRun(wf *workflow.Workflow, environmentIdentifier *common.Identifier) *workflow.WorkflowRun {
+	var last *workflow.WorkflowRun
+	for _, run := range wf.ListRuns() {
+		if run.GetEnvironment() == nil || run.GetEnvironment().ToString() != environmentIdentifier.ToString() || run.GetMode() == workflow.PLAN_ONLY {
+			continue
+		}
+		if last == nil || run.GetUpdatedAt() > last.GetUpdatedAt() || (run.GetUpdatedAt() == last.GetUpdatedAt() && run.GetStatus() == common.FAILURE) {
+			last = run
+		}
+	}
+	return last
+}
@@ -0,0 +1,97 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+func newTestPipeline(t *testing.T) (*Project, *Environment, *Environment) {
+	t.Helper()
+	p, _ := NewProject("project", "")
+	dev, _ := NewEnvironment(p.GetIdentifier().ToString(), "dev", "", false)
+	staging, _ := NewEnvironment(p.GetIdentifier().ToString(), "staging", "", false)
+	for _, env := range []*Environment{dev, staging} {
+		region, _ := NewVariable("region", workflow.STRING, env.GetName()+"-region")
+		env.GetVariableSet().SetVariable(region)
+		p.AddEnvironment(env)
+	}
+	if err := p.SetPipeline([]*common.Identifier{dev.GetIdentifier(), staging.GetIdentifier()}); err != nil {
+		t.Fatalf("failed to set the pipeline: %v", err)
+	}
+	return p, dev, staging
+}
+
+func TestProjectSetPipeline(t *testing.T) {
+	p, dev, staging := newTestPipeline(t)
+	if err := p.SetPipeline([]*common.Identifier{dev.GetIdentifier(), dev.GetIdentifier()}); err != ErrEnvironmentAlreadyInPipeline {
+		t.Errorf("expected err to be ErrEnvironmentAlreadyInPipeline, got %v", err)
+	}
+	unknown, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ:environment:1234567890")
+	if err := p.SetPipeline([]*common.Identifier{unknown}); err != ErrEnvironmentNotFound {
+		t.Errorf("expected err to be ErrEnvironmentNotFound, got %v", err)
+	}
+	if next := p.GetNextEnvironment(dev.GetIdentifier()); next != staging {
+		t.Errorf("expected staging to follow dev, got %v", next)
+	}
+	if p.GetNextEnvironment(staging.GetIdentifier()) != nil {
+		t.Error("expected the last stage to have no next environment")
+	}
+	p.RemoveEnvironment(staging.GetIdentifier())
+	if len(p.ListPipeline()) != 1 {
+		t.Errorf("expected removed environments to leave the pipeline, got %v", p.ListPipeline())
+	}
+}
+
+func TestProjectPromote(t *testing.T) {
+	p, dev, staging := newTestPipeline(t)
+	wf := newTestWorkflow(t, p.GetIdentifier().ToString())
+	replicas, _ := workflow.NewWorkflowAttribute(wf.GetIdentifier().ToString(), "replicas", "", workflow.NUMBER, "")
+	wf.AddInput(replicas)
+	user := newTestUser(t, "developer", policy.ALLOW, wf.GetIdentifier())
+
+	if _, err := p.Promote(user, wf, dev.GetIdentifier(), "promote", ""); err != ErrNothingToPromote {
+		t.Errorf("expected err to be ErrNothingToPromote, got %v", err)
+	}
+
+	deploying, _ := dev.StartRun(user, wf, "deploy", "", workflow.STANDARD, map[string]string{"replicas": "3"})
+	if _, err := p.RecordDeployment(wf, deploying); err != ErrRunNotDeployable {
+		t.Errorf("expected err to be ErrRunNotDeployable, got %v", err)
+	}
+	deploying.SetStatus(common.SUCCESS)
+	deployment, err := p.RecordDeployment(wf, deploying)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if dev.GetDeployment(wf.GetIdentifier()) != deployment || deployment.GetWorkflowVersion() != wf.GetVersion() {
+		t.Errorf("unexpected deployment %+v", deployment)
+	}
+
+	if _, err := p.Promote(user, wf, staging.GetIdentifier(), "promote", ""); err != ErrNoNextEnvironment {
+		t.Errorf("expected err to be ErrNoNextEnvironment, got %v", err)
+	}
+	run, err := p.Promote(user, wf, dev.GetIdentifier(), "promote", "")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	inputs := run.GetInputs()
+	if inputs["region"] != "staging-region" || inputs["replicas"] != "3" {
+		t.Errorf("expected the next environment variables with the deployed inputs, got %v", inputs)
+	}
+	if run.GetEnvironment().ToString() != staging.GetIdentifier().ToString() || run.GetPromotedFrom().ToString() != deploying.GetIdentifier().ToString() {
+		t.Error("expected the run to promote the deployment to staging")
+	}
+
+	newer, _ := workflow.ExistingWorkflow(wf.GetIdentifier().ToString(), wf.GetName(), "", common.PENDING, wf.GetSourcePath(), 2, wf.ListInputs(), nil, nil, nil, nil)
+	if _, err := p.Promote(user, newer, dev.GetIdentifier(), "promote", ""); err != ErrPromotedVersionMismatch {
+		t.Errorf("expected err to be ErrPromotedVersionMismatch, got %v", err)
+	}
+
+	failed, _ := dev.StartRun(user, wf, "deploy", "", workflow.STANDARD, map[string]string{"replicas": "4"})
+	failed.SetStatus(common.FAILURE)
+	if _, err := p.Promote(user, wf, dev.GetIdentifier(), "promote", ""); err != ErrPromotionBlocked {
+		t.Errorf("expected err to be ErrPromotionBlocked, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package project
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// Variable is a typed value of a VariableSet, or a reference to a secret of the project.
+type Variable struct {
+	name          string
+	attributeType workflow.WorkflowAttributeType
+	value         string
+}
+
+// NewVariable creates a Variable.
+// Returns an error if the name is empty, or if the value is neither a secret reference nor valid for the type.
+func NewVariable(name string, attributeType workflow.WorkflowAttributeType, value string) (*Variable, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidVariableName
+	}
+	value = strings.TrimSpace(value)
+	if !secret.IsReference(value) {
+		if err := attributeType.Validate(value); err != nil {
+			return nil, err
+		}
+	}
+	return &Variable{
+		name:          name,
+		attributeType: attributeType,
+		value:         value,
+	}, nil
+}
+
+// GetName returns the name of the variable, matched against the names of the workflow inputs.
+func (v *Variable) GetName() string {
+	return v.name
+}
+
+// GetType returns the type of the variable.
+func (v *Variable) GetType() workflow.WorkflowAttributeType {
+	return v.attributeType
+}
+
+// GetValue returns the value of the variable.
+func (v *Variable) GetValue() string {
+	return v.value
+}
+
+// IsSensitive returns true if the value of the variable is a secret reference.
+func (v *Variable) IsSensitive() bool {
+	return secret.IsReference(v.value)
+}
+
+// VariableSet is a named set of variables merged into the inputs of the runs targeting an environment.
+type VariableSet struct {
+	name      string
+	variables map[string]*Variable
+}
+
+// NewVariableSet creates a VariableSet.
+// Returns an error if the name is empty or if two variables have the same name.
+func NewVariableSet(name string, variables []*Variable) (*VariableSet, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidVariableSetName
+	}
+	set := &VariableSet{
+		name:      name,
+		variables: map[string]*Variable{},
+	}
+	for _, variable := range variables {
+		if _, found := set.variables[variable.name]; found {
+			return nil, ErrVariableAlreadyPresent
+		}
+		set.variables[variable.name] = variable
+	}
+	return set, nil
+}
+
+// GetName returns the name of the variable set.
+func (s *VariableSet) GetName() string {
+	return s.name
+}
+
+// ListVariables returns the variables of the set, sorted by name.
+func (s *VariableSet) ListVariables() []*Variable {
+	variables := make([]*Variable, 0, len(s.variables))
+	for _, variable := range s.variables {
+		variables = append(variables, variable)
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].name < variables[j].name
+	})
+	return variables
+}
+
+// GetVariable returns the variable with the given name, or nil if not found.
+func (s *VariableSet) GetVariable(name string) *Variable {
+	return s.variables[name]
+}
+
+// SetVariable adds the variable to the set, replacing any variable with the same name.
+func (s *VariableSet) SetVariable(variable *Variable) {
+	s.variables[variable.name] = variable
+}
+
+// RemoveVariable removes the variable with the given name.
+// Returns an error if the variable is not found.
+func (s *VariableSet) RemoveVariable(name string) error {
+	if _, found := s.variables[name]; !found {
+		return ErrVariableNotFound
+	}
+	delete(s.variables, name)
+	return nil
+}
+
+// MergeInputs returns the values of the variables matching the declared workflow inputs, overridden by the provided values.
+// Variables that do not match any input are ignored, since they may target other workflows of the project.
+func (s *VariableSet) MergeInputs(inputs []*workflow.WorkflowAttribute, values map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, input := range inputs {
+		if variable, found := s.variables[input.GetName()]; found {
+			merged[input.GetName()] = variable.value
+		}
+	}
+	for name, value := range values {
+		merged[name] = value
+	}
+	return merged
+}
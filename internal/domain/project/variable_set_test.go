@@ -0,0 +1,70 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+func TestNewVariable(t *testing.T) {
+	_, err := NewVariable(" ", workflow.STRING, "value")
+	if err != ErrInvalidVariableName {
+		t.Errorf("expected err to be ErrInvalidVariableName, got %v", err)
+	}
+	if _, err := NewVariable("replicas", workflow.NUMBER, "three"); err == nil {
+		t.Error("expected an error for a value not matching the variable type")
+	}
+	variable, err := NewVariable("token", workflow.NUMBER, "${autops::project:ABCDEFGHIJ:secret:1234567890}")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if !variable.IsSensitive() {
+		t.Error("expected a secret reference to be sensitive")
+	}
+}
+
+func TestVariableSet(t *testing.T) {
+	region, _ := NewVariable("region", workflow.STRING, "eu-west-1")
+	duplicate, _ := NewVariable("region", workflow.STRING, "us-east-1")
+	if _, err := NewVariableSet("prod", []*Variable{region, duplicate}); err != ErrVariableAlreadyPresent {
+		t.Errorf("expected err to be ErrVariableAlreadyPresent, got %v", err)
+	}
+	if _, err := NewVariableSet("", nil); err != ErrInvalidVariableSetName {
+		t.Errorf("expected err to be ErrInvalidVariableSetName, got %v", err)
+	}
+
+	replicas, _ := NewVariable("replicas", workflow.NUMBER, "3")
+	set, err := NewVariableSet("prod", []*Variable{region, replicas})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if variables := set.ListVariables(); len(variables) != 2 || variables[0].GetName() != "region" {
+		t.Errorf("expected variables sorted by name, got %v", variables)
+	}
+	set.SetVariable(duplicate)
+	if set.GetVariable("region").GetValue() != "us-east-1" {
+		t.Error("expected SetVariable to replace the variable with the same name")
+	}
+	if err := set.RemoveVariable("replicas"); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := set.RemoveVariable("replicas"); err != ErrVariableNotFound {
+		t.Errorf("expected err to be ErrVariableNotFound, got %v", err)
+	}
+}
+
+func TestVariableSetMergeInputs(t *testing.T) {
+	region, _ := NewVariable("region", workflow.STRING, "eu-west-1")
+	replicas, _ := NewVariable("replicas", workflow.NUMBER, "3")
+	unused, _ := NewVariable("unused", workflow.STRING, "value")
+	set, _ := NewVariableSet("prod", []*Variable{region, replicas, unused})
+
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	regionInput, _ := workflow.NewWorkflowAttribute(workflowId, "region", "", workflow.STRING, "")
+	replicasInput, _ := workflow.NewWorkflowAttribute(workflowId, "replicas", "", workflow.NUMBER, "")
+
+	merged := set.MergeInputs([]*workflow.WorkflowAttribute{regionInput, replicasInput}, map[string]string{"replicas": "5"})
+	if len(merged) != 2 || merged["region"] != "eu-west-1" || merged["replicas"] != "5" {
+		t.Errorf("unexpected merged inputs %v", merged)
+	}
+}
@@ -0,0 +1,14 @@
+package secret
+
+import "errors"
+
+var (
+	ErrInvalidMasterKey        = errors.New("the master key must be a base64 encoded 32 bytes key")
+	ErrDecryptionFailed        = errors.New("cannot decrypt the secret value with the master key")
+	ErrEmptySecretValue        = errors.New("the secret value cannot be empty")
+	ErrInvalidInjectionType    = errors.New("unsupported secret injection type")
+	ErrInvalidInjectionTarget  = errors.New("invalid injection target: expected an environment variable name or a relative file path")
+	ErrInvalidSecretReference  = errors.New("invalid secret reference: expected format like ${<secret id>}")
+	ErrSecretNotFound          = errors.New("cannot find a secret with the specified identifier")
+	ErrInjectionTargetConflict = errors.New("two secrets are injected into the same environment variable or file")
+)
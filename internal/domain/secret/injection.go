@@ -0,0 +1,65 @@
+package secret
+
+// Injection groups the decrypted secrets provided to an executor, as environment variables and files.
+// It also holds the Masker hiding their values in the execution logs.
+type Injection struct {
+	env    map[string]string
+	files  map[string][]byte
+	masker *Masker
+}
+
+// NewInjection creates an empty Injection.
+func NewInjection() *Injection {
+	return &Injection{
+		env:    map[string]string{},
+		files:  map[string][]byte{},
+		masker: NewMasker(),
+	}
+}
+
+// Add decrypts the secret with the master key and adds it to the injection according to its injection type.
+// Returns an error if the secret cannot be decrypted, or if another secret already targets the same variable or file.
+func (i *Injection) Add(secret *Secret, key *MasterKey) error {
+	plaintext, err := secret.Reveal(key)
+	if err != nil {
+		return err
+	}
+	target := secret.GetInjectionTarget()
+	switch secret.GetInjectionType() {
+	case FILE:
+		if _, found := i.files[target]; found {
+			return ErrInjectionTargetConflict
+		}
+		i.files[target] = plaintext
+	default:
+		if _, found := i.env[target]; found {
+			return ErrInjectionTargetConflict
+		}
+		i.env[target] = string(plaintext)
+	}
+	i.masker.Add(string(plaintext))
+	return nil
+}
+
+// GetEnv returns a copy of the environment variables to set in the executor, indexed by name.
+func (i *Injection) GetEnv() map[string]string {
+	env := make(map[string]string, len(i.env))
+	for name, value := range i.env {
+		env[name] = value
+	}
+	return env
+}
+
+// ListFiles returns a copy of the files to write in the working directory of the executor, indexed by relative path.
+func (i *Injection) ListFiles() map[string][]byte {
+	files := make(map[string][]byte, len(i.files))
+	for path, content := range i.files {
+		files[path] = append([]byte(nil), content...)
+	}
+	return files
+}
+
+// GetMasker returns the Masker hiding the injected values.
+func (i *Injection) GetMasker() *Masker {
+	return i.masker
+}
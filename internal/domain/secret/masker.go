@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// MASKED_VALUE replaces secret values in logs and API responses.
+const MASKED_VALUE = "****"
+
+// Masker replaces known secret values with MASKED_VALUE.
+// Multi-line values are also masked line by line, so that they are masked in line-oriented logs.
+type Masker struct {
+	values []string
+}
+
+// NewMasker creates a Masker hiding the given secret values.
+func NewMasker(values ...string) *Masker {
+	masker := &Masker{values: []string{}}
+	for _, value := range values {
+		masker.Add(value)
+	}
+	return masker
+}
+
+// Add registers a secret value to mask.
+func (m *Masker) Add(value string) {
+	candidates := append([]string{value}, strings.Split(value, "\n")...)
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate != "" {
+			m.values = append(m.values, candidate)
+		}
+	}
+	// Longer values first, so that a value containing another one is fully masked.
+	sort.SliceStable(m.values, func(i, j int) bool {
+		return len(m.values[i]) > len(m.values[j])
+	})
+}
+
+// Mask returns the text with every secret value replaced with MASKED_VALUE.
+func (m *Masker) Mask(text string) string {
+	for _, value := range m.values {
+		text = strings.ReplaceAll(text, value, MASKED_VALUE)
+	}
+	return text
+}
+
+// MaskValues returns a copy of the values with every secret value replaced with MASKED_VALUE.
+func (m *Masker) MaskValues(values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for name, value := range values {
+		masked[name] = m.Mask(value)
+	}
+	return masked
+}
+
+// MaskingWriter masks secret values in the output of an executor before it is written to its ExecutionLog.
+// Output is buffered until the end of each line so that values split across writes are masked;
+// Close must be called to flush the last line.
+type MaskingWriter struct {
+	masker *Masker
+	out    io.Writer
+	buffer bytes.Buffer
+}
+
+// NewMaskingWriter creates a MaskingWriter writing the masked output to out.
+func NewMaskingWriter(out io.Writer, masker *Masker) *MaskingWriter {
+	return &MaskingWriter{
+		masker: masker,
+		out:    out,
+	}
+}
+
+// Write buffers the data and writes every complete line, masked.
+func (w *MaskingWriter) Write(data []byte) (int, error) {
+	w.buffer.Write(data)
+	for {
+		index := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if index == -1 {
+			return len(data), nil
+		}
+		line := string(w.buffer.Next(index + 1))
+		if _, err := io.WriteString(w.out, w.masker.Mask(line)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close writes the remaining buffered data, masked.
+func (w *MaskingWriter) Close() error {
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w.out, w.masker.Mask(w.buffer.String()))
+	w.buffer.Reset()
+	return err
+}
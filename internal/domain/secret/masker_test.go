@@ -0,0 +1,36 @@
+package secret
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMasker(t *testing.T) {
+	masker := NewMasker("abc", "abcdef", "line1\nline2", "")
+
+	if masked := masker.Mask("abcdef then abc"); masked != "**** then ****" {
+		t.Errorf("expected longer values to be masked first, got %s", masked)
+	}
+	if masked := masker.Mask("> line2"); masked != "> ****" {
+		t.Errorf("expected each line of multi-line values to be masked, got %s", masked)
+	}
+	masked := masker.MaskValues(map[string]string{"a": "abc", "b": "public"})
+	if masked["a"] != MASKED_VALUE || masked["b"] != "public" {
+		t.Errorf("unexpected masked values %v", masked)
+	}
+}
+
+func TestMaskingWriter(t *testing.T) {
+	var out strings.Builder
+	writer := NewMaskingWriter(&out, NewMasker("hunter2"))
+
+	writer.Write([]byte("password: hun"))
+	if out.String() != "" {
+		t.Errorf("expected incomplete lines to be buffered, got %q", out.String())
+	}
+	writer.Write([]byte("ter2\nnext: hunter2"))
+	writer.Close()
+	if out.String() != "password: ****\nnext: ****" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -0,0 +1,118 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// MASTER_KEY_LENGTH defines the number of bytes of the master key and of the data keys (AES-256).
+const MASTER_KEY_LENGTH = 32
+
+// MasterKey is the key encrypting the data keys of every secret (envelope encryption).
+// Each secret value is encrypted with its own random data key, and only the encrypted data key is stored with it.
+type MasterKey struct {
+	key []byte
+}
+
+// NewMasterKey creates a MasterKey from its base64 encoded representation, typically read from the configuration.
+// Returns ErrInvalidMasterKey if the value is not a base64 encoded 32 bytes key.
+func NewMasterKey(encoded string) (*MasterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != MASTER_KEY_LENGTH {
+		return nil, ErrInvalidMasterKey
+	}
+	return &MasterKey{key: key}, nil
+}
+
+// EncryptedValue is a secret value encrypted with a data key, itself encrypted with the MasterKey.
+// Nonces are prepended to their ciphertext.
+type EncryptedValue struct {
+	encryptedDataKey []byte
+	ciphertext       []byte
+}
+
+// ExistingEncryptedValue creates an EncryptedValue from persisted data.
+func ExistingEncryptedValue(encryptedDataKey []byte, ciphertext []byte) *EncryptedValue {
+	return &EncryptedValue{
+		encryptedDataKey: append([]byte(nil), encryptedDataKey...),
+		ciphertext:       append([]byte(nil), ciphertext...),
+	}
+}
+
+// GetEncryptedDataKey returns a copy of the data key encrypted with the master key.
+func (v *EncryptedValue) GetEncryptedDataKey() []byte {
+	return append([]byte(nil), v.encryptedDataKey...)
+}
+
+// GetCiphertext returns a copy of the value encrypted with the data key.
+func (v *EncryptedValue) GetCiphertext() []byte {
+	return append([]byte(nil), v.ciphertext...)
+}
+
+// seal encrypts the plaintext with AES-GCM, prepending the random nonce to the ciphertext.
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext produced by seal.
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil || len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// Encrypt encrypts the plaintext with a newly generated data key, and encrypts the data key with the master key.
+func (k *MasterKey) Encrypt(plaintext []byte) (*EncryptedValue, error) {
+	dataKey := make([]byte, MASTER_KEY_LENGTH)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(dataKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	encryptedDataKey, err := seal(k.key, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedValue{
+		encryptedDataKey: encryptedDataKey,
+		ciphertext:       ciphertext,
+	}, nil
+}
+
+// Decrypt decrypts the data key with the master key, then the value with the data key.
+// Returns ErrDecryptionFailed if the value was encrypted with another master key or has been tampered with.
+func (k *MasterKey) Decrypt(value *EncryptedValue) ([]byte, error) {
+	dataKey, err := open(k.key, value.encryptedDataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, value.ciphertext)
+}
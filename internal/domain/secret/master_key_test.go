@@ -0,0 +1,54 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func newTestMasterKey(t *testing.T, fill byte) *MasterKey {
+	key, err := NewMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{fill}, MASTER_KEY_LENGTH)))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	return key
+}
+
+func TestNewMasterKey(t *testing.T) {
+	invalid := []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("too short"))}
+	for _, encoded := range invalid {
+		if _, err := NewMasterKey(encoded); err != ErrInvalidMasterKey {
+			t.Errorf("expected err to be ErrInvalidMasterKey for %q, got %v", encoded, err)
+		}
+	}
+}
+
+func TestMasterKeyEncrypt(t *testing.T) {
+	key := newTestMasterKey(t, 1)
+	plaintext := []byte("hunter2")
+
+	value, err := key.Encrypt(plaintext)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if bytes.Contains(value.GetCiphertext(), plaintext) || bytes.Contains(value.GetEncryptedDataKey(), plaintext) {
+		t.Error("expected the plaintext not to be stored")
+	}
+	other, _ := key.Encrypt(plaintext)
+	if bytes.Equal(value.GetCiphertext(), other.GetCiphertext()) {
+		t.Error("expected each encryption to use its own data key and nonce")
+	}
+
+	decrypted, err := key.Decrypt(ExistingEncryptedValue(value.GetEncryptedDataKey(), value.GetCiphertext()))
+	if err != nil || !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("expected %s, got %s (%v)", plaintext, decrypted, err)
+	}
+	if _, err := newTestMasterKey(t, 2).Decrypt(value); err != ErrDecryptionFailed {
+		t.Errorf("expected err to be ErrDecryptionFailed, got %v", err)
+	}
+	tampered := value.GetCiphertext()
+	tampered[len(tampered)-1] ^= 1
+	if _, err := key.Decrypt(ExistingEncryptedValue(value.GetEncryptedDataKey(), tampered)); err != ErrDecryptionFailed {
+		t.Errorf("expected err to be ErrDecryptionFailed, got %v", err)
+	}
+}
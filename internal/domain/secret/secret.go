@@ -0,0 +1,196 @@
+package secret
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// InjectionType defines how a secret value is provided to the executors running a step.
+type InjectionType int
+
+const (
+	// ENV_VAR injects the value as an environment variable.
+	ENV_VAR InjectionType = iota
+	// FILE injects the value as a file in the working directory of the step.
+	FILE
+)
+
+// ToString returns the string representation of the InjectionType.
+func (t InjectionType) ToString() string {
+	switch t {
+	case FILE:
+		return "file"
+	default:
+		return "env"
+	}
+}
+
+// ParseInjectionType converts a string to an InjectionType.
+// Returns an error if the string does not match a known injection type.
+func ParseInjectionType(str string) (InjectionType, error) {
+	switch strings.ToLower(str) {
+	case "env":
+		return ENV_VAR, nil
+	case "file":
+		return FILE, nil
+	default:
+		return -1, ErrInvalidInjectionType
+	}
+}
+
+var envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateInjectionTarget checks that the target is a valid environment variable name,
+// or a relative file path that does not escape the working directory.
+func validateInjectionTarget(injectionType InjectionType, target string) error {
+	switch injectionType {
+	case ENV_VAR:
+		if !envVarNameRegex.MatchString(target) {
+			return ErrInvalidInjectionTarget
+		}
+	case FILE:
+		cleaned := path.Clean(target)
+		if target == "" || path.IsAbs(target) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") || !common.IsSyntacticallySafePath(target) {
+			return ErrInvalidInjectionTarget
+		}
+	default:
+		return ErrInvalidInjectionType
+	}
+	return nil
+}
+
+// Secret represents a sensitive value scoped to a project, such as a cloud credential or a password.
+// The value is only stored encrypted, and is injected into the executors as an environment variable or a file.
+type Secret struct {
+	common.NamedEntity
+	injectionType   InjectionType
+	injectionTarget string
+	value           *EncryptedValue
+}
+
+// SecretComparator is used to compare two Secret instances based on their identifier.
+type SecretComparator struct{}
+
+// Compare returns a comparison between two Secret identifiers.
+func (SecretComparator) Compare(a *Secret, b *Secret) int {
+	return strings.Compare(a.GetIdentifier().ToString(), b.GetIdentifier().ToString())
+}
+
+// NewSecret creates a new Secret with a generated identifier under the given project,
+// encrypting the plaintext value with the master key.
+//
+// Returns an error if the name, description, injection or value is invalid.
+func NewSecret(projectId string, name string, description string, injectionType InjectionType, injectionTarget string, plaintext []byte, key *MasterKey) (*Secret, error) {
+	identifier, err := common.BuildSecretIdentifier(projectId)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := ExistingSecret(identifier.ToString(), name, description, injectionType, injectionTarget, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = secret.SetValue(plaintext, key)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// ExistingSecret creates a Secret with the provided identifier and encrypted value.
+// This is typically used when reloading from a data store.
+//
+// Returns an error if the name, description or injection is invalid.
+func ExistingSecret(identifier string, name string, description string, injectionType InjectionType, injectionTarget string, value *EncryptedValue) (*Secret, error) {
+	namedEntity, err := common.NewNamedEntity(identifier, name, description)
+	if err != nil {
+		return nil, err
+	}
+	secret := &Secret{
+		NamedEntity: *namedEntity,
+		value:       value,
+	}
+	err = secret.SetInjection(injectionType, injectionTarget)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// GetInjectionType returns how the secret is injected into the executors.
+func (s *Secret) GetInjectionType() InjectionType {
+	return s.injectionType
+}
+
+// GetInjectionTarget returns the environment variable name or the relative file path the secret is injected into.
+func (s *Secret) GetInjectionTarget() string {
+	return s.injectionTarget
+}
+
+// SetInjection sets how the secret is injected into the executors.
+// Returns an error if the target is not a valid environment variable name or relative file path.
+func (s *Secret) SetInjection(injectionType InjectionType, target string) error {
+	target = strings.TrimSpace(target)
+	if err := validateInjectionTarget(injectionType, target); err != nil {
+		return err
+	}
+	s.injectionType = injectionType
+	s.injectionTarget = target
+	return nil
+}
+
+// GetEncryptedValue returns the encrypted value of the secret.
+func (s *Secret) GetEncryptedValue() *EncryptedValue {
+	return s.value
+}
+
+// SetValue encrypts the plaintext value with the master key and replaces the value of the secret.
+// Returns ErrEmptySecretValue if the value is empty.
+func (s *Secret) SetValue(plaintext []byte, key *MasterKey) error {
+	if len(plaintext) == 0 {
+		return ErrEmptySecretValue
+	}
+	value, err := key.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+	s.value = value
+	s.UpdateModificationDate()
+	return nil
+}
+
+// Reveal decrypts the value of the secret with the master key.
+// It must only be used to inject the secret into an executor.
+func (s *Secret) Reveal(key *MasterKey) ([]byte, error) {
+	if s.value == nil {
+		return nil, ErrDecryptionFailed
+	}
+	return key.Decrypt(s.value)
+}
+
+// FormatReference returns the reference to the secret with the given identifier, used as a sensitive input value.
+func FormatReference(secretIdentifier *common.Identifier) string {
+	return "${" + secretIdentifier.ToString() + "}"
+}
+
+// IsReference returns true if the value is a reference to a secret, like ${autops::project:ABCDEFGHIJ:secret:1234567890}.
+func IsReference(value string) bool {
+	_, err := ParseReference(value)
+	return err == nil
+}
+
+// ParseReference returns the identifier of the secret referenced by the value.
+// Returns ErrInvalidSecretReference if the value is not a secret reference.
+func ParseReference(value string) (*common.Identifier, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") {
+		return nil, ErrInvalidSecretReference
+	}
+	identifier, err := common.NewIdentifier(strings.TrimSuffix(strings.TrimPrefix(value, "${"), "}"))
+	if err != nil || identifier.GetType() != common.SECRET {
+		return nil, ErrInvalidSecretReference
+	}
+	return identifier, nil
+}
@@ -0,0 +1,12 @@
+package secret
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+type SecretRepository interface {
+	Create(secret *Secret) error
+	Update(secret *Secret) error
+	Delete(secretId common.Identifier) error
+
+	FindById(secretId common.Identifier) (*Secret, error)
+	FindByProject(projectId common.Identifier, offset int, limit int) ([]*Secret, error)
+}
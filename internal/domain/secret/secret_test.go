@@ -0,0 +1,80 @@
+package secret
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestNewSecret(t *testing.T) {
+	key := newTestMasterKey(t, 1)
+
+	if _, err := NewSecret("autops::project:ABCDEFGHIJ", "db", "", ENV_VAR, "DB_PASSWORD", nil, key); err != ErrEmptySecretValue {
+		t.Errorf("expected err to be ErrEmptySecretValue, got %v", err)
+	}
+	invalidTargets := map[InjectionType]string{ENV_VAR: "1PASSWORD", FILE: "../kubeconfig"}
+	for injectionType, target := range invalidTargets {
+		if _, err := NewSecret("autops::project:ABCDEFGHIJ", "db", "", injectionType, target, []byte("value"), key); err != ErrInvalidInjectionTarget {
+			t.Errorf("expected err to be ErrInvalidInjectionTarget for %s, got %v", target, err)
+		}
+	}
+
+	secret, err := NewSecret("autops::project:ABCDEFGHIJ", "kubeconfig", "", FILE, ".kube/config", []byte("apiVersion: v1"), key)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if secret.GetIdentifier().GetType() != common.SECRET {
+		t.Errorf("expected a secret identifier, got %s", secret.GetIdentifier().ToString())
+	}
+	revealed, err := secret.Reveal(key)
+	if err != nil || string(revealed) != "apiVersion: v1" {
+		t.Errorf("expected apiVersion: v1, got %s (%v)", revealed, err)
+	}
+	secret.SetValue([]byte("rotated"), key)
+	if revealed, _ := secret.Reveal(key); string(revealed) != "rotated" {
+		t.Errorf("expected rotated, got %s", revealed)
+	}
+}
+
+func TestSecretReference(t *testing.T) {
+	identifier, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ:secret:1234567890")
+	reference := FormatReference(identifier)
+	if reference != "${autops::project:ABCDEFGHIJ:secret:1234567890}" {
+		t.Errorf("unexpected reference %s", reference)
+	}
+	parsed, err := ParseReference(reference)
+	if err != nil || parsed.ToString() != identifier.ToString() {
+		t.Errorf("expected %s, got %v (%v)", identifier.ToString(), parsed, err)
+	}
+
+	invalid := []string{"password", "${password}", "${autops::project:ABCDEFGHIJ:workflow:1234567890}", "autops::project:ABCDEFGHIJ:secret:1234567890"}
+	for _, value := range invalid {
+		if IsReference(value) {
+			t.Errorf("expected %s not to be a secret reference", value)
+		}
+	}
+}
+
+func TestInjection(t *testing.T) {
+	key := newTestMasterKey(t, 1)
+	password, _ := NewSecret("autops::project:ABCDEFGHIJ", "db", "", ENV_VAR, "DB_PASSWORD", []byte("hunter2"), key)
+	other, _ := NewSecret("autops::project:ABCDEFGHIJ", "db-replica", "", ENV_VAR, "DB_PASSWORD", []byte("hunter3"), key)
+	kubeconfig, _ := NewSecret("autops::project:ABCDEFGHIJ", "kubeconfig", "", FILE, "kubeconfig", []byte("token: abc"), key)
+
+	injection := NewInjection()
+	if err := injection.Add(password, key); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := injection.Add(other, key); err != ErrInjectionTargetConflict {
+		t.Errorf("expected err to be ErrInjectionTargetConflict, got %v", err)
+	}
+	if err := injection.Add(kubeconfig, key); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if injection.GetEnv()["DB_PASSWORD"] != "hunter2" || string(injection.ListFiles()["kubeconfig"]) != "token: abc" {
+		t.Errorf("unexpected injection %v %v", injection.GetEnv(), injection.ListFiles())
+	}
+	if masked := injection.GetMasker().Mask("login with hunter2"); masked != "login with "+MASKED_VALUE {
+		t.Errorf("expected the injected value to be masked, got %s", masked)
+	}
+}
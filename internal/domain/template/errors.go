@@ -1,15 +1,25 @@
 package template
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
 
 var (
-	ErrUnsupportedAttributeType     = errors.New("unsupported attribute type")
-	ErrInvalidListFormat            = errors.New("invalid LIST format: expected format like [a, b, c]")
-	ErrEmptyItemInList              = errors.New("invalid LIST format: empty item")
+	ErrInvalidListFormat            = common.ErrInvalidListFormat
+	ErrEmptyItemInList              = common.ErrEmptyItemInList
 	ErrTemplateInputAlreadyPresent  = errors.New("an input with the same identifier is already present in the template")
 	ErrTemplateOutputAlreadyPresent = errors.New("an output with the same identifier is already present in the template")
 	ErrParseInvalidTemplateType     = errors.New("cannot parse the string into a TemplateType")
 	ErrTemplateOutputNotFound       = errors.New("cannot find an output with the specified identifier")
 	ErrTemplateInputNotFound        = errors.New("cannot find an input with the specified identifier")
-	ErrInvalidAttributeType         = errors.New("invalid attribute type")
+	ErrInvalidAttributeType         = common.ErrInvalidAttributeType
+	ErrSensitiveValueNotASecret     = errors.New("the value of a sensitive attribute must be a secret reference")
+	ErrSecretReferenceNotSensitive  = errors.New("a secret reference can only be used as the value of a sensitive attribute")
+	ErrInvalidSourceCredentials     = errors.New("the source credentials must be a secret of the template project")
+	ErrInsecureCredentialsSource    = errors.New("source credentials can only be used with https or ssh git repositories")
+	ErrNotATemplateVersion          = errors.New("the previous version belongs to another template")
+	ErrSemanticVersionTooLow        = errors.New("the semantic version is lower than the one required by the changes of the version")
+	ErrTemplateInputNameAlreadyUsed = errors.New("the template already declares an input with this name")
 )
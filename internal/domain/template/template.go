@@ -57,14 +57,15 @@ func ParseTemplateType(str string) (TemplateType, error) {
 }
 
 // Template represents an infrastructure or configuration template.
-// It includes its type, source path (local or remote), version, and a set of defined inputs/outputs.
+// It includes its type, source path (local, remote or git), version, and a set of defined inputs/outputs.
 // Templates are versioned and validated upon update.
 type Template struct {
 	common.StatefulNamedEntity
 	common.VersionedSource
-	templateType TemplateType
-	inputs       *common.List[*TemplateAttribute]
-	outputs      *common.List[*TemplateAttribute]
+	templateType      TemplateType
+	inputs            *common.List[*TemplateAttribute]
+	outputs           *common.List[*TemplateAttribute]
+	sourceCredentials *common.Identifier
 }
 
 type TemplateComparator struct{}
@@ -111,6 +112,36 @@ func (t *Template) GetTemplateType() TemplateType {
 	return t.templateType
 }
 
+// GetSourceCredentials returns the identifier of the secret authenticating the access to the git source, or nil if it is public.
+func (t *Template) GetSourceCredentials() *common.Identifier {
+	return t.sourceCredentials
+}
+
+// SetSourceCredentials sets the secret authenticating the access to the git source, or clears it if nil.
+// The secret holds a private key for SSH repositories, and a token (optionally prefixed by "<username>:") for HTTPS repositories.
+//
+// Returns ErrInsecureCredentialsSource if the sources are not fetched from an https or ssh git repository,
+// or ErrInvalidSourceCredentials if the identifier is not a secret of the template project.
+func (t *Template) SetSourceCredentials(secretIdentifier *common.Identifier) error {
+	if secretIdentifier == nil {
+		t.sourceCredentials = nil
+		return nil
+	}
+	if source := t.GetGitSource(); source == nil || !source.AcceptsCredentials() {
+		return ErrInsecureCredentialsSource
+	}
+	secretProject, err := secretIdentifier.GetParent()
+	if err != nil || secretIdentifier.GetType() != common.SECRET {
+		return ErrInvalidSourceCredentials
+	}
+	templateProject, err := t.GetIdentifier().GetParent()
+	if err != nil || templateProject.ToString() != secretProject.ToString() {
+		return ErrInvalidSourceCredentials
+	}
+	t.sourceCredentials = secretIdentifier
+	return nil
+}
+
 // AddInput adds a new input to the template.
 // Returns an error if an input with the same identifier already exists.
 func (t *Template) AddInput(input *TemplateAttribute) error {
@@ -1,34 +1,27 @@
 package template
 
 import (
-	"encoding/json"
-	"strconv"
 	"strings"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
 )
 
 // AttributeType defines the type of value that a TemplateAttribute can hold.
-// It determines how the default value will be validated.
-//
-// The supported types are:
-//   - STRING: a plain string
-//   - NUMBER: a numeric value (integer or float)
-//   - BOOL: a boolean value ("true" or "false")
-//   - LIST: a JSON array of values (e.g., ["a", 1, {"k":"v"}])
-//   - OBJECT: a JSON object (e.g., {"key": "value"})
-type AttributeType int
+// It is shared with workflow attributes, so that template outputs can be bound to workflow outputs (see common.AttributeType).
+type AttributeType = common.AttributeType
 
 const (
-	STRING AttributeType = iota
-	NUMBER
-	BOOL
-	LIST
-	OBJECT
+	STRING = common.STRING
+	NUMBER = common.NUMBER
+	LIST   = common.LIST
+	BOOL   = common.BOOL
+	OBJECT = common.OBJECT
 )
 
-// TODO : add type parser and ToString
-func (t AttributeType) ToString() (string, error) {
+// AttributeTypeCode returns the code of the type used in the identifiers of template attributes: "S", "N", "B", "L" or "O".
+// Returns ErrInvalidAttributeType if the type is invalid.
+func AttributeTypeCode(t AttributeType) (string, error) {
 	switch t {
 	case STRING:
 		return "S", nil
@@ -45,13 +38,30 @@ func (t AttributeType) ToString() (string, error) {
 	}
 }
 
+// legacyAttributeTypes maps the ordinals persisted for template attributes before their types were shared with workflows,
+// which declared BOOL before LIST, to the shared types.
+var legacyAttributeTypes = []AttributeType{STRING, NUMBER, BOOL, LIST, OBJECT}
+
+// AttributeTypeFromLegacyOrdinal converts an ordinal persisted for a template attribute before its type was shared with workflows.
+// Returns ErrInvalidAttributeType if the ordinal is unknown.
+func AttributeTypeFromLegacyOrdinal(ordinal int) (AttributeType, error) {
+	if ordinal < 0 || ordinal >= len(legacyAttributeTypes) {
+		return -1, ErrInvalidAttributeType
+	}
+	return legacyAttributeTypes[ordinal], nil
+}
+
 // TemplateAttribute represents a user-defined parameter for a template.
 // Each attribute has a name, description, type, and a default value (as string).
 // The default value is validated according to the attribute type.
+// The value of a sensitive attribute is a reference to a secret, injected into the executor instead of a literal.
+// Values can be further restricted by declarative constraints (see common.AttributeConstraints).
 type TemplateAttribute struct {
 	common.NamedEntity
 	attributeType AttributeType
 	defaultValue  string
+	sensitive     bool
+	constraints   *common.AttributeConstraints
 }
 
 // NewTemplateAttribute creates a new TemplateAttribute instance with a generated identifier.
@@ -59,11 +69,11 @@ type TemplateAttribute struct {
 //
 // Returns an error if validation fails.
 func NewTemplateAttribute(templateId string, name string, description string, attributeType AttributeType, defaultValue string) (*TemplateAttribute, error) {
-	typeStr, err := attributeType.ToString()
+	typeCode, err := AttributeTypeCode(attributeType)
 	if err != nil {
 		return nil, err
 	}
-	identifier, err := common.BuildAttributeIdentifier(templateId, typeStr)
+	identifier, err := common.BuildAttributeIdentifier(templateId, typeCode)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +93,7 @@ func ExistingTemplateAttribute(identifier string, name string, description strin
 		NamedEntity:   *namedEntity,
 		attributeType: attributeType,
 		defaultValue:  "",
+		constraints:   common.NewAttributeConstraints(),
 	}
 	err = attribute.SetDefaultValue(defaultValue)
 	if err != nil {
@@ -101,57 +112,85 @@ func (a *TemplateAttribute) GetDefaultValue() string {
 	return a.defaultValue
 }
 
+// IsSensitive returns true if the value of the attribute is a secret.
+func (a *TemplateAttribute) IsSensitive() bool {
+	return a.sensitive
+}
+
+// SetSensitive marks the attribute as sensitive or not.
+// Returns an error if the current default value does not match, i.e. a literal for a sensitive attribute,
+// or a secret reference for an attribute that is not sensitive.
+func (a *TemplateAttribute) SetSensitive(sensitive bool) error {
+	if a.defaultValue != "" && sensitive != secret.IsReference(a.defaultValue) {
+		if sensitive {
+			return ErrSensitiveValueNotASecret
+		}
+		return ErrSecretReferenceNotSensitive
+	}
+	a.sensitive = sensitive
+	return nil
+}
+
+// GetConstraints returns the constraints the values of the attribute must satisfy.
+func (a *TemplateAttribute) GetConstraints() *common.AttributeConstraints {
+	return a.constraints
+}
+
+// SetConstraints replaces the constraints of the attribute, or removes them if nil.
+// Returns an error if a constraint does not apply to the attribute type,
+// or a common.ConstraintViolationError if the current default value violates them.
+func (a *TemplateAttribute) SetConstraints(constraints *common.AttributeConstraints) error {
+	if constraints == nil {
+		constraints = common.NewAttributeConstraints()
+	}
+	if err := constraints.CheckApplicable(a.attributeType); err != nil {
+		return err
+	}
+	if a.defaultValue != "" && !a.sensitive {
+		if err := constraints.Validate(a.attributeType, a.defaultValue); err != nil {
+			return err
+		}
+	}
+	a.constraints = constraints
+	return nil
+}
+
 // SetDefaultValue validates and sets the default value for the attribute.
-//   - For STRING: any string is accepted.
-//   - For NUMBER: the value must be a valid float or integer.
-//   - For BOOL: the value must be "true" or "false".
-//   - For OBJECT: the value must be valid JSON of type object.
-//   - For LIST: the value must be valid JSON of type array, and no element must be nil.
+// See ValidateValue for the validation rules.
 //
-// Returns an error if the value is invalid for the given type.
+// Returns an error if the value is invalid.
 func (a *TemplateAttribute) SetDefaultValue(value string) error {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil
 	}
-	switch a.attributeType {
-	case STRING:
-		break
-
-	case NUMBER:
-		if _, err := strconv.ParseFloat(value, 64); err != nil {
-			return err
-		}
+	if err := a.ValidateValue(value); err != nil {
+		return err
+	}
+	a.defaultValue = value
+	return nil
+}
 
-	case BOOL:
-		if _, err := strconv.ParseBool(value); err != nil {
-			return err
+// ValidateValue checks a value provided for the attribute, either as default value or at run time.
+// The value must be valid for the attribute type (see common.AttributeType.Validate), then satisfy the constraints of the attribute, every violation being reported by a common.ConstraintViolationError.
+// The value of a sensitive attribute must be a secret reference instead, validated once revealed.
+func (a *TemplateAttribute) ValidateValue(value string) error {
+	value = strings.TrimSpace(value)
+	if value != "" && secret.IsReference(value) != a.sensitive {
+		if a.sensitive {
+			return ErrSensitiveValueNotASecret
 		}
-
-	case OBJECT:
-		var obj map[string]interface{}
-		if err := json.Unmarshal([]byte(value), &obj); err != nil {
+		return ErrSecretReferenceNotSensitive
+	}
+	if a.sensitive {
+		return nil
+	}
+	if value != "" {
+		if err := a.attributeType.Validate(value); err != nil {
 			return err
 		}
-
-	case LIST:
-		var rawList []interface{}
-		if err := json.Unmarshal([]byte(value), &rawList); err != nil {
-			return ErrInvalidListFormat
-		}
-
-		for _, item := range rawList {
-			if item == nil {
-				return ErrEmptyItemInList
-			}
-		}
-
-	default:
-		return ErrUnsupportedAttributeType
 	}
-
-	a.defaultValue = value
-	return nil
+	return a.constraints.Validate(a.attributeType, value)
 }
 
 // TemplateAttributeComparator is used to compare two TemplateAttribute instances
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
@@ -28,6 +29,31 @@ func TestNewTemplateAttribute(t *testing.T) {
 	}
 }
 
+func TestTemplateAttributeTypeCode(t *testing.T) {
+	attribute, err := NewTemplateAttribute("autops::project:1234567890:template:abcdefghij", "name", "desc", BOOL, "true")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if !strings.Contains(attribute.GetIdentifier().ToString(), ":template:abcdefghij:B:") {
+		t.Errorf("expected the identifier to carry the B code, got %s", attribute.GetIdentifier().ToString())
+	}
+	if _, err := AttributeTypeCode(AttributeType(42)); err != ErrInvalidAttributeType {
+		t.Errorf("expected err to be ErrInvalidAttributeType, got %v", err)
+	}
+}
+
+func TestAttributeTypeFromLegacyOrdinal(t *testing.T) {
+	for ordinal, expected := range []AttributeType{STRING, NUMBER, BOOL, LIST, OBJECT} {
+		attributeType, err := AttributeTypeFromLegacyOrdinal(ordinal)
+		if err != nil || attributeType != expected {
+			t.Errorf("expected %d to be %s, got %s (%v)", ordinal, expected.ToString(), attributeType.ToString(), err)
+		}
+	}
+	if _, err := AttributeTypeFromLegacyOrdinal(5); err != ErrInvalidAttributeType {
+		t.Errorf("expected err to be ErrInvalidAttributeType, got %v", err)
+	}
+}
+
 func TestExistingTemplateAttribute(t *testing.T) {
 	templateId := "autops::project:1234567890:template:abcdefghij"
 	name := "name"
@@ -167,3 +193,55 @@ func TestCompare(t *testing.T) {
 		t.Errorf("expected comparison to return 0, got %d", comparator.Compare(attributeC, attributeA))
 	}
 }
+
+func TestTemplateAttributeSensitive(t *testing.T) {
+	reference := "${autops::project:ABCDEFGHIJ:secret:1234567890}"
+	attribute, _ := NewTemplateAttribute("autops::project:ABCDEFGHIJ:template:1234567890", "password", "", STRING, "literal")
+
+	if err := attribute.SetSensitive(true); err != ErrSensitiveValueNotASecret {
+		t.Errorf("expected err to be ErrSensitiveValueNotASecret, got %v", err)
+	}
+	if err := attribute.SetDefaultValue(reference); err != ErrSecretReferenceNotSensitive {
+		t.Errorf("expected err to be ErrSecretReferenceNotSensitive, got %v", err)
+	}
+
+	attribute, _ = NewTemplateAttribute("autops::project:ABCDEFGHIJ:template:1234567890", "port", "", NUMBER, "")
+	if err := attribute.SetSensitive(true); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := attribute.SetDefaultValue("5432"); err != ErrSensitiveValueNotASecret {
+		t.Errorf("expected err to be ErrSensitiveValueNotASecret, got %v", err)
+	}
+	if err := attribute.SetDefaultValue(reference); err != nil || attribute.GetDefaultValue() != reference {
+		t.Errorf("expected default value to be %s, got %s (%v)", reference, attribute.GetDefaultValue(), err)
+	}
+	if err := attribute.SetSensitive(false); err != ErrSecretReferenceNotSensitive {
+		t.Errorf("expected err to be ErrSecretReferenceNotSensitive, got %v", err)
+	}
+}
+
+func TestTemplateAttributeConstraints(t *testing.T) {
+	templateId := "autops::project:ABCDEFGHIJ:template:1234567890"
+	two := 2
+	attribute, _ := NewTemplateAttribute(templateId, "zones", "", LIST, `["a", "b", "c"]`)
+
+	constraints := common.NewAttributeConstraints()
+	constraints.SetItems(nil, &two)
+	elementType := STRING
+	constraints.SetElementType(&elementType)
+	if err := attribute.SetConstraints(constraints); err == nil {
+		t.Error("expected the default value to violate the constraints")
+	}
+	attribute, _ = NewTemplateAttribute(templateId, "zones", "", LIST, `["a"]`)
+	if err := attribute.SetConstraints(constraints); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	err := attribute.ValidateValue(`[1, 2, 3]`)
+	violationErr, ok := err.(*common.ConstraintViolationError)
+	if !ok || len(violationErr.ListViolations()) != 4 {
+		t.Errorf("expected every violation to be reported, got %v", err)
+	}
+	if err := attribute.SetDefaultValue(`["a", "b"]`); err != nil || attribute.GetDefaultValue() != `["a", "b"]` {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+}
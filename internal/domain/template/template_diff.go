@@ -0,0 +1,29 @@
+package template
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// Diff compares two versions of a template, reporting whether the source content changed
+// and which inputs and outputs were added, removed or modified.
+func Diff(from *Template, to *Template) *common.VersionDiff {
+	return common.NewVersionDiff(&from.VersionedSource, &to.VersionedSource, diffAttributes(from.ListInputs(), to.ListInputs()), diffAttributes(from.ListOutputs(), to.ListOutputs()))
+}
+
+// diffAttributes compares two sets of template attributes by name.
+func diffAttributes(previous []*TemplateAttribute, current []*TemplateAttribute) []*common.AttributeChange {
+	return common.DiffAttributes(previous, current, (*TemplateAttribute).GetName, func(a *TemplateAttribute, b *TemplateAttribute) []string {
+		fields := []string{}
+		if a.GetType() != b.GetType() {
+			fields = append(fields, "type")
+		}
+		if a.GetDefaultValue() != b.GetDefaultValue() {
+			fields = append(fields, "default_value")
+		}
+		if a.IsSensitive() != b.IsSensitive() {
+			fields = append(fields, "sensitive")
+		}
+		if a.GetDescription() != b.GetDescription() {
+			fields = append(fields, "description")
+		}
+		return fields
+	})
+}
@@ -0,0 +1,45 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestDiff(t *testing.T) {
+	from, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, "/path/to/v1.zip")
+	to, _ := ExistingTemplate(from.GetIdentifier().ToString(), "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	templateId := from.GetIdentifier().ToString()
+
+	cidr, _ := NewTemplateAttribute(templateId, "cidr", "", STRING, "10.0.0.0/16")
+	changedCidr, _ := NewTemplateAttribute(templateId, "cidr", "", STRING, "10.1.0.0/16")
+	zones, _ := NewTemplateAttribute(templateId, "zones", "", NUMBER, "3")
+	name, _ := NewTemplateAttribute(templateId, "name", "", STRING, "")
+	vpcId, _ := NewTemplateAttribute(templateId, "vpc_id", "", STRING, "")
+	from.AddInput(cidr)
+	from.AddInput(zones)
+	to.AddInput(changedCidr)
+	to.AddInput(name)
+	to.AddOutput(vpcId)
+
+	diff := Diff(from, to)
+	if !diff.IsContentChanged() || diff.GetFromVersion() != 1 || diff.GetToVersion() != 2 {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+	inputs := diff.ListInputChanges()
+	if len(inputs) != 3 {
+		t.Fatalf("expected 3 input changes, got %d", len(inputs))
+	}
+	if inputs[0].GetName() != "cidr" || inputs[0].GetChangeType() != common.MODIFIED || inputs[0].ListChangedFields()[0] != "default_value" {
+		t.Errorf("expected cidr to be modified, got %+v", inputs[0])
+	}
+	if inputs[1].GetName() != "name" || inputs[1].GetChangeType() != common.ADDED {
+		t.Errorf("expected name to be added, got %+v", inputs[1])
+	}
+	if inputs[2].GetName() != "zones" || inputs[2].GetChangeType() != common.REMOVED {
+		t.Errorf("expected zones to be removed, got %+v", inputs[2])
+	}
+	if outputs := diff.ListOutputChanges(); len(outputs) != 1 || outputs[0].GetChangeType() != common.ADDED {
+		t.Errorf("expected vpc_id to be added, got %+v", outputs)
+	}
+}
@@ -0,0 +1,64 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// GetInputSchema returns the JSON Schema describing the inputs of the template.
+func (t *Template) GetInputSchema() *common.InputSchema {
+	properties := make([]*common.SchemaProperty, 0, t.inputs.Len())
+	for _, input := range t.inputs.Items() {
+		properties = append(properties, common.NewSchemaProperty(input.GetName(), input.GetDescription(), input.GetType(), input.GetDefaultValue(), input.IsSensitive(), input.GetConstraints()))
+	}
+	return common.NewInputSchema(t.GetName(), t.GetDescription(), properties)
+}
+
+// AddInputsFromSchema creates an input for each property of the schema, with its type, description, default value, sensitivity and constraints.
+// Either every input is added or none is.
+//
+// Returns the created inputs, or an error prefixed by the name of the first invalid property,
+// wrapping ErrTemplateInputNameAlreadyUsed if the template already declares an input with this name.
+func (t *Template) AddInputsFromSchema(schema *common.InputSchema) ([]*TemplateAttribute, error) {
+	declared := map[string]bool{}
+	for _, input := range t.inputs.Items() {
+		declared[input.GetName()] = true
+	}
+	inputs := []*TemplateAttribute{}
+	for _, property := range schema.ListProperties() {
+		if declared[property.GetName()] {
+			return nil, fmt.Errorf("input %q: %w", property.GetName(), ErrTemplateInputNameAlreadyUsed)
+		}
+		input, err := newTemplateAttributeFromProperty(t.GetIdentifier().ToString(), property)
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", property.GetName(), err)
+		}
+		inputs = append(inputs, input)
+	}
+	for _, input := range inputs {
+		if err := t.AddInput(input); err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+// newTemplateAttributeFromProperty creates a TemplateAttribute described by a property of an input schema.
+// The sensitivity is set before the default value, which is a secret reference for sensitive attributes.
+func newTemplateAttributeFromProperty(templateId string, property *common.SchemaProperty) (*TemplateAttribute, error) {
+	attribute, err := NewTemplateAttribute(templateId, property.GetName(), property.GetDescription(), property.GetType(), "")
+	if err != nil {
+		return nil, err
+	}
+	if err := attribute.SetSensitive(property.IsSensitive()); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetConstraints(property.GetConstraints()); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetDefaultValue(property.GetDefaultValue()); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
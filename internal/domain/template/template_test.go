@@ -222,3 +222,33 @@ func TestTemplateOutputs(t *testing.T) {
 		t.Errorf("expected len(inputs) to be 1, got %d", len(template.ListOutputs()))
 	}
 }
+
+func TestTemplateSourceCredentials(t *testing.T) {
+	tmpl, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, "git::git@host:org/repo.git//modules/vpc?ref=v1.2.0")
+	credentials, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ:secret:1234567890")
+	if err := tmpl.SetSourceCredentials(credentials); err != nil || tmpl.GetSourceCredentials() != credentials {
+		t.Errorf("expected the credentials to be set, got %v", err)
+	}
+	other, _ := common.NewIdentifier("autops::project:KLMNOPQRST:secret:1234567890")
+	if err := tmpl.SetSourceCredentials(other); err != ErrInvalidSourceCredentials {
+		t.Errorf("expected err to be ErrInvalidSourceCredentials, got %v", err)
+	}
+	workflow, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ:workflow:1234567890")
+	if err := tmpl.SetSourceCredentials(workflow); err != ErrInvalidSourceCredentials {
+		t.Errorf("expected err to be ErrInvalidSourceCredentials, got %v", err)
+	}
+	tmpl.SetSourceCredentials(nil)
+	if tmpl.GetSourceCredentials() != nil {
+		t.Error("expected the credentials to be cleared")
+	}
+	for _, sourcePath := range []string{"git::http://host/org/repo.git", "git::file:///srv/git/repo.git", "git::/srv/git/repo.git", "/path/to/file.zip"} {
+		insecure, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, sourcePath)
+		if err := insecure.SetSourceCredentials(credentials); err != ErrInsecureCredentialsSource {
+			t.Errorf("%s: expected err to be ErrInsecureCredentialsSource, got %v", sourcePath, err)
+		}
+	}
+	https, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, "git::https://host/org/repo.git")
+	if err := https.SetSourceCredentials(credentials); err != nil {
+		t.Errorf("expected err to be nil for an https repository, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package template
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// ClassifiedChange is a change of an input or output between two versions of a template, with its semantic versioning level.
+type ClassifiedChange struct {
+	output bool
+	name   string
+	field  string
+	level  common.ChangeLevel
+	reason string
+}
+
+// IsOutput returns true if the change concerns an output, false if it concerns an input.
+func (c *ClassifiedChange) IsOutput() bool {
+	return c.output
+}
+
+// GetName returns the name of the changed attribute.
+func (c *ClassifiedChange) GetName() string {
+	return c.name
+}
+
+// GetField returns the changed field of a modified attribute (e.g. "type" or "default_value"),
+// or an empty string if the attribute was added or removed.
+func (c *ClassifiedChange) GetField() string {
+	return c.field
+}
+
+// GetLevel returns the semantic versioning level of the change.
+func (c *ClassifiedChange) GetLevel() common.ChangeLevel {
+	return c.level
+}
+
+// GetReason returns a description of the change (e.g. "default value removed").
+func (c *ClassifiedChange) GetReason() string {
+	return c.reason
+}
+
+// VersionChange classifies the changes introduced by a template version.
+type VersionChange struct {
+	contentChanged bool
+	changes        []*ClassifiedChange
+}
+
+// ClassifyChange classifies the changes between two versions of a template:
+//   - removing an input or an output, changing the type or the sensitivity of an attribute,
+//     removing the default value of an input, or adding an input without default value are MAJOR changes
+//   - adding an input with a default value or an output is a MINOR change
+//   - other changes, including source content changes, are PATCH changes
+func ClassifyChange(from *Template, to *Template) *VersionChange {
+	changes := classifyAttributes(false, from.ListInputs(), to.ListInputs())
+	changes = append(changes, classifyAttributes(true, from.ListOutputs(), to.ListOutputs())...)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].level > changes[j].level
+	})
+	return &VersionChange{
+		contentChanged: Diff(from, to).IsContentChanged(),
+		changes:        changes,
+	}
+}
+
+// classifyAttributes classifies the changes between two sets of inputs or outputs, matched by name.
+func classifyAttributes(output bool, previous []*TemplateAttribute, current []*TemplateAttribute) []*ClassifiedChange {
+	kind := "input"
+	if output {
+		kind = "output"
+	}
+	changes := []*ClassifiedChange{}
+	add := func(name string, field string, level common.ChangeLevel, reason string) {
+		changes = append(changes, &ClassifiedChange{output: output, name: name, field: field, level: level, reason: reason})
+	}
+	for _, change := range diffAttributes(previous, current) {
+		name := change.GetName()
+		switch change.GetChangeType() {
+		case common.REMOVED:
+			add(name, "", common.MAJOR, kind+" removed")
+		case common.ADDED:
+			if !output && getAttribute(current, name).GetDefaultValue() == "" {
+				add(name, "", common.MAJOR, "required input added")
+			} else {
+				add(name, "", common.MINOR, kind+" added")
+			}
+		default:
+			before, after := getAttribute(previous, name), getAttribute(current, name)
+			for _, field := range change.ListChangedFields() {
+				switch {
+				case field == "type":
+					add(name, field, common.MAJOR, "type changed from "+before.GetType().ToString()+" to "+after.GetType().ToString())
+				case field == "sensitive":
+					add(name, field, common.MAJOR, "sensitivity changed")
+				case field == "default_value" && !output && after.GetDefaultValue() == "":
+					add(name, field, common.MAJOR, "default value removed")
+				default:
+					add(name, field, common.PATCH, strings.ReplaceAll(field, "_", " ")+" changed")
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// getAttribute returns the attribute with the given name, or nil if not found.
+func getAttribute(attributes []*TemplateAttribute, name string) *TemplateAttribute {
+	for _, attribute := range attributes {
+		if attribute.GetName() == name {
+			return attribute
+		}
+	}
+	return nil
+}
+
+// GetLevel returns the highest level of the changes, or PATCH if the attributes did not change.
+func (c *VersionChange) GetLevel() common.ChangeLevel {
+	if len(c.changes) == 0 {
+		return common.PATCH
+	}
+	return c.changes[0].level
+}
+
+// IsBreaking returns true if the version introduces MAJOR changes.
+func (c *VersionChange) IsBreaking() bool {
+	return c.GetLevel() == common.MAJOR
+}
+
+// IsContentChanged returns true if the source content changed between the two versions.
+func (c *VersionChange) IsContentChanged() bool {
+	return c.contentChanged
+}
+
+// ListChanges returns the attribute changes, from the highest level to the lowest.
+func (c *VersionChange) ListChanges() []*ClassifiedChange {
+	return append([]*ClassifiedChange(nil), c.changes...)
+}
+
+// ListBreakingChanges returns the MAJOR attribute changes.
+func (c *VersionChange) ListBreakingChanges() []*ClassifiedChange {
+	breaking := []*ClassifiedChange{}
+	for _, change := range c.changes {
+		if change.level == common.MAJOR {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// ReleaseAfter classifies the changes of the template since its previous version, and assigns it a semantic version accordingly.
+// A semantic version set beforehand is kept if it is at least the one required by the changes (e.g. 2.0.0-rc.1 for a MAJOR change of 1.4.2),
+// otherwise the release version of the previous version is bumped.
+//
+// Returns an error if the previous version is another template, or if the semantic version set beforehand is too low.
+func (t *Template) ReleaseAfter(previous *Template) (*VersionChange, error) {
+	if previous.GetIdentifier().ToString() != t.GetIdentifier().ToString() {
+		return nil, ErrNotATemplateVersion
+	}
+	change := ClassifyChange(previous, t)
+	required := previous.GetReleaseVersion().Bump(change.GetLevel())
+	declared := t.GetSemanticVersion()
+	if declared == nil {
+		t.SetSemanticVersion(required)
+		return change, nil
+	}
+	declaredRelease := common.NewSemanticVersion(declared.GetMajor(), declared.GetMinor(), declared.GetPatch())
+	if declaredRelease.Compare(required) < 0 {
+		return nil, ErrSemanticVersionTooLow
+	}
+	return change, nil
+}
@@ -0,0 +1,104 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestClassifyChange(t *testing.T) {
+	from, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, "/path/to/v1.zip")
+	templateId := from.GetIdentifier().ToString()
+	cidr, _ := NewTemplateAttribute(templateId, "cidr", "", STRING, "10.0.0.0/16")
+	from.AddInput(cidr)
+
+	patch, _ := ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	describedCidr, _ := NewTemplateAttribute(templateId, "cidr", "The CIDR block", STRING, "10.0.0.0/16")
+	patch.AddInput(describedCidr)
+	if change := ClassifyChange(from, patch); change.GetLevel() != common.PATCH || !change.IsContentChanged() || len(change.ListChanges()) != 1 {
+		t.Errorf("expected a patch change, got %s %+v", change.GetLevel().ToString(), change.ListChanges())
+	}
+
+	minor, _ := ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	zones, _ := NewTemplateAttribute(templateId, "zones", "", NUMBER, "3")
+	vpcId, _ := NewTemplateAttribute(templateId, "vpc_id", "", STRING, "")
+	minor.AddInput(cidr)
+	minor.AddInput(zones)
+	minor.AddOutput(vpcId)
+	if change := ClassifyChange(from, minor); change.GetLevel() != common.MINOR || change.IsBreaking() {
+		t.Errorf("expected a minor change, got %s", change.GetLevel().ToString())
+	}
+
+	tests := map[string]func(*Template){
+		"input removed": func(major *Template) {},
+		"type changed from string to number": func(major *Template) {
+			numberCidr, _ := NewTemplateAttribute(templateId, "cidr", "", NUMBER, "16")
+			major.AddInput(numberCidr)
+		},
+		"default value removed": func(major *Template) {
+			requiredCidr, _ := NewTemplateAttribute(templateId, "cidr", "", STRING, "")
+			major.AddInput(requiredCidr)
+		},
+		"required input added": func(major *Template) {
+			name, _ := NewTemplateAttribute(templateId, "name", "", STRING, "")
+			major.AddInput(cidr)
+			major.AddInput(name)
+		},
+	}
+	for reason, build := range tests {
+		major, _ := ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+		build(major)
+		change := ClassifyChange(from, major)
+		breaking := change.ListBreakingChanges()
+		if !change.IsBreaking() || len(breaking) != 1 || breaking[0].GetReason() != reason {
+			t.Errorf("expected %q to be a major change, got %+v", reason, breaking)
+		}
+	}
+
+	removedOutput := ClassifyChange(minor, from).ListBreakingChanges()
+	if len(removedOutput) != 2 || !removedOutput[1].IsOutput() || removedOutput[1].GetName() != "vpc_id" {
+		t.Errorf("expected removing an output to be a major change, got %+v", removedOutput)
+	}
+}
+
+func TestTemplateReleaseAfter(t *testing.T) {
+	previous, _ := NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, TERRAFORM, "/path/to/v1.zip")
+	templateId := previous.GetIdentifier().ToString()
+	cidr, _ := NewTemplateAttribute(templateId, "cidr", "", STRING, "10.0.0.0/16")
+	previous.AddInput(cidr)
+
+	next, _ := ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	if _, err := next.ReleaseAfter(previous); err != nil || next.GetSemanticVersion().ToString() != "2.0.0" {
+		t.Errorf("expected the removal of an input to release 2.0.0, got %v (%v)", next.GetSemanticVersion(), err)
+	}
+
+	semanticVersion, _ := common.ParseSemanticVersion("1.4.2")
+	previous.SetSemanticVersion(semanticVersion)
+	next, _ = ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	next.AddInput(cidr)
+	if _, err := next.ReleaseAfter(previous); err != nil || next.GetSemanticVersion().ToString() != "1.4.3" {
+		t.Errorf("expected a source change to release 1.4.3, got %v (%v)", next.GetSemanticVersion(), err)
+	}
+
+	declared, _ := common.ParseSemanticVersion("1.5.0")
+	next.SetSemanticVersion(declared)
+	if _, err := next.ReleaseAfter(previous); err != nil || next.GetSemanticVersion() != declared {
+		t.Errorf("expected the declared version to be kept, got %v (%v)", next.GetSemanticVersion(), err)
+	}
+
+	next, _ = ExistingTemplate(templateId, "vpc", "", common.PENDING, TERRAFORM, "/path/to/v2.zip", 2)
+	next.SetSemanticVersion(declared)
+	if _, err := next.ReleaseAfter(previous); err != ErrSemanticVersionTooLow {
+		t.Errorf("expected err to be ErrSemanticVersionTooLow, got %v", err)
+	}
+	candidate, _ := common.ParseSemanticVersion("2.0.0-rc.1")
+	next.SetSemanticVersion(candidate)
+	if _, err := next.ReleaseAfter(previous); err != nil {
+		t.Errorf("expected a pre-release of the required version to be accepted, got %v", err)
+	}
+
+	other, _ := NewTemplate("autops::project:ABCDEFGHIJ", "eks", "", common.PENDING, TERRAFORM, "/path/to/v1.zip")
+	if _, err := other.ReleaseAfter(previous); err != ErrNotATemplateVersion {
+		t.Errorf("expected err to be ErrNotATemplateVersion, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// ApprovalGate defines the manual approval required before a run can go past a WorkflowStep.
+// Approvers are either explicitly listed users, or users whose policies allow the approver action on the workflow.
+type ApprovalGate struct {
+	approvers      *common.List[*common.Identifier]
+	approverAction policy.PolicyAction
+	minApprovals   int
+	timeout        time.Duration
+}
+
+// NewApprovalGate creates an ApprovalGate.
+//   - approvers lists the identifiers of the users allowed to approve.
+//   - approverAction, if not nil, also allows users holding this action on the workflow to approve.
+//   - minApprovals is the number of approvals required to resume the run.
+//   - timeout is the duration after which a pending approval expires and the run fails.
+//
+// Returns an error if no approver can be determined, if minApprovals is lower than 1, or if timeout is not positive.
+func NewApprovalGate(approvers []*common.Identifier, approverAction policy.PolicyAction, minApprovals int, timeout time.Duration) (*ApprovalGate, error) {
+	if len(approvers) == 0 && approverAction == nil {
+		return nil, ErrApprovalGateWithoutApprovers
+	}
+	if minApprovals < 1 {
+		return nil, ErrInvalidMinApprovals
+	}
+	if timeout <= 0 {
+		return nil, ErrInvalidApprovalTimeout
+	}
+	return &ApprovalGate{
+		approvers:      common.NewList(common.IdentifierComparator{}, approvers),
+		approverAction: approverAction,
+		minApprovals:   minApprovals,
+		timeout:        timeout,
+	}, nil
+}
+
+// ListApprovers returns the identifiers of the users explicitly allowed to approve.
+func (g *ApprovalGate) ListApprovers() []*common.Identifier {
+	return g.approvers.Items()
+}
+
+// GetApproverAction returns the policy action granting the right to approve, or nil if only listed users can approve.
+func (g *ApprovalGate) GetApproverAction() policy.PolicyAction {
+	return g.approverAction
+}
+
+// GetMinApprovals returns the number of approvals required to resume the run.
+func (g *ApprovalGate) GetMinApprovals() int {
+	return g.minApprovals
+}
+
+// GetTimeout returns the duration after which a pending approval expires.
+func (g *ApprovalGate) GetTimeout() time.Duration {
+	return g.timeout
+}
+
+// CanApprove returns true if the user is a listed approver, or if its policies allow the approver action on the workflow
+// in the context of the request, which may be nil.
+func (g *ApprovalGate) CanApprove(user *identity.User, workflowIdentifier *common.Identifier, context *policy.RequestContext) bool {
+	if g.approvers.Contains(user.GetIdentifier()) {
+		return true
+	}
+	return g.approverAction != nil && user.IsAllowed(workflowIdentifier, g.approverAction, context)
+}
+
+// ApprovalDecision records the approval or rejection of a pending approval by a user.
+type ApprovalDecision struct {
+	approver  *common.Identifier
+	approved  bool
+	comment   string
+	decidedAt string
+}
+
+// ExistingApprovalDecision creates an ApprovalDecision from persisted data.
+func ExistingApprovalDecision(approver *common.Identifier, approved bool, comment string, decidedAt string) *ApprovalDecision {
+	return &ApprovalDecision{
+		approver:  approver,
+		approved:  approved,
+		comment:   comment,
+		decidedAt: decidedAt,
+	}
+}
+
+// GetApprover returns the identifier of the user who took the decision.
+func (d *ApprovalDecision) GetApprover() *common.Identifier {
+	return d.approver
+}
+
+// IsApproved returns true if the decision is an approval, false if it is a rejection.
+func (d *ApprovalDecision) IsApproved() bool {
+	return d.approved
+}
+
+// GetComment returns the comment left by the approver.
+func (d *ApprovalDecision) GetComment() string {
+	return d.comment
+}
+
+// GetDecidedAt returns the timestamp of the decision.
+func (d *ApprovalDecision) GetDecidedAt() string {
+	return d.decidedAt
+}
+
+// ApprovalRequest represents the approval awaited by a run on an approval gate step.
+// Its status is WAITING until enough approvals are collected (SUCCESS), or until it is rejected or expires (FAILURE).
+type ApprovalRequest struct {
+	stepNumber int
+	gate       *ApprovalGate
+	status     common.Status
+	expiresAt  string
+	decisions  []*ApprovalDecision
+}
+
+// ExistingApprovalRequest creates an ApprovalRequest from persisted data.
+func ExistingApprovalRequest(stepNumber int, gate *ApprovalGate, status common.Status, expiresAt string, decisions []*ApprovalDecision) *ApprovalRequest {
+	return &ApprovalRequest{
+		stepNumber: stepNumber,
+		gate:       gate,
+		status:     status,
+		expiresAt:  expiresAt,
+		decisions:  append([]*ApprovalDecision(nil), decisions...),
+	}
+}
+
+// GetStepNumber returns the number of the approval gate step.
+func (a *ApprovalRequest) GetStepNumber() int {
+	return a.stepNumber
+}
+
+// GetGate returns the ApprovalGate of the step.
+func (a *ApprovalRequest) GetGate() *ApprovalGate {
+	return a.gate
+}
+
+// GetStatus returns the status of the request.
+func (a *ApprovalRequest) GetStatus() common.Status {
+	return a.status
+}
+
+// GetExpiresAt returns the timestamp after which the request expires.
+func (a *ApprovalRequest) GetExpiresAt() string {
+	return a.expiresAt
+}
+
+// ListDecisions returns the decisions submitted for the request.
+func (a *ApprovalRequest) ListDecisions() []*ApprovalDecision {
+	return append([]*ApprovalDecision(nil), a.decisions...)
+}
+
+// CountApprovals returns the number of approvals submitted for the request.
+func (a *ApprovalRequest) CountApprovals() int {
+	count := 0
+	for _, decision := range a.decisions {
+		if decision.approved {
+			count++
+		}
+	}
+	return count
+}
+
+// IsExpired returns true if the expiry timestamp of the request is in the past.
+func (a *ApprovalRequest) IsExpired() bool {
+	expiresAt, err := time.Parse(time.RFC3339, a.expiresAt)
+	return err != nil || time.Now().After(expiresAt)
+}
+
+// hasDecided returns true if the user already submitted a decision for the request.
+func (a *ApprovalRequest) hasDecided(user *common.Identifier) bool {
+	for _, decision := range a.decisions {
+		if decision.approver.ToString() == user.ToString() {
+			return true
+		}
+	}
+	return false
+}
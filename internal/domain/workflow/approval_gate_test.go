@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+func newTestUser(t *testing.T, username string) *identity.User {
+	t.Helper()
+	user, err := identity.NewUser(username+"@example.com", username)
+	if err != nil {
+		t.Fatalf("failed to create test user: %v", err)
+	}
+	return user
+}
+
+func TestNewApprovalGate(t *testing.T) {
+	approver, _ := common.NewIdentifier("autops::user:1234567890")
+	_, err := NewApprovalGate(nil, nil, 1, time.Hour)
+	if err != ErrApprovalGateWithoutApprovers {
+		t.Errorf("expected err to be ErrApprovalGateWithoutApprovers, got %v", err)
+	}
+	_, err = NewApprovalGate([]*common.Identifier{approver}, nil, 0, time.Hour)
+	if err != ErrInvalidMinApprovals {
+		t.Errorf("expected err to be ErrInvalidMinApprovals, got %v", err)
+	}
+	_, err = NewApprovalGate([]*common.Identifier{approver}, nil, 1, 0)
+	if err != ErrInvalidApprovalTimeout {
+		t.Errorf("expected err to be ErrInvalidApprovalTimeout, got %v", err)
+	}
+	gate, err := NewApprovalGate(nil, policy.UPDATE_WORKFLOW, 2, time.Hour)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if gate.GetMinApprovals() != 2 || gate.GetTimeout() != time.Hour || gate.GetApproverAction() != policy.UPDATE_WORKFLOW {
+		t.Error("unexpected approval gate attributes")
+	}
+}
+
+func TestApprovalGateCanApprove(t *testing.T) {
+	workflowId, _ := common.NewIdentifier("autops::project:ABCDEFGHIJ:workflow:1234567890")
+	listed := newTestUser(t, "listed")
+	holder := newTestUser(t, "holder")
+	other := newTestUser(t, "other")
+
+	statement, _ := policy.NewPolicyStatement(policy.ALLOW, []*common.Identifier{workflowId}, []policy.PolicyAction{policy.UPDATE_WORKFLOW})
+	p, _ := policy.NewPolicy("autops::project:ABCDEFGHIJ", "approvers", "", []*policy.PolicyStatement{statement})
+	holder.AttachPolicy(p)
+
+	gate, _ := NewApprovalGate([]*common.Identifier{listed.GetIdentifier()}, policy.UPDATE_WORKFLOW, 1, time.Hour)
+	if !gate.CanApprove(listed, workflowId, nil) {
+		t.Error("expected listed user to be an approver")
+	}
+	if !gate.CanApprove(holder, workflowId, nil) {
+		t.Error("expected user holding the approver action to be an approver")
+	}
+	if gate.CanApprove(other, workflowId, nil) {
+		t.Error("expected other user not to be an approver")
+	}
+
+	gate, _ = NewApprovalGate([]*common.Identifier{listed.GetIdentifier()}, nil, 1, time.Hour)
+	if gate.CanApprove(holder, workflowId, nil) {
+		t.Error("expected policies to be ignored without approver action")
+	}
+}
@@ -1,11 +1,14 @@
 package workflow
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
 
 var (
-	ErrUnsupportedAttributeType         = errors.New("unsupported attribute type")
-	ErrInvalidListFormat                = errors.New("invalid LIST format: expected format like [a, b, c]")
-	ErrEmptyItemInList                  = errors.New("invalid LIST format: empty item")
+	ErrInvalidListFormat                = common.ErrInvalidListFormat
+	ErrEmptyItemInList                  = common.ErrEmptyItemInList
 	ErrUnsupportedWorkflowAttributeType = errors.New("unsupported attribute type")
 	ErrWorkflowInputAlreadyPresent      = errors.New("a workflow input with the same identifier is already attached")
 	ErrWorkflowOutputAlreadyPresent     = errors.New("a workflow output with the same identifier is already attached")
@@ -13,4 +16,48 @@ var (
 	ErrWorkflowInputNotFound            = errors.New("cannot find a workflow input with the specified identifier")
 	ErrWorkflowOutputNotFound           = errors.New("cannot find a workflow output with the specified identifier")
 	ErrWorkflowStepNotFound             = errors.New("cannot find a workflow step with the specified step number")
+	ErrWebhookTriggerAlreadyPresent     = errors.New("a webhook trigger with the same identifier is already attached")
+	ErrWebhookTriggerNotFound           = errors.New("cannot find a webhook trigger with the specified identifier")
+	ErrInvalidTriggerFilter             = errors.New("invalid trigger filter expression")
+	ErrInvalidWebhookSignature          = errors.New("the webhook signature does not match the payload")
+	ErrInvalidWebhookPayload            = errors.New("the webhook payload is not a valid JSON document")
+	ErrWebhookPayloadFiltered           = errors.New("the webhook payload does not satisfy the trigger filter")
+	ErrWorkflowRunNotFound              = errors.New("cannot find a workflow run with the specified identifier")
+	ErrApprovalGateWithoutApprovers     = errors.New("an approval gate requires at least one approver or an approver policy action")
+	ErrInvalidMinApprovals              = errors.New("the minimum number of approvals must be at least 1")
+	ErrInvalidApprovalTimeout           = errors.New("the approval timeout must be a positive duration")
+	ErrStepIsNotApprovalGate            = errors.New("the workflow step is not an approval gate")
+	ErrRunAlreadyWaitingApproval        = errors.New("the workflow run is already waiting for an approval")
+	ErrNoPendingApproval                = errors.New("the workflow run is not waiting for an approval")
+	ErrApprovalExpired                  = errors.New("the pending approval has expired")
+	ErrNotAnApprover                    = errors.New("the user is not allowed to approve this workflow run")
+	ErrApprovalAlreadySubmitted         = errors.New("the user already submitted a decision for this approval")
+	ErrSelfApproval                     = errors.New("the user who started the workflow run cannot approve it")
+	ErrApprovalRequired                 = errors.New("the workflow run cannot go past an approval gate that has not been approved")
+	ErrStepIsApprovalGate               = errors.New("approval gate steps are passed by submitting approvals")
+	ErrRunAlreadyFinished               = errors.New("the workflow run is already finished")
+	ErrInvalidRunMode                   = errors.New("invalid run mode: correct values are 'standard', 'plan' or 'apply'")
+	ErrInvalidPlanFormat                = errors.New("the plan is not a valid JSON plan representation")
+	ErrPlanUnsupportedTemplateType      = errors.New("only terraform and opentofu steps can be planned")
+	ErrRunIsNotAPlan                    = errors.New("the workflow run is not a successful plan-only run")
+	ErrPlanAlreadyApplied               = errors.New("the plan has already been applied")
+	ErrPlanOutdated                     = errors.New("the workflow, the template version or the inputs changed since the plan was computed")
+	ErrPlanMissing                      = errors.New("a terraform or opentofu step has no saved plan")
+	ErrUnknownWorkflowInput             = errors.New("the workflow does not declare an input with this name")
+	ErrMissingWorkflowInput             = errors.New("a value is required since the input has no default value")
+	ErrUnknownWorkflowOutput            = errors.New("the workflow does not declare an output with this name")
+	ErrUnknownStepOutput                = errors.New("the template of the step does not declare an output with this name")
+	ErrMissingStepOutput                = errors.New("the bound step did not produce this output, and the output has no default value")
+	ErrInvalidOutputReference           = errors.New("invalid output reference: expected format like ${<workflow id>.outputs.<name>}")
+	ErrReferencedWorkflowNotFound       = errors.New("cannot find the workflow referenced by the output reference")
+	ErrReferenceOutsideProject          = errors.New("the referenced workflow does not belong to the project of the workflow")
+	ErrReferenceNotAllowed              = errors.New("the user is not allowed to read the referenced workflow")
+	ErrNoSuccessfulRun                  = errors.New("the workflow has no successful run")
+	ErrSensitiveValueNotASecret         = errors.New("the value of a sensitive attribute must be a secret reference")
+	ErrSecretReferenceNotSensitive      = errors.New("a secret reference can only be used as the value of a sensitive attribute")
+	ErrSecretOutsideProject             = errors.New("the referenced secret does not belong to the project of the workflow")
+	ErrIncompatibleOutputType           = errors.New("the type of the step output cannot be assigned to the type of the workflow output")
+	ErrTemplateVersionOutOfRange        = errors.New("the template version of the step is outside the version range")
+	ErrNoMatchingTemplateVersion        = errors.New("no template version matches the version range of the step")
+	ErrWorkflowInputNameAlreadyUsed     = errors.New("the workflow already declares an input with this name")
 )
@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"strings"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// OUTPUT_REFERENCE_PREFIX and OUTPUT_REFERENCE_SUFFIX delimit an output reference used as an input value.
+const (
+	OUTPUT_REFERENCE_PREFIX = "${"
+	OUTPUT_REFERENCE_SUFFIX = "}"
+)
+
+// outputReferenceSeparator separates the workflow identifier from the output name in an output reference.
+const outputReferenceSeparator = ".outputs."
+
+// OutputReference addresses an output of the latest successful run of a workflow,
+// so that it can be used as an input value of another workflow.
+// Its string representation is ${<workflow id>.outputs.<output name>}.
+type OutputReference struct {
+	workflowIdentifier *common.Identifier
+	outputName         string
+}
+
+// IsOutputReference returns true if the value has the format of an output reference.
+func IsOutputReference(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, OUTPUT_REFERENCE_PREFIX) && strings.HasSuffix(value, OUTPUT_REFERENCE_SUFFIX) && strings.Contains(value, outputReferenceSeparator)
+}
+
+// ParseOutputReference parses an output reference like ${autops::project:ABCDEFGHIJ:workflow:1234567890.outputs.vpc_id}.
+// Returns ErrInvalidOutputReference if the value is malformed or does not address a workflow.
+func ParseOutputReference(value string) (*OutputReference, error) {
+	if !IsOutputReference(value) {
+		return nil, ErrInvalidOutputReference
+	}
+	value = strings.TrimSpace(value)
+	content := strings.TrimSuffix(strings.TrimPrefix(value, OUTPUT_REFERENCE_PREFIX), OUTPUT_REFERENCE_SUFFIX)
+	workflowId, outputName, found := strings.Cut(content, outputReferenceSeparator)
+	if !found || outputName == "" {
+		return nil, ErrInvalidOutputReference
+	}
+	identifier, err := common.NewIdentifier(workflowId)
+	if err != nil || identifier.GetType() != common.WORKFLOW {
+		return nil, ErrInvalidOutputReference
+	}
+	return &OutputReference{
+		workflowIdentifier: identifier,
+		outputName:         outputName,
+	}, nil
+}
+
+// GetWorkflowIdentifier returns the identifier of the referenced workflow.
+func (r *OutputReference) GetWorkflowIdentifier() *common.Identifier {
+	return r.workflowIdentifier
+}
+
+// GetOutputName returns the name of the referenced workflow output.
+func (r *OutputReference) GetOutputName() string {
+	return r.outputName
+}
+
+// ToString returns the string representation of the OutputReference.
+func (r *OutputReference) ToString() string {
+	return OUTPUT_REFERENCE_PREFIX + r.workflowIdentifier.ToString() + outputReferenceSeparator + r.outputName + OUTPUT_REFERENCE_SUFFIX
+}
+
+// Resolve returns the value of the referenced output in the latest successful run of the workflow.
+// Returns an error if the workflow has no successful run, or if the output was not produced.
+func (r *OutputReference) Resolve(referenced *Workflow) (string, error) {
+	run := referenced.GetLatestSuccessfulRun()
+	if run == nil {
+		return "", ErrNoSuccessfulRun
+	}
+	value, found := run.outputs[r.outputName]
+	if !found {
+		return "", ErrUnknownWorkflowOutput
+	}
+	return value, nil
+}
+
+// ResolveOutputReferences replaces the values that are output references with the value of the referenced output,
+// loading the referenced workflows from the repository. Other values are kept as is.
+// Only workflows of the same project can be referenced and, unless reader is nil, the policies of the reader
+// must allow the workflow:Read action on each of them.
+//
+// Returns an AttributeValidationError listing the references that cannot be resolved.
+func (w *Workflow) ResolveOutputReferences(values map[string]string, workflows WorkflowRepository, reader *identity.User) (map[string]string, error) {
+	resolved := make(map[string]string, len(values))
+	fields := map[string]error{}
+	for name, value := range values {
+		if !IsOutputReference(value) {
+			resolved[name] = value
+			continue
+		}
+		reference, err := ParseOutputReference(value)
+		if err != nil {
+			fields[name] = err
+			continue
+		}
+		if err := w.checkReferenceProject(reference); err != nil {
+			fields[name] = err
+			continue
+		}
+		referenced, err := workflows.FindById(*reference.GetWorkflowIdentifier())
+		if err != nil {
+			return nil, err
+		}
+		if referenced == nil {
+			fields[name] = ErrReferencedWorkflowNotFound
+			continue
+		}
+		if reader != nil {
+			context := reader.NewRequestContext(time.Now())
+			context.SetResourceTags(referenced.ListTags())
+			if !reader.IsAllowed(referenced.GetIdentifier(), policy.READ_WORKFLOW, context) {
+				fields[name] = ErrReferenceNotAllowed
+				continue
+			}
+		}
+		resolved[name], err = reference.Resolve(referenced)
+		if err != nil {
+			fields[name] = err
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &AttributeValidationError{kind: "inputs", fields: fields}
+	}
+	return resolved, nil
+}
+
+// checkReferenceProject returns ErrReferenceOutsideProject if the output reference targets a workflow of another project.
+func (w *Workflow) checkReferenceProject(reference *OutputReference) error {
+	referencedProject, err := reference.GetWorkflowIdentifier().GetParent()
+	if err != nil {
+		return err
+	}
+	workflowProject, err := w.GetIdentifier().GetParent()
+	if err != nil {
+		return err
+	}
+	if referencedProject.ToString() != workflowProject.ToString() {
+		return ErrReferenceOutsideProject
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AttributeValidationError reports every invalid run input or output value, indexed by attribute name.
+type AttributeValidationError struct {
+	kind   string
+	fields map[string]error
+}
+
+// Error returns the list of invalid attributes with the reason they were rejected, sorted by attribute name.
+func (e *AttributeValidationError) Error() string {
+	names := make([]string, 0, len(e.fields))
+	for name := range e.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, e.fields[name].Error()))
+	}
+	return fmt.Sprintf("invalid workflow %s: %s", e.kind, strings.Join(messages, "; "))
+}
+
+// ListFieldErrors returns the error associated with each invalid attribute name.
+func (e *AttributeValidationError) ListFieldErrors() map[string]error {
+	fields := make(map[string]error, len(e.fields))
+	for name, err := range e.fields {
+		fields[name] = err
+	}
+	return fields
+}
+
+// ResolveInputs validates run-time values, indexed by input name, against the inputs declared by the workflow.
+// Each value must be valid for the type of its input, or be a secret reference for sensitive inputs.
+// Inputs without value take their default value.
+//
+// Returns the resolved values, or an AttributeValidationError listing unknown inputs, invalid values,
+// and inputs with neither a value nor a default value.
+func (w *Workflow) ResolveInputs(values map[string]string) (map[string]string, error) {
+	resolved := map[string]string{}
+	fields := map[string]error{}
+	declared := map[string]bool{}
+	for _, input := range w.inputs.Items() {
+		name := input.GetName()
+		declared[name] = true
+		value, provided := values[name]
+		switch {
+		case provided:
+			err := input.validateInputValue(value)
+			if err == nil && input.IsSensitive() {
+				err = w.checkSecretProject(value)
+			}
+			if err != nil {
+				fields[name] = err
+			} else {
+				resolved[name] = value
+			}
+		case input.GetDefaultValue() != "":
+			resolved[name] = input.GetDefaultValue()
+		default:
+			fields[name] = ErrMissingWorkflowInput
+		}
+	}
+	for name := range values {
+		if !declared[name] {
+			fields[name] = ErrUnknownWorkflowInput
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &AttributeValidationError{kind: "inputs", fields: fields}
+	}
+	return resolved, nil
+}
+
+// StartRun validates and resolves the run-time input values, then adds a new run of the workflow with the given mode.
+// The run waits for an approval right away if the first step of the workflow is an approval gate.
+//
+// Returns an AttributeValidationError if the values are invalid.
+func (w *Workflow) StartRun(name string, description string, mode RunMode, values map[string]string) (*WorkflowRun, error) {
+	inputs, err := w.ResolveInputs(values)
+	if err != nil {
+		return nil, err
+	}
+	run, err := NewWorkflowRun(w.GetIdentifier().ToString(), name, description)
+	if err != nil {
+		return nil, err
+	}
+	run.SetMode(mode)
+	run.SetInputs(inputs)
+	err = w.startRun(run)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
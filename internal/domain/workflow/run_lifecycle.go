@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"math"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+)
+
+// getStepAfter returns the step with the lowest step number greater than the given one, or nil if there is none.
+func (w *Workflow) getStepAfter(stepNumber int) *WorkflowStep {
+	var next *WorkflowStep
+	for _, step := range w.steps.Items() {
+		if step.GetStepNumber() > stepNumber && (next == nil || step.GetStepNumber() < next.GetStepNumber()) {
+			next = step
+		}
+	}
+	return next
+}
+
+// awaitApproval pauses the run if the step following the given step number is an approval gate.
+// PLAN_ONLY runs do not change anything and go past approval gates, the run applying their plan waiting on them instead.
+func (w *Workflow) awaitApproval(run *WorkflowRun, stepNumber int) error {
+	if run.GetMode() == PLAN_ONLY {
+		return nil
+	}
+	next := w.getStepAfter(stepNumber)
+	if next == nil || !next.IsApprovalGate() {
+		return nil
+	}
+	return run.RequestApproval(next)
+}
+
+// checkApprovals returns ErrApprovalRequired if an approval gate numbered below the given step number has not been approved for the run.
+func (w *Workflow) checkApprovals(run *WorkflowRun, stepNumber int) error {
+	if run.GetMode() == PLAN_ONLY {
+		return nil
+	}
+	for _, step := range w.steps.Items() {
+		if step.IsApprovalGate() && step.GetStepNumber() < stepNumber && !run.isApproved(step.GetStepNumber()) {
+			return ErrApprovalRequired
+		}
+	}
+	return nil
+}
+
+// CompleteStep records the outputs produced by a step of a run, and pauses the run if the next step is an approval gate.
+//
+// Returns ErrWorkflowRunNotFound or ErrWorkflowStepNotFound if the run or step does not exist, ErrRunAlreadyFinished if the run
+// succeeded or failed, ErrStepIsApprovalGate if the step is an approval gate, or ErrApprovalRequired if the run is waiting for
+// an approval or if an approval gate preceding the step has not been approved.
+func (w *Workflow) CompleteStep(runIdentifier string, stepNumber int, outputs map[string]string) error {
+	run := w.GetRun(runIdentifier)
+	if run == nil {
+		return ErrWorkflowRunNotFound
+	}
+	step, found := w.steps.SelectOne(func(s *WorkflowStep) bool {
+		return s.GetStepNumber() == stepNumber
+	})
+	if !found {
+		return ErrWorkflowStepNotFound
+	}
+	if run.GetStatus() == common.SUCCESS || run.GetStatus() == common.FAILURE {
+		return ErrRunAlreadyFinished
+	}
+	if step.IsApprovalGate() {
+		return ErrStepIsApprovalGate
+	}
+	if run.GetPendingApproval() != nil {
+		return ErrApprovalRequired
+	}
+	if err := w.checkApprovals(run, stepNumber); err != nil {
+		return err
+	}
+	run.RecordStepOutputs(stepNumber, outputs)
+	run.SetStatus(common.RUNNING)
+	return w.awaitApproval(run, stepNumber)
+}
+
+// RecordStepPlan saves the plan computed by the runner for a Terraform or OpenTofu step of a PLAN_ONLY run, see NewStepPlan.
+// The plan is bound to the current version of the workflow and to the inputs of the run, which its steps are executed with.
+//
+// Returns ErrWorkflowRunNotFound or ErrWorkflowStepNotFound if the run or step does not exist, ErrRunAlreadyFinished if the run
+// succeeded or failed, ErrRunIsNotAPlan if it is not a PLAN_ONLY run, or any error of NewStepPlan.
+func (w *Workflow) RecordStepPlan(runIdentifier string, stepNumber int, binaryPlan []byte, summary string, planJSON []byte) (*StepPlan, error) {
+	run := w.GetRun(runIdentifier)
+	if run == nil {
+		return nil, ErrWorkflowRunNotFound
+	}
+	step, found := w.steps.SelectOne(func(s *WorkflowStep) bool {
+		return s.GetStepNumber() == stepNumber
+	})
+	if !found {
+		return nil, ErrWorkflowStepNotFound
+	}
+	if run.GetStatus() == common.SUCCESS || run.GetStatus() == common.FAILURE {
+		return nil, ErrRunAlreadyFinished
+	}
+	if run.GetMode() != PLAN_ONLY {
+		return nil, ErrRunIsNotAPlan
+	}
+	plan, err := NewStepPlan(step, w.GetVersion(), run.GetInputs(), binaryPlan, summary, planJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := run.RecordPlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// SubmitApproval records the decision of a user on the pending approval of a run, see WorkflowRun.SubmitApproval.
+// Once approved, the run waits on the next step if it is an approval gate too.
+//
+// Returns ErrWorkflowRunNotFound if the run does not exist, or any error of WorkflowRun.SubmitApproval.
+func (w *Workflow) SubmitApproval(runIdentifier string, approver *identity.User, approved bool, comment string) error {
+	run := w.GetRun(runIdentifier)
+	if run == nil {
+		return ErrWorkflowRunNotFound
+	}
+	request := run.GetPendingApproval()
+	if err := run.SubmitApproval(approver, approved, comment); err != nil {
+		return err
+	}
+	if request.GetStatus() != common.SUCCESS {
+		return nil
+	}
+	return w.awaitApproval(run, request.GetStepNumber())
+}
+
+// startRun adds the run to the workflow, pausing it right away if the first step is an approval gate.
+func (w *Workflow) startRun(run *WorkflowRun) error {
+	if err := w.AddRun(run); err != nil {
+		return err
+	}
+	return w.awaitApproval(run, math.MinInt)
+}
@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func newWorkflowWithApprovalGates(t *testing.T, approvers ...*common.Identifier) *Workflow {
+	t.Helper()
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.zip")
+	workflowId := workflow.GetIdentifier().ToString()
+	gate, _ := NewApprovalGate(approvers, nil, 1, time.Hour)
+	first, _ := NewApprovalStep(workflowId, "approve-plan", "", 1, gate)
+	apply, _ := NewWorkflowStep(workflowId, "apply", "", 2, nil)
+	second, _ := NewApprovalStep(workflowId, "approve-prod", "", 3, gate)
+	third, _ := NewApprovalStep(workflowId, "approve-security", "", 4, gate)
+	deploy, _ := NewWorkflowStep(workflowId, "deploy", "", 5, nil)
+	for _, step := range []*WorkflowStep{first, apply, second, third, deploy} {
+		workflow.AddStep(step)
+	}
+	return workflow
+}
+
+func TestWorkflowRunLifecycle(t *testing.T) {
+	alice := newTestUser(t, "alice")
+	bob := newTestUser(t, "bob")
+	workflow := newWorkflowWithApprovalGates(t, alice.GetIdentifier(), bob.GetIdentifier())
+
+	run, err := workflow.StartRun("run", "", STANDARD, nil)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	runId := run.GetIdentifier().ToString()
+	run.SetStartedBy(bob.GetIdentifier())
+	if run.GetStatus() != common.WAITING || run.GetPendingApproval().GetStepNumber() != 1 {
+		t.Fatalf("expected run to wait on the first step, got %s", run.GetStatus().ToString())
+	}
+	if err := workflow.CompleteStep(runId, 2, nil); err != ErrApprovalRequired {
+		t.Errorf("expected err to be ErrApprovalRequired, got %v", err)
+	}
+	if err := workflow.CompleteRun(runId); err != ErrApprovalRequired {
+		t.Errorf("expected err to be ErrApprovalRequired, got %v", err)
+	}
+	if err := workflow.SubmitApproval(runId, bob, true, ""); err != ErrSelfApproval {
+		t.Errorf("expected err to be ErrSelfApproval, got %v", err)
+	}
+	if err := workflow.SubmitApproval(runId, alice, true, ""); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.RUNNING {
+		t.Errorf("expected run to be resumed, got %s", run.GetStatus().ToString())
+	}
+
+	if err := workflow.CompleteStep(runId, 1, nil); err != ErrStepIsApprovalGate {
+		t.Errorf("expected err to be ErrStepIsApprovalGate, got %v", err)
+	}
+	if err := workflow.CompleteStep(runId, 9, nil); err != ErrWorkflowStepNotFound {
+		t.Errorf("expected err to be ErrWorkflowStepNotFound, got %v", err)
+	}
+	if err := workflow.CompleteStep(runId, 5, nil); err != ErrApprovalRequired {
+		t.Errorf("expected err to be ErrApprovalRequired, got %v", err)
+	}
+	if err := workflow.CompleteStep(runId, 2, map[string]string{"id": "1"}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.WAITING || run.GetPendingApproval().GetStepNumber() != 3 {
+		t.Fatalf("expected run to wait on step 3, got %s", run.GetStatus().ToString())
+	}
+	if err := workflow.SubmitApproval(runId, alice, true, ""); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.WAITING || run.GetPendingApproval().GetStepNumber() != 4 {
+		t.Fatalf("expected run to wait on the chained step 4, got %s", run.GetStatus().ToString())
+	}
+	if err := workflow.SubmitApproval(runId, alice, true, ""); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.CompleteStep(runId, 5, nil); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.CompleteRun(runId); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.SUCCESS {
+		t.Errorf("expected run to succeed, got %s", run.GetStatus().ToString())
+	}
+	if err := workflow.CompleteStep(runId, 5, nil); err != ErrRunAlreadyFinished {
+		t.Errorf("expected err to be ErrRunAlreadyFinished, got %v", err)
+	}
+	if err := workflow.CompleteRun(runId); err != ErrRunAlreadyFinished {
+		t.Errorf("expected err to be ErrRunAlreadyFinished, got %v", err)
+	}
+}
+
+func TestWorkflowPlanRunSkipsApprovalGates(t *testing.T) {
+	alice := newTestUser(t, "alice")
+	workflow := newWorkflowWithApprovalGates(t, alice.GetIdentifier())
+
+	plan, _ := workflow.StartRun("plan", "", PLAN_ONLY, nil)
+	planId := plan.GetIdentifier().ToString()
+	if plan.GetPendingApproval() != nil {
+		t.Fatal("expected plan run not to wait for an approval")
+	}
+	if err := workflow.CompleteStep(planId, 2, nil); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.CompleteStep(planId, 5, nil); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.CompleteRun(planId); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+}
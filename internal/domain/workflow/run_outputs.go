@@ -0,0 +1,196 @@
+package workflow
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"math"
+)
+
+// OutputBinding maps a workflow output to an output produced by one of its steps.
+type OutputBinding struct {
+	stepNumber int
+	stepOutput string
+}
+
+// NewOutputBinding creates an OutputBinding to the output with the given name of the step with the given number.
+func NewOutputBinding(stepNumber int, stepOutput string) *OutputBinding {
+	return &OutputBinding{
+		stepNumber: stepNumber,
+		stepOutput: stepOutput,
+	}
+}
+
+// GetStepNumber returns the number of the step producing the output.
+func (b *OutputBinding) GetStepNumber() int {
+	return b.stepNumber
+}
+
+// GetStepOutput returns the name of the template output produced by the step.
+func (b *OutputBinding) GetStepOutput() string {
+	return b.stepOutput
+}
+
+// getOutput returns the declared output with the given name, or nil if not found.
+func (w *Workflow) getOutput(name string) *WorkflowAttribute {
+	output, _ := w.outputs.SelectOne(func(a *WorkflowAttribute) bool {
+		return a.GetName() == name
+	})
+	return output
+}
+
+// ListOutputBindings returns the binding of each bound workflow output, indexed by output name.
+func (w *Workflow) ListOutputBindings() map[string]*OutputBinding {
+	bindings := make(map[string]*OutputBinding, len(w.bindings))
+	for name, binding := range w.bindings {
+		bindings[name] = binding
+	}
+	return bindings
+}
+
+// SetOutputBindings replaces the output bindings of the workflow, typically when reloading it from a data store.
+func (w *Workflow) SetOutputBindings(bindings map[string]*OutputBinding) {
+	w.bindings = make(map[string]*OutputBinding, len(bindings))
+	for name, binding := range bindings {
+		w.bindings[name] = binding
+	}
+}
+
+// BindOutput maps the workflow output with the given name to an output of the step with the given number,
+// replacing any previous binding of the workflow output.
+//
+// Returns an error if the workflow output or the step does not exist, if the template of the step does not declare the step output,
+// or if the type of the step output cannot be assigned to the type of the workflow output (see common.AttributeType.IsAssignableTo).
+func (w *Workflow) BindOutput(outputName string, stepNumber int, stepOutput string) error {
+	output := w.getOutput(outputName)
+	if output == nil {
+		return ErrUnknownWorkflowOutput
+	}
+	step, found := w.steps.SelectOne(func(s *WorkflowStep) bool {
+		return s.GetStepNumber() == stepNumber
+	})
+	if !found || step.GetTask() == nil {
+		return ErrWorkflowStepNotFound
+	}
+	declared := step.getTaskOutput(stepOutput)
+	if declared == nil {
+		return ErrUnknownStepOutput
+	}
+	if !declared.GetType().IsAssignableTo(output.GetType()) {
+		return ErrIncompatibleOutputType
+	}
+	w.bindings[outputName] = NewOutputBinding(stepNumber, stepOutput)
+	return nil
+}
+
+// getTaskOutput returns the output with the given name declared by the template of the step, or nil if not found.
+func (s *WorkflowStep) getTaskOutput(name string) *template.TemplateAttribute {
+	if s.task == nil {
+		return nil
+	}
+	for _, output := range s.task.ListOutputs() {
+		if output.GetName() == name {
+			return output
+		}
+	}
+	return nil
+}
+
+// coerceStepOutput converts a value produced by a step output to the type of the workflow output it is bound to.
+// Values of step outputs that are no longer declared are only validated against the type of the workflow output.
+func (w *Workflow) coerceStepOutput(binding *OutputBinding, output *WorkflowAttribute, value string) (string, error) {
+	step, found := w.steps.SelectOne(func(s *WorkflowStep) bool {
+		return s.GetStepNumber() == binding.stepNumber
+	})
+	if !found || output.IsSensitive() {
+		return value, nil
+	}
+	declared := step.getTaskOutput(binding.stepOutput)
+	if declared == nil || declared.IsSensitive() {
+		return value, nil
+	}
+	return declared.GetType().Coerce(value, output.GetType())
+}
+
+// UnbindOutput removes the binding of the workflow output with the given name, if it exists.
+func (w *Workflow) UnbindOutput(outputName string) {
+	delete(w.bindings, outputName)
+}
+
+// CompleteRun resolves the outputs of a run once all its steps are done, and marks it as successful.
+// Each declared output takes the value produced by its bound step output, or its default value.
+// Values are converted from the type of the step output to the type of the workflow output, then validated against it, and the run fails if any of them is missing or invalid.
+// The values of sensitive outputs are masked, so they are never exposed nor used by other workflows.
+// PLAN_ONLY runs do not produce outputs.
+//
+// Returns ErrWorkflowRunNotFound if the run does not exist, ErrRunAlreadyFinished if it succeeded or failed,
+// ErrApprovalRequired if an approval gate of the workflow has not been approved for the run,
+// or an AttributeValidationError listing the invalid outputs.
+func (w *Workflow) CompleteRun(runIdentifier string) error {
+	run := w.GetRun(runIdentifier)
+	if run == nil {
+		return ErrWorkflowRunNotFound
+	}
+	if run.GetStatus() == common.SUCCESS || run.GetStatus() == common.FAILURE {
+		return ErrRunAlreadyFinished
+	}
+	if err := w.checkApprovals(run, math.MaxInt); err != nil {
+		return err
+	}
+	resolved := map[string]string{}
+	fields := map[string]error{}
+	if run.GetMode() != PLAN_ONLY {
+		for _, output := range w.outputs.Items() {
+			name := output.GetName()
+			value, produced := "", false
+			var err error
+			if binding, bound := w.bindings[name]; bound {
+				value, produced = run.stepOutputs[binding.stepNumber][binding.stepOutput]
+				if produced {
+					value, err = w.coerceStepOutput(binding, output, value)
+				}
+			}
+			switch {
+			case produced:
+				if err == nil {
+					err = output.ValidateValue(value)
+				}
+				if err != nil {
+					fields[name] = err
+				} else if output.IsSensitive() {
+					resolved[name] = secret.MASKED_VALUE
+				} else {
+					resolved[name] = value
+				}
+			case output.GetDefaultValue() != "":
+				resolved[name] = output.GetDefaultValue()
+			default:
+				fields[name] = ErrMissingStepOutput
+			}
+		}
+	}
+	if len(fields) > 0 {
+		run.SetStatus(common.FAILURE)
+		run.UpdateModificationDate()
+		return &AttributeValidationError{kind: "outputs", fields: fields}
+	}
+	run.SetOutputs(resolved)
+	run.SetStatus(common.SUCCESS)
+	run.UpdateModificationDate()
+	return nil
+}
+
+// GetLatestSuccessfulRun returns the most recently completed successful run producing outputs, or nil if there is none.
+// PLAN_ONLY runs are ignored since they do not produce outputs.
+func (w *Workflow) GetLatestSuccessfulRun() *WorkflowRun {
+	var latest *WorkflowRun
+	for _, run := range w.runs.Items() {
+		if run.GetStatus() != common.SUCCESS || run.GetMode() == PLAN_ONLY {
+			continue
+		}
+		if latest == nil || run.GetUpdatedAt() > latest.GetUpdatedAt() {
+			latest = run
+		}
+	}
+	return latest
+}
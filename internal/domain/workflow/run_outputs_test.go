@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+func newWorkflowWithBoundOutput(t *testing.T) *Workflow {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.zip")
+	vpc, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 1)
+	vpcId, _ := template.NewTemplateAttribute(vpc.GetIdentifier().ToString(), "vpc_id", "", template.STRING, "")
+	vpc.AddOutput(vpcId)
+	step, _ := NewWorkflowStep(workflow.GetIdentifier().ToString(), "vpc", "", 1, vpc)
+	workflow.AddStep(step)
+	output, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "vpc_id", "", STRING, "")
+	workflow.AddOutput(output)
+	subnets, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "subnet_count", "", NUMBER, "2")
+	workflow.AddOutput(subnets)
+	if err := workflow.BindOutput("vpc_id", 1, "vpc_id"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	return workflow
+}
+
+func TestWorkflowBindOutput(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+
+	if err := workflow.BindOutput("unknown", 1, "vpc_id"); err != ErrUnknownWorkflowOutput {
+		t.Errorf("expected err to be ErrUnknownWorkflowOutput, got %v", err)
+	}
+	if err := workflow.BindOutput("vpc_id", 2, "vpc_id"); err != ErrWorkflowStepNotFound {
+		t.Errorf("expected err to be ErrWorkflowStepNotFound, got %v", err)
+	}
+	if err := workflow.BindOutput("vpc_id", 1, "unknown"); err != ErrUnknownStepOutput {
+		t.Errorf("expected err to be ErrUnknownStepOutput, got %v", err)
+	}
+	if err := workflow.BindOutput("subnet_count", 1, "vpc_id"); err != ErrIncompatibleOutputType {
+		t.Errorf("expected err to be ErrIncompatibleOutputType, got %v", err)
+	}
+	binding := workflow.ListOutputBindings()["vpc_id"]
+	if binding == nil || binding.GetStepNumber() != 1 || binding.GetStepOutput() != "vpc_id" {
+		t.Errorf("expected vpc_id to be bound to step 1, got %v", binding)
+	}
+
+	workflow.UnbindOutput("vpc_id")
+	if len(workflow.ListOutputBindings()) != 0 {
+		t.Errorf("expected 0 bindings, got %d", len(workflow.ListOutputBindings()))
+	}
+}
+
+func TestWorkflowCompleteRun(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+
+	if err := workflow.CompleteRun("autops::project:ABCDEFGHIJ:workflow:1234567890:run:unknown123"); err != ErrWorkflowRunNotFound {
+		t.Errorf("expected err to be ErrWorkflowRunNotFound, got %v", err)
+	}
+
+	failed, _ := workflow.StartRun("failed", "", STANDARD, nil)
+	err := workflow.CompleteRun(failed.GetIdentifier().ToString())
+	validationErr, ok := err.(*AttributeValidationError)
+	if !ok || validationErr.ListFieldErrors()["vpc_id"] != ErrMissingStepOutput {
+		t.Errorf("expected err to report ErrMissingStepOutput for vpc_id, got %v", err)
+	}
+	if failed.GetStatus() != common.FAILURE {
+		t.Errorf("expected run status to be FAILURE, got %s", failed.GetStatus().ToString())
+	}
+
+	run, _ := workflow.StartRun("run", "", STANDARD, nil)
+	run.RecordStepOutputs(1, map[string]string{"vpc_id": "vpc-0123"})
+	if err := workflow.CompleteRun(run.GetIdentifier().ToString()); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	outputs := run.GetOutputs()
+	if run.GetStatus() != common.SUCCESS || outputs["vpc_id"] != "vpc-0123" || outputs["subnet_count"] != "2" {
+		t.Errorf("expected resolved outputs, got %s %v", run.GetStatus().ToString(), outputs)
+	}
+	if workflow.GetLatestSuccessfulRun() != run {
+		t.Error("expected run to be the latest successful run")
+	}
+
+	plan, _ := workflow.StartRun("plan", "", PLAN_ONLY, nil)
+	if err := workflow.CompleteRun(plan.GetIdentifier().ToString()); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if len(plan.GetOutputs()) != 0 || workflow.GetLatestSuccessfulRun() != run {
+		t.Error("expected plan runs to produce no outputs")
+	}
+}
+
+func TestWorkflowCompleteRunCoercion(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+	step := workflow.ListSteps()[0]
+	public, _ := template.NewTemplateAttribute(step.GetTask().GetIdentifier().ToString(), "public", "", template.BOOL, "")
+	step.GetTask().AddOutput(public)
+	output, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "public", "", STRING, "")
+	workflow.AddOutput(output)
+	if err := workflow.BindOutput("public", 1, "public"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	run, _ := workflow.StartRun("run", "", STANDARD, nil)
+	run.RecordStepOutputs(1, map[string]string{"vpc_id": "vpc-0123", "public": "1"})
+	if err := workflow.CompleteRun(run.GetIdentifier().ToString()); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if outputs := run.GetOutputs(); outputs["public"] != "true" {
+		t.Errorf("expected public to be coerced to true, got %q", outputs["public"])
+	}
+
+	invalid, _ := workflow.StartRun("invalid", "", STANDARD, nil)
+	invalid.RecordStepOutputs(1, map[string]string{"vpc_id": "vpc-0123", "public": "maybe"})
+	err := workflow.CompleteRun(invalid.GetIdentifier().ToString())
+	validationErr, ok := err.(*AttributeValidationError)
+	if !ok || validationErr.ListFieldErrors()["public"] == nil {
+		t.Errorf("expected err to report public, got %v", err)
+	}
+}
+
+func TestOutputReference(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+	value := "${" + workflow.GetIdentifier().ToString() + ".outputs.vpc_id}"
+
+	invalid := []string{"vpc_id", "${vpc_id}", "${" + workflow.GetIdentifier().ToString() + "}", "${autops::project:ABCDEFGHIJ.outputs.vpc_id}"}
+	for _, str := range invalid {
+		if _, err := ParseOutputReference(str); err != ErrInvalidOutputReference {
+			t.Errorf("expected err to be ErrInvalidOutputReference for %s, got %v", str, err)
+		}
+	}
+	reference, err := ParseOutputReference(value)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if reference.ToString() != value || reference.GetOutputName() != "vpc_id" {
+		t.Errorf("expected %s, got %s", value, reference.ToString())
+	}
+
+	if _, err := reference.Resolve(workflow); err != ErrNoSuccessfulRun {
+		t.Errorf("expected err to be ErrNoSuccessfulRun, got %v", err)
+	}
+	run, _ := workflow.StartRun("run", "", STANDARD, nil)
+	run.RecordStepOutputs(1, map[string]string{"vpc_id": "vpc-0123"})
+	workflow.CompleteRun(run.GetIdentifier().ToString())
+	resolved, err := reference.Resolve(workflow)
+	if err != nil || resolved != "vpc-0123" {
+		t.Errorf("expected vpc-0123, got %s (%v)", resolved, err)
+	}
+}
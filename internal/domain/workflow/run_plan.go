@@ -0,0 +1,292 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// RunMode defines how the steps of a WorkflowRun are executed.
+type RunMode int
+
+const (
+	// STANDARD executes every step directly.
+	STANDARD RunMode = iota
+	// PLAN_ONLY only computes the execution plan of Terraform and OpenTofu steps, without applying it.
+	PLAN_ONLY
+	// APPLY_PLAN applies the plans saved by a previous PLAN_ONLY run.
+	APPLY_PLAN
+)
+
+// ToString returns the string representation of the RunMode.
+func (m RunMode) ToString() string {
+	switch m {
+	case PLAN_ONLY:
+		return "plan"
+	case APPLY_PLAN:
+		return "apply"
+	default:
+		return "standard"
+	}
+}
+
+// ParseRunMode converts a string to a RunMode.
+// Returns an error if the string does not match a known mode.
+func ParseRunMode(str string) (RunMode, error) {
+	switch strings.ToLower(str) {
+	case "standard":
+		return STANDARD, nil
+	case "plan":
+		return PLAN_ONLY, nil
+	case "apply":
+		return APPLY_PLAN, nil
+	default:
+		return -1, ErrInvalidRunMode
+	}
+}
+
+// ResourceChangeAction defines the action planned on an infrastructure resource.
+type ResourceChangeAction int
+
+const (
+	NO_OP ResourceChangeAction = iota
+	CREATE
+	READ
+	UPDATE
+	DELETE
+	REPLACE
+)
+
+// ToString returns the string representation of the ResourceChangeAction.
+func (a ResourceChangeAction) ToString() string {
+	switch a {
+	case CREATE:
+		return "create"
+	case READ:
+		return "read"
+	case UPDATE:
+		return "update"
+	case DELETE:
+		return "delete"
+	case REPLACE:
+		return "replace"
+	default:
+		return "no-op"
+	}
+}
+
+// parseTerraformActions converts the action list of a Terraform resource change to a ResourceChangeAction.
+// A delete combined with a create, in any order, is a replacement.
+func parseTerraformActions(actions []string) (ResourceChangeAction, error) {
+	if len(actions) == 2 && ((actions[0] == "delete" && actions[1] == "create") || (actions[0] == "create" && actions[1] == "delete")) {
+		return REPLACE, nil
+	}
+	if len(actions) != 1 {
+		return -1, ErrInvalidPlanFormat
+	}
+	switch actions[0] {
+	case "no-op":
+		return NO_OP, nil
+	case "create":
+		return CREATE, nil
+	case "read":
+		return READ, nil
+	case "update":
+		return UPDATE, nil
+	case "delete":
+		return DELETE, nil
+	default:
+		return -1, ErrInvalidPlanFormat
+	}
+}
+
+// ResourceChange represents the change planned on a single infrastructure resource.
+type ResourceChange struct {
+	address      string
+	resourceType string
+	action       ResourceChangeAction
+}
+
+// NewResourceChange creates a ResourceChange.
+func NewResourceChange(address string, resourceType string, action ResourceChangeAction) *ResourceChange {
+	return &ResourceChange{
+		address:      address,
+		resourceType: resourceType,
+		action:       action,
+	}
+}
+
+// GetAddress returns the address of the resource (e.g. module.vpc.aws_subnet.private[0]).
+func (c *ResourceChange) GetAddress() string {
+	return c.address
+}
+
+// GetResourceType returns the type of the resource (e.g. aws_subnet).
+func (c *ResourceChange) GetResourceType() string {
+	return c.resourceType
+}
+
+// GetAction returns the action planned on the resource.
+func (c *ResourceChange) GetAction() ResourceChangeAction {
+	return c.action
+}
+
+// terraformPlan is the subset of the `terraform show -json` output describing resource changes.
+type terraformPlan struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Type    string `json:"type"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// ParseTerraformPlanJSON extracts the resource changes from the JSON representation of a plan,
+// as produced by `terraform show -json` or `tofu show -json`.
+func ParseTerraformPlanJSON(planJSON []byte) ([]*ResourceChange, error) {
+	var plan terraformPlan
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, ErrInvalidPlanFormat
+	}
+	changes := []*ResourceChange{}
+	for _, resource := range plan.ResourceChanges {
+		action, err := parseTerraformActions(resource.Change.Actions)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, NewResourceChange(resource.Address, resource.Type, action))
+	}
+	return changes, nil
+}
+
+// DigestInputs returns a stable digest of input values, used to detect changes between a plan and its application.
+func DigestInputs(inputs map[string]string) string {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		encoded, _ := json.Marshal([]string{name, inputs[name]})
+		hash.Write(encoded)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// canPlan returns true if the task is a Terraform or OpenTofu template, whose execution plan can be saved and applied later.
+func canPlan(task *template.Template) bool {
+	return task != nil && (task.GetTemplateType() == template.TERRAFORM || task.GetTemplateType() == template.OPENTOFU)
+}
+
+// StepPlan is the execution plan saved for a Terraform or OpenTofu step during a PLAN_ONLY run.
+// It records the workflow version, the template version and the inputs it was computed with, so that it is only applied if none changed.
+type StepPlan struct {
+	stepNumber         int
+	workflowVersion    int
+	templateIdentifier *common.Identifier
+	templateVersion    int
+	inputsDigest       string
+	binaryPlan         []byte
+	summary            string
+	changes            []*ResourceChange
+}
+
+// NewStepPlan creates a StepPlan for the step of the given workflow version, computed with the given step inputs.
+// binaryPlan is the saved plan file, summary its human-readable output, and planJSON its JSON representation.
+//
+// Returns an error if the step task is not a Terraform or OpenTofu template, or if planJSON is invalid.
+func NewStepPlan(step *WorkflowStep, workflowVersion int, stepInputs map[string]string, binaryPlan []byte, summary string, planJSON []byte) (*StepPlan, error) {
+	task := step.GetTask()
+	if !canPlan(task) {
+		return nil, ErrPlanUnsupportedTemplateType
+	}
+	changes, err := ParseTerraformPlanJSON(planJSON)
+	if err != nil {
+		return nil, err
+	}
+	return ExistingStepPlan(step.GetStepNumber(), workflowVersion, task.GetIdentifier(), task.GetVersion(), DigestInputs(stepInputs), binaryPlan, summary, changes), nil
+}
+
+// ExistingStepPlan creates a StepPlan from persisted data.
+func ExistingStepPlan(stepNumber int, workflowVersion int, templateIdentifier *common.Identifier, templateVersion int, inputsDigest string, binaryPlan []byte, summary string, changes []*ResourceChange) *StepPlan {
+	return &StepPlan{
+		stepNumber:         stepNumber,
+		workflowVersion:    workflowVersion,
+		templateIdentifier: templateIdentifier,
+		templateVersion:    templateVersion,
+		inputsDigest:       inputsDigest,
+		binaryPlan:         append([]byte(nil), binaryPlan...),
+		summary:            summary,
+		changes:            append([]*ResourceChange(nil), changes...),
+	}
+}
+
+// GetStepNumber returns the number of the planned step.
+func (p *StepPlan) GetStepNumber() int {
+	return p.stepNumber
+}
+
+// GetWorkflowVersion returns the version of the workflow the plan was computed with.
+func (p *StepPlan) GetWorkflowVersion() int {
+	return p.workflowVersion
+}
+
+// GetTemplateIdentifier returns the identifier of the template the plan was computed with.
+func (p *StepPlan) GetTemplateIdentifier() *common.Identifier {
+	return p.templateIdentifier
+}
+
+// GetTemplateVersion returns the version of the template the plan was computed with.
+func (p *StepPlan) GetTemplateVersion() int {
+	return p.templateVersion
+}
+
+// GetInputsDigest returns the digest of the step inputs the plan was computed with.
+func (p *StepPlan) GetInputsDigest() string {
+	return p.inputsDigest
+}
+
+// GetBinaryPlan returns a copy of the saved plan file.
+func (p *StepPlan) GetBinaryPlan() []byte {
+	return append([]byte(nil), p.binaryPlan...)
+}
+
+// GetSummary returns the human-readable output of the plan.
+func (p *StepPlan) GetSummary() string {
+	return p.summary
+}
+
+// ListChanges returns the resource changes of the plan.
+func (p *StepPlan) ListChanges() []*ResourceChange {
+	return append([]*ResourceChange(nil), p.changes...)
+}
+
+// CountChanges returns the number of planned resource changes for each action.
+func (p *StepPlan) CountChanges() map[ResourceChangeAction]int {
+	counts := map[ResourceChangeAction]int{}
+	for _, change := range p.changes {
+		counts[change.action]++
+	}
+	return counts
+}
+
+// matchesTemplate returns true if the task is the same template, at the same version, as the one the plan was computed with.
+func (p *StepPlan) matchesTemplate(task *template.Template) bool {
+	return task != nil && task.GetIdentifier().ToString() == p.templateIdentifier.ToString() && task.GetVersion() == p.templateVersion
+}
+
+// Verify checks that the plan can be applied with the given workflow version, task and step inputs.
+// Returns ErrPlanOutdated if the workflow version, the template version or the inputs changed since the plan was computed.
+func (p *StepPlan) Verify(workflowVersion int, task *template.Template, stepInputs map[string]string) error {
+	if workflowVersion != p.workflowVersion || !p.matchesTemplate(task) || DigestInputs(stepInputs) != p.inputsDigest {
+		return ErrPlanOutdated
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+const testPlanJSON = `{
+	"format_version": "1.2",
+	"resource_changes": [
+		{"address": "aws_vpc.main", "type": "aws_vpc", "change": {"actions": ["no-op"]}},
+		{"address": "aws_subnet.private[0]", "type": "aws_subnet", "change": {"actions": ["create"]}},
+		{"address": "aws_subnet.private[1]", "type": "aws_subnet", "change": {"actions": ["create"]}},
+		{"address": "aws_instance.web", "type": "aws_instance", "change": {"actions": ["delete", "create"]}},
+		{"address": "aws_eip.old", "type": "aws_eip", "change": {"actions": ["delete"]}}
+	]
+}`
+
+func TestRunMode(t *testing.T) {
+	for _, mode := range []RunMode{STANDARD, PLAN_ONLY, APPLY_PLAN} {
+		parsed, err := ParseRunMode(mode.ToString())
+		if err != nil || parsed != mode {
+			t.Errorf("expected %s to be parsed back, got %d (%v)", mode.ToString(), parsed, err)
+		}
+	}
+	if _, err := ParseRunMode("destroy"); err != ErrInvalidRunMode {
+		t.Errorf("expected err to be ErrInvalidRunMode, got %v", err)
+	}
+}
+
+func TestParseTerraformPlanJSON(t *testing.T) {
+	changes, err := ParseTerraformPlanJSON([]byte(testPlanJSON))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expected := []ResourceChangeAction{NO_OP, CREATE, CREATE, REPLACE, DELETE}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d", len(expected), len(changes))
+	}
+	for i, action := range expected {
+		if changes[i].GetAction() != action {
+			t.Errorf("expected change %d to be %s, got %s", i, action.ToString(), changes[i].GetAction().ToString())
+		}
+	}
+	if changes[1].GetAddress() != "aws_subnet.private[0]" || changes[1].GetResourceType() != "aws_subnet" {
+		t.Errorf("unexpected change %+v", changes[1])
+	}
+
+	if _, err := ParseTerraformPlanJSON([]byte("not json")); err != ErrInvalidPlanFormat {
+		t.Errorf("expected err to be ErrInvalidPlanFormat, got %v", err)
+	}
+	if _, err := ParseTerraformPlanJSON([]byte(`{"resource_changes": [{"change": {"actions": ["explode"]}}]}`)); err != ErrInvalidPlanFormat {
+		t.Errorf("expected err to be ErrInvalidPlanFormat, got %v", err)
+	}
+}
+
+func TestStepPlan(t *testing.T) {
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	terraform, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 3)
+	ansible, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:0987654321", "config", "", common.SUCCESS, template.ANSIBLE, "path/to/config.zip", 1)
+	ansibleStep, _ := NewWorkflowStep(workflowId, "configure", "", 2, ansible)
+	step, _ := NewWorkflowStep(workflowId, "network", "", 1, terraform)
+	inputs := map[string]string{"cidr": "10.0.0.0/16", "name": "main"}
+
+	if _, err := NewStepPlan(ansibleStep, 2, inputs, nil, "", []byte(testPlanJSON)); err != ErrPlanUnsupportedTemplateType {
+		t.Errorf("expected err to be ErrPlanUnsupportedTemplateType, got %v", err)
+	}
+	plan, err := NewStepPlan(step, 2, inputs, []byte{0x50, 0x4b}, "Plan: 2 to add, 0 to change, 2 to destroy.", []byte(testPlanJSON))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if plan.GetTemplateVersion() != 3 || plan.GetWorkflowVersion() != 2 || plan.GetStepNumber() != 1 || len(plan.GetBinaryPlan()) != 2 {
+		t.Error("unexpected plan attributes")
+	}
+	counts := plan.CountChanges()
+	if counts[CREATE] != 2 || counts[REPLACE] != 1 || counts[DELETE] != 1 || counts[NO_OP] != 1 {
+		t.Errorf("unexpected change counts %v", counts)
+	}
+
+	if err := plan.Verify(2, terraform, map[string]string{"name": "main", "cidr": "10.0.0.0/16"}); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := plan.Verify(3, terraform, inputs); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for new workflow version, got %v", err)
+	}
+	if err := plan.Verify(2, terraform, map[string]string{"name": "main", "cidr": "10.1.0.0/16"}); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for changed inputs, got %v", err)
+	}
+	upgraded, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 4)
+	if err := plan.Verify(2, upgraded, inputs); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for new template version, got %v", err)
+	}
+}
+
+func TestWorkflowRecordStepPlan(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.zip")
+	terraform, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 1)
+	step, _ := NewWorkflowStep(workflow.GetIdentifier().ToString(), "network", "", 1, terraform)
+	workflow.AddStep(step)
+	cidr, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "cidr", "", STRING, "10.0.0.0/16")
+	workflow.AddInput(cidr)
+
+	standardRun, _ := workflow.StartRun("standard", "", STANDARD, nil)
+	if _, err := workflow.RecordStepPlan(standardRun.GetIdentifier().ToString(), 1, nil, "", []byte(testPlanJSON)); err != ErrRunIsNotAPlan {
+		t.Errorf("expected err to be ErrRunIsNotAPlan, got %v", err)
+	}
+	planRun, _ := workflow.StartRun("plan", "", PLAN_ONLY, nil)
+	planRunId := planRun.GetIdentifier().ToString()
+	if _, err := workflow.RecordStepPlan(planRunId, 2, nil, "", []byte(testPlanJSON)); err != ErrWorkflowStepNotFound {
+		t.Errorf("expected err to be ErrWorkflowStepNotFound, got %v", err)
+	}
+	plan, err := workflow.RecordStepPlan(planRunId, 1, []byte{0x50, 0x4b}, "", []byte(testPlanJSON))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if planRun.GetPlan(1) != plan || plan.GetInputsDigest() != DigestInputs(map[string]string{"cidr": "10.0.0.0/16"}) {
+		t.Error("expected the plan to be recorded with the inputs of the run")
+	}
+
+	planRun.SetStatus(common.SUCCESS)
+	if _, err := workflow.RecordStepPlan(planRunId, 1, nil, "", []byte(testPlanJSON)); err != ErrRunAlreadyFinished {
+		t.Errorf("expected err to be ErrRunAlreadyFinished, got %v", err)
+	}
+	if _, err := workflow.ApplyPlan(planRunId); err != nil {
+		t.Errorf("expected the recorded plan to be applicable, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+)
+
+// checkSecretProject returns ErrSecretOutsideProject if the secret reference targets a secret of another project.
+func (w *Workflow) checkSecretProject(reference string) error {
+	secretIdentifier, err := secret.ParseReference(reference)
+	if err != nil {
+		return err
+	}
+	secretProject, err := secretIdentifier.GetParent()
+	if err != nil {
+		return err
+	}
+	workflowProject, err := w.GetIdentifier().GetParent()
+	if err != nil {
+		return err
+	}
+	if secretProject.ToString() != workflowProject.ToString() {
+		return ErrSecretOutsideProject
+	}
+	return nil
+}
+
+// ResolveSecrets loads and decrypts the secrets referenced by the sensitive inputs of a run,
+// grouping them into the Injection provided to its executors.
+//
+// Returns an error if the run does not exist, or if a referenced secret cannot be found or decrypted.
+func (w *Workflow) ResolveSecrets(runIdentifier string, secrets secret.SecretRepository, key *secret.MasterKey) (*secret.Injection, error) {
+	run := w.GetRun(runIdentifier)
+	if run == nil {
+		return nil, ErrWorkflowRunNotFound
+	}
+	injection := secret.NewInjection()
+	for _, input := range w.inputs.Items() {
+		reference, found := run.inputs[input.GetName()]
+		if !input.IsSensitive() || !found {
+			continue
+		}
+		if err := w.checkSecretProject(reference); err != nil {
+			return nil, err
+		}
+		secretIdentifier, _ := secret.ParseReference(reference)
+		referenced, err := secrets.FindById(*secretIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		if referenced == nil {
+			return nil, secret.ErrSecretNotFound
+		}
+		if err := injection.Add(referenced, key); err != nil {
+			return nil, err
+		}
+	}
+	return injection, nil
+}
@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+)
+
+// fakeSecretRepository is an in-memory secret.SecretRepository.
+type fakeSecretRepository map[string]*secret.Secret
+
+func (f fakeSecretRepository) Create(s *secret.Secret) error {
+	f[s.GetIdentifier().ToString()] = s
+	return nil
+}
+
+func (f fakeSecretRepository) Update(s *secret.Secret) error {
+	f[s.GetIdentifier().ToString()] = s
+	return nil
+}
+
+func (f fakeSecretRepository) Delete(secretId common.Identifier) error {
+	delete(f, secretId.ToString())
+	return nil
+}
+
+func (f fakeSecretRepository) FindById(secretId common.Identifier) (*secret.Secret, error) {
+	return f[secretId.ToString()], nil
+}
+
+func (f fakeSecretRepository) FindByProject(projectId common.Identifier, offset int, limit int) ([]*secret.Secret, error) {
+	return nil, nil
+}
+
+func TestWorkflowSensitiveInputs(t *testing.T) {
+	key, _ := secret.NewMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.MASTER_KEY_LENGTH)))
+	password, _ := secret.NewSecret("autops::project:ABCDEFGHIJ", "db", "", secret.ENV_VAR, "DB_PASSWORD", []byte("hunter2"), key)
+	foreign, _ := secret.NewSecret("autops::project:KLMNOPQRST", "db", "", secret.ENV_VAR, "DB_PASSWORD", []byte("hunter3"), key)
+	secrets := fakeSecretRepository{}
+	secrets.Create(password)
+	secrets.Create(foreign)
+
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "database", "", "/path/to/file.zip")
+	input, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "password", "", STRING, "")
+	input.SetSensitive(true)
+	workflow.AddInput(input)
+	region, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "region", "", STRING, "")
+	workflow.AddInput(region)
+
+	invalid := map[string]map[string]string{
+		"password": {"password": "hunter2", "region": "eu-west-1"},
+		"region":   {"password": secret.FormatReference(password.GetIdentifier()), "region": secret.FormatReference(password.GetIdentifier())},
+	}
+	expected := map[string]error{"password": ErrSensitiveValueNotASecret, "region": ErrSecretReferenceNotSensitive}
+	for name, values := range invalid {
+		_, err := workflow.StartRun("run", "", STANDARD, values)
+		validationErr, ok := err.(*AttributeValidationError)
+		if !ok || validationErr.ListFieldErrors()[name] != expected[name] {
+			t.Errorf("expected err to report %v for %s, got %v", expected[name], name, err)
+		}
+	}
+	_, err := workflow.StartRun("run", "", STANDARD, map[string]string{"password": secret.FormatReference(foreign.GetIdentifier()), "region": "eu-west-1"})
+	if validationErr, ok := err.(*AttributeValidationError); !ok || validationErr.ListFieldErrors()["password"] != ErrSecretOutsideProject {
+		t.Errorf("expected err to report ErrSecretOutsideProject, got %v", err)
+	}
+
+	run, err := workflow.StartRun("run", "", STANDARD, map[string]string{"password": secret.FormatReference(password.GetIdentifier()), "region": "eu-west-1"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	injection, err := workflow.ResolveSecrets(run.GetIdentifier().ToString(), secrets, key)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if injection.GetEnv()["DB_PASSWORD"] != "hunter2" || len(injection.GetEnv()) != 1 {
+		t.Errorf("expected DB_PASSWORD to be injected, got %v", injection.GetEnv())
+	}
+
+	secrets.Delete(*password.GetIdentifier())
+	if _, err := workflow.ResolveSecrets(run.GetIdentifier().ToString(), secrets, key); err != secret.ErrSecretNotFound {
+		t.Errorf("expected err to be ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestWorkflowSensitiveOutputs(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+	for _, output := range workflow.ListOutputs() {
+		if output.GetName() == "vpc_id" {
+			output.SetSensitive(true)
+		}
+	}
+	run, _ := workflow.StartRun("run", "", STANDARD, nil)
+	run.RecordStepOutputs(1, map[string]string{"vpc_id": "vpc-0123"})
+	workflow.CompleteRun(run.GetIdentifier().ToString())
+	if run.GetOutputs()["vpc_id"] != secret.MASKED_VALUE {
+		t.Errorf("expected sensitive output to be masked, got %v", run.GetOutputs())
+	}
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// UpgradeWarning reports a breaking change that upgrading the task of a step to another template version would introduce.
+type UpgradeWarning struct {
+	stepNumber int
+	change     *template.ClassifiedChange
+	message    string
+}
+
+// GetStepNumber returns the number of the step whose task would be upgraded.
+func (w *UpgradeWarning) GetStepNumber() int {
+	return w.stepNumber
+}
+
+// GetChange returns the breaking change of the template.
+func (w *UpgradeWarning) GetChange() *template.ClassifiedChange {
+	return w.change
+}
+
+// GetMessage returns a description of the warning.
+func (w *UpgradeWarning) GetMessage() string {
+	return w.message
+}
+
+// CheckTemplateUpgrade lists the breaking changes that upgrading to the candidate version would introduce
+// in the steps whose version range accepts it.
+// Input changes always break the step, while output changes only break it if the output is bound to a workflow output,
+// and type changes only if the new type cannot be assigned to the type of the workflow output.
+func (w *Workflow) CheckTemplateUpgrade(candidate *template.Template) []*UpgradeWarning {
+	warnings := []*UpgradeWarning{}
+	for _, step := range w.ListSteps() {
+		if step.versionRange == nil || !step.AcceptsTaskVersion(candidate) {
+			continue
+		}
+		for _, change := range template.ClassifyChange(step.task, candidate).ListBreakingChanges() {
+			if !change.IsOutput() {
+				warnings = append(warnings, &UpgradeWarning{
+					stepNumber: step.stepNumber,
+					change:     change,
+					message:    fmt.Sprintf("step %d: input %q: %s", step.stepNumber, change.GetName(), change.GetReason()),
+				})
+				continue
+			}
+			for _, outputName := range w.listOutputsBoundTo(step.stepNumber, change.GetName()) {
+				if change.GetField() == "type" && candidateOutputType(candidate, change.GetName()).IsAssignableTo(w.getOutput(outputName).GetType()) {
+					continue
+				}
+				warnings = append(warnings, &UpgradeWarning{
+					stepNumber: step.stepNumber,
+					change:     change,
+					message:    fmt.Sprintf("step %d: output %q bound to workflow output %q: %s", step.stepNumber, change.GetName(), outputName, change.GetReason()),
+				})
+			}
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].stepNumber < warnings[j].stepNumber
+	})
+	return warnings
+}
+
+// listOutputsBoundTo returns the names of the workflow outputs bound to the given output of a step, sorted by name.
+func (w *Workflow) listOutputsBoundTo(stepNumber int, stepOutput string) []string {
+	names := []string{}
+	for name, binding := range w.bindings {
+		if binding.stepNumber == stepNumber && binding.stepOutput == stepOutput {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// candidateOutputType returns the type of the output with the given name declared by the template, or an invalid type if not found.
+func candidateOutputType(candidate *template.Template, name string) common.AttributeType {
+	for _, output := range candidate.ListOutputs() {
+		if output.GetName() == name {
+			return output.GetType()
+		}
+	}
+	return -1
+}
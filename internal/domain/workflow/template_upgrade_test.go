@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// newTemplateVersion creates a version of the vpc template of newWorkflowWithBoundOutput with the given semantic version and outputs.
+func newTemplateVersion(version int, semanticVersion string, outputs ...string) *template.Template {
+	vpc, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", version)
+	parsed, _ := common.ParseSemanticVersion(semanticVersion)
+	vpc.SetSemanticVersion(parsed)
+	for _, name := range outputs {
+		output, _ := template.NewTemplateAttribute(vpc.GetIdentifier().ToString(), name, "", template.STRING, "")
+		vpc.AddOutput(output)
+	}
+	return vpc
+}
+
+func TestWorkflowStepVersionRange(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+	step := workflow.ListSteps()[0]
+	caret, _ := common.ParseVersionRange("^1.0.0")
+	if err := step.SetVersionRange(caret); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	outside, _ := common.ParseVersionRange("^2.0.0")
+	if err := step.SetVersionRange(outside); err != ErrTemplateVersionOutOfRange {
+		t.Errorf("expected err to be ErrTemplateVersionOutOfRange, got %v", err)
+	}
+
+	versions := []*template.Template{
+		newTemplateVersion(2, "1.1.0", "vpc_id"),
+		newTemplateVersion(3, "1.3.0-rc.1", "vpc_id"),
+		newTemplateVersion(4, "1.2.0", "vpc_id"),
+		newTemplateVersion(5, "2.0.0"),
+	}
+	selected, err := step.SelectTaskVersion(versions)
+	if err != nil || selected.GetVersion() != 4 {
+		t.Errorf("expected version 4 to be selected, got %v (%v)", selected, err)
+	}
+	step.SetVersionRange(nil)
+	if _, err := step.SelectTaskVersion(versions); err != ErrNoMatchingTemplateVersion {
+		t.Errorf("expected err to be ErrNoMatchingTemplateVersion, got %v", err)
+	}
+}
+
+func TestWorkflowCheckTemplateUpgrade(t *testing.T) {
+	workflow := newWorkflowWithBoundOutput(t)
+	step := workflow.ListSteps()[0]
+	anyVersion, _ := common.ParseVersionRange("*")
+	step.SetVersionRange(anyVersion)
+
+	if warnings := workflow.CheckTemplateUpgrade(newTemplateVersion(2, "1.1.0", "vpc_id", "subnet_ids")); len(warnings) != 0 {
+		t.Errorf("expected a compatible upgrade to have no warning, got %d", len(warnings))
+	}
+	if warnings := workflow.CheckTemplateUpgrade(newTemplateVersion(2, "2.0.0", "vpc_id", "subnet_ids")); len(warnings) != 0 {
+		t.Errorf("expected the removal of an unbound output not to warn, got %d", len(warnings))
+	}
+	warnings := workflow.CheckTemplateUpgrade(newTemplateVersion(2, "2.0.0", "subnet_ids"))
+	if len(warnings) != 1 || warnings[0].GetStepNumber() != 1 || !strings.Contains(warnings[0].GetMessage(), `workflow output "vpc_id"`) {
+		t.Errorf("expected the removal of a bound output to warn, got %+v", warnings)
+	}
+
+	pinned, _ := common.ParseVersionRange("1.x")
+	step.SetVersionRange(pinned)
+	if warnings := workflow.CheckTemplateUpgrade(newTemplateVersion(2, "2.0.0", "subnet_ids")); len(warnings) != 0 {
+		t.Errorf("expected versions outside the range to be ignored, got %d", len(warnings))
+	}
+}
@@ -0,0 +1,392 @@
+package workflow
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// TriggerFilter is a compiled boolean expression evaluated against an incoming webhook payload.
+//
+// The expression language supports:
+//   - JSONPath operands addressing the payload (e.g. $.ref or repository.name)
+//   - string ("main" or 'main'), number, true, false and null literals
+//   - the == and != comparison operators
+//   - the && and || logical operators, ! negation and parentheses
+//   - the contains(a, b), startsWith(a, b) and endsWith(a, b) string functions
+//
+// For example: $.ref == "refs/heads/main" && !contains($.head_commit.message, "[skip ci]")
+type TriggerFilter struct {
+	expression string
+	root       filterNode
+}
+
+// filterNode is a node of the filter abstract syntax tree.
+// It evaluates to any JSON value, which is converted to a boolean by the logical operators.
+type filterNode interface {
+	evaluate(document interface{}) interface{}
+}
+
+type filterLiteral struct {
+	value interface{}
+}
+
+type filterPath struct {
+	path *common.JSONPath
+}
+
+type filterNot struct {
+	operand filterNode
+}
+
+type filterBinary struct {
+	operator string
+	left     filterNode
+	right    filterNode
+}
+
+type filterCall struct {
+	function string
+	left     filterNode
+	right    filterNode
+}
+
+func (n filterLiteral) evaluate(document interface{}) interface{} {
+	return n.value
+}
+
+func (n filterPath) evaluate(document interface{}) interface{} {
+	value, err := n.path.Evaluate(document)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+func (n filterNot) evaluate(document interface{}) interface{} {
+	return !isTruthy(n.operand.evaluate(document))
+}
+
+func (n filterBinary) evaluate(document interface{}) interface{} {
+	switch n.operator {
+	case "&&":
+		return isTruthy(n.left.evaluate(document)) && isTruthy(n.right.evaluate(document))
+	case "||":
+		return isTruthy(n.left.evaluate(document)) || isTruthy(n.right.evaluate(document))
+	case "==":
+		return valuesEqual(n.left.evaluate(document), n.right.evaluate(document))
+	default:
+		return !valuesEqual(n.left.evaluate(document), n.right.evaluate(document))
+	}
+}
+
+func (n filterCall) evaluate(document interface{}) interface{} {
+	left, leftOk := n.left.evaluate(document).(string)
+	right, rightOk := n.right.evaluate(document).(string)
+	if !leftOk || !rightOk {
+		return false
+	}
+	switch n.function {
+	case "contains":
+		return strings.Contains(left, right)
+	case "startsWith":
+		return strings.HasPrefix(left, right)
+	default:
+		return strings.HasSuffix(left, right)
+	}
+}
+
+// isTruthy converts a JSON value to a boolean: null, false, 0 and "" are false, anything else is true.
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// valuesEqual compares two JSON values, using their JSON encoding for lists and objects.
+func valuesEqual(a interface{}, b interface{}) bool {
+	switch a.(type) {
+	case []interface{}, map[string]interface{}:
+		aJSON, _ := json.Marshal(a)
+		bJSON, _ := json.Marshal(b)
+		return string(aJSON) == string(bJSON)
+	}
+	switch b.(type) {
+	case []interface{}, map[string]interface{}:
+		return false
+	}
+	return a == b
+}
+
+// ParseTriggerFilter compiles a filter expression.
+//
+// An empty expression is valid and matches every payload.
+// Returns ErrInvalidTriggerFilter if the expression cannot be parsed.
+func ParseTriggerFilter(expression string) (*TriggerFilter, error) {
+	expression = strings.TrimSpace(expression)
+	filter := &TriggerFilter{
+		expression: expression,
+		root:       filterLiteral{value: true},
+	}
+	if expression == "" {
+		return filter, nil
+	}
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	parser := &filterParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if parser.position != len(tokens) {
+		return nil, ErrInvalidTriggerFilter
+	}
+	filter.root = root
+	return filter, nil
+}
+
+// ToString returns the source expression of the TriggerFilter.
+func (f *TriggerFilter) ToString() string {
+	return f.expression
+}
+
+// Matches evaluates the filter against a payload decoded with encoding/json.
+// Paths that do not exist in the payload evaluate to null.
+func (f *TriggerFilter) Matches(document interface{}) bool {
+	return isTruthy(f.root.evaluate(document))
+}
+
+type filterTokenKind int
+
+const (
+	tokenOperator filterTokenKind = iota
+	tokenString
+	tokenNumber
+	tokenIdentifier
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+}
+
+// tokenizeFilter splits a filter expression into operators, literals and identifiers (paths, keywords and function names).
+func tokenizeFilter(expression string) ([]filterToken, error) {
+	tokens := []filterToken{}
+	runes := []rune(expression)
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			tokens = append(tokens, filterToken{kind: tokenOperator, value: string(r)})
+			i++
+		case r == '!' && (i+1 >= len(runes) || runes[i+1] != '='):
+			tokens = append(tokens, filterToken{kind: tokenOperator, value: "!"})
+			i++
+		case i+1 < len(runes) && (string(runes[i:i+2]) == "==" || string(runes[i:i+2]) == "!=" || string(runes[i:i+2]) == "&&" || string(runes[i:i+2]) == "||"):
+			tokens = append(tokens, filterToken{kind: tokenOperator, value: string(runes[i : i+2])})
+			i += 2
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, ErrInvalidTriggerFilter
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, value: string(runes[i+1 : end])})
+			i = end + 1
+		case unicode.IsDigit(r) || r == '-':
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: tokenNumber, value: string(runes[i:end])})
+			i = end
+		default:
+			end := i
+			depth := 0
+			for end < len(runes) {
+				c := runes[end]
+				if c == '[' {
+					depth++
+				} else if c == ']' {
+					depth--
+				} else if depth == 0 && !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' || c == '$') {
+					break
+				}
+				end++
+			}
+			if end == i {
+				return nil, ErrInvalidTriggerFilter
+			}
+			tokens = append(tokens, filterToken{kind: tokenIdentifier, value: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser building the filter syntax tree, by increasing precedence:
+// ||, &&, ! and comparisons.
+type filterParser struct {
+	tokens   []filterToken
+	position int
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.position >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.position]
+}
+
+func (p *filterParser) acceptOperator(operator string) bool {
+	token := p.peek()
+	if token != nil && token.kind == tokenOperator && token.value == operator {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterBinary{operator: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOperator("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterBinary{operator: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.acceptOperator("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range []string{"==", "!="} {
+		if p.acceptOperator(operator) {
+			right, err := p.parsePrimary()
+			if err != nil {
+				return nil, err
+			}
+			return filterBinary{operator: operator, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parsePrimary() (filterNode, error) {
+	token := p.peek()
+	if token == nil {
+		return nil, ErrInvalidTriggerFilter
+	}
+	p.position++
+	switch token.kind {
+	case tokenString:
+		return filterLiteral{value: token.value}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, ErrInvalidTriggerFilter
+		}
+		return filterLiteral{value: number}, nil
+	case tokenOperator:
+		if token.value != "(" {
+			return nil, ErrInvalidTriggerFilter
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOperator(")") {
+			return nil, ErrInvalidTriggerFilter
+		}
+		return node, nil
+	}
+
+	switch token.value {
+	case "true":
+		return filterLiteral{value: true}, nil
+	case "false":
+		return filterLiteral{value: false}, nil
+	case "null":
+		return filterLiteral{value: nil}, nil
+	case "contains", "startsWith", "endsWith":
+		if p.acceptOperator("(") {
+			return p.parseCall(token.value)
+		}
+	}
+	path, err := common.ParseJSONPath(token.value)
+	if err != nil {
+		return nil, ErrInvalidTriggerFilter
+	}
+	return filterPath{path: path}, nil
+}
+
+func (p *filterParser) parseCall(function string) (filterNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptOperator(",") {
+		return nil, ErrInvalidTriggerFilter
+	}
+	right, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptOperator(")") {
+		return nil, ErrInvalidTriggerFilter
+	}
+	return filterCall{function: function, left: left, right: right}, nil
+}
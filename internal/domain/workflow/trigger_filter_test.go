@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseTriggerFilter_Invalid(t *testing.T) {
+	invalids := []string{
+		"$.ref ==",
+		"($.ref == \"main\"",
+		"$.ref == \"main",
+		"&& $.ref",
+		"contains($.ref)",
+		"$.ref == \"a\" \"b\"",
+	}
+
+	for _, expression := range invalids {
+		if _, err := ParseTriggerFilter(expression); err != ErrInvalidTriggerFilter {
+			t.Errorf("expected ErrInvalidTriggerFilter for %q, got %v", expression, err)
+		}
+	}
+}
+
+func TestTriggerFilterMatches(t *testing.T) {
+	var document interface{}
+	payload := `{"ref": "refs/heads/main", "forced": false, "size": 3, "head_commit": {"message": "fix: typo [skip ci]"}}`
+	if err := json.Unmarshal([]byte(payload), &document); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"", true},
+		{"$.ref == \"refs/heads/main\"", true},
+		{"ref == 'refs/heads/develop'", false},
+		{"$.ref != \"refs/heads/develop\"", true},
+		{"$.size == 3 && !$.forced", true},
+		{"$.forced || $.size == 4", false},
+		{"$.missing == null", true},
+		{"$.missing", false},
+		{"startsWith($.ref, \"refs/heads/\") && endsWith($.ref, 'main')", true},
+		{"!contains($.head_commit.message, \"[skip ci]\")", false},
+		{"($.forced || $.size == 3) && $.ref == \"refs/heads/main\"", true},
+	}
+
+	for _, tt := range tests {
+		filter, err := ParseTriggerFilter(tt.expression)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.expression, err)
+			continue
+		}
+		if filter.Matches(document) != tt.expected {
+			t.Errorf("expected %q to evaluate to %t", tt.expression, tt.expected)
+		}
+		if filter.ToString() != tt.expression {
+			t.Errorf("expected %q, got %q", tt.expression, filter.ToString())
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package workflow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// WEBHOOK_SECRET_LENGTH defines the number of random bytes used to generate a webhook secret.
+const WEBHOOK_SECRET_LENGTH = 32
+
+// WEBHOOK_SIGNATURE_PREFIX is the prefix of the signature header value, followed by the hex encoded HMAC-SHA256 of the payload.
+const WEBHOOK_SIGNATURE_PREFIX = "sha256="
+
+// WebhookTrigger represents an inbound webhook able to start a run of its workflow.
+// Incoming payloads are authenticated with an HMAC secret, optionally filtered with a TriggerFilter,
+// and mapped into workflow input values using JSONPath expressions.
+type WebhookTrigger struct {
+	common.NamedEntity
+	secret        string
+	filter        *TriggerFilter
+	inputMappings map[string]*common.JSONPath
+}
+
+// WebhookTriggerComparator is used to compare two WebhookTrigger instances based on their identifier.
+type WebhookTriggerComparator struct{}
+
+// Compare returns a comparison between two WebhookTrigger identifiers.
+func (WebhookTriggerComparator) Compare(a *WebhookTrigger, b *WebhookTrigger) int {
+	return strings.Compare(a.GetIdentifier().ToString(), b.GetIdentifier().ToString())
+}
+
+// generateWebhookSecret returns a random hex encoded secret.
+func generateWebhookSecret() (string, error) {
+	bytes := make([]byte, WEBHOOK_SECRET_LENGTH)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// NewWebhookTrigger creates a new WebhookTrigger with a generated identifier and secret.
+// The filter expression may be empty, and inputMappings associates workflow input names to JSONPath expressions.
+//
+// Returns an error if the name, description, filter or any mapping is invalid.
+func NewWebhookTrigger(workflowId string, name string, description string, filter string, inputMappings map[string]string) (*WebhookTrigger, error) {
+	identifier, err := common.BuildAttributeIdentifier(workflowId, "trigger")
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	return ExistingWebhookTrigger(identifier.ToString(), name, description, secret, filter, inputMappings)
+}
+
+// ExistingWebhookTrigger creates a WebhookTrigger with the provided identifier and secret.
+// This is typically used when reloading from a data store.
+//
+// Returns an error if the name, description, filter or any mapping is invalid.
+func ExistingWebhookTrigger(identifier string, name string, description string, secret string, filter string, inputMappings map[string]string) (*WebhookTrigger, error) {
+	namedEntity, err := common.NewNamedEntity(identifier, name, description)
+	if err != nil {
+		return nil, err
+	}
+	trigger := &WebhookTrigger{
+		NamedEntity:   *namedEntity,
+		secret:        secret,
+		filter:        nil,
+		inputMappings: map[string]*common.JSONPath{},
+	}
+	err = trigger.SetFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	for inputName, path := range inputMappings {
+		err = trigger.SetInputMapping(inputName, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return trigger, nil
+}
+
+// GetSecret returns the HMAC secret used to sign payloads sent to the trigger.
+func (t *WebhookTrigger) GetSecret() string {
+	return t.secret
+}
+
+// RotateSecret replaces the HMAC secret of the trigger with a newly generated one.
+func (t *WebhookTrigger) RotateSecret() error {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return err
+	}
+	t.secret = secret
+	return nil
+}
+
+// GetURL returns the URL to which payloads must be sent, relative to the provided API base URL.
+func (t *WebhookTrigger) GetURL(baseURL string) string {
+	return fmt.Sprintf("%s/webhooks/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(t.GetIdentifier().ToString()))
+}
+
+// GetFilter returns the filter expression of the trigger, or an empty string if every payload is accepted.
+func (t *WebhookTrigger) GetFilter() string {
+	return t.filter.ToString()
+}
+
+// SetFilter compiles and sets the filter expression of the trigger.
+// Returns ErrInvalidTriggerFilter if the expression cannot be parsed.
+func (t *WebhookTrigger) SetFilter(filter string) error {
+	compiled, err := ParseTriggerFilter(filter)
+	if err != nil {
+		return err
+	}
+	t.filter = compiled
+	return nil
+}
+
+// ListInputMappings returns the JSONPath expression associated to each mapped workflow input name.
+func (t *WebhookTrigger) ListInputMappings() map[string]string {
+	mappings := make(map[string]string, len(t.inputMappings))
+	for inputName, path := range t.inputMappings {
+		mappings[inputName] = path.ToString()
+	}
+	return mappings
+}
+
+// SetInputMapping maps the value found at the JSONPath expression in the payload to the workflow input with the given name.
+// Returns an error if the expression is invalid.
+func (t *WebhookTrigger) SetInputMapping(inputName string, path string) error {
+	compiled, err := common.ParseJSONPath(path)
+	if err != nil {
+		return err
+	}
+	t.inputMappings[inputName] = compiled
+	return nil
+}
+
+// RemoveInputMapping removes the mapping of the workflow input with the given name, if it exists.
+func (t *WebhookTrigger) RemoveInputMapping(inputName string) {
+	delete(t.inputMappings, inputName)
+}
+
+// VerifySignature checks that the signature matches the HMAC-SHA256 of the payload computed with the trigger secret.
+// The signature is expected in the "sha256=<hex digest>" format.
+func (t *WebhookTrigger) VerifySignature(payload []byte, signature string) bool {
+	if !strings.HasPrefix(signature, WEBHOOK_SIGNATURE_PREFIX) {
+		return false
+	}
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, WEBHOOK_SIGNATURE_PREFIX))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(t.secret))
+	mac.Write(payload)
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+// Matches returns true if the decoded payload satisfies the filter of the trigger.
+func (t *WebhookTrigger) Matches(document interface{}) bool {
+	return t.filter.Matches(document)
+}
+
+// MapInputs extracts the workflow input values from the decoded payload using the input mappings.
+// Strings are used as is, while numbers, booleans, lists and objects are serialized to their JSON representation.
+// Mappings that do not match any value in the payload are skipped.
+func (t *WebhookTrigger) MapInputs(document interface{}) map[string]string {
+	inputs := map[string]string{}
+	for inputName, path := range t.inputMappings {
+		value, err := path.Evaluate(document)
+		if err != nil || value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			inputs[inputName] = v
+		case float64:
+			inputs[inputName] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			serialized, _ := json.Marshal(v)
+			inputs[inputName] = string(serialized)
+		}
+	}
+	return inputs
+}
@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return WEBHOOK_SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewWebhookTrigger(t *testing.T) {
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	_, err := NewWebhookTrigger(workflowId, "invalid name", "", "", nil)
+	if err != common.ErrInvalidName {
+		t.Errorf("expected err to be ErrInvalidName, got %v", err)
+	}
+	_, err = NewWebhookTrigger(workflowId, "on-push", "", "$.ref ==", nil)
+	if err != ErrInvalidTriggerFilter {
+		t.Errorf("expected err to be ErrInvalidTriggerFilter, got %v", err)
+	}
+	_, err = NewWebhookTrigger(workflowId, "on-push", "", "", map[string]string{"branch": "$..ref"})
+	if err != common.ErrInvalidJSONPath {
+		t.Errorf("expected err to be ErrInvalidJSONPath, got %v", err)
+	}
+
+	trigger, err := NewWebhookTrigger(workflowId, "on-push", "", "$.ref == \"refs/heads/main\"", map[string]string{"commit": "$.after"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trigger.GetSecret()) != 2*WEBHOOK_SECRET_LENGTH {
+		t.Errorf("expected a %d characters secret, got %q", 2*WEBHOOK_SECRET_LENGTH, trigger.GetSecret())
+	}
+	if !strings.HasPrefix(trigger.GetURL("https://autops.example.com/"), "https://autops.example.com/webhooks/autops::project:ABCDEFGHIJ:workflow:1234567890:trigger:") {
+		t.Errorf("unexpected URL %s", trigger.GetURL("https://autops.example.com/"))
+	}
+	if trigger.ListInputMappings()["commit"] != "$.after" {
+		t.Errorf("expected commit to be mapped to $.after, got %v", trigger.ListInputMappings())
+	}
+
+	secret := trigger.GetSecret()
+	if err := trigger.RotateSecret(); err != nil || trigger.GetSecret() == secret {
+		t.Error("expected the secret to be rotated")
+	}
+}
+
+func TestWebhookTriggerVerifySignature(t *testing.T) {
+	trigger, _ := NewWebhookTrigger("autops::project:ABCDEFGHIJ:workflow:1234567890", "on-push", "", "", nil)
+	payload := []byte(`{"ref": "refs/heads/main"}`)
+
+	if !trigger.VerifySignature(payload, signPayload(trigger.GetSecret(), payload)) {
+		t.Error("expected signature to be valid")
+	}
+	if trigger.VerifySignature(payload, signPayload("another-secret", payload)) {
+		t.Error("expected signature computed with another secret to be invalid")
+	}
+	if trigger.VerifySignature([]byte(`{"ref": "refs/heads/dev"}`), signPayload(trigger.GetSecret(), payload)) {
+		t.Error("expected signature of another payload to be invalid")
+	}
+	if trigger.VerifySignature(payload, strings.TrimPrefix(signPayload(trigger.GetSecret(), payload), WEBHOOK_SIGNATURE_PREFIX)) {
+		t.Error("expected signature without prefix to be invalid")
+	}
+}
+
+func TestWebhookTriggerMapInputs(t *testing.T) {
+	trigger, _ := NewWebhookTrigger("autops::project:ABCDEFGHIJ:workflow:1234567890", "on-push", "", "", map[string]string{
+		"branch":  "$.ref",
+		"size":    "$.size",
+		"forced":  "$.forced",
+		"commits": "$.commits",
+		"missing": "$.missing",
+	})
+	var document interface{}
+	json.Unmarshal([]byte(`{"ref": "refs/heads/main", "size": 2.5, "forced": true, "commits": ["a", "b"]}`), &document)
+
+	inputs := trigger.MapInputs(document)
+	expected := map[string]string{
+		"branch":  "refs/heads/main",
+		"size":    "2.5",
+		"forced":  "true",
+		"commits": `["a","b"]`,
+	}
+	if len(inputs) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, inputs)
+	}
+	for name, value := range expected {
+		if inputs[name] != value {
+			t.Errorf("expected %s to be %q, got %q", name, value, inputs[name])
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package workflow
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
@@ -11,10 +13,12 @@ import (
 type Workflow struct {
 	common.StatefulNamedEntity
 	common.VersionedSource
-	inputs  *common.List[*WorkflowAttribute]
-	outputs *common.List[*WorkflowAttribute]
-	steps   *common.List[*WorkflowStep]
-	runs    *common.List[*WorkflowRun]
+	inputs   *common.List[*WorkflowAttribute]
+	outputs  *common.List[*WorkflowAttribute]
+	steps    *common.List[*WorkflowStep]
+	runs     *common.List[*WorkflowRun]
+	triggers *common.List[*WebhookTrigger]
+	bindings map[string]*OutputBinding
 }
 
 type WorkflowComparator struct{}
@@ -23,9 +27,9 @@ func (WorkflowComparator) Compare(t1, t2 *Workflow) int {
 	return strings.Compare(t1.GetIdentifier().ToString(), t2.GetIdentifier().ToString())
 }
 
-// ExistingWorkflow creates a Workflow instance using an existing identifier, status, version, and provided lists of inputs, outputs, and steps.
+// ExistingWorkflow creates a Workflow instance using an existing identifier, status, version, and provided lists of inputs, outputs, steps, runs and triggers.
 // Returns an error if the name, description, or source path are invalid.
-func ExistingWorkflow(workflowIdentifier string, name string, description string, status common.Status, sourcePath string, version int, inputs []*WorkflowAttribute, outputs []*WorkflowAttribute, steps []*WorkflowStep, runs []*WorkflowRun) (*Workflow, error) {
+func ExistingWorkflow(workflowIdentifier string, name string, description string, status common.Status, sourcePath string, version int, inputs []*WorkflowAttribute, outputs []*WorkflowAttribute, steps []*WorkflowStep, runs []*WorkflowRun, triggers []*WebhookTrigger) (*Workflow, error) {
 	statefulEntity, err := common.NewStatefulNamedEntity(workflowIdentifier, name, description, status)
 	if err != nil {
 		return nil, err
@@ -41,6 +45,8 @@ func ExistingWorkflow(workflowIdentifier string, name string, description string
 		outputs:             common.NewList(common.Comparator[*WorkflowAttribute](WorkflowAttributeComparator{}), outputs),
 		steps:               common.NewList(common.Comparator[*WorkflowStep](WorkflowStepComparator{}), steps),
 		runs:                common.NewList(common.Comparator[*WorkflowRun](WorkflowRunComparator{}), runs),
+		triggers:            common.NewList(common.Comparator[*WebhookTrigger](WebhookTriggerComparator{}), triggers),
+		bindings:            map[string]*OutputBinding{},
 	}
 	return &workflowEntity, nil
 }
@@ -51,7 +57,7 @@ func NewWorkflow(projectIdentifier string, name string, description string, sour
 	if err != nil {
 		return nil, err
 	}
-	return ExistingWorkflow(workflowIdentifier.ToString(), name, description, common.PENDING, sourcePath, 1, []*WorkflowAttribute{}, []*WorkflowAttribute{}, []*WorkflowStep{}, []*WorkflowRun{})
+	return ExistingWorkflow(workflowIdentifier.ToString(), name, description, common.PENDING, sourcePath, 1, []*WorkflowAttribute{}, []*WorkflowAttribute{}, []*WorkflowStep{}, []*WorkflowRun{}, []*WebhookTrigger{})
 }
 
 // ListInputs returns the list of WorkflowAttributes defined as inputs.
@@ -74,6 +80,139 @@ func (w *Workflow) ListRuns() []*WorkflowRun {
 	return w.runs.Items()
 }
 
+// GetRun returns the WorkflowRun with the given identifier, or nil if not found.
+func (w *Workflow) GetRun(runIdentifier string) *WorkflowRun {
+	run, _ := w.runs.SelectOne(func(r *WorkflowRun) bool {
+		return r.GetIdentifier().ToString() == runIdentifier
+	})
+	return run
+}
+
+// ApplyPlan creates a new APPLY_PLAN run applying the plans saved by a successful PLAN_ONLY run, with the same environment.
+// The input values of the plan run are resolved again against the inputs declared by the workflow, and the resulting inputs
+// of the apply run must be the ones each plan was computed with. Unlike the plan run, it waits on the approval gates of the workflow.
+//
+// Returns an error if the plan run cannot be found, is not a successful PLAN_ONLY run, or has already been applied.
+// Returns ErrPlanMissing if a Terraform or OpenTofu step has no saved plan, or ErrPlanOutdated if a planned step was removed,
+// if the input values of the plan run are no longer valid, or if a plan was computed with another workflow version,
+// template version or inputs than the ones the run would apply.
+func (w *Workflow) ApplyPlan(planRunIdentifier string) (*WorkflowRun, error) {
+	planRun := w.GetRun(planRunIdentifier)
+	if planRun == nil {
+		return nil, ErrWorkflowRunNotFound
+	}
+	if planRun.GetMode() != PLAN_ONLY || planRun.GetStatus() != common.SUCCESS {
+		return nil, ErrRunIsNotAPlan
+	}
+	_, applied := w.runs.SelectOne(func(r *WorkflowRun) bool {
+		return r.GetAppliedPlan() != nil && r.GetAppliedPlan().ToString() == planRunIdentifier && r.GetStatus() != common.FAILURE
+	})
+	if applied {
+		return nil, ErrPlanAlreadyApplied
+	}
+	for _, plan := range planRun.ListPlans() {
+		_, found := w.steps.SelectOne(func(s *WorkflowStep) bool {
+			return s.GetStepNumber() == plan.GetStepNumber()
+		})
+		if !found {
+			return nil, ErrPlanOutdated
+		}
+	}
+	inputs, err := w.ResolveInputs(planRun.GetInputs())
+	if err != nil {
+		return nil, ErrPlanOutdated
+	}
+	for _, step := range w.steps.Items() {
+		if !canPlan(step.GetTask()) {
+			continue
+		}
+		plan := planRun.GetPlan(step.GetStepNumber())
+		if plan == nil {
+			return nil, ErrPlanMissing
+		}
+		if err := plan.Verify(w.GetVersion(), step.GetTask(), inputs); err != nil {
+			return nil, err
+		}
+	}
+
+	run, err := NewWorkflowRun(w.GetIdentifier().ToString(), planRun.GetName(), fmt.Sprintf("Apply of the plan computed by run %s", planRunIdentifier))
+	if err != nil {
+		return nil, err
+	}
+	run.SetMode(APPLY_PLAN)
+	run.SetAppliedPlan(planRun.GetIdentifier())
+	run.SetInputs(inputs)
+	run.SetEnvironment(planRun.GetEnvironment())
+	err = w.startRun(run)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// ListTriggers returns the list of WebhookTriggers able to start a run of the workflow.
+func (w *Workflow) ListTriggers() []*WebhookTrigger {
+	return w.triggers.Items()
+}
+
+// GetTrigger returns the WebhookTrigger with the given identifier, or nil if not found.
+func (w *Workflow) GetTrigger(triggerIdentifier string) *WebhookTrigger {
+	trigger, _ := w.triggers.SelectOne(func(t *WebhookTrigger) bool {
+		return t.GetIdentifier().ToString() == triggerIdentifier
+	})
+	return trigger
+}
+
+// AddTrigger adds a new WebhookTrigger to the workflow.
+// Returns an error if the trigger is already present.
+func (w *Workflow) AddTrigger(trigger *WebhookTrigger) error {
+	if w.triggers.Contains(trigger) {
+		return ErrWebhookTriggerAlreadyPresent
+	}
+	w.triggers.Append(trigger)
+	return nil
+}
+
+// RemoveTrigger removes a trigger by its identifier from the workflow.
+// Returns an error if the trigger is not found.
+func (w *Workflow) RemoveTrigger(triggerIdentifier string) error {
+	trigger := w.GetTrigger(triggerIdentifier)
+	if trigger == nil {
+		return ErrWebhookTriggerNotFound
+	}
+	w.triggers.Remove(trigger)
+	return nil
+}
+
+// HandleWebhook processes a payload received by one of the workflow triggers.
+// The payload signature is verified against the trigger secret, the payload is matched against the trigger filter,
+// and a new run attributed to the trigger is added to the workflow with the mapped input values.
+//
+// Returns ErrWebhookPayloadFiltered if the payload is valid but does not satisfy the filter,
+// or an AttributeValidationError if the mapped values are not valid workflow inputs.
+func (w *Workflow) HandleWebhook(triggerIdentifier string, payload []byte, signature string) (*WorkflowRun, error) {
+	trigger := w.GetTrigger(triggerIdentifier)
+	if trigger == nil {
+		return nil, ErrWebhookTriggerNotFound
+	}
+	if !trigger.VerifySignature(payload, signature) {
+		return nil, ErrInvalidWebhookSignature
+	}
+	var document interface{}
+	if err := json.Unmarshal(payload, &document); err != nil {
+		return nil, ErrInvalidWebhookPayload
+	}
+	if !trigger.Matches(document) {
+		return nil, ErrWebhookPayloadFiltered
+	}
+	run, err := w.StartRun(trigger.GetName(), fmt.Sprintf("Triggered by webhook %s", trigger.GetName()), STANDARD, trigger.MapInputs(document))
+	if err != nil {
+		return nil, err
+	}
+	run.SetTriggeredBy(trigger.GetIdentifier())
+	return run, nil
+}
+
 // AddInput adds a new WorkflowAttribute as input to the workflow.
 // Returns an error if the input is already present.
 func (w *Workflow) AddInput(input *WorkflowAttribute) error {
@@ -100,6 +239,8 @@ func (w *Workflow) AddStep(step *WorkflowStep) {
 	w.steps.Append(step)
 }
 
+// AddRun adds a new WorkflowRun to the workflow.
+// Returns an error if the run is already present.
 func (w *Workflow) AddRun(run *WorkflowRun) error {
 	if w.runs.Contains(run) {
 		return ErrWorkflowRunAlreadyPresent
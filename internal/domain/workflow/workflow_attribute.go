@@ -1,73 +1,44 @@
 package workflow
 
 import (
-	"encoding/json"
-	"strconv"
 	"strings"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
 )
 
 // WorkflowAttributeType defines the type of an attribute in a workflow.
-type WorkflowAttributeType int
+// It is shared with template attributes, so that template outputs can be bound to workflow outputs (see common.AttributeType).
+type WorkflowAttributeType = common.AttributeType
 
 const (
-	// STRING represents a string attribute type.
-	STRING WorkflowAttributeType = iota
-	// NUMBER represents a numeric attribute type.
-	NUMBER
-	// LIST represents a list attribute type.
-	LIST
-	// BOOL representes a boolean attribute type.
-	BOOL
-	// OBJECT represents an object (JSON) attribute type.
-	OBJECT
+	STRING = common.STRING
+	NUMBER = common.NUMBER
+	LIST   = common.LIST
+	BOOL   = common.BOOL
+	OBJECT = common.OBJECT
 )
 
-// ToString converts the WorkflowAttributeType to its string representation.
-func (t WorkflowAttributeType) ToString() string {
-	switch t {
-	case STRING:
-		return "string"
-	case NUMBER:
-		return "number"
-	case BOOL:
-		return "bool"
-	case LIST:
-		return "list"
-	case OBJECT:
-		return "object"
-	default:
-		return "unknown"
-	}
-}
-
 // ParseWorkflowAttributeType parses a string into a WorkflowAttributeType.
 // Returns an error if the string does not match a known type.
 func ParseWorkflowAttributeType(str string) (WorkflowAttributeType, error) {
-	str = strings.ToLower(str)
-	switch str {
-	case "string":
-		return STRING, nil
-	case "number":
-		return NUMBER, nil
-	case "bool":
-		return BOOL, nil
-	case "list":
-		return LIST, nil
-	case "object":
-		return OBJECT, nil
-	default:
+	attributeType, err := common.ParseAttributeType(str)
+	if err != nil {
 		return -1, ErrUnsupportedWorkflowAttributeType
 	}
+	return attributeType, nil
 }
 
 // WorkflowAttribute represents a named attribute within a workflow,
 // with a specified type and optional default value.
+// Values of sensitive inputs are references to secrets, and values of sensitive outputs are masked once resolved.
+// Values can be further restricted by declarative constraints (see common.AttributeConstraints).
 type WorkflowAttribute struct {
 	common.NamedEntity
 	attributeType WorkflowAttributeType
 	defaultValue  string
+	sensitive     bool
+	constraints   *common.AttributeConstraints
 }
 
 // NewWorkflowAttribute creates a new WorkflowAttribute with a generated unique identifier.
@@ -92,6 +63,7 @@ func ExistingWorkflowAttribute(identifier string, name string, description strin
 		NamedEntity:   *namedEntity,
 		attributeType: attributeType,
 		defaultValue:  "",
+		constraints:   common.NewAttributeConstraints(),
 	}
 
 	err = attribute.SetDefaultValue(defaultValue)
@@ -113,56 +85,92 @@ func (a *WorkflowAttribute) GetDefaultValue() string {
 }
 
 // SetDefaultValue validates and sets the default value for the attribute.
-//   - For STRING: any string is accepted.
-//   - For NUMBER: the value must be a valid float or integer.
-//   - For BOOL: the value must be "true" or "false".
-//   - For OBJECT: the value must be valid JSON of type object.
-//   - For LIST: the value must be valid JSON of type array, and no element must be nil.
+// An empty value removes the default value. See ValidateValue for the validation rules,
+// and SetSensitive for the default value of sensitive attributes.
 //
 // Returns an error if the value is invalid for the given type.
 func (a *WorkflowAttribute) SetDefaultValue(value string) error {
 	value = strings.TrimSpace(value)
 	if value == "" {
+		a.defaultValue = ""
 		return nil
 	}
-	switch a.attributeType {
-	case STRING:
-		break
+	if err := a.validateInputValue(value); err != nil {
+		return err
+	}
+	a.defaultValue = value
+	return nil
+}
 
-	case NUMBER:
-		if _, err := strconv.ParseFloat(value, 64); err != nil {
-			return err
-		}
+// IsSensitive returns true if the values of the attribute are secrets.
+func (a *WorkflowAttribute) IsSensitive() bool {
+	return a.sensitive
+}
 
-	case BOOL:
-		if _, err := strconv.ParseBool(value); err != nil {
-			return err
+// SetSensitive marks the attribute as sensitive or not.
+// Returns an error if the current default value does not match, i.e. a literal for a sensitive attribute,
+// or a secret reference for an attribute that is not sensitive.
+func (a *WorkflowAttribute) SetSensitive(sensitive bool) error {
+	if a.defaultValue != "" && sensitive != secret.IsReference(a.defaultValue) {
+		if sensitive {
+			return ErrSensitiveValueNotASecret
 		}
+		return ErrSecretReferenceNotSensitive
+	}
+	a.sensitive = sensitive
+	return nil
+}
 
-	case OBJECT:
-		var obj map[string]interface{}
-		if err := json.Unmarshal([]byte(value), &obj); err != nil {
-			return err
-		}
+// GetConstraints returns the constraints the values of the attribute must satisfy.
+func (a *WorkflowAttribute) GetConstraints() *common.AttributeConstraints {
+	return a.constraints
+}
 
-	case LIST:
-		var rawList []interface{}
-		if err := json.Unmarshal([]byte(value), &rawList); err != nil {
-			return ErrInvalidListFormat
+// SetConstraints replaces the constraints of the attribute, or removes them if nil.
+// Returns an error if a constraint does not apply to the attribute type,
+// or a common.ConstraintViolationError if the current default value violates them.
+func (a *WorkflowAttribute) SetConstraints(constraints *common.AttributeConstraints) error {
+	if constraints == nil {
+		constraints = common.NewAttributeConstraints()
+	}
+	if err := constraints.CheckApplicable(a.attributeType); err != nil {
+		return err
+	}
+	if a.defaultValue != "" && !a.sensitive {
+		if err := constraints.Validate(a.attributeType, a.defaultValue); err != nil {
+			return err
 		}
+	}
+	a.constraints = constraints
+	return nil
+}
 
-		for _, item := range rawList {
-			if item == nil {
-				return ErrEmptyItemInList
-			}
+// validateInputValue checks a value provided for the attribute used as an input.
+// Sensitive attributes only accept secret references, which are validated against the type once revealed.
+// Other attributes reject secret references, so that secrets are never used where they are not masked.
+func (a *WorkflowAttribute) validateInputValue(value string) error {
+	if secret.IsReference(value) {
+		if !a.sensitive {
+			return ErrSecretReferenceNotSensitive
 		}
-
-	default:
-		return ErrUnsupportedAttributeType
+		return nil
 	}
+	if a.sensitive {
+		return ErrSensitiveValueNotASecret
+	}
+	return a.ValidateValue(value)
+}
 
-	a.defaultValue = value
-	return nil
+// ValidateValue checks that a value is valid for the type of the attribute (see common.AttributeType.Validate),
+// then satisfies its constraints.
+//
+// Returns an error if the value is invalid for the given type,
+// or a common.ConstraintViolationError listing every violated constraint.
+func (a *WorkflowAttribute) ValidateValue(value string) error {
+	if err := a.attributeType.Validate(value); err != nil {
+		return err
+	}
+	return a.constraints.Validate(a.attributeType, value)
 }
 
 // WorkflowAttributeComparator is used to compare two WorkflowAttribute instances
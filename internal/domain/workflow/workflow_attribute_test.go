@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
@@ -219,3 +220,31 @@ func TestCompare(t *testing.T) {
 		t.Errorf("expected comparison to return 0, got %d", comparator.Compare(attributeC, attributeA))
 	}
 }
+
+func TestWorkflowAttributeConstraints(t *testing.T) {
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	minimum, maximum := 1.0, 10.0
+	attribute, _ := NewWorkflowAttribute(workflowId, "replicas", "", NUMBER, "20")
+
+	constraints := common.NewAttributeConstraints()
+	constraints.SetRange(&minimum, &maximum)
+	var violationErr *common.ConstraintViolationError
+	if err := attribute.SetConstraints(constraints); !errors.As(err, &violationErr) {
+		t.Errorf("expected the default value to be checked, got %v", err)
+	}
+	attribute.SetDefaultValue("")
+	if err := attribute.SetConstraints(constraints); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := attribute.SetDefaultValue("11"); !errors.As(err, &violationErr) {
+		t.Errorf("expected err to be a ConstraintViolationError, got %v", err)
+	}
+	if err := attribute.ValidateValue("5"); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+
+	name, _ := NewWorkflowAttribute(workflowId, "name", "", STRING, "")
+	if err := name.SetConstraints(constraints); err != common.ErrConstraintNotApplicable {
+		t.Errorf("expected err to be ErrConstraintNotApplicable, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package workflow
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
+// Diff compares two versions of a workflow, reporting whether the source content changed
+// and which inputs and outputs were added, removed or modified.
+func Diff(from *Workflow, to *Workflow) *common.VersionDiff {
+	return common.NewVersionDiff(&from.VersionedSource, &to.VersionedSource, diffAttributes(from.ListInputs(), to.ListInputs()), diffAttributes(from.ListOutputs(), to.ListOutputs()))
+}
+
+// diffAttributes compares two sets of workflow attributes by name.
+func diffAttributes(previous []*WorkflowAttribute, current []*WorkflowAttribute) []*common.AttributeChange {
+	return common.DiffAttributes(previous, current, (*WorkflowAttribute).GetName, func(a *WorkflowAttribute, b *WorkflowAttribute) []string {
+		fields := []string{}
+		if a.GetType() != b.GetType() {
+			fields = append(fields, "type")
+		}
+		if a.GetDefaultValue() != b.GetDefaultValue() {
+			fields = append(fields, "default_value")
+		}
+		if a.IsSensitive() != b.IsSensitive() {
+			fields = append(fields, "sensitive")
+		}
+		if a.GetDescription() != b.GetDescription() {
+			fields = append(fields, "description")
+		}
+		return fields
+	})
+}
@@ -2,14 +2,27 @@ package workflow
 
 import (
 	"strings"
+	"time"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
 )
 
 // WorkflowRun represents a single execution instance of a workflow.
-// It inherits identification, name, and description from NamedEntity.
+// It inherits identification, name, description and status from StatefulNamedEntity.
 type WorkflowRun struct {
-	common.NamedEntity
+	common.StatefulNamedEntity
+	triggeredBy      *common.Identifier
+	startedBy        *common.Identifier
+	inputs           map[string]string
+	approvalRequests []*ApprovalRequest
+	mode             RunMode
+	appliedPlan      *common.Identifier
+	plans            []*StepPlan
+	stepOutputs      map[int]map[string]string
+	outputs          map[string]string
+	environment      *common.Identifier
+	promotedFrom     *common.Identifier
 }
 
 // NewWorkflowRun creates a new WorkflowRun with a generated unique identifier.
@@ -25,16 +38,285 @@ func NewWorkflowRun(workflowId string, name string, description string) (*Workfl
 // ExistingWorkflowRun creates a WorkflowRun with the provided identifier, name, and description.
 // Returns an error if the name or description is invalid.
 func ExistingWorkflowRun(identifier string, name string, description string) (*WorkflowRun, error) {
-	namedEntity, err := common.NewNamedEntity(identifier, name, description)
+	statefulEntity, err := common.NewStatefulNamedEntity(identifier, name, description, common.PENDING)
 	if err != nil {
 		return nil, err
 	}
 
 	return &WorkflowRun{
-		NamedEntity: *namedEntity,
+		StatefulNamedEntity: *statefulEntity,
+		triggeredBy:         nil,
+		inputs:              map[string]string{},
+		approvalRequests:    []*ApprovalRequest{},
+		mode:                STANDARD,
+		appliedPlan:         nil,
+		plans:               []*StepPlan{},
+		stepOutputs:         map[int]map[string]string{},
+		outputs:             map[string]string{},
 	}, nil
 }
 
+// GetTriggeredBy returns the identifier of the trigger that started the run, or nil if it was started manually.
+func (r *WorkflowRun) GetTriggeredBy() *common.Identifier {
+	return r.triggeredBy
+}
+
+// SetTriggeredBy attributes the run to the trigger with the given identifier.
+func (r *WorkflowRun) SetTriggeredBy(triggerIdentifier *common.Identifier) {
+	r.triggeredBy = triggerIdentifier
+}
+
+// GetStartedBy returns the identifier of the user who started the run, or nil if it was started by a trigger or a sync.
+func (r *WorkflowRun) GetStartedBy() *common.Identifier {
+	return r.startedBy
+}
+
+// SetStartedBy attributes the run to the user with the given identifier, who cannot approve it.
+func (r *WorkflowRun) SetStartedBy(userIdentifier *common.Identifier) {
+	r.startedBy = userIdentifier
+}
+
+// GetInputs returns a copy of the input values of the run, indexed by workflow input name.
+func (r *WorkflowRun) GetInputs() map[string]string {
+	return copyValues(r.inputs)
+}
+
+// SetInputs replaces the input values of the run.
+func (r *WorkflowRun) SetInputs(inputs map[string]string) {
+	r.inputs = copyValues(inputs)
+}
+
+// ListApprovalRequests returns every approval requested during the run, in chronological order.
+func (r *WorkflowRun) ListApprovalRequests() []*ApprovalRequest {
+	return append([]*ApprovalRequest(nil), r.approvalRequests...)
+}
+
+// SetApprovalRequests replaces the approval requests of the run, typically when reloading it from a data store.
+func (r *WorkflowRun) SetApprovalRequests(requests []*ApprovalRequest) {
+	r.approvalRequests = append([]*ApprovalRequest(nil), requests...)
+}
+
+// GetPendingApproval returns the approval request the run is waiting for, or nil if it is not waiting.
+func (r *WorkflowRun) GetPendingApproval() *ApprovalRequest {
+	if len(r.approvalRequests) == 0 {
+		return nil
+	}
+	last := r.approvalRequests[len(r.approvalRequests)-1]
+	if last.status != common.WAITING {
+		return nil
+	}
+	return last
+}
+
+// RequestApproval pauses the run on an approval gate step, until enough approvers accept it or one rejects it.
+// Returns an error if the step is not an approval gate or if the run is already waiting for an approval.
+func (r *WorkflowRun) RequestApproval(step *WorkflowStep) error {
+	if !step.IsApprovalGate() {
+		return ErrStepIsNotApprovalGate
+	}
+	if r.GetPendingApproval() != nil {
+		return ErrRunAlreadyWaitingApproval
+	}
+	gate := step.GetApprovalGate()
+	r.approvalRequests = append(r.approvalRequests, &ApprovalRequest{
+		stepNumber: step.GetStepNumber(),
+		gate:       gate,
+		status:     common.WAITING,
+		expiresAt:  time.Now().Add(gate.GetTimeout()).Format(time.RFC3339),
+		decisions:  []*ApprovalDecision{},
+	})
+	r.SetStatus(common.WAITING)
+	return nil
+}
+
+// SubmitApproval records the decision of a user on the pending approval of the run.
+// A rejection fails the run, while reaching the minimum number of approvals resumes it.
+//
+// Returns an error if the run is not waiting for an approval, if the approval expired (which fails the run),
+// if the user is not an approver or started the run, or if the user already submitted a decision.
+func (r *WorkflowRun) SubmitApproval(approver *identity.User, approved bool, comment string) error {
+	request := r.GetPendingApproval()
+	if request == nil {
+		return ErrNoPendingApproval
+	}
+	if r.ExpirePendingApproval() {
+		return ErrApprovalExpired
+	}
+	workflowIdentifier, err := r.GetIdentifier().GetParent()
+	if err != nil {
+		return err
+	}
+	if !request.gate.CanApprove(approver, workflowIdentifier, approver.NewRequestContext(time.Now())) {
+		return ErrNotAnApprover
+	}
+	if r.startedBy != nil && r.startedBy.ToString() == approver.GetIdentifier().ToString() {
+		return ErrSelfApproval
+	}
+	if request.hasDecided(approver.GetIdentifier()) {
+		return ErrApprovalAlreadySubmitted
+	}
+
+	request.decisions = append(request.decisions, ExistingApprovalDecision(approver.GetIdentifier(), approved, strings.TrimSpace(comment), common.CurrentTimestamp()))
+	if !approved {
+		request.status = common.FAILURE
+		r.SetStatus(common.FAILURE)
+	} else if request.CountApprovals() >= request.gate.GetMinApprovals() {
+		request.status = common.SUCCESS
+		r.SetStatus(common.RUNNING)
+	}
+	r.UpdateModificationDate()
+	return nil
+}
+
+// isApproved returns true if an approval requested on the step with the given number has been granted.
+func (r *WorkflowRun) isApproved(stepNumber int) bool {
+	for _, request := range r.approvalRequests {
+		if request.stepNumber == stepNumber && request.status == common.SUCCESS {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpirePendingApproval fails the run if its pending approval has expired.
+// Returns true if the run has been failed.
+func (r *WorkflowRun) ExpirePendingApproval() bool {
+	request := r.GetPendingApproval()
+	if request == nil || !request.IsExpired() {
+		return false
+	}
+	request.status = common.FAILURE
+	r.SetStatus(common.FAILURE)
+	r.UpdateModificationDate()
+	return true
+}
+
+// GetEnvironment returns the identifier of the environment targeted by the run, or nil if it targets none.
+func (r *WorkflowRun) GetEnvironment() *common.Identifier {
+	return r.environment
+}
+
+// SetEnvironment tags the run with the identifier of the environment it targets.
+func (r *WorkflowRun) SetEnvironment(environmentIdentifier *common.Identifier) {
+	r.environment = environmentIdentifier
+}
+
+// GetPromotedFrom returns the identifier of the run whose deployment is promoted by this run, or nil if it is not a promotion.
+func (r *WorkflowRun) GetPromotedFrom() *common.Identifier {
+	return r.promotedFrom
+}
+
+// SetPromotedFrom marks the run as the promotion of the deployment performed by the run with the given identifier.
+func (r *WorkflowRun) SetPromotedFrom(runIdentifier *common.Identifier) {
+	r.promotedFrom = runIdentifier
+}
+
+// GetMode returns the RunMode of the run.
+func (r *WorkflowRun) GetMode() RunMode {
+	return r.mode
+}
+
+// SetMode sets the RunMode of the run.
+func (r *WorkflowRun) SetMode(mode RunMode) {
+	r.mode = mode
+}
+
+// GetAppliedPlan returns the identifier of the PLAN_ONLY run applied by this run, or nil if it does not apply a plan.
+func (r *WorkflowRun) GetAppliedPlan() *common.Identifier {
+	return r.appliedPlan
+}
+
+// SetAppliedPlan sets the identifier of the PLAN_ONLY run applied by this run.
+func (r *WorkflowRun) SetAppliedPlan(planRunIdentifier *common.Identifier) {
+	r.appliedPlan = planRunIdentifier
+}
+
+// ListPlans returns the step plans saved by the run.
+func (r *WorkflowRun) ListPlans() []*StepPlan {
+	return append([]*StepPlan(nil), r.plans...)
+}
+
+// SetPlans replaces the step plans of the run, typically when reloading it from a data store.
+func (r *WorkflowRun) SetPlans(plans []*StepPlan) {
+	r.plans = append([]*StepPlan(nil), plans...)
+}
+
+// GetPlan returns the plan saved for the step with the given number, or nil if none was saved.
+func (r *WorkflowRun) GetPlan(stepNumber int) *StepPlan {
+	for _, plan := range r.plans {
+		if plan.stepNumber == stepNumber {
+			return plan
+		}
+	}
+	return nil
+}
+
+// RecordPlan saves the plan computed for a step, replacing any plan previously saved for the same step.
+// Returns an error if the run is not a PLAN_ONLY run.
+func (r *WorkflowRun) RecordPlan(plan *StepPlan) error {
+	if r.mode != PLAN_ONLY {
+		return ErrRunIsNotAPlan
+	}
+	plans := []*StepPlan{}
+	for _, existing := range r.plans {
+		if existing.stepNumber != plan.stepNumber {
+			plans = append(plans, existing)
+		}
+	}
+	r.plans = append(plans, plan)
+	r.UpdateModificationDate()
+	return nil
+}
+
+// copyValues returns a copy of values indexed by attribute name.
+func copyValues(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for name, value := range values {
+		copied[name] = value
+	}
+	return copied
+}
+
+// RecordStepOutputs saves the output values produced by a step, indexed by template output name.
+// It replaces any output previously saved for the same step.
+func (r *WorkflowRun) RecordStepOutputs(stepNumber int, outputs map[string]string) {
+	r.stepOutputs[stepNumber] = copyValues(outputs)
+	r.UpdateModificationDate()
+}
+
+// GetStepOutputs returns a copy of the output values produced by the step with the given number.
+func (r *WorkflowRun) GetStepOutputs(stepNumber int) map[string]string {
+	return copyValues(r.stepOutputs[stepNumber])
+}
+
+// ListStepOutputs returns a copy of the output values produced by each step, indexed by step number.
+func (r *WorkflowRun) ListStepOutputs() map[int]map[string]string {
+	stepOutputs := make(map[int]map[string]string, len(r.stepOutputs))
+	for stepNumber, outputs := range r.stepOutputs {
+		stepOutputs[stepNumber] = copyValues(outputs)
+	}
+	return stepOutputs
+}
+
+// SetStepOutputs replaces the step output values of the run, typically when reloading it from a data store.
+func (r *WorkflowRun) SetStepOutputs(stepOutputs map[int]map[string]string) {
+	r.stepOutputs = make(map[int]map[string]string, len(stepOutputs))
+	for stepNumber, outputs := range stepOutputs {
+		r.stepOutputs[stepNumber] = copyValues(outputs)
+	}
+}
+
+// GetOutputs returns a copy of the resolved output values of the run, indexed by workflow output name.
+// Outputs are only resolved once the run completed successfully.
+func (r *WorkflowRun) GetOutputs() map[string]string {
+	return copyValues(r.outputs)
+}
+
+// SetOutputs replaces the resolved output values of the run, typically when reloading it from a data store.
+func (r *WorkflowRun) SetOutputs(outputs map[string]string) {
+	r.outputs = copyValues(outputs)
+}
+
 // WorkflowRunComparator is used to compare two WorkflowRun instances
 // based on their identifier. It enables deterministic sorting within lists.
 type WorkflowRunComparator struct{}
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"testing"
+	"time"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
 )
@@ -22,3 +23,87 @@ func TestWorkflowRun(t *testing.T) {
 		t.Errorf("expected %s to be %s", workflowRun.GetName(), name)
 	}
 }
+
+func TestWorkflowRunApproval(t *testing.T) {
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	alice := newTestUser(t, "alice")
+	bob := newTestUser(t, "bob")
+	eve := newTestUser(t, "eve")
+	gate, _ := NewApprovalGate([]*common.Identifier{alice.GetIdentifier(), bob.GetIdentifier()}, nil, 2, time.Hour)
+	approvalStep, _ := NewApprovalStep(workflowId, "approve-prod", "", 2, gate)
+	regularStep, _ := NewWorkflowStep(workflowId, "apply", "", 3, nil)
+
+	run, _ := NewWorkflowRun(workflowId, "run", "")
+	if run.GetStatus() != common.PENDING {
+		t.Errorf("expected new run to be pending, got %s", run.GetStatus().ToString())
+	}
+	if err := run.SubmitApproval(alice, true, ""); err != ErrNoPendingApproval {
+		t.Errorf("expected err to be ErrNoPendingApproval, got %v", err)
+	}
+	if err := run.RequestApproval(regularStep); err != ErrStepIsNotApprovalGate {
+		t.Errorf("expected err to be ErrStepIsNotApprovalGate, got %v", err)
+	}
+	if err := run.RequestApproval(approvalStep); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.WAITING {
+		t.Errorf("expected run to be waiting, got %s", run.GetStatus().ToString())
+	}
+	if err := run.RequestApproval(approvalStep); err != ErrRunAlreadyWaitingApproval {
+		t.Errorf("expected err to be ErrRunAlreadyWaitingApproval, got %v", err)
+	}
+
+	if err := run.SubmitApproval(eve, true, ""); err != ErrNotAnApprover {
+		t.Errorf("expected err to be ErrNotAnApprover, got %v", err)
+	}
+	if err := run.SubmitApproval(alice, true, "looks good"); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := run.SubmitApproval(alice, true, ""); err != ErrApprovalAlreadySubmitted {
+		t.Errorf("expected err to be ErrApprovalAlreadySubmitted, got %v", err)
+	}
+	if run.GetStatus() != common.WAITING {
+		t.Errorf("expected run to wait for a second approval, got %s", run.GetStatus().ToString())
+	}
+	if err := run.SubmitApproval(bob, true, ""); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.RUNNING || run.GetPendingApproval() != nil {
+		t.Errorf("expected run to be resumed, got %s", run.GetStatus().ToString())
+	}
+	requests := run.ListApprovalRequests()
+	if len(requests) != 1 || requests[0].GetStatus() != common.SUCCESS || requests[0].CountApprovals() != 2 {
+		t.Error("expected a single successful approval request with 2 approvals")
+	}
+	if requests[0].ListDecisions()[0].GetComment() != "looks good" {
+		t.Errorf("expected comment to be recorded, got %q", requests[0].ListDecisions()[0].GetComment())
+	}
+
+	run.RequestApproval(approvalStep)
+	if err := run.SubmitApproval(bob, false, "not now"); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if run.GetStatus() != common.FAILURE {
+		t.Errorf("expected rejected run to fail, got %s", run.GetStatus().ToString())
+	}
+}
+
+func TestWorkflowRunApprovalExpiry(t *testing.T) {
+	workflowId := "autops::project:ABCDEFGHIJ:workflow:1234567890"
+	alice := newTestUser(t, "alice")
+	gate, _ := NewApprovalGate([]*common.Identifier{alice.GetIdentifier()}, nil, 1, time.Hour)
+	run, _ := NewWorkflowRun(workflowId, "run", "")
+	run.SetStatus(common.WAITING)
+	expired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	run.SetApprovalRequests([]*ApprovalRequest{ExistingApprovalRequest(1, gate, common.WAITING, expired, nil)})
+
+	if err := run.SubmitApproval(alice, true, ""); err != ErrApprovalExpired {
+		t.Errorf("expected err to be ErrApprovalExpired, got %v", err)
+	}
+	if run.GetStatus() != common.FAILURE {
+		t.Errorf("expected expired run to fail, got %s", run.GetStatus().ToString())
+	}
+	if run.ExpirePendingApproval() {
+		t.Error("expected no pending approval to expire")
+	}
+}
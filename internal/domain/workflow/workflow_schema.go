@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// GetInputSchema returns the JSON Schema describing the inputs of the workflow, used to render the form starting a run.
+func (w *Workflow) GetInputSchema() *common.InputSchema {
+	properties := make([]*common.SchemaProperty, 0, w.inputs.Len())
+	for _, input := range w.inputs.Items() {
+		properties = append(properties, common.NewSchemaProperty(input.GetName(), input.GetDescription(), input.GetType(), input.GetDefaultValue(), input.IsSensitive(), input.GetConstraints()))
+	}
+	return common.NewInputSchema(w.GetName(), w.GetDescription(), properties)
+}
+
+// AddInputsFromSchema creates an input for each property of the schema, with its type, description, default value, sensitivity and constraints.
+// Either every input is added or none is.
+//
+// Returns the created inputs, or an error prefixed by the name of the first invalid property,
+// wrapping ErrWorkflowInputNameAlreadyUsed if the workflow already declares an input with this name.
+func (w *Workflow) AddInputsFromSchema(schema *common.InputSchema) ([]*WorkflowAttribute, error) {
+	declared := map[string]bool{}
+	for _, input := range w.inputs.Items() {
+		declared[input.GetName()] = true
+	}
+	inputs := []*WorkflowAttribute{}
+	for _, property := range schema.ListProperties() {
+		if declared[property.GetName()] {
+			return nil, fmt.Errorf("input %q: %w", property.GetName(), ErrWorkflowInputNameAlreadyUsed)
+		}
+		input, err := newWorkflowAttributeFromProperty(w.GetIdentifier().ToString(), property)
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", property.GetName(), err)
+		}
+		inputs = append(inputs, input)
+	}
+	for _, input := range inputs {
+		if err := w.AddInput(input); err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+// newWorkflowAttributeFromProperty creates a WorkflowAttribute described by a property of an input schema.
+// The sensitivity is set before the default value, which is a secret reference for sensitive attributes.
+func newWorkflowAttributeFromProperty(workflowId string, property *common.SchemaProperty) (*WorkflowAttribute, error) {
+	attribute, err := NewWorkflowAttribute(workflowId, property.GetName(), property.GetDescription(), property.GetType(), "")
+	if err != nil {
+		return nil, err
+	}
+	if err := attribute.SetSensitive(property.IsSensitive()); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetConstraints(property.GetConstraints()); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetDefaultValue(property.GetDefaultValue()); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+func TestWorkflowInputSchema(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "network", "", "/path/to/file.zip")
+	region, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "region", "", STRING, "eu-west-1")
+	workflow.AddInput(region)
+
+	schema, _ := common.ParseInputSchema([]byte(`{"properties": {
+		"replicas": {"type": "number", "default": 2, "minimum": 1},
+		"token": {"type": "string", "writeOnly": true, "default": "${autops::project:ABCDEFGHIJ:secret:1234567890}"}
+	}}`))
+	inputs, err := workflow.AddInputsFromSchema(schema)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if len(inputs) != 2 || len(workflow.ListInputs()) != 3 {
+		t.Fatalf("expected 2 created inputs, got %d", len(inputs))
+	}
+	if replicas := inputs[0]; replicas.GetType() != NUMBER || replicas.GetDefaultValue() != "2" || replicas.ValidateValue("0") == nil {
+		t.Errorf("unexpected replicas input")
+	}
+	if token := inputs[1]; !token.IsSensitive() || token.GetDefaultValue() == "" {
+		t.Errorf("unexpected token input")
+	}
+	if properties := workflow.GetInputSchema().ListProperties(); len(properties) != 3 || properties[0].GetName() != "region" {
+		t.Errorf("expected the schema to describe the 3 inputs, got %d", len(properties))
+	}
+
+	duplicate, _ := common.ParseInputSchema([]byte(`{"properties": {"zone": {"type": "string"}, "region": {"type": "string"}}}`))
+	if _, err := workflow.AddInputsFromSchema(duplicate); !errors.Is(err, ErrWorkflowInputNameAlreadyUsed) {
+		t.Errorf("expected err to be ErrWorkflowInputNameAlreadyUsed, got %v", err)
+	}
+	invalid, _ := common.ParseInputSchema([]byte(`{"properties": {"zone": {"type": "string"}, "size": {"type": "number", "default": 0, "minimum": 1}}}`))
+	if _, err := workflow.AddInputsFromSchema(invalid); err == nil {
+		t.Error("expected an error for a default value violating the constraints")
+	}
+	if len(workflow.ListInputs()) != 3 {
+		t.Errorf("expected no input to be added by a failed import, got %d inputs", len(workflow.ListInputs()))
+	}
+}
@@ -7,10 +7,14 @@ import (
 
 // WorkflowStep represents a single step in a workflow.
 // Each step has a unique identifier, a name, a description, a step number, and is associated with a task (template).
+// A step can also be an approval gate, pausing the run until it is manually approved.
+// The task can be pinned by a semantic version range, accepting the compatible upgrades of its template.
 type WorkflowStep struct {
 	common.NamedEntity
-	stepNumber int
-	task       *template.Template
+	stepNumber   int
+	task         *template.Template
+	versionRange *common.VersionRange
+	approvalGate *ApprovalGate
 }
 
 // NewWorkflowStep creates a new WorkflowStep with a generated identifier.
@@ -32,12 +36,24 @@ func ExistingWorkflowStep(identifier string, name string, description string, st
 	}
 
 	return &WorkflowStep{
-		NamedEntity: *namedEntity,
-		stepNumber:  stepNumber,
-		task:        task,
+		NamedEntity:  *namedEntity,
+		stepNumber:   stepNumber,
+		task:         task,
+		approvalGate: nil,
 	}, nil
 }
 
+// NewApprovalStep creates a new WorkflowStep acting as an approval gate, with a generated identifier and no task.
+// Returns an error if the name or description is invalid.
+func NewApprovalStep(workflowId string, name string, description string, stepNumber int, gate *ApprovalGate) (*WorkflowStep, error) {
+	step, err := NewWorkflowStep(workflowId, name, description, stepNumber, nil)
+	if err != nil {
+		return nil, err
+	}
+	step.SetApprovalGate(gate)
+	return step, nil
+}
+
 // GetStepNumber returns the step number of the WorkflowStep.
 func (s *WorkflowStep) GetStepNumber() int {
 	return s.stepNumber
@@ -48,6 +64,63 @@ func (s *WorkflowStep) GetTask() *template.Template {
 	return s.task
 }
 
+// GetVersionRange returns the semantic version range pinning the task, or nil if the task is pinned to its exact version.
+func (s *WorkflowStep) GetVersionRange() *common.VersionRange {
+	return s.versionRange
+}
+
+// SetVersionRange pins the task by a semantic version range, or to its exact version if nil.
+// Returns an error if the release version of the current task is outside the range.
+func (s *WorkflowStep) SetVersionRange(versionRange *common.VersionRange) error {
+	if versionRange != nil && (s.task == nil || !versionRange.Contains(s.task.GetReleaseVersion())) {
+		return ErrTemplateVersionOutOfRange
+	}
+	s.versionRange = versionRange
+	return nil
+}
+
+// AcceptsTaskVersion returns true if the template version can be used as the task of the step:
+// it must be a version of the current task, within the version range if the step has one, or the exact same version otherwise.
+func (s *WorkflowStep) AcceptsTaskVersion(candidate *template.Template) bool {
+	if s.task == nil || candidate == nil || candidate.GetIdentifier().ToString() != s.task.GetIdentifier().ToString() {
+		return false
+	}
+	if s.versionRange == nil {
+		return candidate.GetVersion() == s.task.GetVersion()
+	}
+	return s.versionRange.Contains(candidate.GetReleaseVersion())
+}
+
+// SelectTaskVersion returns the highest of the template versions accepted by the step (see AcceptsTaskVersion).
+// Returns ErrNoMatchingTemplateVersion if none is accepted.
+func (s *WorkflowStep) SelectTaskVersion(versions []*template.Template) (*template.Template, error) {
+	var selected *template.Template
+	for _, candidate := range versions {
+		if s.AcceptsTaskVersion(candidate) && (selected == nil || candidate.GetReleaseVersion().Compare(selected.GetReleaseVersion()) > 0) {
+			selected = candidate
+		}
+	}
+	if selected == nil {
+		return nil, ErrNoMatchingTemplateVersion
+	}
+	return selected, nil
+}
+
+// GetApprovalGate returns the ApprovalGate of the step, or nil if the step does not require an approval.
+func (s *WorkflowStep) GetApprovalGate() *ApprovalGate {
+	return s.approvalGate
+}
+
+// SetApprovalGate makes the step an approval gate, or a regular step if gate is nil.
+func (s *WorkflowStep) SetApprovalGate(gate *ApprovalGate) {
+	s.approvalGate = gate
+}
+
+// IsApprovalGate returns true if the step requires a manual approval.
+func (s *WorkflowStep) IsApprovalGate() bool {
+	return s.approvalGate != nil
+}
+
 // WorkflowStepComparator provides comparison logic between two WorkflowSteps based on their step numbers.
 type WorkflowStepComparator struct{}
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
 )
 
 func TestWorkflow(t *testing.T) {
@@ -143,3 +144,222 @@ func TestWorkflowRuns(t *testing.T) {
 		t.Error("expected err to be ErrWorkflowRunAlreadyPresent")
 	}
 }
+
+func TestWorkflowHandleWebhook(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "valid-name", "", "/path/to/file.zip")
+	commit, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "commit", "", STRING, "")
+	workflow.AddInput(commit)
+	trigger, _ := NewWebhookTrigger(workflow.GetIdentifier().ToString(), "on-push", "", "$.ref == \"refs/heads/main\"", map[string]string{"commit": "$.after"})
+	if err := workflow.AddTrigger(trigger); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.AddTrigger(trigger); err != ErrWebhookTriggerAlreadyPresent {
+		t.Errorf("expected err to be ErrWebhookTriggerAlreadyPresent, got %v", err)
+	}
+
+	payload := []byte(`{"ref": "refs/heads/main", "after": "0123abcd"}`)
+	_, err := workflow.HandleWebhook("autops::project:ABCDEFGHIJ:workflow:1234567890:trigger:unknown123", payload, signPayload(trigger.GetSecret(), payload))
+	if err != ErrWebhookTriggerNotFound {
+		t.Errorf("expected err to be ErrWebhookTriggerNotFound, got %v", err)
+	}
+	_, err = workflow.HandleWebhook(trigger.GetIdentifier().ToString(), payload, "sha256=00")
+	if err != ErrInvalidWebhookSignature {
+		t.Errorf("expected err to be ErrInvalidWebhookSignature, got %v", err)
+	}
+	invalid := []byte("not json")
+	_, err = workflow.HandleWebhook(trigger.GetIdentifier().ToString(), invalid, signPayload(trigger.GetSecret(), invalid))
+	if err != ErrInvalidWebhookPayload {
+		t.Errorf("expected err to be ErrInvalidWebhookPayload, got %v", err)
+	}
+	filtered := []byte(`{"ref": "refs/heads/dev"}`)
+	_, err = workflow.HandleWebhook(trigger.GetIdentifier().ToString(), filtered, signPayload(trigger.GetSecret(), filtered))
+	if err != ErrWebhookPayloadFiltered {
+		t.Errorf("expected err to be ErrWebhookPayloadFiltered, got %v", err)
+	}
+	if len(workflow.ListRuns()) != 0 {
+		t.Errorf("expected 0 runs, got %d", len(workflow.ListRuns()))
+	}
+
+	run, err := workflow.HandleWebhook(trigger.GetIdentifier().ToString(), payload, signPayload(trigger.GetSecret(), payload))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetTriggeredBy() != trigger.GetIdentifier() {
+		t.Errorf("expected run to be attributed to %s, got %v", trigger.GetIdentifier().ToString(), run.GetTriggeredBy())
+	}
+	if run.GetInputs()["commit"] != "0123abcd" {
+		t.Errorf("expected commit input to be 0123abcd, got %v", run.GetInputs())
+	}
+	if len(workflow.ListRuns()) != 1 {
+		t.Errorf("expected 1 run, got %d", len(workflow.ListRuns()))
+	}
+
+	if err := workflow.RemoveTrigger(trigger.GetIdentifier().ToString()); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if err := workflow.RemoveTrigger(trigger.GetIdentifier().ToString()); err != ErrWebhookTriggerNotFound {
+		t.Errorf("expected err to be ErrWebhookTriggerNotFound, got %v", err)
+	}
+}
+
+func TestWorkflowResolveInputs(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "valid-name", "", "/path/to/file.zip")
+	replicas, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "replicas", "", NUMBER, "")
+	region, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "region", "", STRING, "eu-west-1")
+	workflow.AddInput(replicas)
+	workflow.AddInput(region)
+
+	inputs, err := workflow.ResolveInputs(map[string]string{"replicas": "3"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if inputs["replicas"] != "3" || inputs["region"] != "eu-west-1" {
+		t.Errorf("expected default value to be applied, got %v", inputs)
+	}
+
+	_, err = workflow.ResolveInputs(map[string]string{"replicas": "three", "zone": "a"})
+	validationErr, ok := err.(*AttributeValidationError)
+	if !ok {
+		t.Fatalf("expected err to be an AttributeValidationError, got %v", err)
+	}
+	fields := validationErr.ListFieldErrors()
+	if len(fields) != 2 || fields["replicas"] == nil || fields["zone"] != ErrUnknownWorkflowInput {
+		t.Errorf("expected replicas and zone to be invalid, got %v", fields)
+	}
+
+	_, err = workflow.ResolveInputs(map[string]string{})
+	validationErr, ok = err.(*AttributeValidationError)
+	if !ok || validationErr.ListFieldErrors()["replicas"] != ErrMissingWorkflowInput {
+		t.Errorf("expected err to report ErrMissingWorkflowInput for replicas, got %v", err)
+	}
+}
+
+func TestWorkflowResolveInputsConstraints(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "valid-name", "", "/path/to/file.zip")
+	region, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "region", "", STRING, "")
+	constraints := common.NewAttributeConstraints()
+	constraints.SetRequired(true)
+	constraints.SetAllowedValues([]string{"eu-west-1", "us-east-1"})
+	constraints.SetPattern("^[a-z0-9-]+$")
+	region.SetConstraints(constraints)
+	workflow.AddInput(region)
+
+	if _, err := workflow.ResolveInputs(map[string]string{"region": "us-east-1"}); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	_, err := workflow.ResolveInputs(map[string]string{"region": "EU"})
+	validationErr, ok := err.(*AttributeValidationError)
+	if !ok {
+		t.Fatalf("expected err to be an AttributeValidationError, got %v", err)
+	}
+	violationErr, ok := validationErr.ListFieldErrors()["region"].(*common.ConstraintViolationError)
+	if !ok || len(violationErr.ListViolations()) != 2 {
+		t.Errorf("expected both violations to be reported, got %v", validationErr.ListFieldErrors()["region"])
+	}
+	if _, err := workflow.ResolveInputs(map[string]string{"region": ""}); err == nil {
+		t.Error("expected an empty value of a required input to be rejected")
+	}
+}
+
+func TestWorkflowStartRun(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "valid-name", "", "/path/to/file.zip")
+	enabled, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "enabled", "", BOOL, "")
+	workflow.AddInput(enabled)
+
+	if _, err := workflow.StartRun("run", "", STANDARD, map[string]string{"enabled": "yes"}); err == nil {
+		t.Error("expected err to be an AttributeValidationError")
+	}
+	if len(workflow.ListRuns()) != 0 {
+		t.Errorf("expected 0 runs, got %d", len(workflow.ListRuns()))
+	}
+
+	run, err := workflow.StartRun("run", "", PLAN_ONLY, map[string]string{"enabled": "true"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if run.GetMode() != PLAN_ONLY || run.GetInputs()["enabled"] != "true" {
+		t.Errorf("expected plan run with enabled input, got %s %v", run.GetMode().ToString(), run.GetInputs())
+	}
+	if len(workflow.ListRuns()) != 1 {
+		t.Errorf("expected 1 run, got %d", len(workflow.ListRuns()))
+	}
+}
+
+func TestWorkflowApplyPlan(t *testing.T) {
+	workflow, _ := NewWorkflow("autops::project:ABCDEFGHIJ", "valid-name", "", "/path/to/file.zip")
+	terraform, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 1)
+	step, _ := NewWorkflowStep(workflow.GetIdentifier().ToString(), "network", "", 1, terraform)
+	workflow.AddStep(step)
+	env, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "env", "", STRING, "")
+	workflow.AddInput(env)
+
+	standardRun, _ := NewWorkflowRun(workflow.GetIdentifier().ToString(), "standard", "")
+	workflow.AddRun(standardRun)
+	if err := standardRun.RecordPlan(&StepPlan{}); err != ErrRunIsNotAPlan {
+		t.Errorf("expected err to be ErrRunIsNotAPlan, got %v", err)
+	}
+
+	planRun, _ := NewWorkflowRun(workflow.GetIdentifier().ToString(), "plan", "")
+	planRun.SetMode(PLAN_ONLY)
+	planRun.SetInputs(map[string]string{"env": "prod"})
+	workflow.AddRun(planRun)
+	plan, _ := NewStepPlan(step, workflow.GetVersion(), map[string]string{"env": "prod"}, nil, "", []byte(`{"resource_changes": []}`))
+	if err := planRun.RecordPlan(plan); err != nil {
+		t.Errorf("expected err to be nil, got %v", err)
+	}
+	if planRun.GetPlan(1) != plan {
+		t.Error("expected plan to be recorded for step 1")
+	}
+
+	if _, err := workflow.ApplyPlan("autops::project:ABCDEFGHIJ:workflow:1234567890:run:unknown123"); err != ErrWorkflowRunNotFound {
+		t.Errorf("expected err to be ErrWorkflowRunNotFound, got %v", err)
+	}
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrRunIsNotAPlan {
+		t.Errorf("expected err to be ErrRunIsNotAPlan for an unfinished plan, got %v", err)
+	}
+	planRun.SetStatus(common.SUCCESS)
+
+	database, _ := NewWorkflowStep(workflow.GetIdentifier().ToString(), "database", "", 2, terraform)
+	workflow.AddStep(database)
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanMissing {
+		t.Errorf("expected err to be ErrPlanMissing for an unplanned step, got %v", err)
+	}
+	workflow.RemoveStep(2)
+	planRun.SetInputs(map[string]string{"env": "staging"})
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for a plan computed with other inputs, got %v", err)
+	}
+	planRun.SetInputs(map[string]string{"env": "prod"})
+	outdated, _ := NewStepPlan(step, workflow.GetVersion()-1, map[string]string{"env": "prod"}, nil, "", []byte(`{"resource_changes": []}`))
+	planRun.RecordPlan(outdated)
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for a plan of another workflow version, got %v", err)
+	}
+	planRun.RecordPlan(plan)
+
+	applyRun, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString())
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if applyRun.GetMode() != APPLY_PLAN || applyRun.GetAppliedPlan() != planRun.GetIdentifier() || applyRun.GetInputs()["env"] != "prod" {
+		t.Error("unexpected apply run attributes")
+	}
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanAlreadyApplied {
+		t.Errorf("expected err to be ErrPlanAlreadyApplied, got %v", err)
+	}
+	applyRun.SetStatus(common.FAILURE)
+
+	region, _ := NewWorkflowAttribute(workflow.GetIdentifier().ToString(), "region", "", STRING, "eu-west-1")
+	workflow.AddInput(region)
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated for an apply run resolving other inputs, got %v", err)
+	}
+
+	newVersion, _ := template.ExistingTemplate("autops::project:ABCDEFGHIJ:template:1234567890", "vpc", "", common.SUCCESS, template.TERRAFORM, "path/to/vpc.zip", 2)
+	workflow.RemoveStep(1)
+	newStep, _ := NewWorkflowStep(workflow.GetIdentifier().ToString(), "network", "", 1, newVersion)
+	workflow.AddStep(newStep)
+	if _, err := workflow.ApplyPlan(planRun.GetIdentifier().ToString()); err != ErrPlanOutdated {
+		t.Errorf("expected err to be ErrPlanOutdated, got %v", err)
+	}
+}
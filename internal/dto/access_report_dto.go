@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/accessreport"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// AccessReportDTO lists the grants of an effective permissions report, evaluated at the generation date.
+type AccessReportDTO struct {
+	GeneratedAt string     `json:"generated_at"`
+	Grants      []GrantDTO `json:"grants"`
+}
+
+// GrantDTO is an action a user, group or role is allowed to perform on a resource, with the policies allowing it.
+// The principal type is "user", "group" or "role".
+type GrantDTO struct {
+	PrincipalType string           `json:"principal_type"`
+	PrincipalId   string           `json:"principal_id"`
+	PrincipalName string           `json:"principal_name"`
+	ResourceType  string           `json:"resource_type"`
+	ResourceId    string           `json:"resource_id"`
+	ResourceName  string           `json:"resource_name"`
+	Action        string           `json:"action"`
+	Policies      []GrantPolicyDTO `json:"policies"`
+}
+
+type GrantPolicyDTO struct {
+	PolicyId   string `json:"policy_id"`
+	PolicyName string `json:"policy_name"`
+}
+
+// NewAccessReportDTO maps a Report to its DTO, with actions formatted as "resource_type:action".
+func NewAccessReportDTO(report *accessreport.Report) AccessReportDTO {
+	result := AccessReportDTO{GeneratedAt: report.GetGeneratedAt(), Grants: []GrantDTO{}}
+	for _, grant := range report.ListGrants() {
+		principalType, _ := grant.GetPrincipal().GetType().ToString()
+		resourceType, _ := grant.GetResource().GetType().ToString()
+		action, _ := policy.FormatPolicyAction(grant.GetAction())
+		grantDTO := GrantDTO{
+			PrincipalType: principalType,
+			PrincipalId:   grant.GetPrincipal().ToString(),
+			PrincipalName: grant.GetPrincipalName(),
+			ResourceType:  resourceType,
+			ResourceId:    grant.GetResource().ToString(),
+			ResourceName:  grant.GetResourceName(),
+			Action:        action,
+			Policies:      []GrantPolicyDTO{},
+		}
+		for _, p := range grant.ListPolicies() {
+			grantDTO.Policies = append(grantDTO.Policies, GrantPolicyDTO{PolicyId: p.GetIdentifier().ToString(), PolicyName: p.GetName()})
+		}
+		result.Grants = append(result.Grants, grantDTO)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+type ApprovalDecisionDTO struct {
+	Approver  string `json:"approver"`
+	Approved  bool   `json:"approved"`
+	Comment   string `json:"comment"`
+	DecidedAt string `json:"decided_at"`
+}
+
+type ApprovalRequestDTO struct {
+	StepNumber     int                   `json:"step_number"`
+	Status         string                `json:"status"`
+	Approvers      []string              `json:"approvers"`
+	ApproverAction *string               `json:"approver_action"`
+	MinApprovals   int                   `json:"min_approvals"`
+	ExpiresAt      string                `json:"expires_at"`
+	Decisions      []ApprovalDecisionDTO `json:"decisions"`
+}
+
+type SubmitApprovalDTO struct {
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment"`
+}
+
+// NewApprovalRequestDTO maps an ApprovalRequest to its DTO.
+func NewApprovalRequestDTO(request *workflow.ApprovalRequest) ApprovalRequestDTO {
+	gate := request.GetGate()
+	result := ApprovalRequestDTO{
+		StepNumber:     request.GetStepNumber(),
+		Status:         request.GetStatus().ToString(),
+		Approvers:      []string{},
+		ApproverAction: nil,
+		MinApprovals:   gate.GetMinApprovals(),
+		ExpiresAt:      request.GetExpiresAt(),
+		Decisions:      []ApprovalDecisionDTO{},
+	}
+	for _, approver := range gate.ListApprovers() {
+		result.Approvers = append(result.Approvers, approver.ToString())
+	}
+	if gate.GetApproverAction() != nil {
+		if action, err := policy.FormatPolicyAction(gate.GetApproverAction()); err == nil {
+			result.ApproverAction = &action
+		}
+	}
+	for _, decision := range request.ListDecisions() {
+		result.Decisions = append(result.Decisions, ApprovalDecisionDTO{
+			Approver:  decision.GetApprover().ToString(),
+			Approved:  decision.IsApproved(),
+			Comment:   decision.GetComment(),
+			DecidedAt: decision.GetDecidedAt(),
+		})
+	}
+	return result
+}
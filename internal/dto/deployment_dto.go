@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/project"
+
+type DeploymentDTO struct {
+	WorkflowIdentifier string         `json:"workflow_id"`
+	WorkflowVersion    int            `json:"workflow_version"`
+	TemplateVersions   map[string]int `json:"template_versions"`
+	RunIdentifier      string         `json:"run_id"`
+	DeployedAt         string         `json:"deployed_at"`
+}
+
+// PipelineDTO lists the environment identifiers of a promotion pipeline, from the first stage to the last.
+type PipelineDTO struct {
+	Environments []string `json:"environments"`
+}
+
+type PromoteWorkflowDTO struct {
+	WorkflowIdentifier string `json:"workflow_id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+}
+
+// NewDeploymentDTO maps a Deployment to its DTO.
+func NewDeploymentDTO(d *project.Deployment) DeploymentDTO {
+	return DeploymentDTO{
+		WorkflowIdentifier: d.GetWorkflowIdentifier().ToString(),
+		WorkflowVersion:    d.GetWorkflowVersion(),
+		TemplateVersions:   d.GetTemplateVersions(),
+		RunIdentifier:      d.GetRunIdentifier().ToString(),
+		DeployedAt:         d.GetDeployedAt(),
+	}
+}
+
+// NewPipelineDTO maps the promotion pipeline of a Project to its DTO.
+func NewPipelineDTO(p *project.Project) PipelineDTO {
+	result := PipelineDTO{Environments: []string{}}
+	for _, id := range p.ListPipeline() {
+		result.Environments = append(result.Environments, id.ToString())
+	}
+	return result
+}
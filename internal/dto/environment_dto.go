@@ -0,0 +1,68 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/project"
+
+type EnvironmentDTO struct {
+	Identifier  string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Protected   bool          `json:"protected"`
+	Variables   []VariableDTO `json:"variables"`
+	Policies    []string      `json:"policies"`
+	CreatedAt   *string       `json:"created_at"`
+	UpdatedAt   *string       `json:"updated_at"`
+}
+
+// VariableDTO carries the value of a variable, which is a secret reference when the variable is sensitive.
+type VariableDTO struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+type CreateEnvironmentDTO struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Protected   bool          `json:"protected"`
+	Variables   []VariableDTO `json:"variables"`
+}
+
+// StartEnvironmentRunDTO starts a run of a workflow of the project against an environment.
+type StartEnvironmentRunDTO struct {
+	WorkflowIdentifier string `json:"workflow_id"`
+	StartWorkflowRunDTO
+}
+
+// NewEnvironmentDTO maps an Environment to its DTO, with the identifiers of its policies.
+func NewEnvironmentDTO(e *project.Environment) EnvironmentDTO {
+	createdAt := e.GetCreatedAt()
+	updatedAt := e.GetUpdatedAt()
+	result := EnvironmentDTO{
+		Identifier:  e.GetIdentifier().ToString(),
+		Name:        e.GetName(),
+		Description: e.GetDescription(),
+		Protected:   e.IsProtected(),
+		Variables:   []VariableDTO{},
+		Policies:    []string{},
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &updatedAt,
+	}
+	for _, variable := range e.GetVariableSet().ListVariables() {
+		result.Variables = append(result.Variables, NewVariableDTO(variable))
+	}
+	for _, p := range e.ListPolicies() {
+		result.Policies = append(result.Policies, p.GetIdentifier().ToString())
+	}
+	return result
+}
+
+// NewVariableDTO maps a Variable to its DTO.
+func NewVariableDTO(v *project.Variable) VariableDTO {
+	return VariableDTO{
+		Name:      v.GetName(),
+		Type:      v.GetType().ToString(),
+		Value:     v.GetValue(),
+		Sensitive: v.IsSensitive(),
+	}
+}
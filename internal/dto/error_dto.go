@@ -0,0 +1,5 @@
+package dto
+
+type ErrorDTO struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+type GroupDTO struct {
+	Identifier  string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []TagDTO `json:"tags"`
+	Members     []string `json:"members"`
+	Policies    []string `json:"policies"`
+	CreatedAt   *string  `json:"created_at"`
+	UpdatedAt   *string  `json:"updated_at"`
+}
+
+// CreateGroupDTO creates a group. Its tags are principal tags of its members.
+type CreateGroupDTO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []TagDTO `json:"tags"`
+}
+
+// EffectivePolicyDTO is a policy applying to a user, attached to the user itself, to some of its groups, or both.
+type EffectivePolicyDTO struct {
+	Policy   PolicyDTO `json:"policy"`
+	Attached bool      `json:"attached"`
+	Groups   []string  `json:"groups"`
+}
+
+// NewGroupDTO maps a Group to its DTO, with the identifiers of its members and attached policies.
+func NewGroupDTO(g *identity.Group) GroupDTO {
+	createdAt := g.GetCreatedAt()
+	updatedAt := g.GetUpdatedAt()
+	result := GroupDTO{
+		Identifier:  g.GetIdentifier().ToString(),
+		Name:        g.GetName(),
+		Description: g.GetDescription(),
+		Tags:        NewTagDTOs(g.ListTags()),
+		Members:     []string{},
+		Policies:    []string{},
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &updatedAt,
+	}
+	for _, member := range g.ListMembers() {
+		result.Members = append(result.Members, member.ToString())
+	}
+	for _, p := range g.ListAttachedPolicies() {
+		result.Policies = append(result.Policies, p.GetIdentifier().ToString())
+	}
+	return result
+}
+
+// NewEffectivePolicyDTOs maps the effective policies of a user to their DTOs, in the order of ListEffectivePolicies,
+// with the groups each policy comes from.
+func NewEffectivePolicyDTOs(user *identity.User) []EffectivePolicyDTO {
+	result := []EffectivePolicyDTO{}
+	for _, p := range user.ListEffectivePolicies() {
+		effective := EffectivePolicyDTO{
+			Policy:   NewPolicyDTO(p),
+			Attached: user.GetAttachedPolicy(p.GetIdentifier()) != nil,
+			Groups:   []string{},
+		}
+		for _, group := range user.ListGroups() {
+			if hasPolicy(group.ListAttachedPolicies(), p) {
+				effective.Groups = append(effective.Groups, group.GetIdentifier().ToString())
+			}
+		}
+		result = append(result, effective)
+	}
+	return result
+}
+
+// hasPolicy returns true if the policies contain a policy with the same identifier.
+func hasPolicy(policies []*policy.Policy, p *policy.Policy) bool {
+	for _, candidate := range policies {
+		if candidate.GetIdentifier().ToString() == p.GetIdentifier().ToString() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/manifest"
+
+// ImportPlanDTO lists the changes of a manifest import, and the breaking changes they introduce in the current workflows.
+// Applied is false for dry runs.
+type ImportPlanDTO struct {
+	ProjectId string             `json:"project_id"`
+	Applied   bool               `json:"applied"`
+	Changes   []ImportChangeDTO  `json:"changes"`
+	Warnings  []ImportWarningDTO `json:"warnings"`
+}
+
+type ImportChangeDTO struct {
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	Name         string `json:"name"`
+	Identifier   string `json:"identifier"`
+}
+
+type ImportWarningDTO struct {
+	Workflow   string `json:"workflow"`
+	Template   string `json:"template"`
+	StepNumber int    `json:"step_number"`
+	Message    string `json:"message"`
+}
+
+// NewImportPlanDTO maps an import plan of the project to its DTO.
+func NewImportPlanDTO(projectId string, plan *manifest.ImportPlan, applied bool) ImportPlanDTO {
+	result := ImportPlanDTO{ProjectId: projectId, Applied: applied, Changes: []ImportChangeDTO{}, Warnings: []ImportWarningDTO{}}
+	for _, change := range plan.ListChanges() {
+		result.Changes = append(result.Changes, newImportChangeDTO(change))
+	}
+	for _, warning := range plan.ListWarnings() {
+		result.Warnings = append(result.Warnings, ImportWarningDTO{
+			Workflow:   warning.GetWorkflowName(),
+			Template:   warning.GetTemplateName(),
+			StepNumber: warning.GetUpgradeWarning().GetStepNumber(),
+			Message:    warning.GetUpgradeWarning().GetMessage(),
+		})
+	}
+	return result
+}
+
+// newImportChangeDTO maps a change of an import to its DTO.
+func newImportChangeDTO(change *manifest.Change) ImportChangeDTO {
+	resourceType, _ := change.GetResourceType().ToString()
+	return ImportChangeDTO{
+		Action:       change.GetAction().ToString(),
+		ResourceType: resourceType,
+		Name:         change.GetName(),
+		Identifier:   change.GetIdentifier().ToString(),
+	}
+}
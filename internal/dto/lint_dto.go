@@ -0,0 +1,37 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/policylint"
+
+// LintReportDTO lists the findings of the linter on a policy document. The policy is valid when no finding is an error.
+type LintReportDTO struct {
+	Valid    bool             `json:"valid"`
+	Findings []LintFindingDTO `json:"findings"`
+}
+
+// LintFindingDTO locates a finding on a statement of the policy, and on one of its fields, e.g. "resources[1]", when not empty.
+// Severity is either "error" or "warning".
+type LintFindingDTO struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Statement int    `json:"statement"`
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+}
+
+// NewLintReportDTO maps the findings of the linter to the report DTO.
+func NewLintReportDTO(findings []*policylint.Finding) LintReportDTO {
+	result := LintReportDTO{Valid: true, Findings: []LintFindingDTO{}}
+	for _, finding := range findings {
+		if finding.GetSeverity() == policylint.ERROR {
+			result.Valid = false
+		}
+		result.Findings = append(result.Findings, LintFindingDTO{
+			Rule:      finding.GetRule().ToString(),
+			Severity:  finding.GetSeverity().ToString(),
+			Statement: finding.GetStatement(),
+			Field:     finding.GetField(),
+			Message:   finding.GetMessage(),
+		})
+	}
+	return result
+}
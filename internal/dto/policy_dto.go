@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
 type PolicyDTO struct {
 	Identifier  string               `json:"id"`
 	Name        string               `json:"name"`
@@ -11,7 +16,99 @@ type PolicyDTO struct {
 }
 
 type PolicyStatementDTO struct {
-	Effect    string   `json:"effect"`
-	Actions   []string `json:"actions"`
-	Resources []string `json:"resources"`
+	Effect     string               `json:"effect"`
+	Actions    []string             `json:"actions"`
+	Resources  []string             `json:"resources"`
+	Conditions []PolicyConditionDTO `json:"conditions,omitempty"`
+}
+
+// PolicyConditionDTO restricts a statement to the requests whose context key matches the values with the operator,
+// e.g. {"operator": "StringEquals", "key": "resource:tag/env", "values": ["dev"]}.
+type PolicyConditionDTO struct {
+	Operator string   `json:"operator"`
+	Key      string   `json:"key"`
+	Values   []string `json:"values"`
+}
+
+// PolicyStatementErrorDTO locates the invalid field of a policy statement, e.g. statement 0 and field "actions[1]".
+type PolicyStatementErrorDTO struct {
+	Error     string `json:"error"`
+	Statement int    `json:"statement"`
+	Field     string `json:"field"`
+}
+
+// NewPolicyDTO maps a Policy to its DTO, with actions formatted as "resource_type:action".
+func NewPolicyDTO(p *policy.Policy) PolicyDTO {
+	createdAt := p.GetCreatedAt()
+	updatedAt := p.GetUpdatedAt()
+	result := PolicyDTO{
+		Identifier:  p.GetIdentifier().ToString(),
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Tags:        []TagDTO{},
+		Statements:  []PolicyStatementDTO{},
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &updatedAt,
+	}
+	for _, tag := range p.ListTags() {
+		result.Tags = append(result.Tags, TagDTO{Key: tag.GetKey(), Value: tag.GetValue()})
+	}
+	for _, statement := range p.ListStatements() {
+		effect, _ := statement.GetEffect().ToString()
+		statementDTO := PolicyStatementDTO{Effect: effect, Actions: []string{}, Resources: []string{}}
+		for _, action := range statement.ListActions() {
+			formatted, _ := policy.FormatPolicyAction(action)
+			statementDTO.Actions = append(statementDTO.Actions, formatted)
+		}
+		for _, resource := range statement.ListResources() {
+			statementDTO.Resources = append(statementDTO.Resources, resource.ToString())
+		}
+		for _, condition := range statement.ListConditions() {
+			operator, _ := condition.GetOperator().ToString()
+			statementDTO.Conditions = append(statementDTO.Conditions, PolicyConditionDTO{Operator: operator, Key: condition.GetKey(), Values: condition.ListValues()})
+		}
+		result.Statements = append(result.Statements, statementDTO)
+	}
+	return result
+}
+
+// ParsePolicyDTO creates the policy described by a DTO, with the given identifier and creation date.
+// The identifier and dates of the DTO are ignored, since they are managed by the API.
+//
+// Returns a policy.StatementError locating the first invalid field of the statements, or an error if the name or description is invalid.
+func ParsePolicyDTO(identifier string, createdAt string, d PolicyDTO) (*policy.Policy, error) {
+	statements := make([]*policy.PolicyStatement, 0, len(d.Statements))
+	for i, statement := range d.Statements {
+		parsed, err := policy.ParsePolicyStatement(i, statement.Effect, statement.Actions, statement.Resources)
+		if err != nil {
+			return nil, err
+		}
+		conditions := make([]*policy.Condition, 0, len(statement.Conditions))
+		for j, condition := range statement.Conditions {
+			parsedCondition, err := policy.ParseStatementCondition(i, j, condition.Operator, condition.Key, condition.Values)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, parsedCondition)
+		}
+		parsed.SetConditions(conditions)
+		statements = append(statements, parsed)
+	}
+	p, err := policy.ExistingPolicy(identifier, d.Name, d.Description, createdAt, common.CurrentTimestamp(), statements)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range d.Tags {
+		p.AddTag(common.NewTag(tag.Key, tag.Value))
+	}
+	return p, nil
+}
+
+// NewPolicyStatementErrorDTO maps a StatementError to its DTO.
+func NewPolicyStatementErrorDTO(err *policy.StatementError) PolicyStatementErrorDTO {
+	return PolicyStatementErrorDTO{
+		Error:     err.Error(),
+		Statement: err.GetIndex(),
+		Field:     err.GetField(),
+	}
 }
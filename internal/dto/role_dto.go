@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+)
+
+type RoleDTO struct {
+	Identifier         string   `json:"id"`
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	Tags               []TagDTO `json:"tags"`
+	TrustedPrincipals  []string `json:"trusted_principals"`
+	Policies           []string `json:"policies"`
+	MaxSessionDuration string   `json:"max_session_duration"`
+	CreatedAt          *string  `json:"created_at"`
+	UpdatedAt          *string  `json:"updated_at"`
+}
+
+// CreateRoleDTO creates a role. The maximum session duration is a duration like "1h", defaulting to one hour.
+// The tags of the role are the principal tags of the users acting with it.
+type CreateRoleDTO struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	Tags               []TagDTO `json:"tags"`
+	MaxSessionDuration string   `json:"max_session_duration"`
+}
+
+// AssumeRoleDTO assumes a role for a duration like "30m", defaulting to the maximum session duration of the role.
+// The reason is recorded along with the session.
+type AssumeRoleDTO struct {
+	Duration string `json:"duration"`
+	Reason   string `json:"reason"`
+}
+
+// RoleCredentialsDTO holds the temporary credentials obtained by assuming a role.
+// The token is only disclosed once, and must be sent along with the session identifier to act with the role.
+type RoleCredentialsDTO struct {
+	Session   string `json:"session_id"`
+	Token     string `json:"token"`
+	Role      string `json:"role"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// RoleSessionDTO is the audit record of a user assuming a role.
+type RoleSessionDTO struct {
+	Identifier string `json:"id"`
+	Role       string `json:"role"`
+	Principal  string `json:"principal"`
+	Reason     string `json:"reason"`
+	Expired    bool   `json:"expired"`
+	CreatedAt  string `json:"created_at"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+// NewRoleDTO maps a Role to its DTO, with the identifiers of its trusted principals and attached policies.
+func NewRoleDTO(role *identity.Role) RoleDTO {
+	createdAt := role.GetCreatedAt()
+	updatedAt := role.GetUpdatedAt()
+	result := RoleDTO{
+		Identifier:         role.GetIdentifier().ToString(),
+		Name:               role.GetName(),
+		Description:        role.GetDescription(),
+		Tags:               NewTagDTOs(role.ListTags()),
+		TrustedPrincipals:  []string{},
+		Policies:           []string{},
+		MaxSessionDuration: role.GetMaxSessionDuration().String(),
+		CreatedAt:          &createdAt,
+		UpdatedAt:          &updatedAt,
+	}
+	for _, principal := range role.ListTrustedPrincipals() {
+		result.TrustedPrincipals = append(result.TrustedPrincipals, principal.ToString())
+	}
+	for _, p := range role.ListAttachedPolicies() {
+		result.Policies = append(result.Policies, p.GetIdentifier().ToString())
+	}
+	return result
+}
+
+// NewRoleCredentialsDTO maps a new RoleSession and its token to the credentials returned to the user.
+func NewRoleCredentialsDTO(session *identity.RoleSession, token string) RoleCredentialsDTO {
+	return RoleCredentialsDTO{
+		Session:   session.GetIdentifier().ToString(),
+		Token:     token,
+		Role:      session.GetRole().ToString(),
+		ExpiresAt: session.GetExpiresAt().Format(time.RFC3339),
+	}
+}
+
+// NewRoleSessionDTO maps a RoleSession to its DTO, without its token.
+func NewRoleSessionDTO(session *identity.RoleSession) RoleSessionDTO {
+	return RoleSessionDTO{
+		Identifier: session.GetIdentifier().ToString(),
+		Role:       session.GetRole().ToString(),
+		Principal:  session.GetPrincipal().ToString(),
+		Reason:     session.GetReason(),
+		Expired:    session.IsExpired(time.Now()),
+		CreatedAt:  session.GetCreatedAt(),
+		ExpiresAt:  session.GetExpiresAt().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+
+// SecretDTO never includes the secret value: it is write-only through the API.
+type SecretDTO struct {
+	Identifier      string  `json:"id"`
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	InjectionType   string  `json:"injection_type"`
+	InjectionTarget string  `json:"injection_target"`
+	Reference       string  `json:"reference"`
+	CreatedAt       *string `json:"created_at"`
+	UpdatedAt       *string `json:"updated_at"`
+}
+
+type CreateSecretDTO struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	InjectionType   string `json:"injection_type"`
+	InjectionTarget string `json:"injection_target"`
+	Value           string `json:"value"`
+}
+
+type SecretValueDTO struct {
+	Value string `json:"value"`
+}
+
+// NewSecretDTO maps a Secret to its DTO, with the reference to use as the value of sensitive inputs.
+func NewSecretDTO(s *secret.Secret) SecretDTO {
+	createdAt := s.GetCreatedAt()
+	updatedAt := s.GetUpdatedAt()
+	return SecretDTO{
+		Identifier:      s.GetIdentifier().ToString(),
+		Name:            s.GetName(),
+		Description:     s.GetDescription(),
+		InjectionType:   s.GetInjectionType().ToString(),
+		InjectionTarget: s.GetInjectionTarget(),
+		Reference:       secret.FormatReference(s.GetIdentifier()),
+		CreatedAt:       &createdAt,
+		UpdatedAt:       &updatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+
+// SimulatePolicyDTO requests the access decisions of a principal for every pair of action and resource.
+// The principal is either a user, evaluated with its effective policies including those of its groups and limited by its
+// permission boundary, or a set of policy documents.
+// Context sets the request context keys against which the statement conditions are evaluated, e.g. "resource:tag/env".
+type SimulatePolicyDTO struct {
+	Principal PrincipalDTO      `json:"principal"`
+	Actions   []string          `json:"actions"`
+	Resources []string          `json:"resources"`
+	Context   map[string]string `json:"context"`
+}
+
+type PrincipalDTO struct {
+	User     *string     `json:"user"`
+	Policies []PolicyDTO `json:"policies"`
+}
+
+type SimulationDTO struct {
+	Results []DecisionDTO `json:"results"`
+}
+
+// DecisionDTO explains the access decision for an action on a resource.
+// Effect is "Allow", "Deny", or "Unspecified" when no statement applies, in which case the reason is "default_deny",
+// or when the permission boundary of the user does not allow the action, in which case the reason is "boundary_deny".
+type DecisionDTO struct {
+	Action   string              `json:"action"`
+	Resource string              `json:"resource"`
+	Effect   string              `json:"effect"`
+	Allowed  bool                `json:"allowed"`
+	Reason   string              `json:"reason"`
+	Matches  []StatementMatchDTO `json:"matches"`
+}
+
+type StatementMatchDTO struct {
+	PolicyId   string `json:"policy_id"`
+	PolicyName string `json:"policy_name"`
+	Statement  int    `json:"statement"`
+	Effect     string `json:"effect"`
+}
+
+// NewDecisionDTO maps a Decision to its DTO.
+func NewDecisionDTO(decision *policy.Decision) DecisionDTO {
+	action, _ := policy.FormatPolicyAction(decision.GetAction())
+	result := DecisionDTO{
+		Action:   action,
+		Resource: decision.GetResource().ToString(),
+		Effect:   formatEffect(decision.GetEffect()),
+		Allowed:  decision.IsAllowed(),
+		Reason:   decision.GetReason().ToString(),
+		Matches:  []StatementMatchDTO{},
+	}
+	for _, match := range decision.ListMatches() {
+		result.Matches = append(result.Matches, StatementMatchDTO{
+			PolicyId:   match.GetPolicy().GetIdentifier().ToString(),
+			PolicyName: match.GetPolicy().GetName(),
+			Statement:  match.GetIndex(),
+			Effect:     formatEffect(match.GetEffect()),
+		})
+	}
+	return result
+}
+
+// formatEffect returns the string representation of a PolicyEffect, including UNSPECIFIED.
+func formatEffect(effect policy.PolicyEffect) string {
+	if str, err := effect.ToString(); err == nil {
+		return str
+	}
+	return "Unspecified"
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+type ResourceChangeDTO struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Action  string `json:"action"`
+}
+
+type StepPlanDTO struct {
+	StepNumber      int                 `json:"step_number"`
+	WorkflowVersion int                 `json:"workflow_version"`
+	TemplateId      string              `json:"template_id"`
+	TemplateVersion int                 `json:"template_version"`
+	Summary         string              `json:"summary"`
+	ChangeCounts    map[string]int      `json:"change_counts"`
+	Changes         []ResourceChangeDTO `json:"changes"`
+}
+
+// NewStepPlanDTO maps a StepPlan to its DTO. The binary plan is not exposed.
+func NewStepPlanDTO(plan *workflow.StepPlan) StepPlanDTO {
+	result := StepPlanDTO{
+		StepNumber:      plan.GetStepNumber(),
+		WorkflowVersion: plan.GetWorkflowVersion(),
+		TemplateId:      plan.GetTemplateIdentifier().ToString(),
+		TemplateVersion: plan.GetTemplateVersion(),
+		Summary:         plan.GetSummary(),
+		ChangeCounts:    map[string]int{},
+		Changes:         []ResourceChangeDTO{},
+	}
+	for action, count := range plan.CountChanges() {
+		result.ChangeCounts[action.ToString()] = count
+	}
+	for _, change := range plan.ListChanges() {
+		result.Changes = append(result.Changes, ResourceChangeDTO{
+			Address: change.GetAddress(),
+			Type:    change.GetResourceType(),
+			Action:  change.GetAction().ToString(),
+		})
+	}
+	return result
+}
+
+// RecordStepPlanDTO reports the plan computed by the runner for a step of a plan-only run.
+// BinaryPlan is the saved plan file, encoded in base64, and Plan its JSON representation, as produced by `terraform show -json`.
+type RecordStepPlanDTO struct {
+	BinaryPlan []byte          `json:"binary_plan"`
+	Summary    string          `json:"summary"`
+	Plan       json.RawMessage `json:"plan"`
+}
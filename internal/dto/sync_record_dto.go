@@ -0,0 +1,67 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/gitops"
+
+// SyncRecordDTO describes a sync of the GitOps repository. Commit is null when the repository could not be fetched.
+type SyncRecordDTO struct {
+	Commit     *string          `json:"commit"`
+	Status     string           `json:"status"`
+	Drift      bool             `json:"drift"`
+	Error      *string          `json:"error"`
+	StartedAt  string           `json:"started_at"`
+	FinishedAt string           `json:"finished_at"`
+	Projects   []ProjectSyncDTO `json:"projects"`
+}
+
+type ProjectSyncDTO struct {
+	File          string            `json:"file"`
+	ProjectId     *string           `json:"project_id"`
+	Drift         bool              `json:"drift"`
+	Applied       bool              `json:"applied"`
+	Changes       []ImportChangeDTO `json:"changes"`
+	TriggeredRuns []string          `json:"triggered_runs"`
+	Error         *string           `json:"error"`
+}
+
+// NewSyncRecordDTO maps a SyncRecord to its DTO.
+func NewSyncRecordDTO(record *gitops.SyncRecord) SyncRecordDTO {
+	result := SyncRecordDTO{
+		Status:     record.GetStatus().ToString(),
+		Drift:      record.HasDrift(),
+		StartedAt:  record.GetStartedAt(),
+		FinishedAt: record.GetFinishedAt(),
+		Projects:   []ProjectSyncDTO{},
+	}
+	if commit := record.GetCommit(); commit != "" {
+		result.Commit = &commit
+	}
+	if err := record.GetError(); err != nil {
+		message := err.Error()
+		result.Error = &message
+	}
+	for _, project := range record.ListProjects() {
+		projectSync := ProjectSyncDTO{
+			File:          project.GetFile(),
+			Drift:         project.IsDrift(),
+			Applied:       project.IsApplied(),
+			Changes:       []ImportChangeDTO{},
+			TriggeredRuns: []string{},
+		}
+		if project.GetProjectIdentifier() != nil {
+			projectId := project.GetProjectIdentifier().ToString()
+			projectSync.ProjectId = &projectId
+		}
+		for _, change := range project.ListChanges() {
+			projectSync.Changes = append(projectSync.Changes, newImportChangeDTO(change))
+		}
+		for _, run := range project.ListTriggeredRuns() {
+			projectSync.TriggeredRuns = append(projectSync.TriggeredRuns, run.ToString())
+		}
+		if err := project.GetError(); err != nil {
+			message := err.Error()
+			projectSync.Error = &message
+		}
+		result.Projects = append(result.Projects, projectSync)
+	}
+	return result
+}
@@ -1,6 +1,24 @@
 package dto
 
+import "github.com/AutOpsProject/AutOps-API/internal/domain/common"
+
 type TagDTO struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// NewTagDTOs maps tags to their DTOs.
+func NewTagDTOs(tags []*common.Tag) []TagDTO {
+	result := []TagDTO{}
+	for _, tag := range tags {
+		result = append(result, TagDTO{Key: tag.GetKey(), Value: tag.GetValue()})
+	}
+	return result
+}
+
+// AddTags adds the tags of the DTOs to the entity, replacing the tags with the same key.
+func AddTags(entity *common.TaggedEntity, tags []TagDTO) {
+	for _, tag := range tags {
+		entity.AddTag(common.NewTag(tag.Key, tag.Value))
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// ValidationErrorDTO lists the error of each invalid attribute, and the violated constraints of the attributes that have some.
+type ValidationErrorDTO struct {
+	Error      string              `json:"error"`
+	Fields     map[string]string   `json:"fields"`
+	Violations map[string][]string `json:"violations"`
+}
+
+// NewValidationErrorDTO maps an AttributeValidationError to its DTO, with the error message of each invalid attribute.
+func NewValidationErrorDTO(err *workflow.AttributeValidationError) ValidationErrorDTO {
+	fields := map[string]string{}
+	violations := map[string][]string{}
+	for name, fieldErr := range err.ListFieldErrors() {
+		fields[name] = fieldErr.Error()
+		var constraintErr *common.ConstraintViolationError
+		if errors.As(fieldErr, &constraintErr) {
+			violations[name] = constraintErr.ListViolations()
+		}
+	}
+	return ValidationErrorDTO{
+		Error:      err.Error(),
+		Fields:     fields,
+		Violations: violations,
+	}
+}
@@ -0,0 +1,148 @@
+package dto
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+)
+
+type VersionDTO struct {
+	Version         int     `json:"version"`
+	SemanticVersion *string `json:"semantic_version"`
+	SourcePath      string  `json:"source_path"`
+	Commit          *string `json:"commit"`
+	ContentHash     *string `json:"content_hash"`
+	Author          *string `json:"author"`
+	Changelog       string  `json:"changelog"`
+	CreatedAt       *string `json:"created_at"`
+}
+
+// VersionDiffDTO lists the changes between two versions. Files is null when the source content cannot be loaded,
+// and Classification is only provided for templates.
+type VersionDiffDTO struct {
+	FromVersion    int                  `json:"from_version"`
+	ToVersion      int                  `json:"to_version"`
+	ContentChanged bool                 `json:"content_changed"`
+	Files          []FileDiffDTO        `json:"files"`
+	Inputs         []AttributeChangeDTO `json:"inputs"`
+	Outputs        []AttributeChangeDTO `json:"outputs"`
+	Classification *VersionChangeDTO    `json:"classification"`
+}
+
+// VersionChangeDTO is the semantic versioning classification of the changes of a template version.
+type VersionChangeDTO struct {
+	Level    string                `json:"level"`
+	Breaking bool                  `json:"breaking"`
+	Changes  []ClassifiedChangeDTO `json:"changes"`
+}
+
+type ClassifiedChangeDTO struct {
+	Attribute string `json:"attribute"`
+	Name      string `json:"name"`
+	Level     string `json:"level"`
+	Reason    string `json:"reason"`
+}
+
+type FileDiffDTO struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Patch  string `json:"patch"`
+}
+
+type AttributeChangeDTO struct {
+	Name   string   `json:"name"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields"`
+}
+
+// NewVersionDTO maps a version of a template or workflow to its DTO.
+func NewVersionDTO(source *common.VersionedSource, createdAt string) VersionDTO {
+	result := VersionDTO{
+		Version:         source.GetVersion(),
+		SemanticVersion: nil,
+		SourcePath:      source.GetSourcePath(),
+		Commit:          nil,
+		ContentHash:     nil,
+		Author:          nil,
+		Changelog:       source.GetChangelog(),
+		CreatedAt:       &createdAt,
+	}
+	if source.GetSemanticVersion() != nil {
+		semanticVersion := source.GetSemanticVersion().ToString()
+		result.SemanticVersion = &semanticVersion
+	}
+	if commit := source.GetCommit(); commit != "" {
+		result.Commit = &commit
+	}
+	if hash := source.GetContentHash(); hash != "" {
+		result.ContentHash = &hash
+	}
+	if source.GetAuthor() != nil {
+		author := source.GetAuthor().ToString()
+		result.Author = &author
+	}
+	return result
+}
+
+// NewVersionDiffDTO maps a VersionDiff and the file changes of the source content, if available, to their DTO.
+func NewVersionDiffDTO(diff *common.VersionDiff, files []*versioning.FileDiff) VersionDiffDTO {
+	result := VersionDiffDTO{
+		FromVersion:    diff.GetFromVersion(),
+		ToVersion:      diff.GetToVersion(),
+		ContentChanged: diff.IsContentChanged(),
+		Files:          nil,
+		Inputs:         newAttributeChangeDTOs(diff.ListInputChanges()),
+		Outputs:        newAttributeChangeDTOs(diff.ListOutputChanges()),
+		Classification: nil,
+	}
+	if files != nil {
+		result.Files = []FileDiffDTO{}
+		for _, file := range files {
+			result.Files = append(result.Files, FileDiffDTO{
+				Path:   file.GetPath(),
+				Change: file.GetChangeType().ToString(),
+				Patch:  file.GetPatch(),
+			})
+		}
+	}
+	return result
+}
+
+// newAttributeChangeDTOs maps attribute changes to their DTOs.
+func newAttributeChangeDTOs(changes []*common.AttributeChange) []AttributeChangeDTO {
+	result := []AttributeChangeDTO{}
+	for _, change := range changes {
+		fields := change.ListChangedFields()
+		if fields == nil {
+			fields = []string{}
+		}
+		result = append(result, AttributeChangeDTO{
+			Name:   change.GetName(),
+			Change: change.GetChangeType().ToString(),
+			Fields: fields,
+		})
+	}
+	return result
+}
+
+// NewVersionChangeDTO maps the classification of the changes of a template version to its DTO.
+func NewVersionChangeDTO(change *template.VersionChange) *VersionChangeDTO {
+	result := &VersionChangeDTO{
+		Level:    change.GetLevel().ToString(),
+		Breaking: change.IsBreaking(),
+		Changes:  []ClassifiedChangeDTO{},
+	}
+	for _, classified := range change.ListChanges() {
+		attribute := "input"
+		if classified.IsOutput() {
+			attribute = "output"
+		}
+		result.Changes = append(result.Changes, ClassifiedChangeDTO{
+			Attribute: attribute,
+			Name:      classified.GetName(),
+			Level:     classified.GetLevel().ToString(),
+			Reason:    classified.GetReason(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+
+type WebhookTriggerDTO struct {
+	Identifier    string            `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	URL           string            `json:"url"`
+	Secret        string            `json:"secret,omitempty"`
+	Filter        string            `json:"filter"`
+	InputMappings map[string]string `json:"input_mappings"`
+	CreatedAt     *string           `json:"created_at"`
+	UpdatedAt     *string           `json:"updated_at"`
+}
+
+// NewWebhookTriggerDTO maps a WebhookTrigger to its DTO, with its URL relative to the provided API base URL.
+// The secret is only included when withSecret is true, so that it is disclosed once at creation.
+func NewWebhookTriggerDTO(trigger *workflow.WebhookTrigger, baseURL string, withSecret bool) WebhookTriggerDTO {
+	createdAt := trigger.GetCreatedAt()
+	updatedAt := trigger.GetUpdatedAt()
+	result := WebhookTriggerDTO{
+		Identifier:    trigger.GetIdentifier().ToString(),
+		Name:          trigger.GetName(),
+		Description:   trigger.GetDescription(),
+		URL:           trigger.GetURL(baseURL),
+		Filter:        trigger.GetFilter(),
+		InputMappings: trigger.ListInputMappings(),
+		CreatedAt:     &createdAt,
+		UpdatedAt:     &updatedAt,
+	}
+	if withSecret {
+		result.Secret = trigger.GetSecret()
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+type WorkflowRunDTO struct {
+	Identifier   string            `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Status       string            `json:"status"`
+	Mode         string            `json:"mode"`
+	AppliedPlan  *string           `json:"applied_plan"`
+	TriggeredBy  *string           `json:"triggered_by"`
+	StartedBy    *string           `json:"started_by"`
+	Environment  *string           `json:"environment"`
+	PromotedFrom *string           `json:"promoted_from"`
+	Inputs       map[string]string `json:"inputs"`
+	Outputs      map[string]string `json:"outputs"`
+	CreatedAt    *string           `json:"created_at"`
+	UpdatedAt    *string           `json:"updated_at"`
+}
+
+// NewWorkflowRunDTO maps a WorkflowRun to its DTO.
+func NewWorkflowRunDTO(run *workflow.WorkflowRun) WorkflowRunDTO {
+	createdAt := run.GetCreatedAt()
+	updatedAt := run.GetUpdatedAt()
+	result := WorkflowRunDTO{
+		Identifier:   run.GetIdentifier().ToString(),
+		Name:         run.GetName(),
+		Description:  run.GetDescription(),
+		Status:       run.GetStatus().ToString(),
+		Mode:         run.GetMode().ToString(),
+		AppliedPlan:  nil,
+		TriggeredBy:  nil,
+		StartedBy:    nil,
+		Environment:  nil,
+		PromotedFrom: nil,
+		Inputs:       run.GetInputs(),
+		Outputs:      run.GetOutputs(),
+		CreatedAt:    &createdAt,
+		UpdatedAt:    &updatedAt,
+	}
+	if run.GetTriggeredBy() != nil {
+		triggeredBy := run.GetTriggeredBy().ToString()
+		result.TriggeredBy = &triggeredBy
+	}
+	if run.GetStartedBy() != nil {
+		startedBy := run.GetStartedBy().ToString()
+		result.StartedBy = &startedBy
+	}
+	if run.GetEnvironment() != nil {
+		environment := run.GetEnvironment().ToString()
+		result.Environment = &environment
+	}
+	if run.GetPromotedFrom() != nil {
+		promotedFrom := run.GetPromotedFrom().ToString()
+		result.PromotedFrom = &promotedFrom
+	}
+	if run.GetAppliedPlan() != nil {
+		appliedPlan := run.GetAppliedPlan().ToString()
+		result.AppliedPlan = &appliedPlan
+	}
+	return result
+}
+
+type StartWorkflowRunDTO struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Mode        string                     `json:"mode"`
+	Inputs      map[string]json.RawMessage `json:"inputs"`
+}
+
+// CompleteStepDTO reports the output values produced by a step of a run, indexed by template output name.
+type CompleteStepDTO struct {
+	Outputs map[string]string `json:"outputs"`
+}
+
+type WorkflowRunOutputsDTO struct {
+	RunIdentifier string            `json:"run_id"`
+	Status        string            `json:"status"`
+	Outputs       map[string]string `json:"outputs"`
+}
+
+// NewWorkflowRunOutputsDTO maps the resolved outputs of a WorkflowRun to their DTO.
+func NewWorkflowRunOutputsDTO(run *workflow.WorkflowRun) WorkflowRunOutputsDTO {
+	return WorkflowRunOutputsDTO{
+		RunIdentifier: run.GetIdentifier().ToString(),
+		Status:        run.GetStatus().ToString(),
+		Outputs:       run.GetOutputs(),
+	}
+}
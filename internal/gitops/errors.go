@@ -0,0 +1,9 @@
+package gitops
+
+import "errors"
+
+var (
+	ErrSyncInProgress          = errors.New("a sync of the repository is already in progress")
+	ErrManifestProjectNotFound = errors.New("cannot find the project declared by the manifest identifier")
+	ErrAmbiguousProjectName    = errors.New("several projects have the name declared by the manifest, which must declare the project identifier")
+)
@@ -0,0 +1,334 @@
+// Package gitops syncs AutOps projects from the manifests of a git repository.
+//
+// The reconciler watches a branch of a repository and imports every YAML manifest of a directory, so that the projects,
+// templates, workflows and policies they declare converge to the content of the branch. Between two commits, changes made
+// through the API are reported as drift, and reverted when self-healing is enabled.
+package gitops
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/AutOpsProject/AutOps-API/internal/manifest"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+)
+
+// MAX_SYNC_HISTORY bounds the number of sync records kept by a reconciler.
+const MAX_SYNC_HISTORY = 100
+
+// projectPageSize is the number of projects loaded at once when matching a manifest by project name.
+const projectPageSize = 200
+
+// trackedManifest remembers the project of a manifest and the digest of the content last applied to it.
+type trackedManifest struct {
+	projectId string
+	digest    [sha256.Size]byte
+}
+
+// Reconciler syncs projects from the manifests of a directory of a git repository, fetched at the ref of a git source.
+// Manifests without project identifier are matched to the project with the same name, and create it if there is none.
+// Projects whose manifest is removed from the repository are left untouched.
+type Reconciler struct {
+	fetcher     *gitsource.Fetcher
+	source      *common.GitSource
+	credentials *gitsource.Credentials
+	projects    project.ProjectRepository
+	templates   template.TemplateRepository
+	workflows   workflow.WorkflowRepository
+	sources     *versioning.SourceLoader
+	secrets     secret.SecretRepository
+	key         *secret.MasterKey
+	selfHeal    bool
+	autoTrigger bool
+	running     sync.Mutex
+	mutex       sync.Mutex
+	tracked     map[string]*trackedManifest
+	history     []*SyncRecord
+}
+
+// NewReconciler creates a Reconciler syncing the manifests found in the subdirectory of the git source, at its ref.
+// The repository may be a local path, to a working copy or a bare repository, or any repository supported by the fetcher.
+// Templates and workflows are only stored in their own repositories when these are provided.
+func NewReconciler(fetcher *gitsource.Fetcher, source *common.GitSource, projects project.ProjectRepository, templates template.TemplateRepository, workflows workflow.WorkflowRepository) *Reconciler {
+	return &Reconciler{
+		fetcher:   fetcher,
+		source:    source,
+		projects:  projects,
+		templates: templates,
+		workflows: workflows,
+		tracked:   map[string]*trackedManifest{},
+		history:   []*SyncRecord{},
+	}
+}
+
+// GetSource returns the git source of the manifests.
+func (r *Reconciler) GetSource() *common.GitSource {
+	return r.source
+}
+
+// SetCredentials sets the credentials used to fetch a private repository, or nil for a public one.
+func (r *Reconciler) SetCredentials(credentials *gitsource.Credentials) {
+	r.credentials = credentials
+}
+
+// SetSourceLoader sets the loader recording the source content of the template and workflow versions created by a sync,
+// or nil to leave them without content hash. The content of private template sources is only recorded when secrets and key are provided.
+func (r *Reconciler) SetSourceLoader(sources *versioning.SourceLoader, secrets secret.SecretRepository, key *secret.MasterKey) {
+	r.sources = sources
+	r.secrets = secrets
+	r.key = key
+}
+
+// SetSelfHeal enables or disables the revert of the changes made to the projects through the API.
+// Drift is reported in the sync records in both cases.
+func (r *Reconciler) SetSelfHeal(selfHeal bool) {
+	r.selfHeal = selfHeal
+}
+
+// SetAutoTrigger enables or disables starting a run of each workflow created or updated by a sync.
+// Runs are only started when the workflow repository is provided, and use the default values of the workflow inputs.
+func (r *Reconciler) SetAutoTrigger(autoTrigger bool) {
+	r.autoTrigger = autoTrigger
+}
+
+// ListSyncs returns the sync records, from the most recent one.
+func (r *Reconciler) ListSyncs() []*SyncRecord {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	syncs := make([]*SyncRecord, 0, len(r.history))
+	for i := len(r.history) - 1; i >= 0; i-- {
+		syncs = append(syncs, r.history[i])
+	}
+	return syncs
+}
+
+// Watch syncs the repository immediately, then at each interval until the context is done, and returns the error of the context.
+// Failed syncs are recorded and retried at the next interval.
+func (r *Reconciler) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.Sync(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync fetches the ref of the repository and imports each manifest whose project differs from it.
+// Projects that differ from a manifest already applied unchanged are drifted: they are only imported again when self-healing is enabled.
+// The sync of a manifest stops at its first error, without preventing the sync of the others.
+//
+// Returns the sync record, with an error if the repository could not be fetched, or ErrSyncInProgress if a sync is already running.
+func (r *Reconciler) Sync(ctx context.Context) (*SyncRecord, error) {
+	if !r.running.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer r.running.Unlock()
+
+	record := &SyncRecord{startedAt: common.CurrentTimestamp(), projects: []*ProjectSync{}}
+	checkout, err := r.fetcher.Fetch(ctx, r.source, "", r.credentials)
+	if err == nil {
+		record.commit = checkout.GetCommit()
+		var files []string
+		files, err = listManifests(checkout.GetPath())
+		for _, file := range files {
+			record.projects = append(record.projects, r.syncManifest(ctx, checkout, file))
+		}
+	}
+	record.err = err
+	record.finishedAt = common.CurrentTimestamp()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.history = append(r.history, record)
+	if len(r.history) > MAX_SYNC_HISTORY {
+		r.history = r.history[len(r.history)-MAX_SYNC_HISTORY:]
+	}
+	return record, err
+}
+
+// listManifests returns the paths of the YAML files of the directory and its subdirectories, relative to it and sorted.
+func listManifests(root string) ([]string, error) {
+	files := []string{}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if extension := filepath.Ext(path); extension == ".yaml" || extension == ".yml" {
+			relative, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(relative))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// syncManifest imports a manifest of the checkout, applying its changes unless they are a drift and self-healing is disabled.
+func (r *Reconciler) syncManifest(ctx context.Context, checkout *gitsource.Checkout, file string) *ProjectSync {
+	result := &ProjectSync{file: file, changes: []*manifest.Change{}, runs: []*common.Identifier{}}
+	document, err := os.ReadFile(filepath.Join(checkout.GetPath(), filepath.FromSlash(file)))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	parsed, err := manifest.Parse(document)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	current, err := r.findProject(file, parsed)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	plan, err := manifest.NewImportPlan(current, parsed)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.projectId = plan.GetProjectIdentifier()
+	result.changes = plan.ListChanges()
+
+	digest := sha256.Sum256(document)
+	r.mutex.Lock()
+	tracked := r.tracked[file]
+	r.mutex.Unlock()
+	if !plan.HasChanges() {
+		r.track(file, result.projectId, digest)
+		return result
+	}
+	result.drift = tracked != nil && tracked.digest == digest
+	if result.drift && !r.selfHeal {
+		return result
+	}
+	if err := r.recordVersions(ctx, checkout, plan); err != nil {
+		result.err = err
+		return result
+	}
+	imported, err := plan.Save(r.projects, r.templates, r.workflows)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.applied = true
+	r.track(file, result.projectId, digest)
+	if r.autoTrigger && r.workflows != nil {
+		result.runs, result.err = r.triggerWorkflows(imported, plan.ListChanges(), checkout.GetCommit())
+	}
+	return result
+}
+
+// recordVersions describes the versions created by a sync with the commit of the checkout, and records their content.
+func (r *Reconciler) recordVersions(ctx context.Context, checkout *gitsource.Checkout, plan *manifest.ImportPlan) error {
+	if err := plan.SetChangelog(fmt.Sprintf("Synced from commit %s", checkout.GetCommit())); err != nil {
+		return err
+	}
+	if r.sources == nil {
+		return nil
+	}
+	return plan.RecordContent(ctx, r.sources, r.secrets, r.key)
+}
+
+// track remembers the project a manifest was applied to, and the digest of its content.
+func (r *Reconciler) track(file string, projectId *common.Identifier, digest [sha256.Size]byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tracked[file] = &trackedManifest{projectId: projectId.ToString(), digest: digest}
+}
+
+// findProject returns the project of a manifest: the project of its identifier, the project it was last applied to,
+// or the only project with its name. Returns nil if the manifest declares a new project.
+func (r *Reconciler) findProject(file string, m *manifest.Manifest) (*project.Project, error) {
+	if m.Metadata.ID != "" {
+		projectId, err := common.NewIdentifier(m.Metadata.ID)
+		if err != nil {
+			return nil, err
+		}
+		found, err := r.projects.FindById(*projectId)
+		if err == nil && found == nil {
+			err = ErrManifestProjectNotFound
+		}
+		return found, err
+	}
+
+	r.mutex.Lock()
+	tracked := r.tracked[file]
+	r.mutex.Unlock()
+	if tracked != nil {
+		projectId, err := common.NewIdentifier(tracked.projectId)
+		if err != nil {
+			return nil, err
+		}
+		found, err := r.projects.FindById(*projectId)
+		if err != nil || found != nil {
+			return found, err
+		}
+	}
+
+	var match *project.Project
+	for offset := 0; ; offset += projectPageSize {
+		page, err := r.projects.FindAll(offset, projectPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page {
+			if p.GetName() != m.Metadata.Name {
+				continue
+			}
+			if match != nil {
+				return nil, ErrAmbiguousProjectName
+			}
+			match = p
+		}
+		if len(page) < projectPageSize {
+			return match, nil
+		}
+	}
+}
+
+// triggerWorkflows starts a run of each workflow created or updated by the sync of a commit, stopping at the first error.
+func (r *Reconciler) triggerWorkflows(imported *project.Project, changes []*manifest.Change, commit string) ([]*common.Identifier, error) {
+	runs := []*common.Identifier{}
+	for _, change := range changes {
+		if change.GetResourceType() != common.WORKFLOW || change.GetAction() == manifest.DELETE {
+			continue
+		}
+		wf := imported.GetWorkflow(change.GetIdentifier())
+		run, err := wf.StartRun("gitops-"+commit[:12], "Triggered by the sync of commit "+commit, workflow.STANDARD, map[string]string{})
+		if err != nil {
+			return runs, err
+		}
+		if err := r.workflows.Update(wf); err != nil {
+			return runs, err
+		}
+		runs = append(runs, run.GetIdentifier())
+	}
+	return runs, nil
+}
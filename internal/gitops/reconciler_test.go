@@ -0,0 +1,216 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+)
+
+const network = `apiVersion: autops/v1
+kind: Project
+metadata:
+  name: network
+templates:
+  - name: vpc
+    type: terraform
+    source: path/to/vpc.zip
+workflows:
+  - name: deploy
+    source: path/to/deploy.yml
+    inputs:
+      - name: region
+        type: string
+        default: eu-west-1
+    steps:
+      - number: 1
+        name: vpc
+        template: vpc
+`
+
+// git runs a git command in the given directory for the test fixtures, returning its trimmed output.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// push writes a file in the working copy, commits it and pushes it to the main branch, returning the commit identifier.
+func push(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", "--all")
+	git(t, dir, "commit", "--quiet", "--message", "update "+name)
+	git(t, dir, "push", "--quiet", "origin", "HEAD:main")
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+// fakeProjectRepository is an in-memory project.ProjectRepository.
+type fakeProjectRepository struct {
+	projects []*project.Project
+}
+
+func (f *fakeProjectRepository) Create(p *project.Project) error {
+	f.projects = append(f.projects, p)
+	return nil
+}
+
+func (f *fakeProjectRepository) Update(p *project.Project) error {
+	return nil
+}
+
+func (f *fakeProjectRepository) Delete(projectId common.Identifier) error {
+	return nil
+}
+
+func (f *fakeProjectRepository) FindById(id common.Identifier) (*project.Project, error) {
+	for _, p := range f.projects {
+		if p.GetIdentifier().ToString() == id.ToString() {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeProjectRepository) FindAll(offset int, limit int) ([]*project.Project, error) {
+	if offset >= len(f.projects) {
+		return []*project.Project{}, nil
+	}
+	return f.projects[offset:min(offset+limit, len(f.projects))], nil
+}
+
+func (f *fakeProjectRepository) FindWithAllTags(tags []*common.Tag, offset int, limit int) ([]*project.Project, error) {
+	return nil, nil
+}
+
+func (f *fakeProjectRepository) FindWithAnyTags(tags []*common.Tag, offset int, limit int) ([]*project.Project, error) {
+	return nil, nil
+}
+
+// fakeWorkflowRepository is an in-memory workflow.WorkflowRepository counting the stored versions.
+type fakeWorkflowRepository struct {
+	versions int
+}
+
+func (f *fakeWorkflowRepository) Create(wf *workflow.Workflow) error {
+	f.versions++
+	return nil
+}
+
+func (f *fakeWorkflowRepository) Update(wf *workflow.Workflow) error {
+	return nil
+}
+
+func (f *fakeWorkflowRepository) Delete(workflowId common.Identifier) error {
+	return nil
+}
+
+func (f *fakeWorkflowRepository) FindByProject(projectId common.Identifier, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindAllVersions(workflowId common.Identifier, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindById(workflowId common.Identifier) (*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindAll(offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindWithAllTags(tags []*common.Tag, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func (f *fakeWorkflowRepository) FindWithAnyTags(tags []*common.Tag, offset int, limit int) ([]*workflow.Workflow, error) {
+	return nil, nil
+}
+
+func TestReconcilerSync(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	bare := filepath.Join(root, "repo.git")
+	work := filepath.Join(root, "work")
+	git(t, root, "init", "--quiet", "--bare", "--initial-branch=main", bare)
+	git(t, root, "clone", "--quiet", bare, work)
+	first := push(t, work, "projects/network.yaml", network)
+
+	fetcher, _ := gitsource.NewFetcher(t.TempDir())
+	source, _ := common.ParseGitSource("git::" + bare + "//projects?ref=main")
+	projects, workflows := &fakeProjectRepository{}, &fakeWorkflowRepository{}
+	reconciler := NewReconciler(fetcher, source, projects, nil, workflows)
+	reconciler.SetAutoTrigger(true)
+	ctx := context.Background()
+
+	record, err := reconciler.Sync(ctx)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if record.GetCommit() != first || record.GetStatus() != common.SUCCESS || len(record.ListProjects()) != 1 {
+		t.Fatalf("unexpected sync record of commit %s", record.GetCommit())
+	}
+	synced := record.ListProjects()[0]
+	if !synced.IsApplied() || len(synced.ListChanges()) != 3 || len(synced.ListTriggeredRuns()) != 1 || len(projects.projects) != 1 {
+		t.Fatalf("expected the project to be created and its workflow triggered, got %d changes", len(synced.ListChanges()))
+	}
+	imported := projects.projects[0]
+	if len(imported.ListWorkflows()[0].ListRuns()) != 1 || workflows.versions != 1 {
+		t.Errorf("expected the workflow to be stored with a run")
+	}
+
+	record, _ = reconciler.Sync(ctx)
+	if synced := record.ListProjects()[0]; synced.IsApplied() || len(synced.ListChanges()) != 0 || record.HasDrift() {
+		t.Errorf("expected a sync without changes, got %d changes", len(synced.ListChanges()))
+	}
+
+	imported.SetDescription("changed through the API")
+	record, _ = reconciler.Sync(ctx)
+	if synced := record.ListProjects()[0]; !synced.IsDrift() || synced.IsApplied() || imported.GetDescription() == "" {
+		t.Errorf("expected the drift to be reported without being reverted")
+	}
+	reconciler.SetSelfHeal(true)
+	record, _ = reconciler.Sync(ctx)
+	if synced := record.ListProjects()[0]; !synced.IsDrift() || !synced.IsApplied() || imported.GetDescription() != "" {
+		t.Errorf("expected the drift to be reverted")
+	}
+
+	push(t, work, "projects/broken.yml", "kind: Project")
+	second := push(t, work, "projects/network.yaml", strings.Replace(network, "path/to/vpc.zip", "path/to/vpc-v2.zip", 1))
+	record, err = reconciler.Sync(ctx)
+	if err != nil || record.GetCommit() != second {
+		t.Fatalf("expected the new commit to be synced, got %s (%v)", record.GetCommit(), err)
+	}
+	if record.GetStatus() != common.FAILURE || len(record.ListProjects()) != 2 || record.ListProjects()[0].GetError() == nil {
+		t.Errorf("expected the invalid manifest to fail the sync")
+	}
+	synced = record.ListProjects()[1]
+	if synced.IsDrift() || !synced.IsApplied() || len(projects.projects) != 1 || imported.ListTemplates()[0].GetSourcePath() != "path/to/vpc-v2.zip" {
+		t.Errorf("expected the existing project to be updated")
+	}
+	if len(reconciler.ListSyncs()) != 5 || reconciler.ListSyncs()[0] != record {
+		t.Errorf("expected the syncs to be recorded from the most recent one")
+	}
+}
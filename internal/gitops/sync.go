@@ -0,0 +1,110 @@
+package gitops
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/manifest"
+)
+
+// ProjectSync is the outcome of the sync of one manifest of the repository.
+type ProjectSync struct {
+	file      string
+	projectId *common.Identifier
+	changes   []*manifest.Change
+	drift     bool
+	applied   bool
+	runs      []*common.Identifier
+	err       error
+}
+
+// GetFile returns the path of the manifest, relative to the synced directory of the repository.
+func (s *ProjectSync) GetFile() string {
+	return s.file
+}
+
+// GetProjectIdentifier returns the identifier of the project declared by the manifest, or nil if the manifest is invalid.
+func (s *ProjectSync) GetProjectIdentifier() *common.Identifier {
+	return s.projectId
+}
+
+// ListChanges returns the changes between the project and the manifest.
+func (s *ProjectSync) ListChanges() []*manifest.Change {
+	return append([]*manifest.Change(nil), s.changes...)
+}
+
+// IsDrift returns true if the changes were made to the project through the API since the manifest was last applied,
+// rather than by a new commit of the manifest.
+func (s *ProjectSync) IsDrift() bool {
+	return s.drift
+}
+
+// IsApplied returns true if the changes were applied to the project.
+func (s *ProjectSync) IsApplied() bool {
+	return s.applied
+}
+
+// ListTriggeredRuns returns the identifiers of the workflow runs started because the sync changed their workflow.
+func (s *ProjectSync) ListTriggeredRuns() []*common.Identifier {
+	return append([]*common.Identifier(nil), s.runs...)
+}
+
+// GetError returns the error that prevented the sync of the manifest, or nil.
+func (s *ProjectSync) GetError() error {
+	return s.err
+}
+
+// SyncRecord records a sync of the repository: the commit synced and the outcome for each manifest.
+type SyncRecord struct {
+	commit     string
+	startedAt  string
+	finishedAt string
+	projects   []*ProjectSync
+	err        error
+}
+
+// GetCommit returns the commit synced, or an empty string if the repository could not be fetched.
+func (r *SyncRecord) GetCommit() string {
+	return r.commit
+}
+
+// GetStartedAt returns the date the sync started.
+func (r *SyncRecord) GetStartedAt() string {
+	return r.startedAt
+}
+
+// GetFinishedAt returns the date the sync finished.
+func (r *SyncRecord) GetFinishedAt() string {
+	return r.finishedAt
+}
+
+// ListProjects returns the outcome of the sync of each manifest, ordered by file path.
+func (r *SyncRecord) ListProjects() []*ProjectSync {
+	return append([]*ProjectSync(nil), r.projects...)
+}
+
+// GetError returns the error that prevented the repository from being fetched, or nil.
+func (r *SyncRecord) GetError() error {
+	return r.err
+}
+
+// GetStatus returns SUCCESS if every manifest was synced, or FAILURE otherwise.
+func (r *SyncRecord) GetStatus() common.Status {
+	if r.err != nil {
+		return common.FAILURE
+	}
+	for _, project := range r.projects {
+		if project.err != nil {
+			return common.FAILURE
+		}
+	}
+	return common.SUCCESS
+}
+
+// HasDrift returns true if a project was changed through the API since its manifest was last applied.
+func (r *SyncRecord) HasDrift() bool {
+	for _, project := range r.projects {
+		if project.drift {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package gitsource
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// DEFAULT_TOKEN_USERNAME is the username sent with tokens stored without one, which is ignored by most git hosts.
+const DEFAULT_TOKEN_USERNAME = "autops"
+
+// Credentials authenticate the access to a private repository, either with a username and a token over HTTPS,
+// or with a private key over SSH.
+type Credentials struct {
+	username   string
+	token      string
+	privateKey []byte
+}
+
+// NewTokenCredentials creates HTTPS credentials. An empty username stands for DEFAULT_TOKEN_USERNAME.
+func NewTokenCredentials(username string, token string) *Credentials {
+	if username == "" {
+		username = DEFAULT_TOKEN_USERNAME
+	}
+	return &Credentials{
+		username: username,
+		token:    token,
+	}
+}
+
+// NewSSHCredentials creates SSH credentials from a PEM or OpenSSH encoded private key.
+func NewSSHCredentials(privateKey []byte) *Credentials {
+	return &Credentials{
+		privateKey: append([]byte(nil), privateKey...),
+	}
+}
+
+// CredentialsFromSecret decrypts the secret holding the credentials of the source.
+// The secret holds a private key for SSH repositories, and a token optionally prefixed by "<username>:" for HTTPS repositories.
+//
+// Returns ErrInsecureRepository if the repository uses neither SSH nor HTTPS, or an error if the secret cannot be decrypted or is empty.
+func CredentialsFromSecret(source *common.GitSource, s *secret.Secret, key *secret.MasterKey) (*Credentials, error) {
+	if !source.AcceptsCredentials() {
+		return nil, ErrInsecureRepository
+	}
+	value, err := s.Reveal(key)
+	if err != nil {
+		return nil, err
+	}
+	if source.IsSSH() {
+		if len(strings.TrimSpace(string(value))) == 0 {
+			return nil, ErrInvalidCredentials
+		}
+		return NewSSHCredentials(value), nil
+	}
+	token := strings.TrimSpace(string(value))
+	username := ""
+	if index := strings.Index(token, ":"); index >= 0 {
+		username, token = token[:index], token[index+1:]
+	}
+	if token == "" {
+		return nil, ErrInvalidCredentials
+	}
+	return NewTokenCredentials(username, token), nil
+}
+
+// TemplateCredentials loads and decrypts the source credentials of the template, or returns nil if its git source is public.
+// Returns an error if the template sources are not in a git repository, or if the credentials secret cannot be found or decrypted.
+func TemplateCredentials(t *template.Template, secrets secret.SecretRepository, key *secret.MasterKey) (*Credentials, error) {
+	source := t.GetGitSource()
+	if source == nil {
+		return nil, ErrNotAGitSource
+	}
+	if t.GetSourceCredentials() == nil {
+		return nil, nil
+	}
+	s, err := secrets.FindById(*t.GetSourceCredentials())
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, secret.ErrSecretNotFound
+	}
+	return CredentialsFromSecret(source, s, key)
+}
+
+// environment returns the environment variables passing the credentials to git, without exposing them in its arguments.
+// Redirects are not followed when a token is sent, so that it cannot be forwarded to another host.
+// The returned cleanup function removes the temporary private key file, if any.
+func (c *Credentials) environment() ([]string, func(), error) {
+	if c == nil {
+		return nil, func() {}, nil
+	}
+	if len(c.privateKey) > 0 {
+		file, err := os.CreateTemp("", "autops-ssh-key-*")
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup := func() { os.Remove(file.Name()) }
+		_, err = file.Write(c.privateKey)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		command := fmt.Sprintf("ssh -i '%s' -o IdentitiesOnly=yes -o BatchMode=yes -o StrictHostKeyChecking=accept-new", file.Name())
+		return []string{"GIT_SSH_COMMAND=" + command}, cleanup, nil
+	}
+	authorization := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.token))
+	return []string{
+		"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + authorization,
+		"GIT_CONFIG_KEY_1=http.followRedirects",
+		"GIT_CONFIG_VALUE_1=false",
+	}, func() {}, nil
+}
@@ -0,0 +1,13 @@
+package gitsource
+
+import "errors"
+
+var (
+	ErrGitNotFound          = errors.New("the git executable cannot be found")
+	ErrNotAGitSource        = errors.New("the template sources are not fetched from a git repository")
+	ErrGitCommandFailed     = errors.New("git command failed")
+	ErrRefNotFound          = errors.New("the ref cannot be resolved to a commit of the repository")
+	ErrSubdirectoryNotFound = errors.New("the subdirectory does not exist in the repository")
+	ErrInvalidCredentials   = errors.New("the credentials secret is empty or malformed")
+	ErrInsecureRepository   = errors.New("credentials are only sent to https or ssh repositories")
+)
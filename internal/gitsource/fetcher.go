@@ -0,0 +1,240 @@
+// Package gitsource fetches the sources of templates stored in git repositories, using the git command line.
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// ALLOWED_PROTOCOLS restricts the transports git may use, excluding the ones able to run arbitrary commands (e.g. ext::).
+const ALLOWED_PROTOCOLS = "file:http:https:ssh"
+
+// fetchedHeadRef stores the commit of the remote HEAD, resolving the sources without ref to the default branch of the repository.
+const fetchedHeadRef = "refs/autops/HEAD"
+
+// Checkout is a working copy of the sources of a git source at a given commit.
+type Checkout struct {
+	path   string
+	commit string
+}
+
+// GetPath returns the directory holding the sources, which is the subdirectory of the source within the working copy.
+func (c *Checkout) GetPath() string {
+	return c.path
+}
+
+// GetCommit returns the commit checked out.
+func (c *Checkout) GetCommit() string {
+	return c.commit
+}
+
+// Fetcher clones repositories into a cache directory, as bare mirrors fetched again when a ref must be resolved,
+// and checks out each commit once into a read-only working copy shared by every source using it.
+type Fetcher struct {
+	cacheDir string
+	git      string
+	mutex    sync.Mutex
+}
+
+// NewFetcher creates a Fetcher caching repositories and checkouts in the given directory, which is created if needed.
+// Returns ErrGitNotFound if the git executable is not in the PATH.
+func NewFetcher(cacheDir string) (*Fetcher, error) {
+	git, err := exec.LookPath("git")
+	if err != nil {
+		return nil, ErrGitNotFound
+	}
+	for _, dir := range []string{"repositories", "checkouts"} {
+		if err := os.MkdirAll(filepath.Join(cacheDir, dir), 0o700); err != nil {
+			return nil, err
+		}
+	}
+	return &Fetcher{
+		cacheDir: cacheDir,
+		git:      git,
+	}, nil
+}
+
+// FetchTemplate checks out the git source of the template, authenticated with its source credentials if any.
+// The first fetch of a template version resolves its ref and pins the version to the resulting commit,
+// which is checked out by the following fetches even if the ref has moved since.
+//
+// Returns ErrNotAGitSource if the template sources are not in a git repository, or any error of Fetch.
+func (f *Fetcher) FetchTemplate(ctx context.Context, t *template.Template, secrets secret.SecretRepository, key *secret.MasterKey) (*Checkout, error) {
+	source := t.GetGitSource()
+	if source == nil {
+		return nil, ErrNotAGitSource
+	}
+	credentials, err := TemplateCredentials(t, secrets, key)
+	if err != nil {
+		return nil, err
+	}
+	checkout, err := f.Fetch(ctx, source, t.GetCommit(), credentials)
+	if err != nil {
+		return nil, err
+	}
+	if t.GetCommit() == "" {
+		if err := t.SetCommit(checkout.commit); err != nil {
+			return nil, err
+		}
+	}
+	return checkout, nil
+}
+
+// Fetch checks out the git source at the given commit or, if the commit is empty, at the commit its ref currently resolves to.
+// The repository is only fetched when the commit is not already in the cache.
+//
+// Returns ErrRefNotFound if the ref or commit is not in the repository, ErrSubdirectoryNotFound if the source subdirectory
+// does not exist at that commit or is a symbolic link leading outside of the checkout, or ErrGitCommandFailed if the repository cannot be fetched.
+func (f *Fetcher) Fetch(ctx context.Context, source *common.GitSource, commit string, credentials *Credentials) (*Checkout, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	mirror, err := f.initMirror(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if commit == "" || !f.hasCommit(ctx, mirror, commit) {
+		if err := f.fetchMirror(ctx, mirror, source, credentials); err != nil {
+			return nil, err
+		}
+	}
+	if commit == "" {
+		ref := source.GetRef()
+		if ref == common.GIT_DEFAULT_REF {
+			ref = fetchedHeadRef
+		}
+		commit, err = f.resolve(ctx, mirror, ref)
+		if err != nil {
+			return nil, err
+		}
+	} else if !f.hasCommit(ctx, mirror, commit) {
+		return nil, ErrRefNotFound
+	}
+
+	root, err := f.checkout(ctx, mirror, commit)
+	if err != nil {
+		return nil, err
+	}
+	path, err := resolveWithin(root, filepath.FromSlash(source.GetSubdirectory()))
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil, ErrSubdirectoryNotFound
+	}
+	return &Checkout{
+		path:   path,
+		commit: commit,
+	}, nil
+}
+
+// resolveWithin resolves the symbolic links of the relative path within the root directory.
+// Returns ErrSubdirectoryNotFound if the path does not exist or resolves outside of the root directory.
+func resolveWithin(root string, relative string) (string, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", ErrSubdirectoryNotFound
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(resolvedRoot, relative))
+	if err != nil || (path != resolvedRoot && !strings.HasPrefix(path, resolvedRoot+string(filepath.Separator))) {
+		return "", ErrSubdirectoryNotFound
+	}
+	return path, nil
+}
+
+// initMirror creates the bare mirror of the repository in the cache, if it does not exist yet, and returns its path.
+func (f *Fetcher) initMirror(ctx context.Context, source *common.GitSource) (string, error) {
+	digest := sha256.Sum256([]byte(source.GetRepository()))
+	mirror := filepath.Join(f.cacheDir, "repositories", hex.EncodeToString(digest[:])+".git")
+	if _, err := os.Stat(mirror); err == nil {
+		return mirror, nil
+	}
+	if _, err := f.run(ctx, nil, "init", "--quiet", "--bare", mirror); err != nil {
+		return "", err
+	}
+	return mirror, nil
+}
+
+// fetchMirror fetches the branches, tags and HEAD of the repository into the mirror.
+func (f *Fetcher) fetchMirror(ctx context.Context, mirror string, source *common.GitSource, credentials *Credentials) error {
+	env, cleanup, err := credentials.environment()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	_, err = f.run(ctx, env, "--git-dir", mirror, "fetch", "--quiet", "--force", "--prune", "--end-of-options", source.GetRepository(),
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+HEAD:"+fetchedHeadRef)
+	return err
+}
+
+// hasCommit returns true if the commit is in the mirror.
+func (f *Fetcher) hasCommit(ctx context.Context, mirror string, commit string) bool {
+	if !common.IsValidGitCommit(commit) {
+		return false
+	}
+	_, err := f.run(ctx, nil, "--git-dir", mirror, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// resolve returns the full commit identifier the ref resolves to in the mirror.
+func (f *Fetcher) resolve(ctx context.Context, mirror string, ref string) (string, error) {
+	output, err := f.run(ctx, nil, "--git-dir", mirror, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return "", ErrRefNotFound
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// checkout extracts the commit into its working copy in the cache, if not done yet, and returns its path.
+// The files are extracted into a temporary directory renamed once complete, so that a working copy is never partial.
+func (f *Fetcher) checkout(ctx context.Context, mirror string, commit string) (string, error) {
+	path := filepath.Join(f.cacheDir, "checkouts", commit)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	tmp, err := os.MkdirTemp(filepath.Join(f.cacheDir, "checkouts"), commit+".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmp, ".git-index")}
+	if _, err := f.run(ctx, env, "--git-dir", mirror, "--work-tree", tmp, "read-tree", commit); err != nil {
+		return "", err
+	}
+	if _, err := f.run(ctx, env, "--git-dir", mirror, "--work-tree", tmp, "checkout-index", "--all", "--force"); err != nil {
+		return "", err
+	}
+	if err := os.Remove(filepath.Join(tmp, ".git-index")); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// run executes a git command without prompting for credentials, returning its standard output.
+func (f *Fetcher) run(ctx context.Context, env []string, args ...string) (string, error) {
+	command := exec.CommandContext(ctx, f.git, args...)
+	command.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ALLOW_PROTOCOL="+ALLOWED_PROTOCOLS)
+	command.Env = append(command.Env, env...)
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("%w: git %s: %s", ErrGitCommandFailed, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,186 @@
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+// git runs a git command in the given directory for the test fixtures, returning its trimmed output.
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	command.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	output, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commitFile writes a file in the working copy and commits it, returning the commit identifier.
+func commitFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, dir, "add", "--all")
+	git(t, dir, "commit", "--quiet", "--message", "update "+name)
+	return git(t, dir, "rev-parse", "HEAD")
+}
+
+// newTestRepository creates a local bare repository with a tagged first commit, returning its path, the working copy and the tagged commit.
+func newTestRepository(t *testing.T) (string, string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	bare := filepath.Join(root, "repo.git")
+	work := filepath.Join(root, "work")
+	git(t, root, "init", "--quiet", "--bare", "--initial-branch=main", bare)
+	git(t, root, "clone", "--quiet", bare, work)
+	tagged := commitFile(t, work, "modules/vpc/main.tf", "version 1")
+	git(t, work, "tag", "v1.0.0")
+	git(t, work, "push", "--quiet", "origin", "HEAD:main", "v1.0.0")
+	return bare, work, tagged
+}
+
+func TestFetcherFetch(t *testing.T) {
+	bare, work, tagged := newTestRepository(t)
+	fetcher, err := NewFetcher(t.TempDir())
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	ctx := context.Background()
+
+	source, _ := common.ParseGitSource("git::" + bare + "//modules/vpc?ref=v1.0.0")
+	checkout, err := fetcher.Fetch(ctx, source, "", nil)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if checkout.GetCommit() != tagged {
+		t.Errorf("expected commit %s, got %s", tagged, checkout.GetCommit())
+	}
+	if content, _ := os.ReadFile(filepath.Join(checkout.GetPath(), "main.tf")); string(content) != "version 1" {
+		t.Errorf("unexpected checked out content %q", content)
+	}
+
+	latest := commitFile(t, work, "modules/vpc/main.tf", "version 2")
+	git(t, work, "push", "--quiet", "origin", "HEAD:main")
+	head, _ := common.ParseGitSource("git::" + bare + "//modules/vpc")
+	checkout, err = fetcher.Fetch(ctx, head, "", nil)
+	if err != nil || checkout.GetCommit() != latest {
+		t.Errorf("expected the default branch to resolve to %s, got %v (%v)", latest, checkout, err)
+	}
+	checkout, err = fetcher.Fetch(ctx, head, tagged, nil)
+	if err != nil || checkout.GetCommit() != tagged {
+		t.Errorf("expected the pinned commit %s, got %v (%v)", tagged, checkout, err)
+	}
+
+	missing, _ := common.ParseGitSource("git::" + bare + "?ref=v9.9.9")
+	if _, err := fetcher.Fetch(ctx, missing, "", nil); err != ErrRefNotFound {
+		t.Errorf("expected err to be ErrRefNotFound, got %v", err)
+	}
+	subdirectory, _ := common.ParseGitSource("git::" + bare + "//modules/eks")
+	if _, err := fetcher.Fetch(ctx, subdirectory, "", nil); err != ErrSubdirectoryNotFound {
+		t.Errorf("expected err to be ErrSubdirectoryNotFound, got %v", err)
+	}
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(work, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("modules/vpc", filepath.Join(work, "vpc")); err != nil {
+		t.Fatal(err)
+	}
+	linked := commitFile(t, work, "README.md", "links")
+	git(t, work, "push", "--quiet", "origin", "HEAD:main")
+	escape, _ := common.ParseGitSource("git::" + bare + "//escape")
+	if _, err := fetcher.Fetch(ctx, escape, linked, nil); err != ErrSubdirectoryNotFound {
+		t.Errorf("expected a subdirectory linking outside of the checkout to be rejected, got %v", err)
+	}
+	alias, _ := common.ParseGitSource("git::" + bare + "//vpc")
+	checkout, err = fetcher.Fetch(ctx, alias, linked, nil)
+	if err != nil {
+		t.Fatalf("expected a subdirectory linking inside the checkout to be accepted, got %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(checkout.GetPath(), "main.tf")); string(content) != "version 2" {
+		t.Errorf("unexpected checked out content %q", content)
+	}
+	unknown, _ := common.ParseGitSource("git::" + filepath.Join(t.TempDir(), "unknown.git"))
+	if _, err := fetcher.Fetch(ctx, unknown, "", nil); !errors.Is(err, ErrGitCommandFailed) {
+		t.Errorf("expected err to be ErrGitCommandFailed, got %v", err)
+	}
+}
+
+func TestFetcherFetchTemplate(t *testing.T) {
+	bare, work, tagged := newTestRepository(t)
+	fetcher, _ := NewFetcher(t.TempDir())
+	tmpl, err := template.NewTemplate("autops::project:ABCDEFGHIJ", "vpc", "", common.PENDING, template.TERRAFORM, "git::"+bare+"//modules/vpc?ref=main")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if _, err := fetcher.FetchTemplate(context.Background(), tmpl, nil, nil); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if tmpl.GetCommit() != tagged {
+		t.Errorf("expected the template version to be pinned to %s, got %s", tagged, tmpl.GetCommit())
+	}
+
+	commitFile(t, work, "modules/vpc/main.tf", "version 2")
+	git(t, work, "push", "--quiet", "origin", "HEAD:main")
+	checkout, err := fetcher.FetchTemplate(context.Background(), tmpl, nil, nil)
+	if err != nil || checkout.GetCommit() != tagged {
+		t.Errorf("expected the pinned commit to be checked out, got %v (%v)", checkout, err)
+	}
+
+	local, _ := template.NewTemplate("autops::project:ABCDEFGHIJ", "local", "", common.PENDING, template.TERRAFORM, "/path/to/file.zip")
+	if _, err := fetcher.FetchTemplate(context.Background(), local, nil, nil); err != ErrNotAGitSource {
+		t.Errorf("expected err to be ErrNotAGitSource, got %v", err)
+	}
+}
+
+func TestCredentialsFromSecret(t *testing.T) {
+	key, _ := secret.NewMasterKey(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, secret.MASTER_KEY_LENGTH)))
+	token, _ := secret.NewSecret("autops::project:ABCDEFGHIJ", "token", "", secret.ENV_VAR, "GIT_TOKEN", []byte("deploy:s3cr3t"), key)
+	https, _ := common.ParseGitSource("git::https://example.com/org/repo.git")
+	credentials, err := CredentialsFromSecret(https, token, key)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	env, cleanup, _ := credentials.environment()
+	defer cleanup()
+	expected := "GIT_CONFIG_VALUE_0=Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("deploy:s3cr3t"))
+	if len(env) != 5 || env[2] != expected || env[3] != "GIT_CONFIG_KEY_1=http.followRedirects" || env[4] != "GIT_CONFIG_VALUE_1=false" {
+		t.Errorf("unexpected environment %v", env)
+	}
+	for _, sourcePath := range []string{"git::http://example.com/org/repo.git", "git::file:///srv/git/repo.git", "git::/srv/git/repo.git"} {
+		insecure, _ := common.ParseGitSource(sourcePath)
+		if _, err := CredentialsFromSecret(insecure, token, key); err != ErrInsecureRepository {
+			t.Errorf("%s: expected err to be ErrInsecureRepository, got %v", sourcePath, err)
+		}
+	}
+
+	ssh, _ := common.ParseGitSource("git::git@example.com:org/repo.git")
+	credentials, _ = CredentialsFromSecret(ssh, token, key)
+	env, cleanup, _ = credentials.environment()
+	if len(env) != 1 || !strings.HasPrefix(env[0], "GIT_SSH_COMMAND=ssh -i ") {
+		t.Errorf("unexpected environment %v", env)
+	}
+	cleanup()
+}
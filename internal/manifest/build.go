@@ -0,0 +1,331 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// buildTemplate creates the template declared by a spec, with the given identifier and version number.
+func buildTemplate(identifier string, version int, spec *TemplateSpec) (*template.Template, error) {
+	templateType, err := template.ParseTemplateType(spec.Type)
+	if err != nil {
+		return nil, fmt.Errorf("type: %w", err)
+	}
+	t, err := template.ExistingTemplate(identifier, spec.Name, spec.Description, common.PENDING, templateType, spec.Source, version)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Version != "" {
+		semanticVersion, err := common.ParseSemanticVersion(spec.Version)
+		if err != nil {
+			return nil, fmt.Errorf("version: %w", err)
+		}
+		t.SetSemanticVersion(semanticVersion)
+	}
+	if spec.SourceCredentials != "" {
+		credentials, err := common.NewIdentifier(spec.SourceCredentials)
+		if err == nil {
+			err = t.SetSourceCredentials(credentials)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("source_credentials: %w", err)
+		}
+	}
+	for i, attributeSpec := range spec.Inputs {
+		input, err := buildTemplateAttribute(identifier, attributeSpec)
+		if err == nil {
+			err = t.AddInput(input)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("inputs[%d]: %w", i, err)
+		}
+	}
+	for i, attributeSpec := range spec.Outputs {
+		output, err := buildTemplateAttribute(identifier, attributeSpec)
+		if err == nil {
+			err = t.AddOutput(output)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+	}
+	return t, nil
+}
+
+// buildTemplateAttribute creates the template attribute declared by a spec.
+// The sensitivity is set before the default value, which is a secret reference for sensitive attributes.
+func buildTemplateAttribute(templateId string, spec *AttributeSpec) (*template.TemplateAttribute, error) {
+	attributeType, err := common.ParseAttributeType(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := buildConstraints(spec.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	attribute, err := template.NewTemplateAttribute(templateId, spec.Name, spec.Description, attributeType, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := attribute.SetSensitive(spec.Sensitive); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetConstraints(constraints); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetDefaultValue(spec.Default); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
+
+// buildWorkflowAttribute creates the workflow attribute declared by a spec.
+// The sensitivity is set before the default value, which is a secret reference for sensitive attributes.
+func buildWorkflowAttribute(workflowId string, spec *AttributeSpec) (*workflow.WorkflowAttribute, error) {
+	attributeType, err := common.ParseAttributeType(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := buildConstraints(spec.Constraints)
+	if err != nil {
+		return nil, err
+	}
+	attribute, err := workflow.NewWorkflowAttribute(workflowId, spec.Name, spec.Description, attributeType, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := attribute.SetSensitive(spec.Sensitive); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetConstraints(constraints); err != nil {
+		return nil, err
+	}
+	if err := attribute.SetDefaultValue(spec.Default); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
+
+// buildConstraints creates the attribute constraints declared by a spec, or returns nil if there are none.
+func buildConstraints(spec *ConstraintsSpec) (*common.AttributeConstraints, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	constraints := common.NewAttributeConstraints()
+	constraints.SetRequired(spec.Required)
+	constraints.SetAllowedValues(spec.AllowedValues)
+	if spec.Pattern != "" {
+		if err := constraints.SetPattern(spec.Pattern); err != nil {
+			return nil, err
+		}
+	}
+	if err := constraints.SetLength(spec.MinLength, spec.MaxLength); err != nil {
+		return nil, err
+	}
+	if err := constraints.SetRange(spec.Minimum, spec.Maximum); err != nil {
+		return nil, err
+	}
+	if err := constraints.SetItems(spec.MinItems, spec.MaxItems); err != nil {
+		return nil, err
+	}
+	if spec.ElementType != "" {
+		elementType, err := common.ParseAttributeType(spec.ElementType)
+		if err != nil {
+			return nil, err
+		}
+		if err := constraints.SetElementType(&elementType); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Schema != nil {
+		document, err := json.Marshal(spec.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", common.ErrInvalidJSONSchema, err.Error())
+		}
+		schema, err := common.ParseJSONSchema(string(document))
+		if err != nil {
+			return nil, err
+		}
+		constraints.SetSchema(schema)
+	}
+	return constraints, nil
+}
+
+// buildWorkflow creates the workflow declared by a spec, with the given identifier and version number.
+// Steps run the templates of the manifest, indexed by name. The runs and triggers of the previous version are kept, if any.
+func buildWorkflow(identifier string, version int, spec *WorkflowSpec, templates map[string]*template.Template, previous *workflow.Workflow) (*workflow.Workflow, error) {
+	inputs := []*workflow.WorkflowAttribute{}
+	for i, attributeSpec := range spec.Inputs {
+		input, err := buildWorkflowAttribute(identifier, attributeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("inputs[%d]: %w", i, err)
+		}
+		inputs = append(inputs, input)
+	}
+	outputs := []*workflow.WorkflowAttribute{}
+	for i, attributeSpec := range spec.Outputs {
+		output, err := buildWorkflowAttribute(identifier, attributeSpec)
+		if err != nil {
+			return nil, fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+		outputs = append(outputs, output)
+	}
+	runs, triggers := []*workflow.WorkflowRun{}, []*workflow.WebhookTrigger{}
+	if previous != nil {
+		runs, triggers = previous.ListRuns(), previous.ListTriggers()
+	}
+	w, err := workflow.ExistingWorkflow(identifier, spec.Name, spec.Description, common.PENDING, spec.Source, version, inputs, outputs, []*workflow.WorkflowStep{}, runs, triggers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps are added by increasing number, since adding a step shifts the steps from its number.
+	order := make([]int, len(spec.Steps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return spec.Steps[order[i]].Number < spec.Steps[order[j]].Number
+	})
+	numbers := map[int]bool{}
+	for _, i := range order {
+		stepSpec := spec.Steps[i]
+		if numbers[stepSpec.Number] {
+			return nil, fmt.Errorf("%w: steps[%d].number: duplicate step number %d", ErrInvalidManifest, i, stepSpec.Number)
+		}
+		numbers[stepSpec.Number] = true
+		step, err := buildStep(identifier, stepSpec, templates)
+		if err != nil {
+			return nil, fmt.Errorf("steps[%d]: %w", i, err)
+		}
+		w.AddStep(step)
+	}
+
+	names := make([]string, 0, len(spec.OutputBindings))
+	for name := range spec.OutputBindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		binding := spec.OutputBindings[name]
+		if binding == nil {
+			return nil, fmt.Errorf("%w: output_bindings.%s: a step and an output are required", ErrInvalidManifest, name)
+		}
+		if err := w.BindOutput(name, binding.Step, binding.Output); err != nil {
+			return nil, fmt.Errorf("output_bindings.%s: %w", name, err)
+		}
+	}
+	return w, nil
+}
+
+// buildStep creates the workflow step declared by a spec.
+func buildStep(workflowId string, spec *StepSpec, templates map[string]*template.Template) (*workflow.WorkflowStep, error) {
+	var task *template.Template
+	if spec.Template != "" {
+		task = templates[spec.Template]
+		if task == nil {
+			return nil, fmt.Errorf("template: %w", ErrUnknownTemplate)
+		}
+	}
+	step, err := workflow.NewWorkflowStep(workflowId, spec.Name, spec.Description, spec.Number, task)
+	if err != nil {
+		return nil, err
+	}
+	if spec.VersionRange != "" {
+		versionRange, err := common.ParseVersionRange(spec.VersionRange)
+		if err == nil {
+			err = step.SetVersionRange(versionRange)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("version_range: %w", err)
+		}
+	}
+	if spec.Approval != nil {
+		gate, err := buildApprovalGate(spec.Approval)
+		if err != nil {
+			return nil, fmt.Errorf("approval: %w", err)
+		}
+		step.SetApprovalGate(gate)
+	}
+	return step, nil
+}
+
+// buildApprovalGate creates the approval gate declared by a spec.
+func buildApprovalGate(spec *ApprovalSpec) (*workflow.ApprovalGate, error) {
+	approvers := []*common.Identifier{}
+	for _, approver := range spec.Approvers {
+		identifier, err := common.NewIdentifier(approver)
+		if err != nil {
+			return nil, err
+		}
+		approvers = append(approvers, identifier)
+	}
+	var action policy.PolicyAction
+	if spec.ApproverAction != "" {
+		parsed, err := policy.ParsePolicyAction(spec.ApproverAction)
+		if err != nil {
+			return nil, err
+		}
+		action = parsed
+	}
+	timeout, err := time.ParseDuration(spec.Timeout)
+	if err != nil {
+		return nil, ErrInvalidApprovalTimeout
+	}
+	return workflow.NewApprovalGate(approvers, action, spec.MinApprovals, timeout)
+}
+
+// buildPolicy creates the policy declared by a spec, with the given identifier and creation date.
+// Resources are resolved from their manifest references, and errors located like "statements[0].actions[1]: ...".
+func buildPolicy(identifier string, createdAt string, spec *PolicySpec, r *references) (*policy.Policy, error) {
+	statements := []*policy.PolicyStatement{}
+	for i, statementSpec := range spec.Statements {
+		resources := []string{}
+		for j, reference := range statementSpec.Resources {
+			resource, err := r.resolve(reference)
+			if err != nil {
+				return nil, policy.NewStatementError(i, fmt.Sprintf("resources[%d]", j), err)
+			}
+			resources = append(resources, resource.ToString())
+		}
+		statement, err := policy.ParsePolicyStatement(i, statementSpec.Effect, statementSpec.Actions, resources)
+		if err != nil {
+			return nil, err
+		}
+		conditions := []*policy.Condition{}
+		for j, conditionSpec := range statementSpec.Conditions {
+			condition, err := policy.ParseStatementCondition(i, j, conditionSpec.Operator, conditionSpec.Key, conditionSpec.Values)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		statement.SetConditions(conditions)
+		statements = append(statements, statement)
+	}
+	p, err := policy.ExistingPolicy(identifier, spec.Name, spec.Description, createdAt, common.CurrentTimestamp(), statements)
+	if err != nil {
+		return nil, err
+	}
+	addTags(&p.TaggedEntity, spec.Tags)
+	return p, nil
+}
+
+// addTags adds the tags to the entity, sorted by key.
+func addTags(entity *common.TaggedEntity, tags map[string]string) {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entity.AddTag(common.NewTag(key, tags[key]))
+	}
+}
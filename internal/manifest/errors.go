@@ -0,0 +1,11 @@
+package manifest
+
+import "errors"
+
+var (
+	ErrInvalidManifest        = errors.New("invalid project manifest")
+	ErrUnknownTemplate        = errors.New("the manifest does not declare a template with this name")
+	ErrTemplateOutsideProject = errors.New("the step runs a template of another project, which cannot be described by the manifest")
+	ErrInvalidApprovalTimeout = errors.New("the approval timeout must be a duration like 24h")
+	ErrProjectMismatch        = errors.New("the manifest describes another project")
+)
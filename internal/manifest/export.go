@@ -0,0 +1,259 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// Export describes a project as a manifest.
+// Returns ErrTemplateOutsideProject if a step of a workflow runs a template of another project.
+func Export(p *project.Project) (*Manifest, error) {
+	references := newReferences(p.GetIdentifier(), p.ListTemplates(), p.ListWorkflows(), p.ListPolicies())
+	manifest := &Manifest{
+		APIVersion: API_VERSION,
+		Kind:       KIND,
+		Metadata: ProjectMetadata{
+			ID:          p.GetIdentifier().ToString(),
+			Name:        p.GetName(),
+			Description: p.GetDescription(),
+			Tags:        exportTags(p.ListTags()),
+		},
+	}
+	for _, t := range p.ListTemplates() {
+		manifest.Templates = append(manifest.Templates, exportTemplate(t))
+	}
+	for _, w := range p.ListWorkflows() {
+		spec, err := exportWorkflow(w, references)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Workflows = append(manifest.Workflows, spec)
+	}
+	for _, pol := range p.ListPolicies() {
+		manifest.Policies = append(manifest.Policies, exportPolicy(pol, references))
+	}
+	return manifest, nil
+}
+
+// references maps the identifiers of the resources of a project to their manifest references, and back.
+type references struct {
+	toReference map[string]string
+	toIdentity  map[string]*common.Identifier
+}
+
+// newReferences indexes the resources of a project by identifier and by reference.
+func newReferences(projectId *common.Identifier, templates []*template.Template, workflows []*workflow.Workflow, policies []*policy.Policy) *references {
+	r := &references{
+		toReference: map[string]string{projectId.ToString(): "project"},
+		toIdentity:  map[string]*common.Identifier{"project": projectId},
+	}
+	for _, t := range templates {
+		r.add(common.TEMPLATE, t.GetIdentifier(), t.GetName())
+	}
+	for _, w := range workflows {
+		r.add(common.WORKFLOW, w.GetIdentifier(), w.GetName())
+	}
+	for _, pol := range policies {
+		r.add(common.POLICY, pol.GetIdentifier(), pol.GetName())
+	}
+	return r
+}
+
+// add indexes a resource of the project, referenced as "<resource type>/<name>".
+func (r *references) add(resourceType common.ResourceType, identifier *common.Identifier, name string) {
+	kind, _ := resourceType.ToString()
+	r.toReference[identifier.ToString()] = kind + "/" + name
+	r.toIdentity[kind+"/"+name] = identifier
+}
+
+// reference returns the manifest reference of a resource, or its identifier if it is not a resource of the project.
+func (r *references) reference(identifier *common.Identifier) string {
+	if reference, found := r.toReference[identifier.ToString()]; found {
+		return reference
+	}
+	return identifier.ToString()
+}
+
+// resolve returns the identifier of a manifest reference, or parses it as an identifier if it is not a reference to a resource of the project.
+func (r *references) resolve(reference string) (*common.Identifier, error) {
+	if identifier, found := r.toIdentity[reference]; found {
+		return identifier, nil
+	}
+	return common.NewIdentifier(reference)
+}
+
+// templateName returns the name of a template of the project.
+func (r *references) templateName(t *template.Template) (string, bool) {
+	reference, found := r.toReference[t.GetIdentifier().ToString()]
+	if !found {
+		return "", false
+	}
+	return reference[len("template/"):], true
+}
+
+// exportTags returns the tags as a map, or nil if there are none.
+func exportTags(tags []*common.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	exported := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		exported[tag.GetKey()] = tag.GetValue()
+	}
+	return exported
+}
+
+// exportTemplate describes a template as a TemplateSpec.
+func exportTemplate(t *template.Template) *TemplateSpec {
+	spec := &TemplateSpec{
+		Name:        t.GetName(),
+		Description: t.GetDescription(),
+		Type:        t.GetTemplateType().ToString(),
+		Source:      t.GetSourcePath(),
+	}
+	if t.GetSemanticVersion() != nil {
+		spec.Version = t.GetSemanticVersion().ToString()
+	}
+	if t.GetSourceCredentials() != nil {
+		spec.SourceCredentials = t.GetSourceCredentials().ToString()
+	}
+	for _, input := range t.ListInputs() {
+		spec.Inputs = append(spec.Inputs, exportAttribute(input.GetName(), input.GetDescription(), input.GetType(), input.GetDefaultValue(), input.IsSensitive(), input.GetConstraints()))
+	}
+	for _, output := range t.ListOutputs() {
+		spec.Outputs = append(spec.Outputs, exportAttribute(output.GetName(), output.GetDescription(), output.GetType(), output.GetDefaultValue(), output.IsSensitive(), output.GetConstraints()))
+	}
+	return spec
+}
+
+// exportAttribute describes an attribute of a template or a workflow as an AttributeSpec.
+func exportAttribute(name string, description string, attributeType common.AttributeType, defaultValue string, sensitive bool, constraints *common.AttributeConstraints) *AttributeSpec {
+	return &AttributeSpec{
+		Name:        name,
+		Description: description,
+		Type:        attributeType.ToString(),
+		Default:     defaultValue,
+		Sensitive:   sensitive,
+		Constraints: exportConstraints(constraints),
+	}
+}
+
+// exportConstraints describes attribute constraints as a ConstraintsSpec, or returns nil if there are none.
+func exportConstraints(constraints *common.AttributeConstraints) *ConstraintsSpec {
+	if constraints == nil || constraints.IsEmpty() {
+		return nil
+	}
+	spec := &ConstraintsSpec{
+		Required:      constraints.IsRequired(),
+		AllowedValues: constraints.ListAllowedValues(),
+		Pattern:       constraints.GetPattern(),
+	}
+	if len(spec.AllowedValues) == 0 {
+		spec.AllowedValues = nil
+	}
+	spec.MinLength, spec.MaxLength = constraints.GetLength()
+	spec.Minimum, spec.Maximum = constraints.GetRange()
+	spec.MinItems, spec.MaxItems = constraints.GetItems()
+	if constraints.GetElementType() != nil {
+		spec.ElementType = constraints.GetElementType().ToString()
+	}
+	if constraints.GetSchema() != nil {
+		json.Unmarshal([]byte(constraints.GetSchema().ToString()), &spec.Schema)
+	}
+	return spec
+}
+
+// exportWorkflow describes a workflow as a WorkflowSpec, referencing the templates of its steps by name.
+func exportWorkflow(w *workflow.Workflow, r *references) (*WorkflowSpec, error) {
+	spec := &WorkflowSpec{
+		Name:        w.GetName(),
+		Description: w.GetDescription(),
+		Source:      w.GetSourcePath(),
+	}
+	for _, input := range w.ListInputs() {
+		spec.Inputs = append(spec.Inputs, exportAttribute(input.GetName(), input.GetDescription(), input.GetType(), input.GetDefaultValue(), input.IsSensitive(), input.GetConstraints()))
+	}
+	for _, output := range w.ListOutputs() {
+		spec.Outputs = append(spec.Outputs, exportAttribute(output.GetName(), output.GetDescription(), output.GetType(), output.GetDefaultValue(), output.IsSensitive(), output.GetConstraints()))
+	}
+	steps := append([]*workflow.WorkflowStep(nil), w.ListSteps()...)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].GetStepNumber() < steps[j].GetStepNumber()
+	})
+	for _, step := range steps {
+		stepSpec := &StepSpec{
+			Number:      step.GetStepNumber(),
+			Name:        step.GetName(),
+			Description: step.GetDescription(),
+		}
+		if step.GetTask() != nil {
+			name, found := r.templateName(step.GetTask())
+			if !found {
+				return nil, fmt.Errorf("workflow %q, step %d: %w", w.GetName(), step.GetStepNumber(), ErrTemplateOutsideProject)
+			}
+			stepSpec.Template = name
+		}
+		if step.GetVersionRange() != nil {
+			stepSpec.VersionRange = step.GetVersionRange().ToString()
+		}
+		if step.IsApprovalGate() {
+			stepSpec.Approval = exportApprovalGate(step.GetApprovalGate())
+		}
+		spec.Steps = append(spec.Steps, stepSpec)
+	}
+	for name, binding := range w.ListOutputBindings() {
+		if spec.OutputBindings == nil {
+			spec.OutputBindings = map[string]*BindingSpec{}
+		}
+		spec.OutputBindings[name] = &BindingSpec{Step: binding.GetStepNumber(), Output: binding.GetStepOutput()}
+	}
+	return spec, nil
+}
+
+// exportApprovalGate describes an approval gate as an ApprovalSpec.
+func exportApprovalGate(gate *workflow.ApprovalGate) *ApprovalSpec {
+	spec := &ApprovalSpec{
+		MinApprovals: gate.GetMinApprovals(),
+		Timeout:      gate.GetTimeout().String(),
+	}
+	for _, approver := range gate.ListApprovers() {
+		spec.Approvers = append(spec.Approvers, approver.ToString())
+	}
+	if gate.GetApproverAction() != nil {
+		spec.ApproverAction, _ = policy.FormatPolicyAction(gate.GetApproverAction())
+	}
+	return spec
+}
+
+// exportPolicy describes a policy as a PolicySpec, referencing the resources of the project by kind and name.
+func exportPolicy(p *policy.Policy, r *references) *PolicySpec {
+	spec := &PolicySpec{
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Tags:        exportTags(p.ListTags()),
+	}
+	for _, statement := range p.ListStatements() {
+		effect, _ := statement.GetEffect().ToString()
+		statementSpec := &StatementSpec{Effect: effect, Actions: []string{}, Resources: []string{}}
+		for _, action := range statement.ListActions() {
+			formatted, _ := policy.FormatPolicyAction(action)
+			statementSpec.Actions = append(statementSpec.Actions, formatted)
+		}
+		for _, resource := range statement.ListResources() {
+			statementSpec.Resources = append(statementSpec.Resources, r.reference(resource))
+		}
+		for _, condition := range statement.ListConditions() {
+			operator, _ := condition.GetOperator().ToString()
+			statementSpec.Conditions = append(statementSpec.Conditions, &ConditionSpec{Operator: operator, Key: condition.GetKey(), Values: condition.ListValues()})
+		}
+		spec.Statements = append(spec.Statements, statementSpec)
+	}
+	return spec
+}
@@ -0,0 +1,437 @@
+package manifest
+
+import (
+	"fmt"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// ChangeAction defines how an import changes a resource.
+type ChangeAction int
+
+const (
+	CREATE ChangeAction = iota
+	UPDATE
+	DELETE
+)
+
+// ToString returns the string representation of a ChangeAction.
+func (a ChangeAction) ToString() string {
+	switch a {
+	case CREATE:
+		return "create"
+	case UPDATE:
+		return "update"
+	default:
+		return "delete"
+	}
+}
+
+// Change describes how an import changes the project or one of its resources.
+type Change struct {
+	action       ChangeAction
+	resourceType common.ResourceType
+	name         string
+	identifier   *common.Identifier
+}
+
+// GetAction returns how the resource is changed.
+func (c *Change) GetAction() ChangeAction {
+	return c.action
+}
+
+// GetResourceType returns the type of the changed resource: PROJECT, TEMPLATE, WORKFLOW or POLICY.
+func (c *Change) GetResourceType() common.ResourceType {
+	return c.resourceType
+}
+
+// GetName returns the name of the changed resource.
+func (c *Change) GetName() string {
+	return c.name
+}
+
+// GetIdentifier returns the identifier of the changed resource, generated beforehand for created resources.
+func (c *Change) GetIdentifier() *common.Identifier {
+	return c.identifier
+}
+
+// Warning reports a breaking change that the plan introduces in a current workflow, by upgrading the template of one of its steps.
+type Warning struct {
+	workflow string
+	template string
+	upgrade  *workflow.UpgradeWarning
+}
+
+// GetWorkflowName returns the name of the workflow whose step is upgraded.
+func (w *Warning) GetWorkflowName() string {
+	return w.workflow
+}
+
+// GetTemplateName returns the name of the upgraded template.
+func (w *Warning) GetTemplateName() string {
+	return w.template
+}
+
+// GetUpgradeWarning returns the breaking change introduced in the step.
+func (w *Warning) GetUpgradeWarning() *workflow.UpgradeWarning {
+	return w.upgrade
+}
+
+// ImportPlan lists the changes needed to make a project match a manifest, and applies them.
+// Templates and workflows that change get a new version, and keep their identifier like policies.
+// Resources of the project missing from the manifest are deleted.
+type ImportPlan struct {
+	current   *project.Project
+	target    *project.Project
+	metadata  ProjectMetadata
+	templates []*template.Template
+	workflows []*workflow.Workflow
+	policies  []*policy.Policy
+	changes   []*Change
+	warnings  []*Warning
+}
+
+// NewImportPlan builds every resource declared by the manifest and compares them to the resources of the current project,
+// or plans the creation of a new project if current is nil.
+//
+// Returns an error prefixed by the location of the invalid declaration (e.g. "templates[0].inputs[1]: ..."),
+// or ErrProjectMismatch if the manifest declares the identifier of another project.
+func NewImportPlan(current *project.Project, manifest *Manifest) (*ImportPlan, error) {
+	if current != nil && manifest.Metadata.ID != "" && manifest.Metadata.ID != current.GetIdentifier().ToString() {
+		return nil, ErrProjectMismatch
+	}
+	target, err := project.NewProject(manifest.Metadata.Name, manifest.Metadata.Description)
+	if err != nil {
+		return nil, fmt.Errorf("metadata: %w", err)
+	}
+	plan := &ImportPlan{current: current, target: target, metadata: manifest.Metadata}
+	if current == nil {
+		addTags(&target.TaggedEntity, manifest.Metadata.Tags)
+		plan.addChange(CREATE, common.PROJECT, target.GetName(), target.GetIdentifier())
+	} else {
+		plan.target = current
+		if current.GetName() != manifest.Metadata.Name || current.GetDescription() != manifest.Metadata.Description || !sameSpec(exportTags(current.ListTags()), manifest.Metadata.Tags) {
+			plan.addChange(UPDATE, common.PROJECT, manifest.Metadata.Name, current.GetIdentifier())
+		}
+	}
+
+	if err := plan.planTemplates(manifest.Templates); err != nil {
+		return nil, err
+	}
+	if err := plan.planWorkflows(manifest.Workflows); err != nil {
+		return nil, err
+	}
+	if err := plan.planPolicies(manifest.Policies); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// addChange records a change of the plan.
+func (p *ImportPlan) addChange(action ChangeAction, resourceType common.ResourceType, name string, identifier *common.Identifier) {
+	p.changes = append(p.changes, &Change{action: action, resourceType: resourceType, name: name, identifier: identifier})
+}
+
+// planTemplates builds the templates of the manifest, keeping the current templates that do not change.
+func (p *ImportPlan) planTemplates(specs []*TemplateSpec) error {
+	current := []*template.Template{}
+	if p.current != nil {
+		current = p.current.ListTemplates()
+	}
+	existing := map[string]*template.Template{}
+	for _, t := range current {
+		existing[t.GetName()] = t
+	}
+	for i, spec := range specs {
+		previous := existing[spec.Name]
+		delete(existing, spec.Name)
+		identifier, version := "", 1
+		if previous != nil {
+			identifier, version = previous.GetIdentifier().ToString(), previous.GetVersion()+1
+		} else {
+			generated, err := common.BuildTemplateIdentifier(p.target.GetIdentifier().ToString())
+			if err != nil {
+				return err
+			}
+			identifier = generated.ToString()
+		}
+		built, err := buildTemplate(identifier, version, spec)
+		if err != nil {
+			return fmt.Errorf("templates[%d]: %w", i, err)
+		}
+		declared := built.GetSemanticVersion()
+		if previous != nil && declared == nil {
+			// A template declared without version is unchanged if it only lacks the version released with the previous one.
+			built.SetSemanticVersion(previous.GetSemanticVersion())
+		}
+		switch {
+		case previous == nil:
+			p.addChange(CREATE, common.TEMPLATE, spec.Name, built.GetIdentifier())
+		case sameSpec(exportTemplate(previous), exportTemplate(built)):
+			built = previous
+		default:
+			built.SetSemanticVersion(declared)
+			if _, err := built.ReleaseAfter(previous); err != nil {
+				return fmt.Errorf("templates[%d]: %w", i, err)
+			}
+			p.addChange(UPDATE, common.TEMPLATE, spec.Name, built.GetIdentifier())
+			p.checkUpgrade(built)
+		}
+		p.templates = append(p.templates, built)
+	}
+	planDeletions(p, common.TEMPLATE, current, existing)
+	return nil
+}
+
+// checkUpgrade records the breaking changes that the new version of a template introduces in the current workflows.
+func (p *ImportPlan) checkUpgrade(candidate *template.Template) {
+	for _, w := range p.current.ListWorkflows() {
+		for _, upgrade := range w.CheckTemplateUpgrade(candidate) {
+			p.warnings = append(p.warnings, &Warning{workflow: w.GetName(), template: candidate.GetName(), upgrade: upgrade})
+		}
+	}
+}
+
+// planWorkflows builds the workflows of the manifest, keeping the current workflows that do not change.
+// A workflow also changes if one of its steps runs a template that changes.
+func (p *ImportPlan) planWorkflows(specs []*WorkflowSpec) error {
+	templates := map[string]*template.Template{}
+	for _, t := range p.templates {
+		templates[t.GetName()] = t
+	}
+	r := newReferences(p.target.GetIdentifier(), p.templates, nil, nil)
+	current := []*workflow.Workflow{}
+	if p.current != nil {
+		current = p.current.ListWorkflows()
+	}
+	existing := map[string]*workflow.Workflow{}
+	for _, w := range current {
+		existing[w.GetName()] = w
+	}
+	for i, spec := range specs {
+		previous := existing[spec.Name]
+		delete(existing, spec.Name)
+		identifier, version := "", 1
+		if previous != nil {
+			identifier, version = previous.GetIdentifier().ToString(), previous.GetVersion()+1
+		} else {
+			generated, err := common.BuildWorkflowIdentifier(p.target.GetIdentifier().ToString())
+			if err != nil {
+				return err
+			}
+			identifier = generated.ToString()
+		}
+		built, err := buildWorkflow(identifier, version, spec, templates, previous)
+		if err != nil {
+			return fmt.Errorf("workflows[%d]: %w", i, err)
+		}
+		switch {
+		case previous == nil:
+			p.addChange(CREATE, common.WORKFLOW, spec.Name, built.GetIdentifier())
+		case sameWorkflow(previous, built, templates, r):
+			built = previous
+		default:
+			p.addChange(UPDATE, common.WORKFLOW, spec.Name, built.GetIdentifier())
+		}
+		p.workflows = append(p.workflows, built)
+	}
+	planDeletions(p, common.WORKFLOW, current, existing)
+	return nil
+}
+
+// sameWorkflow returns true if the current workflow declares the same workflow as the built one,
+// and its steps already run the planned templates.
+func sameWorkflow(previous *workflow.Workflow, built *workflow.Workflow, templates map[string]*template.Template, r *references) bool {
+	for _, step := range previous.ListSteps() {
+		task := step.GetTask()
+		if task == nil {
+			continue
+		}
+		planned := templates[task.GetName()]
+		if planned == nil || planned.GetIdentifier().ToString() != task.GetIdentifier().ToString() || planned.GetVersion() != task.GetVersion() {
+			return false
+		}
+	}
+	previousSpec, err := exportWorkflow(previous, r)
+	if err != nil {
+		return false
+	}
+	builtSpec, err := exportWorkflow(built, r)
+	return err == nil && sameSpec(previousSpec, builtSpec)
+}
+
+// planPolicies builds the policies of the manifest, keeping the current policies that do not change.
+// Policies are built once every resource of the project has an identifier, so that their statements can reference any of them.
+func (p *ImportPlan) planPolicies(specs []*PolicySpec) error {
+	current := []*policy.Policy{}
+	if p.current != nil {
+		current = p.current.ListPolicies()
+	}
+	existing := map[string]*policy.Policy{}
+	for _, pol := range current {
+		existing[pol.GetName()] = pol
+	}
+	r := newReferences(p.target.GetIdentifier(), p.templates, p.workflows, nil)
+	identifiers := make([]*common.Identifier, len(specs))
+	for i, spec := range specs {
+		if previous := existing[spec.Name]; previous != nil {
+			identifiers[i] = previous.GetIdentifier()
+		} else {
+			generated, err := common.BuildPolicyIdentifier(p.target.GetIdentifier().ToString())
+			if err != nil {
+				return err
+			}
+			identifiers[i] = generated
+		}
+		r.add(common.POLICY, identifiers[i], spec.Name)
+	}
+	for i, spec := range specs {
+		previous := existing[spec.Name]
+		delete(existing, spec.Name)
+		createdAt := common.CurrentTimestamp()
+		if previous != nil {
+			createdAt = previous.GetCreatedAt()
+		}
+		built, err := buildPolicy(identifiers[i].ToString(), createdAt, spec, r)
+		if err != nil {
+			return fmt.Errorf("policies[%d]: %w", i, err)
+		}
+		switch {
+		case previous == nil:
+			p.addChange(CREATE, common.POLICY, spec.Name, built.GetIdentifier())
+		case sameSpec(exportPolicy(previous, r), exportPolicy(built, r)):
+			built = previous
+		default:
+			p.addChange(UPDATE, common.POLICY, spec.Name, built.GetIdentifier())
+		}
+		p.policies = append(p.policies, built)
+	}
+	planDeletions(p, common.POLICY, current, existing)
+	return nil
+}
+
+// planDeletions records the deletion of the current resources missing from the manifest, in the order of the project.
+func planDeletions[T interface {
+	GetName() string
+	GetIdentifier() *common.Identifier
+}](p *ImportPlan, resourceType common.ResourceType, current []T, missing map[string]T) {
+	for _, resource := range current {
+		if _, found := missing[resource.GetName()]; found {
+			p.addChange(DELETE, resourceType, resource.GetName(), resource.GetIdentifier())
+		}
+	}
+}
+
+// ListChanges returns the changes of the plan: the project first, then its templates, workflows and policies.
+func (p *ImportPlan) ListChanges() []*Change {
+	return append([]*Change(nil), p.changes...)
+}
+
+// ListWarnings returns the breaking changes introduced in the current workflows by the template versions of the plan.
+func (p *ImportPlan) ListWarnings() []*Warning {
+	return append([]*Warning(nil), p.warnings...)
+}
+
+// ListUpdatedPolicies returns the existing policies the plan updates, which apply to the principals they are attached to.
+func (p *ImportPlan) ListUpdatedPolicies() []*policy.Policy {
+	updated := []*policy.Policy{}
+	for _, pol := range p.policies {
+		for _, change := range p.changes {
+			if change.action == UPDATE && change.identifier.ToString() == pol.GetIdentifier().ToString() {
+				updated = append(updated, pol)
+			}
+		}
+	}
+	return updated
+}
+
+// HasChanges returns true if the project does not match the manifest yet.
+func (p *ImportPlan) HasChanges() bool {
+	return len(p.changes) > 0
+}
+
+// GetProjectIdentifier returns the identifier of the imported project, generated beforehand for a new project.
+func (p *ImportPlan) GetProjectIdentifier() *common.Identifier {
+	return p.target.GetIdentifier()
+}
+
+// IsNewProject returns true if the plan creates a new project.
+func (p *ImportPlan) IsNewProject() bool {
+	return p.current == nil
+}
+
+// Apply makes the project match the manifest, and returns it.
+// The resources of the project are replaced by the resources of the manifest, in the order of the manifest.
+func (p *ImportPlan) Apply() *project.Project {
+	if p.current != nil {
+		p.current.SetName(p.metadata.Name)
+		p.current.SetDescription(p.metadata.Description)
+		for _, tag := range p.current.ListTags() {
+			p.current.RemoveTag(tag.GetKey())
+		}
+		addTags(&p.current.TaggedEntity, p.metadata.Tags)
+	}
+	for _, t := range p.target.ListTemplates() {
+		p.target.RemoveTemplate(t.GetIdentifier())
+	}
+	for _, t := range p.templates {
+		p.target.AddTemplate(t)
+	}
+	for _, w := range p.target.ListWorkflows() {
+		p.target.RemoveWorkflow(w.GetIdentifier())
+	}
+	for _, w := range p.workflows {
+		p.target.AddWorkflow(w)
+	}
+	for _, pol := range p.target.ListPolicies() {
+		p.target.RemovePolicy(pol.GetIdentifier())
+	}
+	for _, pol := range p.policies {
+		p.target.AddPolicy(pol)
+	}
+	if p.HasChanges() {
+		p.target.UpdateModificationDate()
+	}
+	return p.target
+}
+
+// Save applies the plan and stores the result: a new version of each created or updated template and workflow,
+// the deletion of the removed ones, then the project itself if it changed.
+// Templates and workflows are only stored in their own repositories when these are provided.
+func (p *ImportPlan) Save(projects project.ProjectRepository, templates template.TemplateRepository, workflows workflow.WorkflowRepository) (*project.Project, error) {
+	imported := p.Apply()
+	for _, change := range p.changes {
+		var err error
+		switch {
+		case change.resourceType == common.TEMPLATE && templates != nil:
+			if change.action == DELETE {
+				err = templates.Delete(*change.identifier)
+			} else {
+				err = templates.Create(imported.GetTemplate(change.identifier))
+			}
+		case change.resourceType == common.WORKFLOW && workflows != nil:
+			if change.action == DELETE {
+				err = workflows.Delete(*change.identifier)
+			} else {
+				err = workflows.Create(imported.GetWorkflow(change.identifier))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if p.IsNewProject() {
+		err = projects.Create(imported)
+	} else if p.HasChanges() {
+		err = projects.Update(imported)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
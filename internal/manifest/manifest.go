@@ -0,0 +1,224 @@
+// Package manifest describes AutOps projects as YAML manifests, to manage their configuration as code.
+//
+// A manifest declares a project with its tags, templates, workflows and policies. Exporting a project and importing
+// the resulting manifest is lossless: resources are matched by name, and an import only reports the changes between
+// the project and the manifest. Run-time state (runs, triggers, environments, template statuses and version history)
+// is not part of the manifest.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// API_VERSION and KIND identify the manifest format.
+const (
+	API_VERSION = "autops/v1"
+	KIND        = "Project"
+)
+
+// Manifest is the declarative description of a project.
+type Manifest struct {
+	APIVersion string          `yaml:"apiVersion"`
+	Kind       string          `yaml:"kind"`
+	Metadata   ProjectMetadata `yaml:"metadata"`
+	Templates  []*TemplateSpec `yaml:"templates,omitempty"`
+	Workflows  []*WorkflowSpec `yaml:"workflows,omitempty"`
+	Policies   []*PolicySpec   `yaml:"policies,omitempty"`
+}
+
+// ProjectMetadata declares the project itself.
+// The identifier is only set to update an existing project, a new project being created otherwise.
+type ProjectMetadata struct {
+	ID          string            `yaml:"id,omitempty"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        map[string]string `yaml:"tags,omitempty"`
+}
+
+// TemplateSpec declares a template.
+// The version is the optional semantic version of the template, and the source credentials the identifier of a secret of the project.
+// When a template changes, its version must be at least the one required by its changes, which is assigned if the version is omitted.
+type TemplateSpec struct {
+	Name              string           `yaml:"name"`
+	Description       string           `yaml:"description,omitempty"`
+	Type              string           `yaml:"type"`
+	Source            string           `yaml:"source"`
+	Version           string           `yaml:"version,omitempty"`
+	SourceCredentials string           `yaml:"source_credentials,omitempty"`
+	Inputs            []*AttributeSpec `yaml:"inputs,omitempty"`
+	Outputs           []*AttributeSpec `yaml:"outputs,omitempty"`
+}
+
+// AttributeSpec declares an input or an output of a template or a workflow.
+// Default values are written as carried by attributes, e.g. lists and objects as JSON documents.
+type AttributeSpec struct {
+	Name        string           `yaml:"name"`
+	Description string           `yaml:"description,omitempty"`
+	Type        string           `yaml:"type"`
+	Default     string           `yaml:"default,omitempty"`
+	Sensitive   bool             `yaml:"sensitive,omitempty"`
+	Constraints *ConstraintsSpec `yaml:"constraints,omitempty"`
+}
+
+// ConstraintsSpec declares the constraints of an attribute (see common.AttributeConstraints).
+type ConstraintsSpec struct {
+	Required      bool           `yaml:"required,omitempty"`
+	AllowedValues []string       `yaml:"allowed_values,omitempty"`
+	Pattern       string         `yaml:"pattern,omitempty"`
+	MinLength     *int           `yaml:"min_length,omitempty"`
+	MaxLength     *int           `yaml:"max_length,omitempty"`
+	Minimum       *float64       `yaml:"minimum,omitempty"`
+	Maximum       *float64       `yaml:"maximum,omitempty"`
+	MinItems      *int           `yaml:"min_items,omitempty"`
+	MaxItems      *int           `yaml:"max_items,omitempty"`
+	ElementType   string         `yaml:"element_type,omitempty"`
+	Schema        map[string]any `yaml:"schema,omitempty"`
+}
+
+// WorkflowSpec declares a workflow, its steps and the bindings of its outputs to the outputs of its steps.
+type WorkflowSpec struct {
+	Name           string                  `yaml:"name"`
+	Description    string                  `yaml:"description,omitempty"`
+	Source         string                  `yaml:"source"`
+	Inputs         []*AttributeSpec        `yaml:"inputs,omitempty"`
+	Outputs        []*AttributeSpec        `yaml:"outputs,omitempty"`
+	Steps          []*StepSpec             `yaml:"steps,omitempty"`
+	OutputBindings map[string]*BindingSpec `yaml:"output_bindings,omitempty"`
+}
+
+// StepSpec declares a step of a workflow, running a template of the manifest referenced by name,
+// or waiting for an approval if it declares an approval gate instead.
+type StepSpec struct {
+	Number       int           `yaml:"number"`
+	Name         string        `yaml:"name"`
+	Description  string        `yaml:"description,omitempty"`
+	Template     string        `yaml:"template,omitempty"`
+	VersionRange string        `yaml:"version_range,omitempty"`
+	Approval     *ApprovalSpec `yaml:"approval,omitempty"`
+}
+
+// ApprovalSpec declares the approval gate of a step.
+// Approvers are user identifiers, the approver action is formatted as "resource_type:action", and the timeout is a duration like "24h".
+type ApprovalSpec struct {
+	Approvers      []string `yaml:"approvers,omitempty"`
+	ApproverAction string   `yaml:"approver_action,omitempty"`
+	MinApprovals   int      `yaml:"min_approvals"`
+	Timeout        string   `yaml:"timeout"`
+}
+
+// BindingSpec binds a workflow output to the output of a step.
+type BindingSpec struct {
+	Step   int    `yaml:"step"`
+	Output string `yaml:"output"`
+}
+
+// PolicySpec declares a policy of the project.
+type PolicySpec struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description,omitempty"`
+	Tags        map[string]string `yaml:"tags,omitempty"`
+	Statements  []*StatementSpec  `yaml:"statements,omitempty"`
+}
+
+// StatementSpec declares a policy statement.
+// Resources of the project are referenced as "project", "template/<name>", "workflow/<name>" or "policy/<name>",
+// and other resources by their identifier.
+type StatementSpec struct {
+	Effect     string           `yaml:"effect"`
+	Actions    []string         `yaml:"actions"`
+	Resources  []string         `yaml:"resources"`
+	Conditions []*ConditionSpec `yaml:"conditions,omitempty"`
+}
+
+// ConditionSpec declares a condition of a policy statement, e.g. the operator "StringEquals" on the key "resource:tag/env".
+type ConditionSpec struct {
+	Operator string   `yaml:"operator"`
+	Key      string   `yaml:"key"`
+	Values   []string `yaml:"values"`
+}
+
+// Parse decodes a YAML manifest, rejecting unknown fields.
+// Returns an error wrapping ErrInvalidManifest if the document is malformed, is not a project manifest,
+// or declares several templates, workflows or policies with the same name.
+func Parse(document []byte) (*Manifest, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(document))
+	decoder.KnownFields(true)
+	var manifest Manifest
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidManifest, err.Error())
+	}
+	if err := manifest.validate(); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// validate checks the structure of the manifest, the content of each resource being validated when it is built.
+func (m *Manifest) validate() error {
+	invalid := func(location string, message string) error {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidManifest, location, message)
+	}
+	if m.APIVersion != API_VERSION {
+		return invalid("apiVersion", fmt.Sprintf("expected %q", API_VERSION))
+	}
+	if m.Kind != KIND {
+		return invalid("kind", fmt.Sprintf("expected %q", KIND))
+	}
+	if strings.TrimSpace(m.Metadata.Name) == "" {
+		return invalid("metadata.name", "a name is required")
+	}
+	checkNames := func(kind string, names []string) error {
+		seen := map[string]bool{}
+		for i, name := range names {
+			if seen[name] {
+				return invalid(fmt.Sprintf("%s[%d].name", kind, i), fmt.Sprintf("duplicate name %q", name))
+			}
+			seen[name] = true
+		}
+		return nil
+	}
+	templates, workflows, policies := []string{}, []string{}, []string{}
+	for _, spec := range m.Templates {
+		templates = append(templates, spec.Name)
+	}
+	for _, spec := range m.Workflows {
+		workflows = append(workflows, spec.Name)
+	}
+	for _, spec := range m.Policies {
+		policies = append(policies, spec.Name)
+	}
+	if err := checkNames("templates", templates); err != nil {
+		return err
+	}
+	if err := checkNames("workflows", workflows); err != nil {
+		return err
+	}
+	return checkNames("policies", policies)
+}
+
+// Marshal encodes the manifest as a YAML document.
+func (m *Manifest) Marshal() ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// sameSpec returns true if two specs declare the same resource.
+// Specs are compared through their JSON encoding, so that equivalent decoded values (e.g. 1 and 1.0 in a schema) are equal.
+func sameSpec(a any, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
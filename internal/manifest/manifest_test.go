@@ -0,0 +1,249 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+)
+
+const network = `apiVersion: autops/v1
+kind: Project
+metadata:
+  name: network
+  tags:
+    team: platform
+templates:
+  - name: vpc
+    type: terraform
+    source: path/to/vpc.zip
+    version: 1.2.0
+    inputs:
+      - name: cidr
+        type: string
+        default: 10.0.0.0/16
+        constraints:
+          pattern: ^10\.
+      - name: zones
+        type: list
+        constraints:
+          min_items: 1
+          element_type: string
+    outputs:
+      - name: vpc_id
+        type: string
+workflows:
+  - name: deploy
+    source: path/to/deploy.yml
+    outputs:
+      - name: vpc_id
+        type: string
+    steps:
+      - number: 1
+        name: vpc
+        template: vpc
+        version_range: ^1.0.0
+      - number: 2
+        name: review
+        approval:
+          approvers:
+            - autops::user:1234567890
+          min_approvals: 1
+          timeout: 24h0m0s
+    output_bindings:
+      vpc_id:
+        step: 1
+        output: vpc_id
+policies:
+  - name: deployers
+    statements:
+      - effect: Allow
+        actions:
+          - workflow:Run
+        resources:
+          - workflow/deploy
+`
+
+func summarize(plan *ImportPlan) []string {
+	summary := []string{}
+	for _, change := range plan.ListChanges() {
+		resourceType, _ := change.GetResourceType().ToString()
+		summary = append(summary, change.GetAction().ToString()+" "+resourceType+"/"+change.GetName())
+	}
+	return summary
+}
+
+func TestImportExportRoundTrip(t *testing.T) {
+	parsed, err := Parse([]byte(network))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	plan, err := NewImportPlan(nil, parsed)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expected := "create project/network, create template/vpc, create workflow/deploy, create policy/deployers"
+	if summary := strings.Join(summarize(plan), ", "); summary != expected {
+		t.Errorf("expected changes %q, got %q", expected, summary)
+	}
+	imported := plan.Apply()
+	if len(imported.ListTemplates()) != 1 || len(imported.ListWorkflows()) != 1 || len(imported.ListPolicies()) != 1 {
+		t.Fatalf("expected the project to contain the resources of the manifest")
+	}
+	deploy := imported.ListWorkflows()[0]
+	if deploy.ListSteps()[0].GetTask() != imported.ListTemplates()[0] || !deploy.ListSteps()[1].IsApprovalGate() {
+		t.Errorf("expected the steps to run the imported template and wait for an approval")
+	}
+	statement := imported.ListPolicies()[0].ListStatements()[0]
+	if statement.ListResources()[0].ToString() != deploy.GetIdentifier().ToString() {
+		t.Errorf("expected the policy to reference the imported workflow, got %s", statement.ListResources()[0].ToString())
+	}
+
+	exported, err := Export(imported)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	document, err := exported.Marshal()
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	reparsed, err := Parse(document)
+	if err != nil {
+		t.Fatalf("expected the export to be a valid manifest, got %v", err)
+	}
+	if reparsed.Metadata.ID != imported.GetIdentifier().ToString() {
+		t.Errorf("expected the export to declare the project identifier")
+	}
+	plan, err = NewImportPlan(imported, reparsed)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("expected no changes when importing an export, got %v", summarize(plan))
+	}
+	again, _ := Export(plan.Apply())
+	if !sameSpec(exported, again) {
+		t.Error("expected the project to be unchanged by an import without changes")
+	}
+}
+
+func TestImportPlanChanges(t *testing.T) {
+	parsed, _ := Parse([]byte(network))
+	plan, _ := NewImportPlan(nil, parsed)
+	imported := plan.Apply()
+	vpc := imported.ListTemplates()[0]
+
+	changed := strings.Replace(network, "path/to/vpc.zip", "path/to/vpc-v2.zip", 1)
+	changed = strings.Replace(changed, "version: 1.2.0", "version: 1.2.1", 1)
+	changed = changed[:strings.Index(changed, "policies:")]
+	changed = strings.Replace(changed, "  tags:\n    team: platform\n", "", 1)
+	parsed, err := Parse([]byte(changed))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	plan, err = NewImportPlan(imported, parsed)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expected := "update project/network, update template/vpc, update workflow/deploy, delete policy/deployers"
+	if summary := strings.Join(summarize(plan), ", "); summary != expected {
+		t.Errorf("expected changes %q, got %q", expected, summary)
+	}
+	if len(imported.ListPolicies()) != 1 || imported.ListTemplates()[0] != vpc {
+		t.Fatal("expected a plan to leave the project unchanged until it is applied")
+	}
+
+	plan.Apply()
+	updated := imported.ListTemplates()[0]
+	if updated.GetIdentifier().ToString() != vpc.GetIdentifier().ToString() || updated.GetVersion() != vpc.GetVersion()+1 || updated.GetSourcePath() != "path/to/vpc-v2.zip" {
+		t.Errorf("expected a new version of the template")
+	}
+	if imported.ListWorkflows()[0].ListSteps()[0].GetTask() != updated {
+		t.Errorf("expected the workflow step to run the new template version")
+	}
+	if len(imported.ListPolicies()) != 0 || len(imported.ListTags()) != 0 {
+		t.Errorf("expected the policy and the tags to be removed")
+	}
+}
+
+func TestImportPlanTemplateRelease(t *testing.T) {
+	ranged := strings.Replace(network, "version_range: ^1.0.0", "version_range: \">=1.0.0\"", 1)
+	parsed, _ := Parse([]byte(ranged))
+	plan, _ := NewImportPlan(nil, parsed)
+	imported := plan.Apply()
+	importChange := func(document string) (*ImportPlan, error) {
+		parsed, err := Parse([]byte(document))
+		if err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+		return NewImportPlan(imported, parsed)
+	}
+
+	undeclared := strings.Replace(ranged, "    version: 1.2.0\n", "", 1)
+	if plan, err := importChange(undeclared); err != nil || plan.HasChanges() {
+		t.Errorf("expected a template declared without version to keep its released version, got %v %v", err, summarize(plan))
+	}
+	plan, err := importChange(strings.Replace(undeclared, "path/to/vpc.zip", "path/to/vpc-v2.zip", 1))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if version := plan.Apply().ListTemplates()[0].GetSemanticVersion(); version == nil || version.ToString() != "1.2.1" {
+		t.Errorf("expected the new template version to be released as 1.2.1, got %v", version)
+	}
+
+	parsed, _ = Parse([]byte(ranged))
+	plan, _ = NewImportPlan(nil, parsed)
+	imported = plan.Apply()
+	breaking := strings.Replace(ranged, "        default: 10.0.0.0/16\n", "", 1)
+	if _, err := importChange(breaking); !errors.Is(err, template.ErrSemanticVersionTooLow) {
+		t.Errorf("expected ErrSemanticVersionTooLow, got %v", err)
+	}
+	plan, err = importChange(strings.Replace(breaking, "version: 1.2.0", "version: 2.0.0", 1))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	warnings := plan.ListWarnings()
+	if len(warnings) != 1 || warnings[0].GetWorkflowName() != "deploy" || warnings[0].GetTemplateName() != "vpc" || warnings[0].GetUpgradeWarning().GetStepNumber() != 1 {
+		t.Fatalf("expected a warning for the first step of the deploy workflow, got %d", len(warnings))
+	}
+}
+
+func TestInvalidManifests(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected error
+	}{
+		{"unknown field", strings.Replace(network, "source: path/to/deploy.yml", "src: path/to/deploy.yml", 1), ErrInvalidManifest},
+		{"wrong kind", strings.Replace(network, "kind: Project", "kind: Workflow", 1), ErrInvalidManifest},
+		{"duplicate name", strings.Replace(network, "name: deployers", "name: deployers\n  - name: deployers", 1), ErrInvalidManifest},
+		{"unknown template", strings.Replace(network, "template: vpc", "template: subnet", 1), ErrUnknownTemplate},
+		{"invalid timeout", strings.Replace(network, "timeout: 24h0m0s", "timeout: tomorrow", 1), ErrInvalidApprovalTimeout},
+		{"invalid constraint", strings.Replace(network, `pattern: ^10\.`, "pattern: (", 1), common.ErrInvalidConstraint},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := Parse([]byte(test.document))
+			if err == nil {
+				_, err = NewImportPlan(nil, parsed)
+			}
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected err to be %v, got %v", test.expected, err)
+			}
+		})
+	}
+
+	parsed, _ := Parse([]byte(network))
+	plan, _ := NewImportPlan(nil, parsed)
+	imported := plan.Apply()
+	parsed.Metadata.ID = "autops::project:ZYXWVUTSRQ"
+	if _, err := NewImportPlan(imported, parsed); !errors.Is(err, ErrProjectMismatch) {
+		t.Errorf("expected err to be ErrProjectMismatch, got %v", err)
+	}
+	parsed, _ = Parse([]byte(strings.Replace(network, "- workflow:Run", "- workflow:Fly", 1)))
+	if _, err := NewImportPlan(nil, parsed); err == nil || !strings.HasPrefix(err.Error(), "policies[0]: statements[0].actions[0]: ") {
+		t.Errorf("expected the error to locate the invalid action, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+// Permission is an action that must be allowed on a resource to apply an import plan.
+type Permission struct {
+	resource *common.Identifier
+	action   policy.PolicyAction
+}
+
+// GetResource returns the resource the action is performed on.
+func (p *Permission) GetResource() *common.Identifier {
+	return p.resource
+}
+
+// GetAction returns the action performed by the plan.
+func (p *Permission) GetAction() policy.PolicyAction {
+	return p.action
+}
+
+// ListRequiredPermissions returns the permissions required to apply the plan, without duplicates:
+//   - creating a template, workflow or policy requires the create action of the project,
+//   - updating a template requires publishing a new version of it, and updating or deleting other resources their own action,
+//   - creating or updating a workflow requires using the existing templates of its steps,
+//   - creating or updating a policy requires the actions it allows on resources outside of the project.
+//
+// Creating a project and its resources otherwise requires no permission, since the project does not exist yet.
+func (p *ImportPlan) ListRequiredPermissions() []*Permission {
+	permissions := []*Permission{}
+	required := map[string]bool{}
+	add := func(resource *common.Identifier, action policy.PolicyAction) {
+		formatted, _ := policy.FormatPolicyAction(action)
+		key := resource.ToString() + " " + formatted
+		if !required[key] {
+			required[key] = true
+			permissions = append(permissions, &Permission{resource: resource, action: action})
+		}
+	}
+
+	projectId := p.target.GetIdentifier()
+	created := map[string]bool{}
+	for _, change := range p.changes {
+		if change.action == CREATE {
+			created[change.identifier.ToString()] = true
+		}
+	}
+	actions := map[common.ResourceType]map[ChangeAction]policy.PolicyAction{
+		common.TEMPLATE: {CREATE: policy.CREATE_TEMPLATE, UPDATE: policy.CREATE_TEMPLATE_VERSION, DELETE: policy.DELETE_TEMPLATE},
+		common.WORKFLOW: {CREATE: policy.CREATE_WORKFLOW, UPDATE: policy.UPDATE_WORKFLOW, DELETE: policy.DELETE_WORKFLOW},
+		common.POLICY:   {CREATE: policy.CREATE_POLICY, UPDATE: policy.UPDATE_POLICY, DELETE: policy.DELETE_POLICY},
+	}
+	for _, change := range p.changes {
+		if change.resourceType == common.PROJECT {
+			if change.action == UPDATE {
+				add(projectId, policy.UPDATE_PROJECT)
+			}
+			continue
+		}
+		action := actions[change.resourceType][change.action]
+		if change.action == CREATE {
+			if !p.IsNewProject() {
+				add(projectId, action)
+			}
+		} else {
+			add(change.identifier, action)
+		}
+	}
+
+	for _, wf := range p.workflows {
+		if !p.changed(wf.GetIdentifier()) {
+			continue
+		}
+		for _, step := range wf.ListSteps() {
+			task := step.GetTask()
+			if task != nil && !created[task.GetIdentifier().ToString()] {
+				add(task.GetIdentifier(), policy.USE_TEMPLATE)
+			}
+		}
+	}
+	for _, pol := range p.policies {
+		if !p.changed(pol.GetIdentifier()) {
+			continue
+		}
+		for _, grant := range pol.ListGrantsOutsideProject() {
+			add(grant.GetResource(), grant.GetAction())
+		}
+	}
+	return permissions
+}
+
+// changed returns true if the plan creates or updates the resource.
+func (p *ImportPlan) changed(identifier *common.Identifier) bool {
+	for _, change := range p.changes {
+		if change.action != DELETE && change.identifier.ToString() == identifier.ToString() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+)
+
+func TestImportPlanRequiredPermissions(t *testing.T) {
+	parsed, _ := Parse([]byte(network))
+	plan, _ := NewImportPlan(nil, parsed)
+	if permissions := plan.ListRequiredPermissions(); len(permissions) != 0 {
+		t.Errorf("expected a new project to require no permission, got %d", len(permissions))
+	}
+	imported := plan.Apply()
+
+	changed := strings.Replace(network, "path/to/vpc.zip", "path/to/vpc-v2.zip", 1)
+	changed = strings.Replace(changed, "version: 1.2.0", "version: 1.2.1", 1)
+	changed = changed[:strings.Index(changed, "policies:")]
+	changed = strings.Replace(changed, "  tags:\n    team: platform\n", "", 1)
+	parsed, _ = Parse([]byte(changed))
+	plan, err := NewImportPlan(imported, parsed)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expected := map[string]string{
+		"project:Update":         imported.GetIdentifier().ToString(),
+		"template:CreateVersion": imported.ListTemplates()[0].GetIdentifier().ToString(),
+		"workflow:Update":        imported.ListWorkflows()[0].GetIdentifier().ToString(),
+		"policy:Delete":          imported.ListPolicies()[0].GetIdentifier().ToString(),
+		"template:Use":           imported.ListTemplates()[0].GetIdentifier().ToString(),
+	}
+	permissions := plan.ListRequiredPermissions()
+	if len(permissions) != len(expected) {
+		t.Fatalf("expected %d permissions, got %d", len(expected), len(permissions))
+	}
+	for _, permission := range permissions {
+		action, _ := policy.FormatPolicyAction(permission.GetAction())
+		if expected[action] != permission.GetResource().ToString() {
+			t.Errorf("unexpected permission %s on %s", action, permission.GetResource().ToString())
+		}
+	}
+
+	withPolicy := strings.Replace(network, "name: deployers", "name: operators", 1)
+	parsed, _ = Parse([]byte(withPolicy))
+	plan, _ = NewImportPlan(imported, parsed)
+	created := false
+	for _, permission := range plan.ListRequiredPermissions() {
+		created = created || (permission.GetAction() == policy.CREATE_POLICY && permission.GetResource() == imported.GetIdentifier())
+	}
+	if !created {
+		t.Errorf("expected the policy creation to be required on the project")
+	}
+
+	outside := strings.Replace(network, "          - workflow/deploy", "          - autops::project:ABCDEFGHIJ:workflow:ABCDEFGHIJ", 1)
+	parsed, _ = Parse([]byte(outside))
+	plan, _ = NewImportPlan(nil, parsed)
+	permissions = plan.ListRequiredPermissions()
+	if len(permissions) != 1 || permissions[0].GetAction() != policy.RUN_WORKFLOW || permissions[0].GetResource().ToString() != "autops::project:ABCDEFGHIJ:workflow:ABCDEFGHIJ" {
+		t.Errorf("expected a policy allowing an action outside of the new project to require it, got %d permissions", len(permissions))
+	}
+}
@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/secret"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/template"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+)
+
+// changedTemplates returns the templates created or updated by the plan.
+func (p *ImportPlan) changedTemplates() []*template.Template {
+	templates := []*template.Template{}
+	for _, t := range p.templates {
+		if p.changed(t.GetIdentifier()) {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+// changedWorkflows returns the workflows created or updated by the plan.
+func (p *ImportPlan) changedWorkflows() []*workflow.Workflow {
+	workflows := []*workflow.Workflow{}
+	for _, w := range p.workflows {
+		if p.changed(w.GetIdentifier()) {
+			workflows = append(workflows, w)
+		}
+	}
+	return workflows
+}
+
+// createdVersions returns the versioned sources of the templates and workflows created or updated by the plan.
+func (p *ImportPlan) createdVersions() []*common.VersionedSource {
+	versions := []*common.VersionedSource{}
+	for _, t := range p.changedTemplates() {
+		versions = append(versions, &t.VersionedSource)
+	}
+	for _, w := range p.changedWorkflows() {
+		versions = append(versions, &w.VersionedSource)
+	}
+	return versions
+}
+
+// SetAuthor attributes the template and workflow versions created by the plan to the user.
+// Returns an error if the identifier is not a user identifier.
+func (p *ImportPlan) SetAuthor(author *common.Identifier) error {
+	for _, version := range p.createdVersions() {
+		if err := version.SetAuthor(author); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetChangelog describes the changes of the template and workflow versions created by the plan.
+// Returns an error if the changelog exceeds common.MAX_CHANGELOG_LENGTH characters.
+func (p *ImportPlan) SetChangelog(changelog string) error {
+	for _, version := range p.createdVersions() {
+		if err := version.SetChangelog(changelog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordContent records the hash of the source content of the template and workflow versions created by the plan,
+// pinning their git sources to the commit their ref resolves to, see versioning.SourceLoader.RecordContent.
+// The credentials of private template sources are decrypted with the master key, and their content is only recorded
+// when the secret repository and the key are provided. Versions whose content cannot be loaded, like URL sources, are left without hash.
+func (p *ImportPlan) RecordContent(ctx context.Context, loader *versioning.SourceLoader, secrets secret.SecretRepository, key *secret.MasterKey) error {
+	for _, t := range p.changedTemplates() {
+		var credentials *gitsource.Credentials
+		if t.GetSourceCredentials() != nil {
+			if secrets == nil || key == nil || t.GetGitSource() == nil {
+				continue
+			}
+			var err error
+			if credentials, err = gitsource.TemplateCredentials(t, secrets, key); err != nil {
+				return err
+			}
+		}
+		if err := recordContent(ctx, loader, &t.VersionedSource, credentials); err != nil {
+			return err
+		}
+	}
+	for _, w := range p.changedWorkflows() {
+		if err := recordContent(ctx, loader, &w.VersionedSource, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordContent records the hash of the source content of the version, ignoring the sources the loader cannot load.
+func recordContent(ctx context.Context, loader *versioning.SourceLoader, version *common.VersionedSource, credentials *gitsource.Credentials) error {
+	err := loader.RecordContent(ctx, version, credentials)
+	if errors.Is(err, versioning.ErrSourceNotAvailable) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/versioning"
+)
+
+func TestImportPlanVersions(t *testing.T) {
+	parsed, _ := Parse([]byte(network))
+	plan, _ := NewImportPlan(nil, parsed)
+	imported := plan.Apply()
+	vpc := imported.ListTemplates()[0]
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "path", "to"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "path", "to", "deploy-v2.yml"), []byte("steps: []"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := Parse([]byte(strings.Replace(network, "path/to/deploy.yml", "path/to/deploy-v2.yml", 1)))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	plan, err = NewImportPlan(imported, parsed)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	author, _ := common.NewIdentifier("autops::user:1234567890")
+	if err := plan.SetAuthor(imported.GetIdentifier()); err != common.ErrInvalidAuthor {
+		t.Errorf("expected ErrInvalidAuthor, got %v", err)
+	}
+	if err := plan.SetChangelog(strings.Repeat("a", common.MAX_CHANGELOG_LENGTH+1)); err != common.ErrInvalidChangelog {
+		t.Errorf("expected ErrInvalidChangelog, got %v", err)
+	}
+	if err := plan.SetAuthor(author); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := plan.SetChangelog("Move the deploy workflow"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := plan.RecordContent(context.Background(), versioning.NewSourceLoader(nil, root), nil, nil); err != nil {
+		t.Fatalf("expected the sources that cannot be loaded to be skipped, got %v", err)
+	}
+
+	updated := plan.Apply()
+	deploy := updated.ListWorkflows()[0]
+	if deploy.GetAuthor() == nil || deploy.GetAuthor().ToString() != author.ToString() || deploy.GetChangelog() != "Move the deploy workflow" {
+		t.Errorf("expected the new workflow version to be attributed to the author, with the changelog")
+	}
+	if !strings.HasPrefix(deploy.GetContentHash(), "sha256:") {
+		t.Errorf("expected the content of the new workflow version to be recorded, got %q", deploy.GetContentHash())
+	}
+	if updated.ListTemplates()[0] != vpc || vpc.GetAuthor() != nil || vpc.GetChangelog() != "" || vpc.GetContentHash() != "" {
+		t.Errorf("expected the unchanged template version to be left as is")
+	}
+}
@@ -0,0 +1,112 @@
+// Package policylint statically analyzes policies, reporting the statements that are invalid, never apply or grant too much.
+package policylint
+
+import "fmt"
+
+// Severity tells whether a finding makes the policy invalid or only deserves attention.
+type Severity int
+
+const (
+	// ERROR indicates a statement that cannot apply as written.
+	ERROR Severity = iota
+	// WARNING indicates a statement that applies, but is likely a mistake or grants too much.
+	WARNING
+)
+
+// ToString returns the string representation of a Severity.
+func (s Severity) ToString() string {
+	if s == ERROR {
+		return "error"
+	}
+	return "warning"
+}
+
+// Rule identifies the check reporting a finding.
+type Rule int
+
+const (
+	// EMPTY_STATEMENT reports a statement without actions or resources, which never applies.
+	EMPTY_STATEMENT Rule = iota
+	// ACTION_TYPE_MISMATCH reports an action or resource that matches the resource type of none of the resources or actions of its statement.
+	ACTION_TYPE_MISMATCH
+	// UNKNOWN_RESOURCE reports a resource identifier pointing at a resource that does not exist, or that the reader cannot read (see Linter.LintAs).
+	UNKNOWN_RESOURCE
+	// SHADOWED_STATEMENT reports a statement whose every action on every resource is covered by another statement with the same effect.
+	SHADOWED_STATEMENT
+	// NEUTRALIZED_ALLOW reports an allow statement whose every action on every resource is denied by other statements.
+	NEUTRALIZED_ALLOW
+	// BROAD_GRANT reports an allow statement granting every action of a resource type, like a "resource_type:*" wildcard would.
+	BROAD_GRANT
+)
+
+// ToString returns the string representation of a Rule.
+func (r Rule) ToString() string {
+	switch r {
+	case EMPTY_STATEMENT:
+		return "empty-statement"
+	case ACTION_TYPE_MISMATCH:
+		return "action-type-mismatch"
+	case UNKNOWN_RESOURCE:
+		return "unknown-resource"
+	case SHADOWED_STATEMENT:
+		return "shadowed-statement"
+	case NEUTRALIZED_ALLOW:
+		return "neutralized-allow"
+	default:
+		return "broad-grant"
+	}
+}
+
+// GetSeverity returns the severity of the findings of the rule.
+func (r Rule) GetSeverity() Severity {
+	switch r {
+	case EMPTY_STATEMENT, ACTION_TYPE_MISMATCH:
+		return ERROR
+	default:
+		return WARNING
+	}
+}
+
+// Finding is an issue reported by a rule on a statement of a policy, or on one of its fields.
+type Finding struct {
+	rule      Rule
+	statement int
+	field     string
+	message   string
+}
+
+// newFinding creates a Finding on the field of the statement at the given index, with a formatted message.
+// The field is empty when the finding concerns the whole statement.
+func newFinding(rule Rule, statement int, field string, format string, args ...any) *Finding {
+	return &Finding{
+		rule:      rule,
+		statement: statement,
+		field:     field,
+		message:   fmt.Sprintf(format, args...),
+	}
+}
+
+// GetRule returns the rule that reported the finding.
+func (f *Finding) GetRule() Rule {
+	return f.rule
+}
+
+// GetSeverity returns the severity of the rule that reported the finding.
+func (f *Finding) GetSeverity() Severity {
+	return f.rule.GetSeverity()
+}
+
+// GetStatement returns the index of the statement of the finding in the policy.
+func (f *Finding) GetStatement() int {
+	return f.statement
+}
+
+// GetField returns the field of the statement of the finding, e.g. "resources[1]", or an empty string for the whole statement.
+func (f *Finding) GetField() string {
+	return f.field
+}
+
+// GetMessage returns the human-readable description of the finding.
+func (f *Finding) GetMessage() string {
+	return f.message
+}
@@ -0,0 +1,308 @@
+package policylint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+)
+
+// Linter analyzes the statements of policies, looking up the resources they target to report the ones that do not exist.
+type Linter struct {
+	projects project.ProjectRepository
+	users    identity.UserRepository
+	groups   identity.GroupRepository
+}
+
+// NewLinter creates a Linter looking up resources in the given repositories.
+// Any repository may be nil, in which case the existence of the resources it stores is not checked.
+// Projects store their templates, workflows, policies and environments, while secrets, roles and sessions are never checked.
+func NewLinter(projects project.ProjectRepository, users identity.UserRepository, groups identity.GroupRepository) *Linter {
+	return &Linter{
+		projects: projects,
+		users:    users,
+		groups:   groups,
+	}
+}
+
+// Lint analyzes every statement of the policy, returning the findings in the order of the statements.
+// Statements without actions or resources are only reported as empty.
+//
+// Returns an error if a resource cannot be looked up.
+func (l *Linter) Lint(p *policy.Policy) ([]*Finding, error) {
+	return l.LintAs(p, nil)
+}
+
+// LintAs analyzes the policy like Lint on behalf of the reader, who is shown the resources it is allowed to read only.
+// The other resources are reported as unknown like the ones that do not exist, so that linting does not disclose them.
+// Every resource is shown if the reader is nil.
+func (l *Linter) LintAs(p *policy.Policy, reader *identity.User) ([]*Finding, error) {
+	findings := []*Finding{}
+	statements := p.ListStatements()
+	resolver := &resolver{linter: l, reader: reader, projects: map[string]*project.Project{}}
+	for i, statement := range statements {
+		if len(statement.ListActions()) == 0 || len(statement.ListResources()) == 0 {
+			findings = append(findings, newFinding(EMPTY_STATEMENT, i, "", "the statement has no actions or no resources, so it never applies"))
+			continue
+		}
+		findings = append(findings, lintTypes(i, statement)...)
+		unknown, err := resolver.lintResources(i, statement)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, unknown...)
+		if finding := lintShadowed(i, statements); finding != nil {
+			findings = append(findings, finding)
+		}
+		if finding := lintNeutralized(i, statements); finding != nil {
+			findings = append(findings, finding)
+		}
+		findings = append(findings, lintBroad(i, statement)...)
+	}
+	return findings, nil
+}
+
+// appliesTo returns true if the action can be requested on resources of the given type.
+// Besides workflows, workflow runs are authorized on the environments they target.
+func appliesTo(action policy.PolicyAction, resourceType common.ResourceType) bool {
+	if action.ResourceType() == resourceType {
+		return true
+	}
+	return action == policy.PolicyAction(policy.RUN_WORKFLOW) && resourceType == common.ENVIRONMENT
+}
+
+// lintTypes reports the actions applying to none of the resources of the statement, and the resources none of its actions apply to.
+func lintTypes(index int, statement *policy.PolicyStatement) []*Finding {
+	findings := []*Finding{}
+	for i, action := range statement.ListActions() {
+		matched := false
+		for _, resource := range statement.ListResources() {
+			matched = matched || appliesTo(action, resource.GetType())
+		}
+		if !matched {
+			formatted, _ := policy.FormatPolicyAction(action)
+			findings = append(findings, newFinding(ACTION_TYPE_MISMATCH, index, fmt.Sprintf("actions[%d]", i), "the action %s applies to none of the resources of the statement", formatted))
+		}
+	}
+	for i, resource := range statement.ListResources() {
+		matched := false
+		for _, action := range statement.ListActions() {
+			matched = matched || appliesTo(action, resource.GetType())
+		}
+		if !matched {
+			findings = append(findings, newFinding(ACTION_TYPE_MISMATCH, index, fmt.Sprintf("resources[%d]", i), "none of the actions of the statement apply to the resource %s", resource.ToString()))
+		}
+	}
+	return findings
+}
+
+// targets returns true if the statement lists both the action, of the same resource type, and the resource.
+func targets(statement *policy.PolicyStatement, resource *common.Identifier, action policy.PolicyAction) bool {
+	if !containsAction(statement.ListActions(), action) {
+		return false
+	}
+	for _, candidate := range statement.ListResources() {
+		if candidate.ToString() == resource.ToString() {
+			return true
+		}
+	}
+	return false
+}
+
+// covers returns true if the statement applies, whatever the request, to every action on every resource of the other statement.
+func covers(statement *policy.PolicyStatement, other *policy.PolicyStatement) bool {
+	if len(statement.ListConditions()) != 0 {
+		return false
+	}
+	for _, resource := range other.ListResources() {
+		for _, action := range other.ListActions() {
+			if !targets(statement, resource, action) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lintShadowed reports the statement at the given index if another statement with the same effect covers it.
+// Of two statements covering each other, only the last one is reported.
+func lintShadowed(index int, statements []*policy.PolicyStatement) *Finding {
+	statement := statements[index]
+	for i, other := range statements {
+		if i == index || other.GetEffect() != statement.GetEffect() || !covers(other, statement) {
+			continue
+		}
+		if covers(statement, other) && i > index {
+			continue
+		}
+		return newFinding(SHADOWED_STATEMENT, index, "", "the statement is redundant, statements[%d] already covers all of its actions and resources", i)
+	}
+	return nil
+}
+
+// lintNeutralized reports the allow statement at the given index if every action on every resource it allows is denied by other statements.
+func lintNeutralized(index int, statements []*policy.PolicyStatement) *Finding {
+	statement := statements[index]
+	if statement.GetEffect() != policy.ALLOW {
+		return nil
+	}
+	denies := []*policy.PolicyStatement{}
+	for _, other := range statements {
+		if other.GetEffect() == policy.DENY && len(other.ListConditions()) == 0 {
+			denies = append(denies, other)
+		}
+	}
+	for _, resource := range statement.ListResources() {
+		for _, action := range statement.ListActions() {
+			denied := false
+			for _, deny := range denies {
+				denied = denied || targets(deny, resource, action)
+			}
+			if !denied {
+				return nil
+			}
+		}
+	}
+	return newFinding(NEUTRALIZED_ALLOW, index, "", "the statement never allows anything, every action on every resource is denied by other statements")
+}
+
+// lintBroad reports the allow statement at the given index for each resource type whose every action it grants.
+func lintBroad(index int, statement *policy.PolicyStatement) []*Finding {
+	findings := []*Finding{}
+	if statement.GetEffect() != policy.ALLOW {
+		return findings
+	}
+	granted := map[common.ResourceType]bool{}
+	for _, action := range statement.ListActions() {
+		granted[action.ResourceType()] = true
+	}
+	for _, resourceType := range []common.ResourceType{common.PROJECT, common.WORKFLOW, common.TEMPLATE, common.POLICY, common.USER, common.GROUP, common.ROLE} {
+		if !granted[resourceType] {
+			continue
+		}
+		all := true
+		for _, action := range policy.ListPolicyActions(resourceType) {
+			all = all && containsAction(statement.ListActions(), action)
+		}
+		if all {
+			name, _ := resourceType.ToString()
+			findings = append(findings, newFinding(BROAD_GRANT, index, "actions", "the statement grants every %s action, like a \"%s:*\" wildcard would", name, name))
+		}
+	}
+	return findings
+}
+
+// containsAction returns true if the action is one of the actions.
+func containsAction(actions []policy.PolicyAction, action policy.PolicyAction) bool {
+	for _, candidate := range actions {
+		if candidate == action {
+			return true
+		}
+	}
+	return false
+}
+
+// resolver looks up the resources of the statements of a policy, loading each project once.
+// When a reader is provided, only the resources it is allowed to read are found.
+type resolver struct {
+	linter   *Linter
+	reader   *identity.User
+	projects map[string]*project.Project
+}
+
+// findProject loads the project with the given identifier, or returns nil if it does not exist.
+func (r *resolver) findProject(projectId *common.Identifier) (*project.Project, error) {
+	if found, ok := r.projects[projectId.ToString()]; ok {
+		return found, nil
+	}
+	found, err := r.linter.projects.FindById(*projectId)
+	if err != nil {
+		return nil, err
+	}
+	r.projects[projectId.ToString()] = found
+	return found, nil
+}
+
+// readable returns true if the reader, if any, is allowed to read the resource with the given tags.
+func (r *resolver) readable(resource *common.Identifier, action policy.PolicyAction, tags []*common.Tag) bool {
+	if r.reader == nil {
+		return true
+	}
+	context := r.reader.NewRequestContext(time.Now())
+	context.SetResourceTags(tags)
+	return r.reader.IsAllowed(resource, action, context)
+}
+
+// exists returns true if the resource exists and can be read by the reader, or if its existence cannot be checked.
+// Environments can be read by the readers of their project.
+func (r *resolver) exists(resource *common.Identifier) (bool, error) {
+	switch resource.GetType() {
+	case common.USER:
+		if r.linter.users == nil {
+			return true, nil
+		}
+		user, err := r.linter.users.FindById(*resource, 0, 1)
+		return user != nil && r.readable(resource, policy.READ_USER, nil), err
+	case common.GROUP:
+		if r.linter.groups == nil {
+			return true, nil
+		}
+		group, err := r.linter.groups.FindById(*resource)
+		return group != nil && r.readable(resource, policy.READ_GROUP, nil), err
+	case common.PROJECT:
+		if r.linter.projects == nil {
+			return true, nil
+		}
+		found, err := r.findProject(resource)
+		return found != nil && r.readable(resource, policy.READ_PROJECT, found.ListTags()), err
+	case common.TEMPLATE, common.WORKFLOW, common.POLICY, common.ENVIRONMENT:
+		if r.linter.projects == nil {
+			return true, nil
+		}
+		projectId, err := resource.GetParent()
+		if err != nil {
+			return false, nil
+		}
+		found, err := r.findProject(projectId)
+		if err != nil || found == nil {
+			return false, err
+		}
+		switch resource.GetType() {
+		case common.TEMPLATE:
+			t := found.GetTemplate(resource)
+			return t != nil && r.readable(resource, policy.READ_TEMPLATE, t.ListTags()), nil
+		case common.WORKFLOW:
+			wf := found.GetWorkflow(resource)
+			return wf != nil && r.readable(resource, policy.READ_WORKFLOW, wf.ListTags()), nil
+		case common.POLICY:
+			p := found.GetPolicy(resource)
+			return p != nil && r.readable(resource, policy.READ_POLICY, p.ListTags()), nil
+		default:
+			return found.GetEnvironment(resource) != nil && r.readable(projectId, policy.READ_PROJECT, found.ListTags()), nil
+		}
+	default:
+		return true, nil
+	}
+}
+
+// lintResources reports the resources of the statement that do not exist, or that the reader cannot read.
+func (r *resolver) lintResources(index int, statement *policy.PolicyStatement) ([]*Finding, error) {
+	findings := []*Finding{}
+	message := "the resource %s does not exist"
+	if r.reader != nil {
+		message = "the resource %s does not exist or cannot be read"
+	}
+	for i, resource := range statement.ListResources() {
+		exists, err := r.exists(resource)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			findings = append(findings, newFinding(UNKNOWN_RESOURCE, index, fmt.Sprintf("resources[%d]", i), message, resource.ToString()))
+		}
+	}
+	return findings, nil
+}
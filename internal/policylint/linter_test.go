@@ -0,0 +1,129 @@
+package policylint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/identity"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/policy"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/project"
+	"github.com/AutOpsProject/AutOps-API/internal/domain/workflow"
+)
+
+// fakeProjectRepository is an in-memory project.ProjectRepository finding a single project.
+type fakeProjectRepository struct {
+	project.ProjectRepository
+	project *project.Project
+}
+
+func (f *fakeProjectRepository) FindById(id common.Identifier) (*project.Project, error) {
+	if f.project.GetIdentifier().ToString() != id.ToString() {
+		return nil, nil
+	}
+	return f.project, nil
+}
+
+func TestLint(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	wf, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "./deploy")
+	p.AddWorkflow(wf)
+	env, _ := project.NewEnvironment(p.GetIdentifier().ToString(), "prod", "", true)
+	p.AddEnvironment(env)
+	missing, _ := common.BuildTemplateIdentifier(p.GetIdentifier().ToString())
+	wfId, envId := wf.GetIdentifier().ToString(), env.GetIdentifier().ToString()
+
+	statement := func(index int, effect string, actions []string, resources ...string) *policy.PolicyStatement {
+		parsed, err := policy.ParsePolicyStatement(index, effect, actions, resources)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return parsed
+	}
+	conditional := statement(8, "Allow", []string{"workflow:Read"}, wfId)
+	condition, _ := policy.NewCondition(policy.STRING_EQUALS, "resource:tag/env", []string{"dev"})
+	conditional.SetConditions([]*policy.Condition{condition})
+	statements := []*policy.PolicyStatement{
+		statement(0, "Allow", []string{"workflow:Read"}, wfId),
+		statement(1, "Allow", []string{"workflow:Read", "workflow:Run"}, wfId, envId),
+		statement(2, "Allow", []string{"project:Read"}, wfId),
+		statement(3, "Allow", []string{}, wfId),
+		statement(4, "Allow", []string{"template:Read"}, missing.ToString()),
+		statement(5, "Deny", []string{"workflow:Delete"}, wfId),
+		statement(6, "Allow", []string{"workflow:Delete"}, wfId),
+		statement(7, "Allow", []string{"workflow:Read", "workflow:Update", "workflow:Delete", "workflow:Run"}, wfId),
+		conditional,
+	}
+	document, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "lint", "", statements)
+
+	findings, err := NewLinter(&fakeProjectRepository{project: p}, nil, nil).Lint(document)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := map[int][]string{}
+	for _, finding := range findings {
+		found[finding.GetStatement()] = append(found[finding.GetStatement()], finding.GetRule().ToString()+" "+finding.GetField())
+	}
+	expected := map[int][]string{
+		0: {"shadowed-statement "},
+		2: {"action-type-mismatch actions[0]", "action-type-mismatch resources[0]"},
+		3: {"empty-statement "},
+		4: {"unknown-resource resources[0]"},
+		6: {"shadowed-statement ", "neutralized-allow "},
+		7: {"broad-grant actions"},
+		8: {"shadowed-statement "},
+	}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected findings %v, got %v", expected, found)
+	}
+	for _, finding := range findings {
+		if finding.GetMessage() == "" || (finding.GetSeverity() == ERROR) != (finding.GetRule() == EMPTY_STATEMENT || finding.GetRule() == ACTION_TYPE_MISMATCH) {
+			t.Errorf("unexpected finding %s: %s", finding.GetRule().ToString(), finding.GetMessage())
+		}
+	}
+
+	unchecked, err := NewLinter(nil, nil, nil).Lint(document)
+	if err != nil || len(unchecked) != len(findings)-1 {
+		t.Errorf("expected resources not to be checked without repositories, got %d findings", len(unchecked))
+	}
+}
+
+func TestLintAs(t *testing.T) {
+	p, _ := project.NewProject("network", "")
+	visible, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "deploy", "", "./deploy")
+	hidden, _ := workflow.NewWorkflow(p.GetIdentifier().ToString(), "destroy", "", "./destroy")
+	p.AddWorkflow(visible)
+	p.AddWorkflow(hidden)
+	missing, _ := common.BuildWorkflowIdentifier(p.GetIdentifier().ToString())
+	read, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Read"}, []string{visible.GetIdentifier().ToString()})
+	readers, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "readers", "", []*policy.PolicyStatement{read})
+	reader, _ := identity.NewUser("reader@example.com", "reader")
+	reader.AttachPolicy(readers)
+	run, _ := policy.ParsePolicyStatement(0, "Allow", []string{"workflow:Run"}, []string{visible.GetIdentifier().ToString(), hidden.GetIdentifier().ToString(), missing.ToString()})
+	document, _ := policy.NewPolicy(p.GetIdentifier().ToString(), "runners", "", []*policy.PolicyStatement{run})
+
+	findings, err := NewLinter(&fakeProjectRepository{project: p}, nil, nil).LintAs(document, reader)
+	if err != nil || len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d (%v)", len(findings), err)
+	}
+	// The hidden workflow is reported exactly like the missing one.
+	for i, finding := range findings {
+		field := []string{"resources[1]", "resources[2]"}[i]
+		resource := run.ListResources()[i+1].ToString()
+		if finding.GetRule() != UNKNOWN_RESOURCE || finding.GetField() != field || finding.GetMessage() != "the resource "+resource+" does not exist or cannot be read" {
+			t.Errorf("unexpected finding %s %s: %s", finding.GetRule().ToString(), finding.GetField(), finding.GetMessage())
+		}
+	}
+}
+
+func TestLintIdenticalStatements(t *testing.T) {
+	projectId, _ := common.BuildProjectIdentifier()
+	first, _ := policy.ParsePolicyStatement(0, "Allow", []string{"project:Read"}, []string{projectId.ToString()})
+	second, _ := policy.ParsePolicyStatement(1, "Allow", []string{"project:Read"}, []string{projectId.ToString()})
+	document, _ := policy.NewPolicy(projectId.ToString(), "duplicates", "", []*policy.PolicyStatement{first, second})
+
+	findings, _ := NewLinter(nil, nil, nil).Lint(document)
+	if len(findings) != 1 || findings[0].GetStatement() != 1 || findings[0].GetRule() != SHADOWED_STATEMENT {
+		t.Errorf("expected only the last of two identical statements to be reported, got %d findings", len(findings))
+	}
+}
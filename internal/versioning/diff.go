@@ -0,0 +1,254 @@
+package versioning
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// MAX_DIFF_FILE_SIZE bounds the size of the files compared line by line.
+const MAX_DIFF_FILE_SIZE = 1 << 20
+
+// MAX_DIFF_LINE_PRODUCT bounds the product of the line counts of two compared files,
+// above which the whole file is reported as replaced instead of computing the smallest diff.
+const MAX_DIFF_LINE_PRODUCT = 4_000_000
+
+// DIFF_CONTEXT_LINES is the number of unchanged lines shown around each change.
+const DIFF_CONTEXT_LINES = 3
+
+// FileDiff describes how a source file changed between two versions.
+type FileDiff struct {
+	path       string
+	changeType common.ChangeType
+	patch      string
+}
+
+// GetPath returns the slash-separated path of the file relative to the source root.
+func (d *FileDiff) GetPath() string {
+	return d.path
+}
+
+// GetChangeType returns how the file changed.
+func (d *FileDiff) GetChangeType() common.ChangeType {
+	return d.changeType
+}
+
+// GetPatch returns the changes of the file in the unified diff format, or a short notice for binary or large files.
+func (d *FileDiff) GetPatch() string {
+	return d.patch
+}
+
+// DiffContent compares the source content at two paths, each being a single file or a directory,
+// returning the added, removed and modified files sorted by path.
+func DiffContent(fromPath string, toPath string) ([]*FileDiff, error) {
+	fromFiles, err := listFiles(fromPath)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := listFiles(toPath)
+	if err != nil {
+		return nil, err
+	}
+	diffs := []*FileDiff{}
+	i, j := 0, 0
+	for i < len(fromFiles) || j < len(toFiles) {
+		var from, to *sourceFile
+		switch {
+		case j == len(toFiles) || (i < len(fromFiles) && fromFiles[i].name < toFiles[j].name):
+			from = &fromFiles[i]
+			i++
+		case i == len(fromFiles) || toFiles[j].name < fromFiles[i].name:
+			to = &toFiles[j]
+			j++
+		default:
+			from, to = &fromFiles[i], &toFiles[j]
+			i++
+			j++
+		}
+		diff, err := diffFile(from, to)
+		if err != nil {
+			return nil, err
+		}
+		if diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+// diffFile compares two versions of a file, either of which is nil if the file does not exist in that version.
+// Returns nil if the file is unchanged.
+func diffFile(from *sourceFile, to *sourceFile) (*FileDiff, error) {
+	var fromContent, toContent []byte
+	truncated := false
+	diff := &FileDiff{changeType: common.MODIFIED}
+	if from != nil {
+		content, fromTruncated, err := readFile(from.path, MAX_DIFF_FILE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		fromContent, truncated, diff.path = content, fromTruncated, from.name
+	} else {
+		diff.changeType = common.ADDED
+	}
+	if to != nil {
+		content, toTruncated, err := readFile(to.path, MAX_DIFF_FILE_SIZE)
+		if err != nil {
+			return nil, err
+		}
+		toContent, truncated, diff.path = content, truncated || toTruncated, to.name
+	} else {
+		diff.changeType = common.REMOVED
+	}
+
+	switch {
+	case truncated:
+		diff.patch = fmt.Sprintf("File %s is too large to be compared", diff.path)
+	case bytes.IndexByte(fromContent, 0) >= 0 || bytes.IndexByte(toContent, 0) >= 0:
+		if diff.changeType == common.MODIFIED && bytes.Equal(fromContent, toContent) {
+			return nil, nil
+		}
+		diff.patch = fmt.Sprintf("Binary files %s differ", diff.path)
+	default:
+		fromName, toName := "a/"+diff.path, "b/"+diff.path
+		if from == nil {
+			fromName = "/dev/null"
+		}
+		if to == nil {
+			toName = "/dev/null"
+		}
+		diff.patch = UnifiedDiff(fromName, toName, string(fromContent), string(toContent))
+		if diff.patch == "" && from != nil && to != nil && from.executable == to.executable {
+			return nil, nil
+		}
+	}
+	return diff, nil
+}
+
+// UnifiedDiff returns the changes between two texts in the unified diff format, or an empty string if they are identical.
+func UnifiedDiff(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+	a, b := splitLines(from), splitLines(to)
+	edits := diffLines(a, b)
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(edits); {
+		if edits[start].kind == ' ' {
+			start++
+			continue
+		}
+		// Extend the hunk until the next change is further than twice the context away.
+		hunkStart := max(start-DIFF_CONTEXT_LINES, 0)
+		end := start
+		for next := start; next < len(edits); next++ {
+			if edits[next].kind != ' ' {
+				end = next
+			} else if next-end > 2*DIFF_CONTEXT_LINES {
+				break
+			}
+		}
+		hunkEnd := min(end+DIFF_CONTEXT_LINES+1, len(edits))
+
+		fromStart, toStart, fromCount, toCount := edits[hunkStart].fromLine, edits[hunkStart].toLine, 0, 0
+		for _, e := range edits[hunkStart:hunkEnd] {
+			if e.kind != '+' {
+				fromCount++
+			}
+			if e.kind != '-' {
+				toCount++
+			}
+		}
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount))
+		for _, e := range edits[hunkStart:hunkEnd] {
+			builder.WriteByte(e.kind)
+			builder.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				builder.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = hunkEnd
+	}
+	return builder.String()
+}
+
+// hunkRange formats the line range of a hunk, whose start is 0-based, as expected by the unified diff format.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits a text into lines, keeping their line terminator.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// edit is a line of a unified diff: kept (' '), removed ('-') or added ('+'),
+// with the 0-based positions reached in both texts before the line.
+type edit struct {
+	kind     byte
+	line     string
+	fromLine int
+	toLine   int
+}
+
+// diffLines computes the shortest sequence of edits turning a into b, from their longest common subsequence.
+// Texts whose line count product exceeds MAX_DIFF_LINE_PRODUCT are reported as entirely replaced.
+func diffLines(a []string, b []string) []edit {
+	edits := make([]edit, 0, len(a)+len(b))
+	if len(a)*len(b) > MAX_DIFF_LINE_PRODUCT {
+		for i, line := range a {
+			edits = append(edits, edit{kind: '-', line: line, fromLine: i, toLine: 0})
+		}
+		for j, line := range b {
+			edits = append(edits, edit{kind: '+', line: line, fromLine: len(a), toLine: j})
+		}
+		return edits
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{kind: ' ', line: a[i], fromLine: i, toLine: j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{kind: '-', line: a[i], fromLine: i, toLine: j})
+			i++
+		default:
+			edits = append(edits, edit{kind: '+', line: b[j], fromLine: i, toLine: j})
+			j++
+		}
+	}
+	return edits
+}
@@ -0,0 +1,8 @@
+package versioning
+
+import "errors"
+
+var (
+	ErrSourceNotAvailable = errors.New("the source content cannot be loaded: only local paths and git sources are supported")
+	ErrContentMismatch    = errors.New("the source content does not match the content hash recorded for the version")
+)
@@ -0,0 +1,98 @@
+// Package versioning records the source content of template and workflow versions, and compares the content of two versions.
+package versioning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// HashContent returns the hash of the source content at the given path, formatted as sha256:<hex digest>.
+// The path is either a single file or a directory, whose files are hashed with their relative path and executable bit,
+// so that renaming a file or making it executable changes the hash. Git metadata directories are ignored.
+func HashContent(path string) (string, error) {
+	files, err := listFiles(path)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, file := range files {
+		content, err := os.ReadFile(file.path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%t\x00%d\x00", file.name, file.executable, len(content))
+		hash.Write(content)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// sourceFile is a regular file of a source, identified by its slash-separated path relative to the source root.
+type sourceFile struct {
+	name       string
+	path       string
+	executable bool
+}
+
+// listFiles lists the regular files of the source at the given path, sorted by name.
+// A single file is listed under its base name.
+func listFiles(root string) ([]sourceFile, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []sourceFile{{name: filepath.Base(root), path: root, executable: info.Mode()&0o111 != 0}}, nil
+	}
+	files := []sourceFile{}
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, sourceFile{name: filepath.ToSlash(name), path: path, executable: info.Mode()&0o111 != 0})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+	return files, nil
+}
+
+// readFile reads a source file, returning at most limit bytes and whether the file was truncated.
+func readFile(path string, limit int64) ([]byte, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(content)) > limit {
+		return content[:limit], true, nil
+	}
+	return content, false, nil
+}
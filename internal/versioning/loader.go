@@ -0,0 +1,108 @@
+package versioning
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+	"github.com/AutOpsProject/AutOps-API/internal/gitsource"
+)
+
+// SourceLoader gives access to the source content of versions stored in local paths or git repositories.
+type SourceLoader struct {
+	fetcher   *gitsource.Fetcher
+	localRoot string
+}
+
+// NewSourceLoader creates a SourceLoader fetching git sources with the given fetcher.
+// Local sources are only loaded from the localRoot directory, so that versions cannot expose other files of the server.
+// Without fetcher, git sources cannot be loaded, and without localRoot, local sources cannot be loaded.
+func NewSourceLoader(fetcher *gitsource.Fetcher, localRoot string) *SourceLoader {
+	return &SourceLoader{
+		fetcher:   fetcher,
+		localRoot: localRoot,
+	}
+}
+
+// Load returns the local path of the source content of the version.
+// Git sources are checked out at the commit pinned on the version, or at the commit their ref resolves to if none is pinned yet.
+//
+// Returns ErrSourceNotAvailable if the source is a URL, a git source while the loader has no fetcher,
+// or a local path that does not exist or is outside of the local root of the loader.
+func (l *SourceLoader) Load(ctx context.Context, source *common.VersionedSource, credentials *gitsource.Credentials) (string, error) {
+	if gitSource := source.GetGitSource(); gitSource != nil {
+		if l.fetcher == nil {
+			return "", ErrSourceNotAvailable
+		}
+		checkout, err := l.fetcher.Fetch(ctx, gitSource, source.GetCommit(), credentials)
+		if err != nil {
+			return "", err
+		}
+		if source.GetCommit() == "" {
+			if err := source.SetCommit(checkout.GetCommit()); err != nil {
+				return "", err
+			}
+		}
+		return checkout.GetPath(), nil
+	}
+	if common.IsValidURL(source.GetSourcePath()) {
+		return "", ErrSourceNotAvailable
+	}
+	return l.resolveLocal(source.GetSourcePath())
+}
+
+// resolveLocal resolves the symbolic links of a local source path, relative paths being relative to the local root.
+// Returns ErrSourceNotAvailable if the loader has no local root, or if the path does not exist or resolves outside of it.
+func (l *SourceLoader) resolveLocal(sourcePath string) (string, error) {
+	if l.localRoot == "" {
+		return "", ErrSourceNotAvailable
+	}
+	root, err := filepath.EvalSymlinks(l.localRoot)
+	if err != nil {
+		return "", ErrSourceNotAvailable
+	}
+	if !filepath.IsAbs(sourcePath) {
+		sourcePath = filepath.Join(root, sourcePath)
+	}
+	path, err := filepath.EvalSymlinks(sourcePath)
+	if err != nil || (path != root && !strings.HasPrefix(path, root+string(filepath.Separator))) {
+		return "", ErrSourceNotAvailable
+	}
+	return path, nil
+}
+
+// RecordContent loads the source content of the version and records its hash, pinning git sources to their resolved commit.
+// If a hash is already recorded, the content is verified against it instead.
+//
+// Returns ErrContentMismatch if the content changed since its hash was recorded, or any error of Load.
+func (l *SourceLoader) RecordContent(ctx context.Context, source *common.VersionedSource, credentials *gitsource.Credentials) error {
+	path, err := l.Load(ctx, source, credentials)
+	if err != nil {
+		return err
+	}
+	hash, err := HashContent(path)
+	if err != nil {
+		return err
+	}
+	if source.GetContentHash() == "" {
+		return source.SetContentHash(hash)
+	}
+	if source.GetContentHash() != hash {
+		return ErrContentMismatch
+	}
+	return nil
+}
+
+// DiffSources compares the source content of two versions, returning the added, removed and modified files.
+func (l *SourceLoader) DiffSources(ctx context.Context, from *common.VersionedSource, fromCredentials *gitsource.Credentials, to *common.VersionedSource, toCredentials *gitsource.Credentials) ([]*FileDiff, error) {
+	fromPath, err := l.Load(ctx, from, fromCredentials)
+	if err != nil {
+		return nil, err
+	}
+	toPath, err := l.Load(ctx, to, toCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return DiffContent(fromPath, toPath)
+}
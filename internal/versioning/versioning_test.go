@@ -0,0 +1,146 @@
+package versioning
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AutOpsProject/AutOps-API/internal/domain/common"
+)
+
+// writeFiles creates a source directory with the given files, indexed by slash-separated path.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestHashContent(t *testing.T) {
+	first := writeFiles(t, map[string]string{"main.tf": "a", "modules/vpc.tf": "b"})
+	same := writeFiles(t, map[string]string{"main.tf": "a", "modules/vpc.tf": "b", ".git/HEAD": "ref"})
+	renamed := writeFiles(t, map[string]string{"main.tf": "a", "modules/eks.tf": "b"})
+
+	hash, err := HashContent(first)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if sameHash, _ := HashContent(same); sameHash != hash {
+		t.Error("expected git metadata to be ignored")
+	}
+	if renamedHash, _ := HashContent(renamed); renamedHash == hash {
+		t.Error("expected renaming a file to change the hash")
+	}
+	fileHash, _ := HashContent(filepath.Join(first, "main.tf"))
+	if err := (&common.VersionedSource{}).SetContentHash(fileHash); err != nil {
+		t.Errorf("expected a valid content hash, got %v", err)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk"
+	expected := "--- a/main.tf\n+++ b/main.tf\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -8,3 +8,4 @@\n h\n i\n j\n+k\n\\ No newline at end of file\n"
+	if patch := UnifiedDiff("a/main.tf", "b/main.tf", from, to); patch != expected {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+	if patch := UnifiedDiff("a/main.tf", "b/main.tf", from, from); patch != "" {
+		t.Errorf("expected identical texts to have no patch, got %s", patch)
+	}
+	if patch := UnifiedDiff("/dev/null", "b/new.tf", "", "x\n"); patch != "--- /dev/null\n+++ b/new.tf\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+}
+
+func TestDiffContent(t *testing.T) {
+	from := writeFiles(t, map[string]string{"main.tf": "a\n", "removed.tf": "r\n", "same.tf": "s\n", "image.bin": "\x00\x01"})
+	to := writeFiles(t, map[string]string{"main.tf": "b\n", "added.tf": "n\n", "same.tf": "s\n", "image.bin": "\x00\x02"})
+	diffs, err := DiffContent(from, to)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expected := []struct {
+		path       string
+		changeType common.ChangeType
+	}{
+		{"added.tf", common.ADDED},
+		{"image.bin", common.MODIFIED},
+		{"main.tf", common.MODIFIED},
+		{"removed.tf", common.REMOVED},
+	}
+	if len(diffs) != len(expected) {
+		t.Fatalf("expected %d diffs, got %d", len(expected), len(diffs))
+	}
+	for i, diff := range diffs {
+		if diff.GetPath() != expected[i].path || diff.GetChangeType() != expected[i].changeType {
+			t.Errorf("expected %s to be %s, got %s %s", expected[i].path, expected[i].changeType.ToString(), diff.GetPath(), diff.GetChangeType().ToString())
+		}
+	}
+	if diffs[1].GetPatch() != "Binary files image.bin differ" {
+		t.Errorf("unexpected binary patch %q", diffs[1].GetPatch())
+	}
+	if diffs[3].GetPatch() != "--- a/removed.tf\n+++ /dev/null\n@@ -1 +0,0 @@\n-r\n" {
+		t.Errorf("unexpected removal patch %q", diffs[3].GetPatch())
+	}
+}
+
+func TestSourceLoaderRecordContent(t *testing.T) {
+	root := writeFiles(t, map[string]string{"main.tf": "a\n"})
+	source, _ := common.NewVersionedSource(root, 1)
+	loader := NewSourceLoader(nil, filepath.Dir(root))
+
+	if err := loader.RecordContent(context.Background(), source, nil); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if source.GetContentHash() == "" {
+		t.Error("expected the content hash to be recorded")
+	}
+	os.WriteFile(filepath.Join(root, "main.tf"), []byte("b\n"), 0o644)
+	if err := loader.RecordContent(context.Background(), source, nil); err != ErrContentMismatch {
+		t.Errorf("expected err to be ErrContentMismatch, got %v", err)
+	}
+
+	remote, _ := common.NewVersionedSource("https://example.com/template.zip", 1)
+	if _, err := loader.Load(context.Background(), remote, nil); err != ErrSourceNotAvailable {
+		t.Errorf("expected err to be ErrSourceNotAvailable, got %v", err)
+	}
+	git, _ := common.NewVersionedSource("git::https://example.com/repo.git", 1)
+	if _, err := loader.Load(context.Background(), git, nil); err != ErrSourceNotAvailable {
+		t.Errorf("expected err to be ErrSourceNotAvailable, got %v", err)
+	}
+}
+
+func TestSourceLoaderLocalRoot(t *testing.T) {
+	root := writeFiles(t, map[string]string{"vpc/main.tf": "a\n"})
+	outside := writeFiles(t, map[string]string{"main.tf": "secret\n"})
+	os.Symlink(outside, filepath.Join(root, "escape"))
+	loader := NewSourceLoader(nil, root)
+
+	for _, sourcePath := range []string{filepath.Join(root, "vpc"), "vpc"} {
+		source, _ := common.NewVersionedSource(sourcePath, 1)
+		if path, err := loader.Load(context.Background(), source, nil); err != nil || path != filepath.Join(root, "vpc") {
+			t.Errorf("%s: expected the source to be loaded, got %q (%v)", sourcePath, path, err)
+		}
+	}
+	for _, sourcePath := range []string{outside, filepath.Join(root, "escape"), filepath.Join(root, "..", filepath.Base(outside)), filepath.Join(root, "missing")} {
+		source, _ := common.NewVersionedSource(sourcePath, 1)
+		if _, err := loader.Load(context.Background(), source, nil); err != ErrSourceNotAvailable {
+			t.Errorf("%s: expected err to be ErrSourceNotAvailable, got %v", sourcePath, err)
+		}
+	}
+	source, _ := common.NewVersionedSource(filepath.Join(root, "vpc"), 1)
+	if _, err := NewSourceLoader(nil, "").Load(context.Background(), source, nil); err != ErrSourceNotAvailable {
+		t.Errorf("expected local sources not to be loaded without local root, got %v", err)
+	}
+}